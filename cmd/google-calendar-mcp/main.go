@@ -0,0 +1,290 @@
+// Command google-calendar-mcp serves a Google Calendar to MCP clients over
+// stdio, TCP or the legacy HTTP+SSE transport. It is configured through
+// environment variables; see the README.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"github.com/cherya/google-calendar-mcp/pkg/server"
+)
+
+const (
+	transportStdio = "stdio"
+	transportSSE   = "sse"
+	transportTCP   = "tcp"
+
+	defaultAddr = "localhost:8080"
+
+	// exitInputError is the exit status when stdin can no longer be read
+	exitInputError = 3
+)
+
+func main() {
+	showVersion := flag.Bool("version", false, "print version information and exit")
+	checkUpdate := flag.Bool("check-update", false, "check GitHub for a newer release and exit")
+	doctor := flag.Bool("doctor", false, "check the credentials and calendar access, report the result and exit")
+	transport := flag.String("transport", transportStdio, "transport to serve: stdio, sse for the legacy HTTP+SSE transport, or tcp for several clients at once")
+	addr := flag.String("addr", defaultAddr, "address the sse and tcp transports listen on")
+	framing := flag.String("framing", server.FramingNewline, "message framing on stdio: newline, or content-length for LSP-style headers")
+	authorizeDevice := flag.Bool("authorize-device", false, "get an OAuth token for GOOGLE_OAUTH_TOKEN_FILE with a code entered on another device, then exit")
+	impersonate := flag.String("impersonate-service-account", "", "act as this service account with short-lived tokens issued to the Application Default Credentials, instead of a key file")
+	flag.Parse()
+
+	switch *transport {
+	case transportStdio, transportSSE, transportTCP:
+	default:
+		log.Fatalf("Invalid -transport %q: expected %s, %s or %s", *transport, transportStdio, transportSSE, transportTCP)
+	}
+	switch *framing {
+	case server.FramingNewline:
+	case server.FramingContentLength:
+		if *transport != transportStdio {
+			log.Fatalf("-framing %s is only supported with -transport %s", server.FramingContentLength, transportStdio)
+		}
+	default:
+		log.Fatalf("Invalid -framing %q: expected %s or %s", *framing, server.FramingNewline, server.FramingContentLength)
+	}
+
+	if *showVersion {
+		fmt.Println(server.VersionInfo())
+		return
+	}
+
+	if *checkUpdate {
+		status, err := server.CheckForUpdate(context.Background())
+		if err != nil {
+			log.Fatalf("Update check failed: %v", err)
+		}
+		fmt.Println(status)
+		return
+	}
+
+	if *authorizeDevice {
+		if err := runDeviceAuthorization(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// cal is the client of the default account, which the startup
+	// diagnostics check
+	var cal *gcal.CalendarClient
+	var svc gcal.Service
+	if names := os.Getenv("GOOGLE_ACCOUNTS"); names != "" {
+		if *impersonate != "" {
+			log.Fatal("-impersonate-service-account can't be combined with GOOGLE_ACCOUNTS")
+		}
+		accounts := gcal.NewAccounts()
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			client, err := newCalendarClient("_"+strings.ToUpper(name), "")
+			if err != nil {
+				log.Fatalf("Account %s: %v", name, err)
+			}
+			if err := accounts.Add(name, client); err != nil {
+				log.Fatal(err)
+			}
+			if cal == nil {
+				cal = client
+			}
+		}
+		svc = accounts
+	} else {
+		client, err := newCalendarClient("", *impersonate)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cal, svc = client, client
+	}
+
+	var out io.Writer = os.Stdout
+	if *transport != transportStdio {
+		// Until a client connects there is nobody to send notifications to
+		out = io.Discard
+	}
+	srv := server.New(svc, out)
+	if err := srv.SetFraming(*framing); err != nil {
+		log.Fatal(err)
+	}
+	if err := srv.LoadEnv(); err != nil {
+		log.Fatal(err)
+	}
+	if *doctor {
+		if !srv.Doctor(context.Background(), os.Stdout, cal) {
+			os.Exit(1)
+		}
+		return
+	}
+	if err := srv.WriteDiagnostics(context.Background(), os.Stderr, cal); err != nil {
+		log.Printf("Failed to write startup diagnostics: %v", err)
+	}
+	if os.Getenv("CALENDAR_UPDATE_CHECK") == "true" {
+		go server.NotifyUpdate(context.Background())
+	}
+	srv.Start()
+	srv.WatchSignals()
+
+	if *transport == transportSSE {
+		l := listen(*addr)
+		log.Printf("Serving the HTTP+SSE transport on http://%s/sse", l.Addr())
+		log.Fatal(http.Serve(l, srv.SSEHandler()))
+	}
+	if *transport == transportTCP {
+		l := listen(*addr)
+		log.Printf("Serving MCP over TCP on %s", l.Addr())
+		log.Fatal(srv.ServeTCP(l))
+	}
+	if err := srv.Run(os.Stdin); err != nil {
+		os.Exit(exitInputError)
+	}
+}
+
+// newCalendarClient creates a client from the GOOGLE_CREDENTIALS_FILE,
+// GOOGLE_OAUTH_TOKEN_FILE, CALENDAR_ID and CALENDAR_CREDENTIALS variables
+// ending in suffix, e.g. CALENDAR_ID_WORK for the account work of
+// GOOGLE_ACCOUNTS. With impersonate, the client acts as that service
+// account instead.
+func newCalendarClient(suffix, impersonate string) (*gcal.CalendarClient, error) {
+	calendarID := os.Getenv("CALENDAR_ID" + suffix)
+	if calendarID == "" {
+		return nil, fmt.Errorf("CALENDAR_ID%s environment variable must be set", suffix)
+	}
+
+	cal, err := newCredentialsClient(suffix, calendarID, impersonate)
+	if err != nil {
+		return nil, err
+	}
+	if err := cal.LoadEnv(); err != nil {
+		return nil, err
+	}
+	if err := mapCredentials(cal, os.Getenv("CALENDAR_CREDENTIALS"+suffix)); err != nil {
+		return nil, fmt.Errorf("invalid CALENDAR_CREDENTIALS%s: %w", suffix, err)
+	}
+	return cal, nil
+}
+
+// newCredentialsClient creates a client acting on calendarID with the
+// credentials of the GOOGLE_CREDENTIALS_FILE and GOOGLE_OAUTH_TOKEN_FILE
+// variables ending in suffix
+func newCredentialsClient(suffix, calendarID, impersonate string) (*gcal.CalendarClient, error) {
+	credentialsFile := os.Getenv("GOOGLE_CREDENTIALS_FILE" + suffix)
+	timezone := os.Getenv("CALENDAR_TIMEZONE")
+
+	var cal *gcal.CalendarClient
+	var err error
+	if impersonate != "" {
+		if credentialsFile != "" {
+			return nil, fmt.Errorf("-impersonate-service-account uses the Application Default Credentials; unset GOOGLE_CREDENTIALS_FILE%s", suffix)
+		}
+		cal, err = gcal.NewImpersonatedCalendarClient(impersonate, calendarID, timezone)
+	} else if credentialsFile == "" {
+		// In-cluster and on VMs the metadata server hands out tokens, so
+		// that no key file has to be deployed
+		if !gcal.OnGoogleCloud() {
+			return nil, fmt.Errorf("GOOGLE_CREDENTIALS_FILE%s must be set outside Google Cloud", suffix)
+		}
+		cal, err = gcal.NewMetadataCalendarClient(calendarID, timezone)
+	} else if tokenFile := os.Getenv("GOOGLE_OAUTH_TOKEN_FILE" + suffix); tokenFile != "" {
+		switch store := os.Getenv("GOOGLE_OAUTH_TOKEN_STORE"); store {
+		case "", "file":
+			cal, err = gcal.NewOAuthCalendarClient(credentialsFile, tokenFile, calendarID, timezone)
+		case "keychain":
+			cal, err = gcal.NewKeychainOAuthCalendarClient(credentialsFile, tokenFile, calendarID, timezone)
+		case "encrypted":
+			cal, err = gcal.NewEncryptedOAuthCalendarClient(credentialsFile, tokenFile, calendarID, timezone, gcal.TokenEncryption{
+				Passphrase: os.Getenv("GOOGLE_OAUTH_TOKEN_PASSPHRASE"),
+				KMSKey:     os.Getenv("GOOGLE_OAUTH_TOKEN_KMS_KEY"),
+			})
+		default:
+			return nil, fmt.Errorf("invalid GOOGLE_OAUTH_TOKEN_STORE %q: use file, keychain or encrypted", store)
+		}
+	} else {
+		cal, err = gcal.NewCalendarClient(credentialsFile, calendarID, timezone)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create calendar client: %w", err)
+	}
+	return cal, nil
+}
+
+// mapCredentials applies a CALENDAR_CREDENTIALS value: comma-separated
+// calendar=SET pairs whose calendars are used with the credentials of the
+// GOOGLE_CREDENTIALS_FILE_SET and GOOGLE_OAUTH_TOKEN_FILE_SET variables
+func mapCredentials(cal *gcal.CalendarClient, mapping string) error {
+	clients := make(map[string]*gcal.CalendarClient)
+	for _, pair := range strings.Split(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		calendarID, set, ok := strings.Cut(pair, "=")
+		calendarID, set = strings.TrimSpace(calendarID), strings.ToUpper(strings.TrimSpace(set))
+		if !ok || calendarID == "" || set == "" {
+			return fmt.Errorf("%q is not calendar=SET", pair)
+		}
+		client, ok := clients[set]
+		if !ok {
+			if os.Getenv("GOOGLE_CREDENTIALS_FILE_"+set) == "" {
+				return fmt.Errorf("credentials %s: GOOGLE_CREDENTIALS_FILE_%s must be set", set, set)
+			}
+			var err error
+			if client, err = newCredentialsClient("_"+set, calendarID, ""); err != nil {
+				return fmt.Errorf("credentials %s: %w", set, err)
+			}
+			clients[set] = client
+		}
+		if err := cal.UseCredentials(calendarID, client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runDeviceAuthorization gets a first OAuth token on a machine without a
+// browser: it prints a URL and a code to enter on another device and waits
+// until access is granted
+func runDeviceAuthorization() error {
+	clientFile, tokenFile := os.Getenv("GOOGLE_CREDENTIALS_FILE"), os.Getenv("GOOGLE_OAUTH_TOKEN_FILE")
+	if clientFile == "" || tokenFile == "" {
+		return fmt.Errorf("-authorize-device needs GOOGLE_CREDENTIALS_FILE, the OAuth client, and GOOGLE_OAUTH_TOKEN_FILE to save the token to")
+	}
+	if store := os.Getenv("GOOGLE_OAUTH_TOKEN_STORE"); store != "" && store != "file" {
+		return fmt.Errorf("-authorize-device saves the token to a plain file; unset GOOGLE_OAUTH_TOKEN_STORE")
+	}
+	auth, err := gcal.AuthorizeDevice(context.Background(), clientFile, tokenFile)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Open %s on any device and enter the code %s\nWaiting until %s...\n", auth.VerificationURL, auth.UserCode, auth.Expiry.Format("15:04"))
+	if err := <-auth.Done; err != nil {
+		return err
+	}
+	fmt.Printf("Access granted; the token is saved to %s\n", tokenFile)
+	return nil
+}
+
+// listen returns the socket systemd activated the process with, or else
+// listens on addr
+func listen(addr string) net.Listener {
+	l, err := server.SystemdListener()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if l != nil {
+		log.Printf("Using the socket passed by systemd")
+		return l
+	}
+	if l, err = net.Listen("tcp", addr); err != nil {
+		log.Fatalf("Failed to listen on %s: %v", addr, err)
+	}
+	return l
+}
@@ -0,0 +1,340 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+type accountKey struct{}
+
+// accountName is what account names may look like, so that they can be
+// used in environment variable names
+var accountName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// Accounts is a Service over several Google accounts, such as work and
+// personal, each with a client of its own. Every call goes to the account
+// named in its context, see WithAccount, or else to the default account,
+// the one added first. Calls without a context, like CalendarID, describe
+// the default account.
+type Accounts struct {
+	names    []string
+	services map[string]Service
+}
+
+// NewAccounts returns an empty registry; add accounts with Add
+func NewAccounts() *Accounts {
+	return &Accounts{services: make(map[string]Service)}
+}
+
+// Add registers svc under name. The first account added is the default.
+func (a *Accounts) Add(name string, svc Service) error {
+	if !accountName.MatchString(name) {
+		return fmt.Errorf("invalid account name %q: use letters, digits and underscores", name)
+	}
+	if _, ok := a.services[name]; ok {
+		return fmt.Errorf("account %s is configured twice", name)
+	}
+	a.names = append(a.names, name)
+	a.services[name] = svc
+	return nil
+}
+
+// Names returns the account names, the default first
+func (a *Accounts) Names() []string {
+	return append([]string(nil), a.names...)
+}
+
+// Account returns the client of the named account
+func (a *Accounts) Account(name string) (Service, bool) {
+	svc, ok := a.services[name]
+	return svc, ok
+}
+
+// WithAccount returns a context whose calls to Accounts go to the named
+// account
+func WithAccount(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, accountKey{}, name)
+}
+
+// AccountFrom returns the account named in ctx, "" for the default
+func AccountFrom(ctx context.Context) string {
+	name, _ := ctx.Value(accountKey{}).(string)
+	return name
+}
+
+func (a *Accounts) defaultService() Service {
+	return a.services[a.names[0]]
+}
+
+func (a *Accounts) pick(ctx context.Context) (Service, error) {
+	name := AccountFrom(ctx)
+	if name == "" {
+		return a.defaultService(), nil
+	}
+	svc, ok := a.services[name]
+	if !ok {
+		return nil, invalidInputf("unknown account %q; configured accounts: %v", name, a.names)
+	}
+	return svc, nil
+}
+
+func (a *Accounts) CalendarID() string  { return a.defaultService().CalendarID() }
+func (a *Accounts) Calendars() []string { return a.defaultService().Calendars() }
+
+func (a *Accounts) ListEventsForDays(ctx context.Context, days int) ([]CalendarEvent, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListEventsForDays(ctx, days)
+}
+
+func (a *Accounts) ListEventsRange(ctx context.Context, startDate, endDate string) ([]CalendarEvent, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListEventsRange(ctx, startDate, endDate)
+}
+
+func (a *Accounts) ListCalendarEvents(ctx context.Context, calendarID, startDate, endDate string) ([]CalendarEvent, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListCalendarEvents(ctx, calendarID, startDate, endDate)
+}
+
+func (a *Accounts) ListCalendars(ctx context.Context) ([]CalendarInfo, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListCalendars(ctx)
+}
+
+func (a *Accounts) GetEvent(ctx context.Context, eventID string) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetEvent(ctx, eventID)
+}
+
+func (a *Accounts) CreateEvent(ctx context.Context, summary, description, date, startTime, endTime string, force bool) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.CreateEvent(ctx, summary, description, date, startTime, endTime, force)
+}
+
+func (a *Accounts) CreateCalendarEvent(ctx context.Context, calendarID string, draft EventDraft) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.CreateCalendarEvent(ctx, calendarID, draft)
+}
+
+func (a *Accounts) QuickAddEvent(ctx context.Context, calendarID, text string) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.QuickAddEvent(ctx, calendarID, text)
+}
+
+func (a *Accounts) UpdateEvent(ctx context.Context, eventID string, updates EventUpdates) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.UpdateEvent(ctx, eventID, updates)
+}
+
+func (a *Accounts) UpdateCalendarEvent(ctx context.Context, calendarID, eventID string, updates EventUpdates) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.UpdateCalendarEvent(ctx, calendarID, eventID, updates)
+}
+
+func (a *Accounts) ListLinkedEvents(ctx context.Context, calendarID, broadcastID string) ([]CalendarEvent, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListLinkedEvents(ctx, calendarID, broadcastID)
+}
+
+func (a *Accounts) DeleteEvent(ctx context.Context, eventID string) error {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.DeleteEvent(ctx, eventID)
+}
+
+func (a *Accounts) DeleteOccurrences(ctx context.Context, calendarID, eventID string, scope SeriesScope) error {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.DeleteOccurrences(ctx, calendarID, eventID, scope)
+}
+
+func (a *Accounts) CreateOutOfOffice(ctx context.Context, summary string, start, end time.Time, autoDecline bool, message string) (*calendar.Event, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.CreateOutOfOffice(ctx, summary, start, end, autoDecline, message)
+}
+
+func (a *Accounts) RespondToEvent(ctx context.Context, eventID, status, comment string) error {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.RespondToEvent(ctx, eventID, status, comment)
+}
+
+func (a *Accounts) SearchEvents(ctx context.Context, calendarID, query, startDate, endDate string) ([]CalendarEvent, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.SearchEvents(ctx, calendarID, query, startDate, endDate)
+}
+
+func (a *Accounts) QueryFreeBusy(ctx context.Context, calendarIDs []string, timeMin, timeMax time.Time) ([]BusyCalendar, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.QueryFreeBusy(ctx, calendarIDs, timeMin, timeMax)
+}
+
+func (a *Accounts) CheckSchedule(ctx context.Context, start, end time.Time) error {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.CheckSchedule(ctx, start, end)
+}
+
+func (a *Accounts) ListInstances(ctx context.Context, seriesID, startDate, endDate string) ([]SeriesInstance, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListInstances(ctx, seriesID, startDate, endDate)
+}
+
+func (a *Accounts) ListDelegatedActions(ctx context.Context, since time.Time) ([]DelegatedAction, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.ListDelegatedActions(ctx, since)
+}
+
+func (a *Accounts) RawRequest(ctx context.Context, method string, params RawParams) (json.RawMessage, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.RawRequest(ctx, method, params)
+}
+
+func (a *Accounts) AuthStatus(ctx context.Context) (*AuthStatus, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reporter, ok := svc.(AuthStatusReporter)
+	if !ok {
+		return nil, fmt.Errorf("the client of this account can't describe its authentication")
+	}
+	return reporter.AuthStatus(ctx)
+}
+
+func (a *Accounts) AuthURL(ctx context.Context) (string, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return "", err
+	}
+	reauthorizer, ok := svc.(Reauthorizer)
+	if !ok {
+		return "", errNotOAuth
+	}
+	return reauthorizer.AuthURL(ctx)
+}
+
+func (a *Accounts) Reauthorize(ctx context.Context, code string) error {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return err
+	}
+	reauthorizer, ok := svc.(Reauthorizer)
+	if !ok {
+		return errNotOAuth
+	}
+	return reauthorizer.Reauthorize(ctx, code)
+}
+
+func (a *Accounts) AuthorizeDevice(ctx context.Context) (*DeviceAuth, error) {
+	svc, err := a.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	authorizer, ok := svc.(DeviceAuthorizer)
+	if !ok {
+		return nil, errNotOAuth
+	}
+	return authorizer.AuthorizeDevice(ctx)
+}
+
+// ReloadCredentials reloads the credentials of every account read from a
+// credentials file
+func (a *Accounts) ReloadCredentials() error {
+	var errs []error
+	reloaded := false
+	for _, name := range a.names {
+		reloader, ok := a.services[name].(CredentialsReloader)
+		if !ok {
+			continue
+		}
+		err := reloader.ReloadCredentials()
+		if errors.Is(err, errNotReloadable) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", name, err))
+		}
+		reloaded = true
+	}
+	if !reloaded {
+		return errNotReloadable
+	}
+	return errors.Join(errs...)
+}
+
+// WithDefaults applies a session's calendar and timezone to the default
+// account; the other accounts keep their own
+func (a *Accounts) WithDefaults(calendarID, timezone string) Service {
+	view := &Accounts{names: a.names, services: make(map[string]Service, len(a.services))}
+	for name, svc := range a.services {
+		view.services[name] = svc
+	}
+	view.services[a.names[0]] = a.defaultService().WithDefaults(calendarID, timezone)
+	return view
+}
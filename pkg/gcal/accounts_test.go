@@ -0,0 +1,57 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// accountStub is a Service of which only the methods under test work
+type accountStub struct {
+	Service
+	id      string
+	deleted string
+}
+
+func (s *accountStub) CalendarID() string { return s.id }
+
+func (s *accountStub) DeleteEvent(_ context.Context, eventID string) error {
+	s.deleted = eventID
+	return nil
+}
+
+func TestAccounts(t *testing.T) {
+	work, personal := &accountStub{id: "me@work.example"}, &accountStub{id: "me@home.example"}
+	accounts := NewAccounts()
+	if err := accounts.Add("work", work); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Add("personal", personal); err != nil {
+		t.Fatal(err)
+	}
+	if accounts.CalendarID() != "me@work.example" {
+		t.Errorf("expected the first account to be the default, got %s", accounts.CalendarID())
+	}
+
+	accounts.DeleteEvent(context.Background(), "a")
+	accounts.DeleteEvent(WithAccount(context.Background(), "personal"), "b")
+	if work.deleted != "a" || personal.deleted != "b" {
+		t.Errorf("expected each call to reach its account, got %q and %q", work.deleted, personal.deleted)
+	}
+
+	err := accounts.DeleteEvent(WithAccount(context.Background(), "other"), "c")
+	var invalid *InvalidInputError
+	if !errors.As(err, &invalid) {
+		t.Errorf("expected an unknown account to be invalid input, got %v", err)
+	}
+}
+
+func TestAccounts_Add(t *testing.T) {
+	accounts := NewAccounts()
+	accounts.Add("work", &accountStub{})
+	for _, name := range []string{"work", "my work", ""} {
+		if err := accounts.Add(name, &accountStub{}); err == nil {
+			t.Errorf("expected an error adding %q", name)
+		}
+	}
+}
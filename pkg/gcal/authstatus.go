@@ -0,0 +1,96 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenInfoURL is Google's endpoint describing an access token
+var tokenInfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+// AuthStatus describes who a client authenticates as and with what access
+type AuthStatus struct {
+	// Mode is how the client authenticates, as AuthMode reports it
+	Mode string
+	// Principal is the account calls are made as: the service account, or
+	// the user who authorized the OAuth client
+	Principal string
+	// Scopes are the OAuth scopes the current access token grants
+	Scopes []string
+	// Expiry is when the current access token expires; the client gets a
+	// new one before then
+	Expiry time.Time
+}
+
+// AuthStatus fetches an access token the way calls do and asks Google what
+// it grants. A failure to get a token is returned as is, e.g. an AuthError
+// once an OAuth token has been revoked.
+func (c *CalendarClient) AuthStatus(ctx context.Context) (*AuthStatus, error) {
+	status := &AuthStatus{Mode: c.authMode, Principal: c.serviceAccount}
+	if c.tokens == nil {
+		return status, nil
+	}
+	token, err := c.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+	status.Expiry = token.Expiry
+
+	info, err := fetchTokenInfo(ctx, token.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the access token: %w", err)
+	}
+	status.Scopes = strings.Fields(info.Scope)
+	if info.Email != "" {
+		status.Principal = info.Email
+	}
+	if exp, err := strconv.ParseInt(info.Exp, 10, 64); err == nil && status.Expiry.IsZero() {
+		status.Expiry = time.Unix(exp, 0)
+	}
+	if status.Principal == "" {
+		// The ID of the primary calendar is the email of its owner
+		if cal, err := c.service.Calendars.Get("primary").Context(ctx).Do(); err == nil {
+			status.Principal = cal.Id
+		}
+	}
+	return status, nil
+}
+
+type tokenInfo struct {
+	Scope string `json:"scope"`
+	Email string `json:"email"`
+	Exp   string `json:"exp"`
+}
+
+func fetchTokenInfo(ctx context.Context, accessToken string) (*tokenInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenInfoURL, strings.NewReader(url.Values{"access_token": {accessToken}}.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tokeninfo: %s", resp.Status)
+	}
+	var info tokenInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AuthStatusReporter is implemented by services that can describe their
+// authentication, such as CalendarClient
+type AuthStatusReporter interface {
+	AuthStatus(ctx context.Context) (*AuthStatus, error)
+}
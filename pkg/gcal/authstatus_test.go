@@ -0,0 +1,41 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestCalendarClient_AuthStatus(t *testing.T) {
+	info := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("access_token") != "access" {
+			http.Error(w, "invalid_token", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"scope":"https://www.googleapis.com/auth/calendar openid","email":"jane@example.com","exp":"1774000000"}`)
+	}))
+	defer info.Close()
+	defer func(url string) { tokenInfoURL = url }(tokenInfoURL)
+	tokenInfoURL = info.URL
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	c := &CalendarClient{authMode: "oauth", tokens: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access", Expiry: expiry})}
+	status, err := c.AuthStatus(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Mode != "oauth" || status.Principal != "jane@example.com" || len(status.Scopes) != 2 || !status.Expiry.Equal(expiry) {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	c.tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "revoked"})
+	if _, err := c.AuthStatus(context.Background()); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
@@ -0,0 +1,38 @@
+package gcal
+
+import (
+	"context"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	// Private extended properties linking the copies of a broadcast event,
+	// so that they can be found again to be edited or deleted together
+	BroadcastIDKey        = "broadcastId"
+	BroadcastCalendarsKey = "broadcastCalendars"
+)
+
+// ListLinkedEvents returns the copies of a broadcast event in one calendar
+func (c *CalendarClient) ListLinkedEvents(ctx context.Context, calendarID, broadcastID string) ([]CalendarEvent, error) {
+	result := []CalendarEvent{}
+	err := c.serviceFor(calendarID).Events.List(calendarID).
+		PrivateExtendedProperty(BroadcastIDKey+"="+broadcastID).
+		Pages(ctx, func(page *calendar.Events) error {
+			for _, e := range page.Items {
+				result = append(result, CalendarEvent{
+					ID:         e.Id,
+					CalendarID: calendarID,
+					Summary:    e.Summary,
+					Start:      EventTime(e.Start),
+					End:        EventTime(e.End),
+					HTMLLink:   e.HtmlLink,
+				})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,791 @@
+// Package gcal is a client for the Google Calendar API tailored to the MCP
+// server: CalendarClient lists, creates and updates events on a primary
+// calendar, honoring the schedule constraints and delegated mode set up
+// with LoadEnv.
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+const defaultTimezone = "UTC"
+
+const (
+	// maxListEvents caps how many events a single listing fetches across pages
+	maxListEvents = 2500
+	listPageSize  = 250
+)
+
+const (
+	// Durations outside these bounds are usually typos (e.g. 10:00-01:00
+	// instead of 10:00-11:00) and need force to go through
+	maxEventDuration = 12 * time.Hour
+	minEventDuration = time.Minute
+)
+
+// Service is the calendar access the MCP server needs; CalendarClient
+// implements it against the Google Calendar API
+type Service interface {
+	CalendarID() string
+	Calendars() []string
+	ListEventsForDays(ctx context.Context, days int) ([]CalendarEvent, error)
+	ListEventsRange(ctx context.Context, startDate, endDate string) ([]CalendarEvent, error)
+	ListCalendarEvents(ctx context.Context, calendarID, startDate, endDate string) ([]CalendarEvent, error)
+	SearchEvents(ctx context.Context, calendarID, query, startDate, endDate string) ([]CalendarEvent, error)
+	QueryFreeBusy(ctx context.Context, calendarIDs []string, timeMin, timeMax time.Time) ([]BusyCalendar, error)
+	CheckSchedule(ctx context.Context, start, end time.Time) error
+	ListCalendars(ctx context.Context) ([]CalendarInfo, error)
+	GetEvent(ctx context.Context, eventID string) (*calendar.Event, error)
+	CreateEvent(ctx context.Context, summary, description, date, startTime, endTime string, force bool) (*calendar.Event, error)
+	CreateCalendarEvent(ctx context.Context, calendarID string, draft EventDraft) (*calendar.Event, error)
+	QuickAddEvent(ctx context.Context, calendarID, text string) (*calendar.Event, error)
+	UpdateEvent(ctx context.Context, eventID string, updates EventUpdates) (*calendar.Event, error)
+	UpdateCalendarEvent(ctx context.Context, calendarID, eventID string, updates EventUpdates) (*calendar.Event, error)
+	ListLinkedEvents(ctx context.Context, calendarID, broadcastID string) ([]CalendarEvent, error)
+	DeleteEvent(ctx context.Context, eventID string) error
+	DeleteOccurrences(ctx context.Context, calendarID, eventID string, scope SeriesScope) error
+	CreateOutOfOffice(ctx context.Context, summary string, start, end time.Time, autoDecline bool, message string) (*calendar.Event, error)
+	RespondToEvent(ctx context.Context, eventID, status, comment string) error
+	ListInstances(ctx context.Context, seriesID, startDate, endDate string) ([]SeriesInstance, error)
+	ListDelegatedActions(ctx context.Context, since time.Time) ([]DelegatedAction, error)
+	RawRequest(ctx context.Context, method string, params RawParams) (json.RawMessage, error)
+	WithDefaults(calendarID, timezone string) Service
+}
+
+// CalendarClient is a Service backed by the Google Calendar API, acting on
+// one primary calendar and reading any number of extra ones
+type CalendarClient struct {
+	service    *calendar.Service
+	calendarID string
+	// extraCalendarIDs are further calendars of the user, read by tools
+	// that look across calendars
+	extraCalendarIDs []string
+	timezone         string
+	// constraints, when set, block events at configured times of day
+	constraints *scheduleConstraints
+	// delegate, when set, labels every change as made by an assistant
+	delegate *delegation
+	authMode string
+	// serviceAccount is the email calendars have to be shared with, when
+	// the client acts as a service account
+	serviceAccount string
+	// tokens is the source of the client's access tokens, for AuthStatus
+	tokens oauth2.TokenSource
+	// identities are the clients whose credentials calls on particular
+	// calendars are made with, by calendar ID
+	identities map[string]*CalendarClient
+}
+
+// CalendarEvent is an event as listed by the client
+type CalendarEvent struct {
+	ID         string `json:"id"`
+	CalendarID string `json:"calendarId,omitempty"`
+	Summary    string `json:"summary"`
+	Start      string `json:"start"`
+	End        string `json:"end"`
+	HTMLLink   string `json:"htmlLink,omitempty"`
+	Location   string `json:"location,omitempty"`
+	// Transparency is "transparent" for events that don't block time
+	Transparency string `json:"transparency,omitempty"`
+	// Attendees is the number of people invited, including the organizer
+	Attendees int     `json:"attendees,omitempty"`
+	Guests    []Guest `json:"guests,omitempty"`
+	// OrganizerSelf marks events organized by the calendar owner
+	OrganizerSelf bool `json:"organizerSelf,omitempty"`
+	// RecurringEventID is set on instances of a recurring series
+	RecurringEventID string `json:"recurringEventId,omitempty"`
+	// Updated is when the event was last modified (RFC 3339)
+	Updated string `json:"updated,omitempty"`
+}
+
+// Guest is an attendee of an event
+type Guest struct {
+	Email string `json:"email"`
+	// ResponseStatus is needsAction, declined, tentative or accepted
+	ResponseStatus string `json:"responseStatus,omitempty"`
+	// Self marks the calendar owner
+	Self bool `json:"self,omitempty"`
+}
+
+// NewCalendarClient returns a client acting on calendarID with the
+// credentials in credentialsFile: a service account key, or an external
+// account configuration for workload identity federation. Times are read
+// and written in timezone, UTC when empty.
+func NewCalendarClient(credentialsFile, calendarID, timezone string) (*CalendarClient, error) {
+	credentials, creds, err := credentialsFileOption(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCalendarClient(context.Background(), calendarID, timezone,
+		credentials,
+		option.WithScopes(calendar.CalendarScope),
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.authMode = string(creds.credType)
+	c.serviceAccount = creds.serviceAccount
+	c.tokens = creds.tokens
+	return c, nil
+}
+
+func newCalendarClient(ctx context.Context, calendarID, timezone string, opts ...option.ClientOption) (*CalendarClient, error) {
+	srv, err := calendar.NewService(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+
+	return &CalendarClient{
+		service:    srv,
+		calendarID: calendarID,
+		timezone:   timezone,
+		authMode:   "service_account",
+	}, nil
+}
+
+// LoadEnv applies the optional client settings of the environment: the
+// extra calendars of CALENDAR_EXTRA_IDS, the schedule constraints and
+// delegated mode
+func (c *CalendarClient) LoadEnv() error {
+	if v := os.Getenv("CALENDAR_EXTRA_IDS"); v != "" {
+		c.AddCalendars(strings.Split(v, ",")...)
+	}
+
+	constraints, err := scheduleConstraintsFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid schedule constraints: %w", err)
+	}
+	c.constraints = constraints
+
+	delegate, err := delegationFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid delegated mode settings: %w", err)
+	}
+	c.delegate = delegate
+	return nil
+}
+
+// AddCalendars adds further calendars of the user, read by tools that look
+// across calendars. Blank IDs and the primary calendar are skipped.
+func (c *CalendarClient) AddCalendars(ids ...string) {
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" && id != c.calendarID {
+			c.extraCalendarIDs = append(c.extraCalendarIDs, id)
+		}
+	}
+}
+
+// CalendarID returns the ID of the calendar the client operates on
+func (c *CalendarClient) CalendarID() string {
+	return c.calendarID
+}
+
+// WithDefaults returns a view of the client that acts on calendarID and
+// reads and writes times in timezone, where given, instead of the
+// configured ones. The view shares the API connection and its quota.
+func (c *CalendarClient) WithDefaults(calendarID, timezone string) Service {
+	view := *c
+	if calendarID != "" && calendarID != c.calendarID {
+		view.calendarID = calendarID
+		// The configured calendar is still one the user looks across
+		view.extraCalendarIDs = []string{c.calendarID}
+		for _, id := range c.extraCalendarIDs {
+			if id != calendarID {
+				view.extraCalendarIDs = append(view.extraCalendarIDs, id)
+			}
+		}
+	}
+	if timezone != "" {
+		view.timezone = timezone
+	}
+	return &view
+}
+
+// Calendars returns the primary calendar followed by the extra calendars
+func (c *CalendarClient) Calendars() []string {
+	return append([]string{c.calendarID}, c.extraCalendarIDs...)
+}
+
+// AuthMode describes how the client authenticates to the Calendar API
+func (c *CalendarClient) AuthMode() string {
+	return c.authMode
+}
+
+// CheckTimezone reports whether the configured timezone is known; unknown
+// timezones silently fall back to UTC elsewhere
+func (c *CalendarClient) CheckTimezone() error {
+	if _, err := time.LoadLocation(c.timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q, falling back to UTC: %w", c.timezone, err)
+	}
+	return nil
+}
+
+// CheckAccess verifies that the configured calendar is reachable
+func (c *CalendarClient) CheckAccess(ctx context.Context) error {
+	_, err := c.serviceFor(c.calendarID).Calendars.Get(c.calendarID).Context(ctx).Do()
+	return c.calendarError(c.calendarID, err)
+}
+
+// ListEventsForDays returns events for the next N days
+func (c *CalendarClient) ListEventsForDays(ctx context.Context, days int) ([]CalendarEvent, error) {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	now := time.Now().In(loc)
+	timeMin := now.Format(time.RFC3339)
+	timeMax := now.AddDate(0, 0, days).Format(time.RFC3339)
+
+	return c.listEvents(ctx, c.calendarID, "", timeMin, timeMax, maxListEvents)
+}
+
+// ListEventsRange returns events between two dates (YYYY-MM-DD format)
+func (c *CalendarClient) ListEventsRange(ctx context.Context, startDate, endDate string) ([]CalendarEvent, error) {
+	return c.ListCalendarEvents(ctx, c.calendarID, startDate, endDate)
+}
+
+// ListCalendarEvents returns events of any calendar the credentials can read
+// between two dates (YYYY-MM-DD format)
+func (c *CalendarClient) ListCalendarEvents(ctx context.Context, calendarID, startDate, endDate string) ([]CalendarEvent, error) {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	start, err := time.ParseInLocation("2006-01-02", startDate, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+
+	end, err := time.ParseInLocation("2006-01-02", endDate, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+
+	// End date should be inclusive, so add one day
+	end = end.AddDate(0, 0, 1)
+
+	timeMin := start.Format(time.RFC3339)
+	timeMax := end.Format(time.RFC3339)
+
+	return c.listEvents(ctx, calendarID, "", timeMin, timeMax, maxListEvents)
+}
+
+// listEvents returns the events of calendarID between timeMin and timeMax,
+// either of which may be empty for no bound, that match query when given
+func (c *CalendarClient) listEvents(ctx context.Context, calendarID, query, timeMin, timeMax string, maxResults int) ([]CalendarEvent, error) {
+	pageSize := min(maxResults, listPageSize)
+
+	var result []CalendarEvent
+	pageToken := ""
+	for {
+		call := c.serviceFor(calendarID).Events.List(calendarID).
+			SingleEvents(true).
+			OrderBy("startTime").
+			MaxResults(int64(pageSize))
+		if timeMin != "" {
+			call = call.TimeMin(timeMin)
+		}
+		if timeMax != "" {
+			call = call.TimeMax(timeMax)
+		}
+		if query != "" {
+			call = call.Q(query)
+		}
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+
+		events, err := call.Context(ctx).Do()
+		if err != nil {
+			return nil, c.calendarError(calendarID, err)
+		}
+
+		pageStart := len(result)
+		for _, e := range events.Items {
+			start := e.Start.DateTime
+			if start == "" {
+				start = e.Start.Date
+			}
+			end := e.End.DateTime
+			if end == "" {
+				end = e.End.Date
+			}
+			result = append(result, CalendarEvent{
+				ID:               e.Id,
+				CalendarID:       calendarID,
+				Summary:          e.Summary,
+				Start:            start,
+				End:              end,
+				HTMLLink:         e.HtmlLink,
+				Location:         e.Location,
+				Transparency:     e.Transparency,
+				Attendees:        len(e.Attendees),
+				Guests:           guests(e.Attendees),
+				OrganizerSelf:    e.Organizer != nil && e.Organizer.Self,
+				RecurringEventID: e.RecurringEventId,
+				Updated:          e.Updated,
+			})
+		}
+
+		ReportPage(ctx, len(result), result[pageStart:])
+
+		if events.NextPageToken == "" || len(result) >= maxResults {
+			break
+		}
+		pageToken = events.NextPageToken
+	}
+
+	if len(result) > maxResults {
+		result = result[:maxResults]
+	}
+	if result == nil {
+		result = []CalendarEvent{}
+	}
+	return result, nil
+}
+
+func guests(attendees []*calendar.EventAttendee) []Guest {
+	if len(attendees) == 0 {
+		return nil
+	}
+	result := make([]Guest, 0, len(attendees))
+	for _, a := range attendees {
+		result = append(result, Guest{Email: a.Email, ResponseStatus: a.ResponseStatus, Self: a.Self})
+	}
+	return result
+}
+
+// GetEvent returns a single event with all its details
+func (c *CalendarClient) GetEvent(ctx context.Context, eventID string) (*calendar.Event, error) {
+	return c.serviceFor(c.calendarID).Events.Get(c.calendarID, eventID).Context(ctx).Do()
+}
+
+// CreateEvent creates a new calendar event
+// date: YYYY-MM-DD, startTime/endTime: HH:MM
+func (c *CalendarClient) CreateEvent(ctx context.Context, summary, description, date, startTime, endTime string, force bool) (*calendar.Event, error) {
+	return c.CreateCalendarEvent(ctx, c.calendarID, EventDraft{
+		Summary:     summary,
+		Description: description,
+		Date:        date,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Force:       force,
+	})
+}
+
+// EventDraft describes an event to create
+type EventDraft struct {
+	Summary     string
+	Description string
+	// Date is YYYY-MM-DD, StartTime and EndTime are HH:MM
+	Date      string
+	StartTime string
+	EndTime   string
+	// Force allows durations outside the usual sanity limits and times
+	// blocked by the schedule constraints
+	Force bool
+	// Tags are stored as private extended properties of the event
+	Tags map[string]string
+}
+
+// CreateCalendarEvent creates an event in any calendar the credentials can
+// write to
+func (c *CalendarClient) CreateCalendarEvent(ctx context.Context, calendarID string, draft EventDraft) (*calendar.Event, error) {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	startStr := draft.Date + "T" + draft.StartTime + ":00"
+	endStr := draft.Date + "T" + draft.EndTime + ":00"
+
+	start, err := time.ParseInLocation("2006-01-02T15:04:05", startStr, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+
+	end, err := time.ParseInLocation("2006-01-02T15:04:05", endStr, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+
+	if err := validateEventTimes(start, end, draft.Force); err != nil {
+		return nil, err
+	}
+	if !draft.Force {
+		if err := c.constraints.check(start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	event := &calendar.Event{
+		Summary:     draft.Summary,
+		Description: draft.Description,
+		Start: &calendar.EventDateTime{
+			DateTime: start.Format(time.RFC3339),
+			TimeZone: c.timezone,
+		},
+		End: &calendar.EventDateTime{
+			DateTime: end.Format(time.RFC3339),
+			TimeZone: c.timezone,
+		},
+	}
+	if len(draft.Tags) > 0 {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{Private: maps.Clone(draft.Tags)}
+	}
+	c.delegate.labelEvent(ctx, event, actionCreated, c.calendarID, time.Now())
+
+	created, err := c.serviceFor(calendarID).Events.Insert(calendarID, event).Context(ctx).Do()
+	if err != nil {
+		return nil, c.calendarError(calendarID, err)
+	}
+	ReportCreated(ctx)
+	return created, nil
+}
+
+// EventUpdates contains optional fields to update
+type EventUpdates struct {
+	Summary     *string
+	Description *string
+	Date        *string
+	StartTime   *string
+	EndTime     *string
+	// Force allows durations outside the usual sanity limits and times
+	// blocked by the schedule constraints
+	Force bool
+	// Scope picks the occurrences changed when the event repeats
+	Scope SeriesScope
+}
+
+// UpdateEvent updates an existing calendar event
+func (c *CalendarClient) UpdateEvent(ctx context.Context, eventID string, updates EventUpdates) (*calendar.Event, error) {
+	return c.UpdateCalendarEvent(ctx, c.calendarID, eventID, updates)
+}
+
+// UpdateCalendarEvent updates an event in any calendar the credentials can
+// write to
+func (c *CalendarClient) UpdateCalendarEvent(ctx context.Context, calendarID, eventID string, updates EventUpdates) (*calendar.Event, error) {
+	// First, get the existing event
+	existing, err := c.serviceFor(calendarID).Events.Get(calendarID, eventID).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	if updates.Scope.ApplyTo != "" {
+		return c.updateOccurrences(ctx, calendarID, existing, updates)
+	}
+	return c.updateStored(ctx, calendarID, existing, updates)
+}
+
+// updateStored applies updates to an event as fetched and saves it
+func (c *CalendarClient) updateStored(ctx context.Context, calendarID string, existing *calendar.Event, updates EventUpdates) (*calendar.Event, error) {
+	if err := c.applyUpdates(existing, updates); err != nil {
+		return nil, err
+	}
+	c.delegate.labelEvent(ctx, existing, actionUpdated, c.calendarID, time.Now())
+
+	return c.serviceFor(calendarID).Events.Update(calendarID, existing.Id, existing).Context(ctx).Do()
+}
+
+// applyUpdates changes an event as updates ask, checking new times against
+// the schedule constraints
+func (c *CalendarClient) applyUpdates(existing *calendar.Event, updates EventUpdates) error {
+	if updates.Summary != nil {
+		existing.Summary = *updates.Summary
+	}
+	if updates.Description != nil {
+		existing.Description = *updates.Description
+	}
+
+	// Handle date/time updates
+	if updates.Date != nil || updates.StartTime != nil || updates.EndTime != nil {
+		loc, err := time.LoadLocation(c.timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+
+		if err := applyTimeUpdates(existing, updates, loc, c.timezone); err != nil {
+			return err
+		}
+		if !updates.Force {
+			if err := c.checkConstraints(existing, loc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyTimeUpdates rewrites the start and end of an existing event.
+// New dates and times are interpreted in loc, whatever timezone the event
+// was originally created in.
+func applyTimeUpdates(existing *calendar.Event, updates EventUpdates, loc *time.Location, timezone string) error {
+	if existing.Start != nil && existing.Start.DateTime == "" && existing.Start.Date != "" {
+		return applyAllDayUpdates(existing, updates, loc, timezone)
+	}
+
+	// Parse existing start/end times
+	var currentStart, currentEnd time.Time
+	hasStart := existing.Start != nil && existing.Start.DateTime != ""
+	hasEnd := existing.End != nil && existing.End.DateTime != ""
+
+	if hasStart {
+		t, err := time.Parse(time.RFC3339, existing.Start.DateTime)
+		if err != nil {
+			return fmt.Errorf("existing event has malformed start time %q: %w", existing.Start.DateTime, err)
+		}
+		currentStart = t.In(loc)
+	}
+	if hasEnd {
+		t, err := time.Parse(time.RFC3339, existing.End.DateTime)
+		if err != nil {
+			return fmt.Errorf("existing event has malformed end time %q: %w", existing.End.DateTime, err)
+		}
+		currentEnd = t.In(loc)
+	}
+
+	// Apply updates with fallback to current values
+	var date, startTime string
+	switch {
+	case updates.Date != nil:
+		date = *updates.Date
+	case hasStart:
+		date = currentStart.Format("2006-01-02")
+	default:
+		return invalidInputf("existing event has no start time; date and start_time are required")
+	}
+	switch {
+	case updates.StartTime != nil:
+		startTime = *updates.StartTime
+	case hasStart:
+		startTime = currentStart.Format("15:04")
+	default:
+		return invalidInputf("existing event has no start time; start_time is required")
+	}
+
+	start, err := time.ParseInLocation("2006-01-02T15:04:05", date+"T"+startTime+":00", loc)
+	if err != nil {
+		return invalidInput(err)
+	}
+
+	var end time.Time
+	switch {
+	case updates.EndTime != nil:
+		end, err = time.ParseInLocation("2006-01-02T15:04:05", date+"T"+*updates.EndTime+":00", loc)
+		if err != nil {
+			return invalidInput(err)
+		}
+	case hasStart && hasEnd && updates.Date != nil:
+		// Moving to another date keeps the original duration, which also
+		// covers events that cross midnight in the configured timezone
+		end = start.Add(currentEnd.Sub(currentStart))
+	case hasEnd:
+		end = currentEnd
+	default:
+		return invalidInputf("existing event has no end time; end_time is required")
+	}
+
+	if err := validateEventTimes(start, end, updates.Force); err != nil {
+		return err
+	}
+
+	existing.Start = &calendar.EventDateTime{
+		DateTime: start.Format(time.RFC3339),
+		TimeZone: timezone,
+	}
+	existing.End = &calendar.EventDateTime{
+		DateTime: end.Format(time.RFC3339),
+		TimeZone: timezone,
+	}
+	return nil
+}
+
+// applyAllDayUpdates moves an all-day event to a new date, or turns it into
+// a timed event when both start and end times are given.
+func applyAllDayUpdates(existing *calendar.Event, updates EventUpdates, loc *time.Location, timezone string) error {
+	currentStart, err := time.Parse("2006-01-02", existing.Start.Date)
+	if err != nil {
+		return fmt.Errorf("existing event has malformed start date %q: %w", existing.Start.Date, err)
+	}
+
+	// The end date of an all-day event is exclusive; a missing end means a
+	// single day
+	days := 1
+	if existing.End != nil && existing.End.Date != "" {
+		currentEnd, err := time.Parse("2006-01-02", existing.End.Date)
+		if err != nil {
+			return fmt.Errorf("existing event has malformed end date %q: %w", existing.End.Date, err)
+		}
+		if n := int(currentEnd.Sub(currentStart).Hours() / 24); n > 0 {
+			days = n
+		}
+	}
+
+	date := existing.Start.Date
+	if updates.Date != nil {
+		date = *updates.Date
+	}
+
+	if updates.StartTime == nil && updates.EndTime == nil {
+		start, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return invalidInput(err)
+		}
+		existing.Start = &calendar.EventDateTime{Date: start.Format("2006-01-02")}
+		existing.End = &calendar.EventDateTime{Date: start.AddDate(0, 0, days).Format("2006-01-02")}
+		return nil
+	}
+
+	if updates.StartTime == nil || updates.EndTime == nil {
+		return invalidInputf("all-day event needs both start_time and end_time to become a timed event")
+	}
+
+	start, err := time.ParseInLocation("2006-01-02T15:04:05", date+"T"+*updates.StartTime+":00", loc)
+	if err != nil {
+		return invalidInput(err)
+	}
+	end, err := time.ParseInLocation("2006-01-02T15:04:05", date+"T"+*updates.EndTime+":00", loc)
+	if err != nil {
+		return invalidInput(err)
+	}
+
+	if err := validateEventTimes(start, end, updates.Force); err != nil {
+		return err
+	}
+
+	existing.Start = &calendar.EventDateTime{
+		DateTime: start.Format(time.RFC3339),
+		TimeZone: timezone,
+	}
+	existing.End = &calendar.EventDateTime{
+		DateTime: end.Format(time.RFC3339),
+		TimeZone: timezone,
+	}
+	return nil
+}
+
+// checkConstraints applies the schedule constraints to a timed event;
+// all-day events are not restricted
+func (c *CalendarClient) checkConstraints(e *calendar.Event, loc *time.Location) error {
+	if c.constraints == nil || e.Start == nil || e.End == nil || e.Start.DateTime == "" || e.End.DateTime == "" {
+		return nil
+	}
+	start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse(time.RFC3339, e.End.DateTime)
+	if err != nil {
+		return err
+	}
+	return c.constraints.check(start.In(loc), end.In(loc))
+}
+
+// CheckSchedule returns the error an event from start to end would be
+// refused with for the schedule constraints, nil when they allow it
+func (c *CalendarClient) CheckSchedule(_ context.Context, start, end time.Time) error {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return c.constraints.check(start.In(loc), end.In(loc))
+}
+
+// DeleteEvent deletes a calendar event. In delegated mode the event is
+// tagged first, so that delegated_actions still lists it once deleted.
+func (c *CalendarClient) DeleteEvent(ctx context.Context, eventID string) error {
+	return c.deleteStored(ctx, c.calendarID, eventID)
+}
+
+// deleteStored deletes an event of any calendar the credentials can write
+// to
+func (c *CalendarClient) deleteStored(ctx context.Context, calendarID, eventID string) error {
+	if c.delegate != nil {
+		patch := &calendar.Event{}
+		c.delegate.tag(ctx, patch, actionDeleted, time.Now())
+		if _, err := c.serviceFor(calendarID).Events.Patch(calendarID, eventID, patch).Context(ctx).Do(); err != nil {
+			return err
+		}
+	}
+	return c.serviceFor(calendarID).Events.Delete(calendarID, eventID).Context(ctx).Do()
+}
+
+// validateEventTimes rejects events that end before they start, and events
+// with implausible durations unless force is set
+func validateEventTimes(start, end time.Time, force bool) error {
+	if !end.After(start) {
+		return invalidInputf("end time %s must be after start time %s", end.Format("15:04"), start.Format("15:04"))
+	}
+
+	if force {
+		return nil
+	}
+
+	duration := end.Sub(start)
+	if duration > maxEventDuration {
+		return invalidInputf("event duration %s exceeds %s; pass force=true if this is intended", FormatDuration(duration), FormatDuration(maxEventDuration))
+	}
+	if duration < minEventDuration {
+		return invalidInputf("event duration %s is shorter than %s; pass force=true if this is intended", FormatDuration(duration), FormatDuration(minEventDuration))
+	}
+
+	return nil
+}
+
+// EventDuration returns the duration of a timed event
+func EventDuration(e *calendar.Event) (time.Duration, bool) {
+	if e.Start == nil || e.End == nil || e.Start.DateTime == "" || e.End.DateTime == "" {
+		return 0, false
+	}
+
+	start, err := time.Parse(time.RFC3339, e.Start.DateTime)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, e.End.DateTime)
+	if err != nil {
+		return 0, false
+	}
+
+	return end.Sub(start), true
+}
+
+// FormatDuration renders a duration as e.g. "1h30m" or "45m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
+// EventTime returns the timestamp of a timed event or the date of an
+// all-day event
+func EventTime(t *calendar.EventDateTime) string {
+	if t.DateTime != "" {
+		return t.DateTime
+	}
+	return t.Date
+}
@@ -0,0 +1,249 @@
+package gcal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+func TestValidateEventTimes(t *testing.T) {
+	base := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		end     time.Time
+		force   bool
+		wantErr bool
+	}{
+		{"normal", base.Add(time.Hour), false, false},
+		{"end before start", base.Add(-time.Hour), false, true},
+		{"end equals start", base, false, true},
+		{"end before start forced", base.Add(-time.Hour), true, true},
+		{"too long", base.Add(13 * time.Hour), false, true},
+		{"too long forced", base.Add(13 * time.Hour), true, false},
+		{"too short", base.Add(30 * time.Second), false, true},
+		{"too short forced", base.Add(30 * time.Second), true, false},
+		{"exactly max", base.Add(maxEventDuration), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEventTimes(base, tt.end, tt.force)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateEventTimes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventDuration(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "2026-03-15T10:00:00+04:00"},
+		End:   &calendar.EventDateTime{DateTime: "2026-03-15T11:30:00+04:00"},
+	}
+
+	d, ok := EventDuration(event)
+	if !ok {
+		t.Fatal("expected duration for timed event")
+	}
+	if d != 90*time.Minute {
+		t.Errorf("expected 90m, got %s", d)
+	}
+
+	allDay := &calendar.Event{
+		Start: &calendar.EventDateTime{Date: "2026-03-15"},
+		End:   &calendar.EventDateTime{Date: "2026-03-16"},
+	}
+	if _, ok := EventDuration(allDay); ok {
+		t.Error("expected no duration for all-day event")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		45 * time.Minute: "45m",
+		2 * time.Hour:    "2h",
+		90 * time.Minute: "1h30m",
+	}
+
+	for d, want := range tests {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestApplyTimeUpdates_TimedEventNewDate(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "2026-03-15T10:00:00Z"},
+		End:   &calendar.EventDateTime{DateTime: "2026-03-15T11:00:00Z"},
+	}
+
+	err := applyTimeUpdates(event, EventUpdates{Date: strPtr("2026-03-20")}, time.UTC, "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Start.DateTime != "2026-03-20T10:00:00Z" || event.End.DateTime != "2026-03-20T11:00:00Z" {
+		t.Errorf("unexpected times: %s - %s", event.Start.DateTime, event.End.DateTime)
+	}
+}
+
+func TestApplyTimeUpdates_AllDayDateOnly(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{Date: "2026-03-15"},
+		End:   &calendar.EventDateTime{Date: "2026-03-17"},
+	}
+
+	err := applyTimeUpdates(event, EventUpdates{Date: strPtr("2026-04-01")}, time.UTC, "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Start.Date != "2026-04-01" || event.End.Date != "2026-04-03" {
+		t.Errorf("expected 2-day span to be preserved, got %s - %s", event.Start.Date, event.End.Date)
+	}
+	if event.Start.DateTime != "" {
+		t.Error("all-day event should stay all-day")
+	}
+}
+
+func TestApplyTimeUpdates_AllDayToTimed(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{Date: "2026-03-15"},
+		End:   &calendar.EventDateTime{Date: "2026-03-16"},
+	}
+
+	err := applyTimeUpdates(event, EventUpdates{StartTime: strPtr("09:00")}, time.UTC, "UTC")
+	if err == nil {
+		t.Error("expected error when only start_time is given for an all-day event")
+	}
+
+	err = applyTimeUpdates(event, EventUpdates{StartTime: strPtr("09:00"), EndTime: strPtr("10:00")}, time.UTC, "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Start.DateTime != "2026-03-15T09:00:00Z" || event.End.DateTime != "2026-03-15T10:00:00Z" {
+		t.Errorf("unexpected times: %s - %s", event.Start.DateTime, event.End.DateTime)
+	}
+}
+
+func TestApplyTimeUpdates_MissingEnd(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "2026-03-15T10:00:00Z"},
+	}
+
+	err := applyTimeUpdates(event, EventUpdates{StartTime: strPtr("11:00")}, time.UTC, "UTC")
+	if err == nil {
+		t.Error("expected error when the event has no end and end_time is not given")
+	}
+
+	err = applyTimeUpdates(event, EventUpdates{StartTime: strPtr("11:00"), EndTime: strPtr("12:00")}, time.UTC, "UTC")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.End.DateTime != "2026-03-15T12:00:00Z" {
+		t.Errorf("unexpected end: %s", event.End.DateTime)
+	}
+}
+
+func TestApplyTimeUpdates_MalformedStart(t *testing.T) {
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "not-a-time"},
+		End:   &calendar.EventDateTime{DateTime: "2026-03-15T11:00:00Z"},
+	}
+
+	if err := applyTimeUpdates(event, EventUpdates{Date: strPtr("2026-03-20")}, time.UTC, "UTC"); err == nil {
+		t.Error("expected error for malformed start time")
+	}
+}
+
+func TestApplyTimeUpdates_CrossTimezoneOriginal(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	// 23:00-01:00 in Tokyo, created in UTC
+	event := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: "2026-03-15T14:00:00Z", TimeZone: "UTC"},
+		End:   &calendar.EventDateTime{DateTime: "2026-03-15T16:00:00Z", TimeZone: "UTC"},
+	}
+
+	err = applyTimeUpdates(event, EventUpdates{Date: strPtr("2026-03-20")}, loc, "Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if event.Start.DateTime != "2026-03-20T23:00:00+09:00" {
+		t.Errorf("unexpected start: %s", event.Start.DateTime)
+	}
+	if event.End.DateTime != "2026-03-21T01:00:00+09:00" {
+		t.Errorf("expected end to cross midnight, got %s", event.End.DateTime)
+	}
+	if event.Start.TimeZone != "Asia/Tokyo" {
+		t.Errorf("expected timezone Asia/Tokyo, got %s", event.Start.TimeZone)
+	}
+}
+
+func TestCalendarClient_WithDefaults(t *testing.T) {
+	c := &CalendarClient{calendarID: "me@example.com", extraCalendarIDs: []string{"team@example.com", "oncall@example.com"}, timezone: "UTC"}
+
+	view := c.WithDefaults("team@example.com", "Europe/Berlin").(*CalendarClient)
+	if view.CalendarID() != "team@example.com" || view.timezone != "Europe/Berlin" {
+		t.Errorf("unexpected view %+v", view)
+	}
+	if got := strings.Join(view.Calendars(), ","); got != "team@example.com,me@example.com,oncall@example.com" {
+		t.Errorf("unexpected calendars %s", got)
+	}
+	if c.calendarID != "me@example.com" || c.timezone != "UTC" || len(c.extraCalendarIDs) != 2 {
+		t.Errorf("the shared client must not change, got %+v", c)
+	}
+
+	if same := c.WithDefaults("", ""); strings.Join(same.Calendars(), ",") != strings.Join(c.Calendars(), ",") {
+		t.Errorf("expected empty defaults to keep the configuration, got %v", same.Calendars())
+	}
+}
+
+func TestCalendarClient_AddCalendars(t *testing.T) {
+	c := &CalendarClient{calendarID: "me@example.com"}
+	c.AddCalendars(" team@example.com", "", "me@example.com", "oncall@example.com")
+	if got := strings.Join(c.Calendars(), ","); got != "me@example.com,team@example.com,oncall@example.com" {
+		t.Errorf("unexpected calendars %s", got)
+	}
+}
+
+func TestCalendarClient_CalendarError(t *testing.T) {
+	c := &CalendarClient{calendarID: "team@example.com", serviceAccount: "calendar@proj.iam.gserviceaccount.com"}
+
+	var notFound *CalendarNotFoundError
+	err := c.calendarError("team@example.com", &googleapi.Error{Code: 404, Message: "Not Found"})
+	if !errors.As(err, &notFound) || notFound.CalendarID != "team@example.com" {
+		t.Fatalf("expected a CalendarNotFoundError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "share it with the service account calendar@proj.iam.gserviceaccount.com") {
+		t.Errorf("expected sharing guidance, got %q", err)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		t.Error("expected the API error to stay reachable")
+	}
+
+	c.serviceAccount = ""
+	if err := c.calendarError("team@example.com", &googleapi.Error{Code: 404}); !strings.Contains(err.Error(), "isn't shared with your account") {
+		t.Errorf("unexpected message without a service account: %q", err)
+	}
+
+	forbidden := &googleapi.Error{Code: 403}
+	if err := c.calendarError("team@example.com", forbidden); err != forbidden {
+		t.Errorf("expected other errors unchanged, got %v", err)
+	}
+	if err := c.calendarError("team@example.com", nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package gcal
+
+import (
+	"context"
+	"sort"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// CalendarInfo is an entry of the user's calendar list
+type CalendarInfo struct {
+	ID string `json:"id"`
+	// Summary is the calendar's name, e.g. a teammate's full name
+	Summary string `json:"summary"`
+	// AccessRole is what the user may do with the calendar: "owner",
+	// "writer", "reader" or "freeBusyReader"
+	AccessRole string `json:"accessRole,omitempty"`
+}
+
+// ListCalendars returns every calendar in the user's calendar list, sorted
+// by ID
+func (c *CalendarClient) ListCalendars(ctx context.Context) ([]CalendarInfo, error) {
+	var result []CalendarInfo
+	err := c.service.CalendarList.List().Pages(ctx, func(page *calendar.CalendarList) error {
+		for _, entry := range page.Items {
+			summary := entry.SummaryOverride
+			if summary == "" {
+				summary = entry.Summary
+			}
+			result = append(result, CalendarInfo{ID: entry.Id, Summary: summary, AccessRole: entry.AccessRole})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
@@ -0,0 +1,1138 @@
+// Package calendartest provides an in-memory gcal.Service for tests of code
+// built on the calendar server, so that they can run against realistic data
+// without Google. A Calendar is set up declaratively from a Fixture, in Go
+// or as a JSON file, and behaves like the Calendar API where tools depend
+// on it: listings are filtered by time and ordered by start, recurring
+// series are expanded into instances with their exceptions, overlapping
+// events are kept as they are, and unknown events and calendars fail with
+// the errors the API returns.
+package calendartest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// Fixture is the state a Calendar starts from
+type Fixture struct {
+	// CalendarID is the primary calendar, test@example.com when empty
+	CalendarID string `json:"calendarId"`
+	// Timezone is the IANA timezone dates are read in, UTC when empty
+	Timezone string `json:"timezone"`
+	// Now is the current time of the calendar (RFC 3339), the real clock
+	// when empty
+	Now string `json:"now"`
+	// Calendars are the entries of the calendar list; the primary calendar
+	// is added as owner unless listed
+	Calendars []gcal.CalendarInfo `json:"calendars"`
+	Events    []Event             `json:"events"`
+}
+
+// Event is an event of a Fixture
+type Event struct {
+	ID string `json:"id"`
+	// Calendar is the calendar of the event, the primary one when empty
+	Calendar    string `json:"calendar"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Location    string `json:"location"`
+	// Start and End are RFC 3339 times, or YYYY-MM-DD dates for all-day
+	// events, in which case End is exclusive
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Recurrence holds RRULE and EXDATE lines as in the Calendar API, e.g.
+	// "RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10"
+	Recurrence []string `json:"recurrence"`
+	// RecurringEventID and OriginalStart make the event an exception of a
+	// series: the instance scheduled at OriginalStart, moved or changed
+	RecurringEventID string `json:"recurringEventId"`
+	OriginalStart    string `json:"originalStart"`
+	// Status is "cancelled" for instances removed from their series
+	Status        string       `json:"status"`
+	Guests        []gcal.Guest `json:"guests"`
+	OrganizerSelf bool         `json:"organizerSelf"`
+	// Transparency is "transparent" for events that don't block time
+	Transparency string `json:"transparency"`
+	// Tags are the private extended properties of the event
+	Tags map[string]string `json:"tags"`
+}
+
+// Calendar is an in-memory gcal.Service. It is safe for concurrent use;
+// views made with WithDefaults share its events.
+type Calendar struct {
+	*store
+	calendarID string
+	loc        *time.Location
+}
+
+var _ gcal.Service = (*Calendar)(nil)
+
+// store is the state views of a Calendar share
+type store struct {
+	mu        sync.Mutex
+	primary   string
+	calendars []gcal.CalendarInfo
+	// events are the events, series and exceptions of each calendar in the
+	// order they were added
+	events   map[string][]*calendar.Event
+	nextID   int
+	now      func() time.Time
+	failures map[string]error
+}
+
+// New returns a Calendar holding the events of f
+func New(f Fixture) (*Calendar, error) {
+	if f.CalendarID == "" {
+		f.CalendarID = "test@example.com"
+	}
+	loc := time.UTC
+	if f.Timezone != "" {
+		l, err := time.LoadLocation(f.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", f.Timezone)
+		}
+		loc = l
+	}
+	s := &store{
+		primary:  f.CalendarID,
+		events:   make(map[string][]*calendar.Event),
+		now:      time.Now,
+		failures: make(map[string]error),
+	}
+	if f.Now != "" {
+		now, err := time.Parse(time.RFC3339, f.Now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid now %q", f.Now)
+		}
+		s.now = func() time.Time { return now }
+	}
+
+	s.calendars = append(s.calendars, f.Calendars...)
+	if !slices.ContainsFunc(s.calendars, func(c gcal.CalendarInfo) bool { return c.ID == f.CalendarID }) {
+		s.calendars = append(s.calendars, gcal.CalendarInfo{ID: f.CalendarID, Summary: f.CalendarID, AccessRole: "owner"})
+	}
+	sort.Slice(s.calendars, func(i, j int) bool { return s.calendars[i].ID < s.calendars[j].ID })
+
+	c := &Calendar{store: s, calendarID: f.CalendarID, loc: loc}
+	for _, e := range f.Events {
+		if err := c.add(e); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Load returns a Calendar holding the Fixture in the JSON file at path
+func Load(path string) (*Calendar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	return New(f)
+}
+
+// add stores a fixture event
+func (c *Calendar) add(e Event) error {
+	calendarID := e.Calendar
+	if calendarID == "" {
+		calendarID = c.calendarID
+	}
+	if e.ID == "" {
+		e.ID = c.newID()
+	}
+	start, err := eventDateTime(e.Start)
+	if err != nil {
+		return fmt.Errorf("event %s: invalid start %q", e.ID, e.Start)
+	}
+	end, err := eventDateTime(e.End)
+	if err != nil {
+		return fmt.Errorf("event %s: invalid end %q", e.ID, e.End)
+	}
+	event := &calendar.Event{
+		Id:               e.ID,
+		Summary:          e.Summary,
+		Description:      e.Description,
+		Location:         e.Location,
+		Start:            start,
+		End:              end,
+		Recurrence:       e.Recurrence,
+		RecurringEventId: e.RecurringEventID,
+		Status:           firstNonEmpty(e.Status, "confirmed"),
+		Transparency:     e.Transparency,
+		Organizer:        &calendar.EventOrganizer{Self: e.OrganizerSelf},
+		HtmlLink:         eventLink(e.ID, calendarID),
+	}
+	if e.RecurringEventID != "" {
+		original, err := eventDateTime(e.OriginalStart)
+		if err != nil {
+			return fmt.Errorf("event %s: invalid originalStart %q", e.ID, e.OriginalStart)
+		}
+		event.OriginalStartTime = original
+	}
+	if len(e.Recurrence) > 0 {
+		if _, err := parseRecurrence(e.Recurrence, c.loc); err != nil {
+			return fmt.Errorf("event %s: %w", e.ID, err)
+		}
+	}
+	for _, g := range e.Guests {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: g.Email, ResponseStatus: g.ResponseStatus, Self: g.Self})
+	}
+	if len(e.Tags) > 0 {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{Private: maps.Clone(e.Tags)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	event.Updated = c.now().UTC().Format(time.RFC3339)
+	c.events[calendarID] = append(c.events[calendarID], event)
+	return nil
+}
+
+// Add stores more events, as if they were part of the fixture
+func (c *Calendar) Add(events ...Event) error {
+	for _, e := range events {
+		if err := c.add(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Events returns copies of the events, series and exceptions stored for a
+// calendar, for assertions on what a test changed
+func (c *Calendar) Events(calendarID string) []*calendar.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make([]*calendar.Event, 0, len(c.events[calendarID]))
+	for _, e := range c.events[calendarID] {
+		result = append(result, clone(e))
+	}
+	return result
+}
+
+// SetNow makes the calendar's clock return now
+func (c *Calendar) SetNow(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = func() time.Time { return now }
+}
+
+// Fail makes every call of the named Service method, e.g. "GetEvent", fail
+// with err until it is called again with a nil err
+func (c *Calendar) Fail(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.failures, method)
+		return
+	}
+	c.failures[method] = err
+}
+
+func (c *Calendar) failure(method string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.failures[method]
+}
+
+func (c *Calendar) CalendarID() string { return c.calendarID }
+
+// Calendars returns the primary calendar followed by the other calendars
+// of the calendar list the user owns
+func (c *Calendar) Calendars() []string {
+	result := []string{c.calendarID}
+	for _, info := range c.calendars {
+		if info.ID != c.calendarID && info.AccessRole == "owner" {
+			result = append(result, info.ID)
+		}
+	}
+	return result
+}
+
+func (c *Calendar) WithDefaults(calendarID, timezone string) gcal.Service {
+	view := *c
+	if calendarID != "" {
+		view.calendarID = calendarID
+	}
+	if loc, err := time.LoadLocation(timezone); timezone != "" && err == nil {
+		view.loc = loc
+	}
+	return &view
+}
+
+func (c *Calendar) ListEventsForDays(ctx context.Context, days int) ([]gcal.CalendarEvent, error) {
+	if err := c.failure("ListEventsForDays"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	now := c.now().In(c.loc)
+	c.mu.Unlock()
+	return c.list(ctx, c.calendarID, "", now, now.AddDate(0, 0, days))
+}
+
+func (c *Calendar) ListEventsRange(ctx context.Context, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	if err := c.failure("ListEventsRange"); err != nil {
+		return nil, err
+	}
+	return c.listDates(ctx, c.calendarID, startDate, endDate)
+}
+
+func (c *Calendar) ListCalendarEvents(ctx context.Context, calendarID, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	if err := c.failure("ListCalendarEvents"); err != nil {
+		return nil, err
+	}
+	return c.listDates(ctx, calendarID, startDate, endDate)
+}
+
+// listDates lists the events between two dates, both inclusive
+func (c *Calendar) listDates(ctx context.Context, calendarID, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	start, err := time.ParseInLocation("2006-01-02", startDate, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	end, err := time.ParseInLocation("2006-01-02", endDate, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	return c.list(ctx, calendarID, "", start, end.AddDate(0, 0, 1))
+}
+
+// list returns the events overlapping [min, max) ordered by start, with
+// series expanded into their instances, as events.list does with
+// singleEvents. With a query, only events matching it are listed.
+func (c *Calendar) list(ctx context.Context, calendarID, query string, min, max time.Time) ([]gcal.CalendarEvent, error) {
+	c.mu.Lock()
+	events, err := c.calendarEvents(calendarID)
+	if err != nil {
+		c.mu.Unlock()
+		return nil, err
+	}
+	var matched []*calendar.Event
+	for _, e := range events {
+		// Exceptions are listed in place of the instances they replace
+		if e.Status == "cancelled" || e.RecurringEventId != "" && slices.ContainsFunc(events, func(series *calendar.Event) bool { return series.Id == e.RecurringEventId }) {
+			continue
+		}
+		if len(e.Recurrence) > 0 {
+			matched = append(matched, c.instances(calendarID, e, max)...)
+		} else {
+			matched = append(matched, e)
+		}
+	}
+	c.mu.Unlock()
+
+	result := []gcal.CalendarEvent{}
+	for _, e := range matched {
+		start, end := c.span(e)
+		if e.Status != "cancelled" && start.Before(max) && end.After(min) && matches(e, query) {
+			result = append(result, c.toCalendarEvent(calendarID, e))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return c.parse(result[i].Start).Before(c.parse(result[j].Start))
+	})
+	gcal.ReportPage(ctx, len(result), result)
+	return result, nil
+}
+
+// SearchEvents matches every word of query, ignoring case, against the
+// summary, description, location and guests of events
+func (c *Calendar) SearchEvents(ctx context.Context, calendarID, query, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	if err := c.failure("SearchEvents"); err != nil {
+		return nil, err
+	}
+	if query == "" {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("query is empty")}
+	}
+	// Without bounds the search covers as much of a series as the fake
+	// expands
+	min, max := time.Time{}, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC)
+	if startDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", startDate, c.loc)
+		if err != nil {
+			return nil, &gcal.InvalidInputError{Err: err}
+		}
+		min = start
+	}
+	if endDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", endDate, c.loc)
+		if err != nil {
+			return nil, &gcal.InvalidInputError{Err: err}
+		}
+		max = end.AddDate(0, 0, 1)
+	}
+	return c.list(ctx, calendarID, query, min, max)
+}
+
+// CheckSchedule allows any time: the fake has no schedule constraints
+func (c *Calendar) CheckSchedule(context.Context, time.Time, time.Time) error {
+	return c.failure("CheckSchedule")
+}
+
+// QueryFreeBusy reports the time taken by events that aren't free time
+// and that the calendar's owner hasn't declined, merged into blocks as the
+// Calendar API does. Calendars the user can't read are notFound.
+func (c *Calendar) QueryFreeBusy(ctx context.Context, calendarIDs []string, timeMin, timeMax time.Time) ([]gcal.BusyCalendar, error) {
+	if err := c.failure("QueryFreeBusy"); err != nil {
+		return nil, err
+	}
+	if len(calendarIDs) == 0 || len(calendarIDs) > gcal.MaxFreeBusyCalendars {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("ask about 1 to %d calendars at a time", gcal.MaxFreeBusyCalendars)}
+	}
+	if !timeMax.After(timeMin) {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("the end of the window must be after its start")}
+	}
+
+	result := []gcal.BusyCalendar{}
+	for _, id := range calendarIDs {
+		busy := gcal.BusyCalendar{ID: id, Busy: []gcal.BusyBlock{}}
+		events, err := c.list(ctx, id, "", timeMin, timeMax)
+		if err != nil {
+			busy.Error = "notFound"
+			result = append(result, busy)
+			continue
+		}
+		var blockStart, blockEnd time.Time
+		flush := func() {
+			if !blockEnd.IsZero() {
+				busy.Busy = append(busy.Busy, gcal.BusyBlock{Start: blockStart.Format(time.RFC3339), End: blockEnd.Format(time.RFC3339)})
+			}
+		}
+		for _, e := range events {
+			if e.Transparency == "transparent" || slices.ContainsFunc(e.Guests, func(g gcal.Guest) bool { return g.Self && g.ResponseStatus == "declined" }) {
+				continue
+			}
+			start, end := c.parse(e.Start), c.parse(e.End)
+			start, end = later(start, timeMin).In(c.loc), earlier(end, timeMax).In(c.loc)
+			if !blockEnd.IsZero() && !start.After(blockEnd) {
+				blockEnd = later(blockEnd, end)
+				continue
+			}
+			flush()
+			blockStart, blockEnd = start, end
+		}
+		flush()
+		result = append(result, busy)
+	}
+	return result, nil
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlier(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// matches reports whether an event contains every word of query
+func matches(e *calendar.Event, query string) bool {
+	text := []string{e.Summary, e.Description, e.Location}
+	for _, a := range e.Attendees {
+		text = append(text, a.Email, a.DisplayName)
+	}
+	haystack := strings.ToLower(strings.Join(text, "\n"))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// calendarEvents returns the events of a calendar the user can read. The
+// caller holds the lock.
+func (c *Calendar) calendarEvents(calendarID string) ([]*calendar.Event, error) {
+	events, ok := c.events[calendarID]
+	if !ok && calendarID != c.calendarID && calendarID != c.primary &&
+		!slices.ContainsFunc(c.calendars, func(info gcal.CalendarInfo) bool { return info.ID == calendarID }) {
+		return nil, &gcal.CalendarNotFoundError{CalendarID: calendarID, Err: notFound()}
+	}
+	return events, nil
+}
+
+// instances expands a series into its instances starting before limit,
+// with its exceptions in place of the instances they replace. Cancelled
+// instances are included. The caller holds the lock.
+func (c *Calendar) instances(calendarID string, series *calendar.Event, limit time.Time) []*calendar.Event {
+	rec, err := parseRecurrence(series.Recurrence, c.loc)
+	if err != nil {
+		return nil
+	}
+	exceptions := make(map[int64]*calendar.Event)
+	for _, e := range c.events[calendarID] {
+		if e.RecurringEventId == series.Id && e.OriginalStartTime != nil {
+			exceptions[c.parse(gcal.EventTime(e.OriginalStartTime)).Unix()] = e
+		}
+	}
+
+	start, end := c.span(series)
+	var result []*calendar.Event
+	for _, t := range rec.starts(start, limit) {
+		if e, ok := exceptions[t.Unix()]; ok {
+			result = append(result, e)
+			delete(exceptions, t.Unix())
+			continue
+		}
+		result = append(result, c.instance(calendarID, series, t, end.Sub(start)))
+	}
+	// Exceptions moved before limit from later in the series
+	for _, e := range exceptions {
+		if s, _ := c.span(e); s.Before(limit) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// instance returns the instance of series starting at start, with the ID
+// the Calendar API gives it
+func (c *Calendar) instance(calendarID string, series *calendar.Event, start time.Time, length time.Duration) *calendar.Event {
+	e := clone(series)
+	e.Recurrence = nil
+	e.RecurringEventId = series.Id
+	if series.Start.Date != "" {
+		e.Id = series.Id + "_" + start.Format("20060102")
+		e.Start = &calendar.EventDateTime{Date: start.Format("2006-01-02")}
+		e.End = &calendar.EventDateTime{Date: start.Add(length).Format("2006-01-02")}
+	} else {
+		e.Id = series.Id + "_" + start.UTC().Format("20060102T150405Z")
+		e.Start = &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)}
+		e.End = &calendar.EventDateTime{DateTime: start.Add(length).Format(time.RFC3339)}
+	}
+	e.OriginalStartTime = e.Start
+	e.HtmlLink = eventLink(e.Id, calendarID)
+	return e
+}
+
+func (c *Calendar) toCalendarEvent(calendarID string, e *calendar.Event) gcal.CalendarEvent {
+	event := gcal.CalendarEvent{
+		ID:               e.Id,
+		CalendarID:       calendarID,
+		Summary:          e.Summary,
+		Start:            gcal.EventTime(e.Start),
+		End:              gcal.EventTime(e.End),
+		HTMLLink:         e.HtmlLink,
+		Location:         e.Location,
+		Transparency:     e.Transparency,
+		Attendees:        len(e.Attendees),
+		OrganizerSelf:    e.Organizer != nil && e.Organizer.Self,
+		RecurringEventID: e.RecurringEventId,
+		Updated:          e.Updated,
+	}
+	for _, a := range e.Attendees {
+		event.Guests = append(event.Guests, gcal.Guest{Email: a.Email, ResponseStatus: a.ResponseStatus, Self: a.Self})
+	}
+	return event
+}
+
+func (c *Calendar) ListCalendars(context.Context) ([]gcal.CalendarInfo, error) {
+	if err := c.failure("ListCalendars"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.calendars), nil
+}
+
+func (c *Calendar) GetEvent(ctx context.Context, eventID string) (*calendar.Event, error) {
+	if err := c.failure("GetEvent"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, err := c.find(c.calendarID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return clone(e), nil
+}
+
+// find returns the stored event, or the instance of a series, with the ID
+// eventID. The caller holds the lock.
+func (c *Calendar) find(calendarID, eventID string) (*calendar.Event, error) {
+	events, err := c.calendarEvents(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		if e.Id == eventID {
+			return e, nil
+		}
+	}
+	// Instance IDs are the series ID followed by the original start
+	if seriesID, at, ok := strings.Cut(eventID, "_"); ok {
+		for _, series := range events {
+			if series.Id != seriesID || len(series.Recurrence) == 0 {
+				continue
+			}
+			for _, inst := range c.instances(calendarID, series, c.parse(atTime(at)).Add(time.Second)) {
+				if inst.Id == eventID {
+					return inst, nil
+				}
+			}
+		}
+	}
+	return nil, notFound()
+}
+
+func (c *Calendar) CreateEvent(ctx context.Context, summary, description, date, startTime, endTime string, force bool) (*calendar.Event, error) {
+	return c.CreateCalendarEvent(ctx, c.calendarID, gcal.EventDraft{
+		Summary:     summary,
+		Description: description,
+		Date:        date,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		Force:       force,
+	})
+}
+
+func (c *Calendar) CreateCalendarEvent(ctx context.Context, calendarID string, draft gcal.EventDraft) (*calendar.Event, error) {
+	if err := c.failure("CreateCalendarEvent"); err != nil {
+		return nil, err
+	}
+	start, err := time.ParseInLocation("2006-01-02T15:04", draft.Date+"T"+draft.StartTime, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	end, err := time.ParseInLocation("2006-01-02T15:04", draft.Date+"T"+draft.EndTime, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	if !end.After(start) {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("end time %s must be after start time %s", draft.EndTime, draft.StartTime)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, err := c.calendarEvents(calendarID); err != nil {
+		return nil, err
+	}
+	id := c.newIDLocked()
+	event := &calendar.Event{
+		Id:          id,
+		Summary:     draft.Summary,
+		Description: draft.Description,
+		Start:       &calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: c.loc.String()},
+		End:         &calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: c.loc.String()},
+		Status:      "confirmed",
+		Organizer:   &calendar.EventOrganizer{Self: true},
+		HtmlLink:    eventLink(id, calendarID),
+		Updated:     c.now().UTC().Format(time.RFC3339),
+	}
+	if len(draft.Tags) > 0 {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{Private: maps.Clone(draft.Tags)}
+	}
+	c.events[calendarID] = append(c.events[calendarID], event)
+	gcal.ReportCreated(ctx)
+	return clone(event), nil
+}
+
+// QuickAddEvent understands far less than Google: only text ending in a
+// date and time, "Lunch with Sam 2026-03-20 13:00", which becomes a one-hour
+// event
+func (c *Calendar) QuickAddEvent(ctx context.Context, calendarID, text string) (*calendar.Event, error) {
+	if err := c.failure("QuickAddEvent"); err != nil {
+		return nil, err
+	}
+	words := strings.Fields(text)
+	if len(words) < 3 {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("expected \"<summary> YYYY-MM-DD HH:MM\", got %q", text)}
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", strings.Join(words[len(words)-2:], " "), c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: fmt.Errorf("expected \"<summary> YYYY-MM-DD HH:MM\", got %q", text)}
+	}
+	return c.CreateCalendarEvent(ctx, calendarID, gcal.EventDraft{
+		Summary:   strings.Join(words[:len(words)-2], " "),
+		Date:      start.Format("2006-01-02"),
+		StartTime: start.Format("15:04"),
+		EndTime:   start.Add(time.Hour).Format("15:04"),
+	})
+}
+
+func (c *Calendar) UpdateEvent(ctx context.Context, eventID string, updates gcal.EventUpdates) (*calendar.Event, error) {
+	return c.UpdateCalendarEvent(ctx, c.calendarID, eventID, updates)
+}
+
+// UpdateCalendarEvent changes an event. Changing an instance of a series
+// turns it into an exception, as in Google Calendar.
+func (c *Calendar) UpdateCalendarEvent(ctx context.Context, calendarID, eventID string, updates gcal.EventUpdates) (*calendar.Event, error) {
+	if err := c.failure("UpdateCalendarEvent"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if updates.Scope.ApplyTo != "" {
+		series, occurrence, err := c.scoped(calendarID, eventID, updates.Scope)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case series == nil, updates.Scope.ApplyTo == gcal.ApplyToThisEvent:
+			eventID = occurrence.Id
+		case updates.Scope.ApplyTo == gcal.ApplyToThisAndFollowing && !c.firstOccurrence(series, occurrence):
+			return c.splitSeries(calendarID, series, occurrence, updates)
+		case updates.Scope.ApplyTo == gcal.ApplyToAll && updates.Date != nil:
+			return nil, &gcal.InvalidInputError{Err: errors.New("a new date can't be given for all occurrences")}
+		default:
+			eventID = series.Id
+		}
+	}
+	e, err := c.stored(calendarID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := clone(e)
+	if err := c.applyUpdates(changed, updates); err != nil {
+		return nil, err
+	}
+	*e = *changed
+	return clone(e), nil
+}
+
+// applyUpdates changes a copy of an event as UpdateEvent asks
+func (c *Calendar) applyUpdates(changed *calendar.Event, updates gcal.EventUpdates) error {
+	if updates.Summary != nil {
+		changed.Summary = *updates.Summary
+	}
+	if updates.Description != nil {
+		changed.Description = *updates.Description
+	}
+	if updates.Date != nil || updates.StartTime != nil || updates.EndTime != nil {
+		if err := c.applyTimes(changed, updates); err != nil {
+			return err
+		}
+	}
+	changed.Updated = c.now().UTC().Format(time.RFC3339)
+	return nil
+}
+
+// scoped finds the series an event belongs to and the occurrence scope
+// means, as gcal.CalendarClient does: series is nil for events that don't
+// repeat, and occurrence is nil when a whole series is meant. The caller
+// holds the lock.
+func (c *Calendar) scoped(calendarID, eventID string, scope gcal.SeriesScope) (series, occurrence *calendar.Event, err error) {
+	switch scope.ApplyTo {
+	case gcal.ApplyToThisEvent, gcal.ApplyToThisAndFollowing, gcal.ApplyToAll:
+	default:
+		return nil, nil, &gcal.InvalidInputError{Err: fmt.Errorf("invalid apply_to %q", scope.ApplyTo)}
+	}
+	e, err := c.find(calendarID, eventID)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case e.RecurringEventId != "":
+		series, err := c.find(calendarID, e.RecurringEventId)
+		return series, e, err
+	case len(e.Recurrence) == 0:
+		return nil, e, nil
+	case scope.ApplyTo == gcal.ApplyToAll:
+		return e, nil, nil
+	case scope.Occurrence == "":
+		return nil, nil, &gcal.InvalidInputError{Err: fmt.Errorf("event %s is a recurring series; give the date of the occurrence meant", eventID)}
+	}
+
+	day, err := time.ParseInLocation("2006-01-02", scope.Occurrence, c.loc)
+	if err != nil {
+		return nil, nil, &gcal.InvalidInputError{Err: err}
+	}
+	for _, inst := range c.instances(calendarID, e, day.AddDate(0, 0, 1)) {
+		if start, _ := c.span(inst); inst.Status != "cancelled" && !start.Before(day) {
+			return e, inst, nil
+		}
+	}
+	return nil, nil, &gcal.InvalidInputError{Err: fmt.Errorf("the series %s has no occurrence on %s", eventID, scope.Occurrence)}
+}
+
+// firstOccurrence reports whether occurrence is the one series starts with
+func (c *Calendar) firstOccurrence(series, occurrence *calendar.Event) bool {
+	start, _ := c.span(series)
+	return c.parse(gcal.EventTime(occurrence.OriginalStartTime)).Equal(start)
+}
+
+// splitSeries ends series before occurrence and continues it as a new
+// series with the updates applied. The caller holds the lock.
+func (c *Calendar) splitSeries(calendarID string, series, occurrence *calendar.Event, updates gcal.EventUpdates) (*calendar.Event, error) {
+	at := c.parse(gcal.EventTime(occurrence.OriginalStartTime))
+	done := 0
+	for _, inst := range c.instances(calendarID, series, at) {
+		if c.parse(gcal.EventTime(inst.OriginalStartTime)).Before(at) {
+			done++
+		}
+	}
+	before, after := gcal.SplitRecurrence(series.Recurrence, at, series.Start.Date != "", done)
+
+	following := clone(series)
+	following.Id = c.newIDLocked()
+	following.HtmlLink = eventLink(following.Id, calendarID)
+	following.Recurrence = after
+	start, end := c.span(series)
+	if series.Start.Date != "" {
+		following.Start = &calendar.EventDateTime{Date: at.Format("2006-01-02")}
+		following.End = &calendar.EventDateTime{Date: at.AddDate(0, 0, int(end.Sub(start).Hours()/24+0.5)).Format("2006-01-02")}
+	} else {
+		following.Start = &calendar.EventDateTime{DateTime: at.Format(time.RFC3339), TimeZone: c.loc.String()}
+		following.End = &calendar.EventDateTime{DateTime: at.Add(end.Sub(start)).Format(time.RFC3339), TimeZone: c.loc.String()}
+	}
+	if err := c.applyUpdates(following, updates); err != nil {
+		return nil, err
+	}
+
+	series.Recurrence = before
+	c.events[calendarID] = append(c.events[calendarID], following)
+	return clone(following), nil
+}
+
+// applyTimes moves an event as UpdateEvent asks; a new date alone keeps
+// the times of day and the duration
+func (c *Calendar) applyTimes(e *calendar.Event, updates gcal.EventUpdates) error {
+	start, end := c.span(e)
+	if e.Start.Date != "" {
+		if updates.StartTime != nil || updates.EndTime != nil {
+			return &gcal.InvalidInputError{Err: errors.New("calendartest: all-day events can only be moved to another date")}
+		}
+		date, err := time.ParseInLocation("2006-01-02", *updates.Date, c.loc)
+		if err != nil {
+			return &gcal.InvalidInputError{Err: err}
+		}
+		days := int(end.Sub(start).Hours()/24 + 0.5)
+		e.Start = &calendar.EventDateTime{Date: date.Format("2006-01-02")}
+		e.End = &calendar.EventDateTime{Date: date.AddDate(0, 0, days).Format("2006-01-02")}
+		return nil
+	}
+
+	date := start.Format("2006-01-02")
+	if updates.Date != nil {
+		date = *updates.Date
+	}
+	startTime, endTime := start.Format("15:04"), end.Format("15:04")
+	if updates.StartTime != nil {
+		startTime = *updates.StartTime
+	}
+	if updates.EndTime != nil {
+		endTime = *updates.EndTime
+	}
+	newStart, err := time.ParseInLocation("2006-01-02T15:04", date+"T"+startTime, c.loc)
+	if err != nil {
+		return &gcal.InvalidInputError{Err: err}
+	}
+	newEnd := newStart.Add(end.Sub(start))
+	if updates.EndTime != nil || updates.StartTime != nil {
+		newEnd, err = time.ParseInLocation("2006-01-02T15:04", date+"T"+endTime, c.loc)
+		if err != nil {
+			return &gcal.InvalidInputError{Err: err}
+		}
+	}
+	if !newEnd.After(newStart) {
+		return &gcal.InvalidInputError{Err: fmt.Errorf("end time %s must be after start time %s", newEnd.Format("15:04"), newStart.Format("15:04"))}
+	}
+	e.Start = &calendar.EventDateTime{DateTime: newStart.Format(time.RFC3339), TimeZone: c.loc.String()}
+	e.End = &calendar.EventDateTime{DateTime: newEnd.Format(time.RFC3339), TimeZone: c.loc.String()}
+	return nil
+}
+
+// stored returns the stored event with the ID eventID; an instance of a
+// series is stored as an exception first. The caller holds the lock.
+func (c *Calendar) stored(calendarID, eventID string) (*calendar.Event, error) {
+	e, err := c.find(calendarID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(c.events[calendarID], e) {
+		return e, nil
+	}
+	c.events[calendarID] = append(c.events[calendarID], e)
+	return e, nil
+}
+
+func (c *Calendar) ListLinkedEvents(ctx context.Context, calendarID, broadcastID string) ([]gcal.CalendarEvent, error) {
+	if err := c.failure("ListLinkedEvents"); err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	events, err := c.calendarEvents(calendarID)
+	if err != nil {
+		return nil, err
+	}
+	result := []gcal.CalendarEvent{}
+	for _, e := range events {
+		if e.Status != "cancelled" && e.ExtendedProperties != nil && e.ExtendedProperties.Private[gcal.BroadcastIDKey] == broadcastID {
+			result = append(result, c.toCalendarEvent(calendarID, e))
+		}
+	}
+	return result, nil
+}
+
+// DeleteEvent removes an event. Deleting a series removes its exceptions
+// too; deleting an instance cancels it.
+func (c *Calendar) DeleteEvent(ctx context.Context, eventID string) error {
+	if err := c.failure("DeleteEvent"); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deleteLocked(c.calendarID, eventID)
+}
+
+// DeleteOccurrences deletes the occurrences scope selects, ending the series
+// before the occurrence for this_and_following
+func (c *Calendar) DeleteOccurrences(ctx context.Context, calendarID, eventID string, scope gcal.SeriesScope) error {
+	method := "DeleteOccurrences"
+	if scope.ApplyTo == "" {
+		method = "DeleteEvent"
+	}
+	if err := c.failure(method); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if scope.ApplyTo == "" {
+		return c.deleteLocked(calendarID, eventID)
+	}
+	series, occurrence, err := c.scoped(calendarID, eventID, scope)
+	if err != nil {
+		return err
+	}
+	switch {
+	case series == nil, scope.ApplyTo == gcal.ApplyToThisEvent:
+		return c.deleteLocked(calendarID, occurrence.Id)
+	case scope.ApplyTo == gcal.ApplyToThisAndFollowing && !c.firstOccurrence(series, occurrence):
+		at := c.parse(gcal.EventTime(occurrence.OriginalStartTime))
+		series.Recurrence, _ = gcal.SplitRecurrence(series.Recurrence, at, series.Start.Date != "", 0)
+		return nil
+	}
+	return c.deleteLocked(calendarID, series.Id)
+}
+
+// deleteLocked removes an event of calendarID. The caller holds the lock.
+func (c *Calendar) deleteLocked(calendarID, eventID string) error {
+	e, err := c.stored(calendarID, eventID)
+	if err != nil {
+		return err
+	}
+	if e.Status == "cancelled" {
+		return &googleapi.Error{Code: http.StatusGone, Message: "Resource has been deleted"}
+	}
+	if e.RecurringEventId != "" {
+		e.Status = "cancelled"
+		return nil
+	}
+	c.events[calendarID] = slices.DeleteFunc(c.events[calendarID], func(other *calendar.Event) bool {
+		return other == e || other.RecurringEventId == e.Id
+	})
+	return nil
+}
+
+func (c *Calendar) CreateOutOfOffice(ctx context.Context, summary string, start, end time.Time, autoDecline bool, message string) (*calendar.Event, error) {
+	if err := c.failure("CreateOutOfOffice"); err != nil {
+		return nil, err
+	}
+	mode := "declineNone"
+	if autoDecline {
+		mode = "declineOnlyNewConflictingInvitations"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.newIDLocked()
+	event := &calendar.Event{
+		Id:        id,
+		Summary:   summary,
+		EventType: "outOfOffice",
+		Start:     &calendar.EventDateTime{DateTime: start.Format(time.RFC3339), TimeZone: c.loc.String()},
+		End:       &calendar.EventDateTime{DateTime: end.Format(time.RFC3339), TimeZone: c.loc.String()},
+		Status:    "confirmed",
+		Organizer: &calendar.EventOrganizer{Self: true},
+		HtmlLink:  eventLink(id, c.calendarID),
+		Updated:   c.now().UTC().Format(time.RFC3339),
+		OutOfOfficeProperties: &calendar.EventOutOfOfficeProperties{
+			AutoDeclineMode: mode,
+			DeclineMessage:  message,
+		},
+	}
+	c.events[c.calendarID] = append(c.events[c.calendarID], event)
+	gcal.ReportCreated(ctx)
+	return clone(event), nil
+}
+
+func (c *Calendar) RespondToEvent(ctx context.Context, eventID, status, comment string) error {
+	if err := c.failure("RespondToEvent"); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, err := c.find(c.calendarID, eventID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(e.Attendees, func(a *calendar.EventAttendee) bool { return a.Self })
+	if i < 0 {
+		return &gcal.InvalidInputError{Err: fmt.Errorf("you are not a guest of event %s", eventID)}
+	}
+	e, _ = c.stored(c.calendarID, eventID)
+	e.Attendees[i].ResponseStatus = status
+	e.Attendees[i].Comment = comment
+	return nil
+}
+
+func (c *Calendar) ListInstances(ctx context.Context, seriesID, startDate, endDate string) ([]gcal.SeriesInstance, error) {
+	if err := c.failure("ListInstances"); err != nil {
+		return nil, err
+	}
+	min, err := time.ParseInLocation("2006-01-02", startDate, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	max, err := time.ParseInLocation("2006-01-02", endDate, c.loc)
+	if err != nil {
+		return nil, &gcal.InvalidInputError{Err: err}
+	}
+	max = max.AddDate(0, 0, 1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	events, err := c.calendarEvents(c.calendarID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(events, func(e *calendar.Event) bool { return e.Id == seriesID && len(e.Recurrence) > 0 })
+	if i < 0 {
+		return nil, notFound()
+	}
+	result := []gcal.SeriesInstance{}
+	for _, e := range c.instances(c.calendarID, events[i], max) {
+		if start, end := c.span(e); start.Before(max) && end.After(min) {
+			result = append(result, gcal.SeriesInstance{
+				ID:            e.Id,
+				Start:         gcal.EventTime(e.Start),
+				End:           gcal.EventTime(e.End),
+				OriginalStart: gcal.EventTime(e.OriginalStartTime),
+				Status:        e.Status,
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return c.parse(result[i].OriginalStart).Before(c.parse(result[j].OriginalStart))
+	})
+	return result, nil
+}
+
+// ListDelegatedActions fails like a client without delegated mode, which
+// the fake doesn't implement
+func (c *Calendar) ListDelegatedActions(context.Context, time.Time) ([]gcal.DelegatedAction, error) {
+	return nil, &gcal.InvalidInputError{Err: errors.New("delegated mode is off")}
+}
+
+// RawRequest is not implemented by the fake unless a test sets a failure
+// for it
+func (c *Calendar) RawRequest(_ context.Context, method string, _ gcal.RawParams) (json.RawMessage, error) {
+	if err := c.failure("RawRequest"); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("calendartest: RawRequest %s is not supported", method)
+}
+
+// span returns the start and end of an event; all-day events span whole
+// days in the calendar's timezone
+func (c *Calendar) span(e *calendar.Event) (time.Time, time.Time) {
+	return c.parse(gcal.EventTime(e.Start)), c.parse(gcal.EventTime(e.End))
+}
+
+// parse reads an RFC 3339 time, or a date as midnight in the calendar's
+// timezone
+func (c *Calendar) parse(v string) time.Time {
+	if t, err := time.ParseInLocation("2006-01-02", v, c.loc); err == nil {
+		return t
+	}
+	t, _ := time.Parse(time.RFC3339, v)
+	return t.In(c.loc)
+}
+
+func (c *Calendar) newID() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.newIDLocked()
+}
+
+func (c *Calendar) newIDLocked() string {
+	c.nextID++
+	return fmt.Sprintf("event%d", c.nextID)
+}
+
+// eventDateTime reads the start or end of a fixture event
+func eventDateTime(v string) (*calendar.EventDateTime, error) {
+	if _, err := time.Parse("2006-01-02", v); err == nil {
+		return &calendar.EventDateTime{Date: v}, nil
+	}
+	if _, err := time.Parse(time.RFC3339, v); err != nil {
+		return nil, err
+	}
+	return &calendar.EventDateTime{DateTime: v}, nil
+}
+
+// atTime turns the original start in an instance ID back into a value
+// parse reads
+func atTime(at string) string {
+	if t, err := time.Parse("20060102T150405Z", at); err == nil {
+		return t.Format(time.RFC3339)
+	}
+	if t, err := time.Parse("20060102", at); err == nil {
+		return t.Format("2006-01-02")
+	}
+	return at
+}
+
+// eventLink builds the htmlLink of an event, which gcal.ParseEventLink
+// reads back
+func eventLink(eventID, calendarID string) string {
+	return "https://www.google.com/calendar/event?eid=" + base64.RawURLEncoding.EncodeToString([]byte(eventID+" "+calendarID))
+}
+
+func notFound() error {
+	return &googleapi.Error{Code: http.StatusNotFound, Message: "Not Found", Errors: []googleapi.ErrorItem{{Reason: "notFound", Message: "Not Found"}}}
+}
+
+// clone copies an event deeply, so that callers can't change stored ones
+func clone(e *calendar.Event) *calendar.Event {
+	data, _ := json.Marshal(e)
+	var copied calendar.Event
+	json.Unmarshal(data, &copied)
+	return &copied
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,288 @@
+package calendartest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/googleapi"
+)
+
+func loadWeek(t *testing.T) *Calendar {
+	t.Helper()
+	c, err := Load("testdata/week.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func summaries(events []gcal.CalendarEvent) []string {
+	var result []string
+	for _, e := range events {
+		result = append(result, e.Summary+" "+e.Start)
+	}
+	return result
+}
+
+func TestListEventsRange(t *testing.T) {
+	c := loadWeek(t)
+	events, err := c.ListEventsRange(context.Background(), "2026-03-16", "2026-03-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"Standup 2026-03-16T10:00:00+01:00",
+		"Design review 2026-03-16T10:00:00+01:00",
+		"Standup (moved) 2026-03-20T11:00:00+01:00",
+	}
+	if got := summaries(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if events[0].ID != "standup_20260316T090000Z" || events[0].RecurringEventID != "standup" || !events[0].OrganizerSelf {
+		t.Errorf("unexpected instance %+v", events[0])
+	}
+	if id, calendarID, err := gcal.ParseEventLink(events[1].HTMLLink); err != nil || id != "review" || calendarID != "me@example.com" {
+		t.Errorf("unexpected link %s: %s %s %v", events[1].HTMLLink, id, calendarID, err)
+	}
+
+	team, err := c.ListCalendarEvents(context.Background(), "team@example.com", "2026-03-20", "2026-03-20")
+	if err != nil || len(team) != 1 || team[0].Start != "2026-03-19" {
+		t.Errorf("expected the offsite to overlap the day, got %+v, %v", team, err)
+	}
+	var notFound *gcal.CalendarNotFoundError
+	if _, err := c.ListCalendarEvents(context.Background(), "stranger@example.com", "2026-03-16", "2026-03-16"); !errors.As(err, &notFound) {
+		t.Errorf("expected CalendarNotFoundError, got %v", err)
+	}
+}
+
+func TestSearchEvents(t *testing.T) {
+	c := loadWeek(t)
+	events, err := c.SearchEvents(context.Background(), "me@example.com", "STANDUP", "2026-03-16", "2026-03-20")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summaries(events); len(got) != 2 || got[0] != "Standup 2026-03-16T10:00:00+01:00" {
+		t.Errorf("expected the standups of the range, moved one included, got %q", got)
+	}
+	if events, _ := c.SearchEvents(context.Background(), "me@example.com", "design review", "", ""); len(events) != 1 {
+		t.Errorf("expected every word to match, got %q", summaries(events))
+	}
+}
+
+func TestQueryFreeBusy(t *testing.T) {
+	c := loadWeek(t)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	busy, err := c.QueryFreeBusy(context.Background(), []string{"me@example.com", "team@example.com", "stranger@example.com"},
+		time.Date(2026, 3, 16, 0, 0, 0, 0, loc), time.Date(2026, 3, 20, 0, 0, 0, 0, loc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gcal.BusyCalendar{
+		{ID: "me@example.com", Busy: []gcal.BusyBlock{{Start: "2026-03-16T10:00:00+01:00", End: "2026-03-16T11:00:00+01:00"}}},
+		{ID: "team@example.com", Busy: []gcal.BusyBlock{{Start: "2026-03-19T00:00:00+01:00", End: "2026-03-20T00:00:00+01:00"}}},
+		{ID: "stranger@example.com", Busy: []gcal.BusyBlock{}, Error: "notFound"},
+	}
+	if !reflect.DeepEqual(busy, want) {
+		t.Errorf("got %+v, want %+v", busy, want)
+	}
+}
+
+func TestListEventsForDays(t *testing.T) {
+	c := loadWeek(t)
+	c.SetNow(time.Date(2026, 3, 16, 9, 30, 0, 0, time.UTC))
+	events, err := c.ListEventsForDays(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The standup ended at 09:15 UTC, the review is still running
+	if got := summaries(events); len(got) != 1 || got[0] != "Design review 2026-03-16T10:00:00+01:00" {
+		t.Errorf("unexpected events %q", got)
+	}
+}
+
+func TestUpdateAndDeleteInstances(t *testing.T) {
+	c := loadWeek(t)
+	ctx := context.Background()
+
+	summary := "Standup (short)"
+	if _, err := c.UpdateEvent(ctx, "standup_20260323T090000Z", gcal.EventUpdates{Summary: &summary}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteEvent(ctx, "standup_20260325T090000Z"); err != nil {
+		t.Fatal(err)
+	}
+	instances, err := c.ListInstances(ctx, "standup", "2026-03-23", "2026-03-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, i := range instances {
+		got = append(got, i.ID+" "+i.Status)
+	}
+	want := []string{"standup_20260323T090000Z confirmed", "standup_20260325T090000Z cancelled", "standup_20260327T090000Z confirmed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	event, err := c.GetEvent(ctx, "standup_20260323T090000Z")
+	if err != nil || event.Summary != summary {
+		t.Errorf("expected the changed instance, got %+v, %v", event, err)
+	}
+
+	if err := c.DeleteEvent(ctx, "standup"); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := c.ListEventsRange(ctx, "2026-03-16", "2026-03-31"); len(events) != 1 {
+		t.Errorf("expected the series and its exceptions to be gone, got %q", summaries(events))
+	}
+	var apiErr *googleapi.Error
+	if err := c.DeleteEvent(ctx, "standup"); !errors.As(err, &apiErr) || apiErr.Code != 404 {
+		t.Errorf("expected 404 for a deleted event, got %v", err)
+	}
+}
+
+func TestSeriesScope(t *testing.T) {
+	c := loadWeek(t)
+	ctx := context.Background()
+	summary := "Standup (new format)"
+
+	// this_event on the series ID changes only the occurrence of that day
+	scope := gcal.SeriesScope{ApplyTo: gcal.ApplyToThisEvent, Occurrence: "2026-03-23"}
+	changed, err := c.UpdateEvent(ctx, "standup", gcal.EventUpdates{Summary: &summary, Scope: scope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed.Id != "standup_20260323T090000Z" {
+		t.Errorf("expected the occurrence to change, got %s", changed.Id)
+	}
+
+	// this_and_following splits the series at the occurrence
+	start, end := "09:30", "09:45"
+	scope = gcal.SeriesScope{ApplyTo: gcal.ApplyToThisAndFollowing}
+	following, err := c.UpdateEvent(ctx, "standup_20260325T090000Z", gcal.EventUpdates{StartTime: &start, EndTime: &end, Scope: scope})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _ := c.ListEventsRange(ctx, "2026-03-20", "2026-03-27")
+	want := []string{
+		"Standup (moved) 2026-03-20T11:00:00+01:00",
+		"Standup (new format) 2026-03-23T10:00:00+01:00",
+		"Standup 2026-03-25T09:30:00+01:00",
+		"Standup 2026-03-27T09:30:00+01:00",
+	}
+	if got := summaries(events); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if events[2].RecurringEventID != following.Id {
+		t.Errorf("expected the later occurrences to belong to the new series %s, got %+v", following.Id, events[2])
+	}
+
+	// Deleting the new series from its second occurrence on ends it there
+	scope = gcal.SeriesScope{ApplyTo: gcal.ApplyToThisAndFollowing, Occurrence: "2026-03-27"}
+	if err := c.DeleteOccurrences(ctx, c.CalendarID(), following.Id, scope); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := c.ListEventsRange(ctx, "2026-03-25", "2026-04-10"); len(events) != 1 {
+		t.Errorf("expected only the first occurrence of the new series left, got %q", summaries(events))
+	}
+
+	if err := c.DeleteOccurrences(ctx, c.CalendarID(), "standup", gcal.SeriesScope{ApplyTo: gcal.ApplyToThisEvent}); err == nil {
+		t.Error("expected a series ID without an occurrence date to be rejected")
+	}
+	date := "2026-03-30"
+	if _, err := c.UpdateEvent(ctx, "standup_20260316T090000Z", gcal.EventUpdates{Date: &date, Scope: gcal.SeriesScope{ApplyTo: gcal.ApplyToAll}}); err == nil {
+		t.Error("expected a new date for all occurrences to be rejected")
+	}
+
+	// Occurrences of other calendars are deleted there
+	if err := c.Add(Event{ID: "handover", Calendar: "oncall@example.com", Summary: "On-call handover", Start: "2026-03-16T14:00:00+01:00", End: "2026-03-16T14:30:00+01:00", Recurrence: []string{"RRULE:FREQ=WEEKLY;COUNT=3"}}); err != nil {
+		t.Fatal(err)
+	}
+	scope = gcal.SeriesScope{ApplyTo: gcal.ApplyToThisAndFollowing, Occurrence: "2026-03-23"}
+	if err := c.DeleteOccurrences(ctx, "oncall@example.com", "handover", scope); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := c.ListCalendarEvents(ctx, "oncall@example.com", "2026-03-16", "2026-04-10"); len(events) != 1 {
+		t.Errorf("expected only the first handover left, got %q", summaries(events))
+	}
+}
+
+func TestCreateAndRespond(t *testing.T) {
+	c := loadWeek(t)
+	ctx := context.Background()
+
+	created, err := c.CreateEvent(ctx, "1:1", "", "2026-03-17", "14:00", "14:30", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Start.DateTime != "2026-03-17T14:00:00+01:00" {
+		t.Errorf("expected the time in the calendar's timezone, got %s", created.Start.DateTime)
+	}
+	if _, err := c.CreateEvent(ctx, "Backwards", "", "2026-03-17", "15:00", "14:00", false); err == nil {
+		t.Error("expected an event ending before it starts to be rejected")
+	}
+
+	if err := c.RespondToEvent(ctx, "review", "accepted", ""); err != nil {
+		t.Fatal(err)
+	}
+	if event, _ := c.GetEvent(ctx, "review"); event.Attendees[0].ResponseStatus != "accepted" {
+		t.Errorf("expected the response to be recorded, got %+v", event.Attendees[0])
+	}
+
+	added, err := c.QuickAddEvent(ctx, "me@example.com", "Lunch with Sam 2026-03-20 13:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.Summary != "Lunch with Sam" || added.End.DateTime != "2026-03-20T14:00:00+01:00" {
+		t.Errorf("expected a one-hour event, got %+v", added)
+	}
+	if _, err := c.QuickAddEvent(ctx, "me@example.com", "Lunch with Sam Friday 1pm"); err == nil {
+		t.Error("expected text without a date and time to be rejected")
+	}
+
+	failure := errors.New("backend down")
+	c.Fail("GetEvent", failure)
+	if _, err := c.GetEvent(ctx, "review"); err != failure {
+		t.Errorf("expected the injected failure, got %v", err)
+	}
+	c.Fail("GetEvent", nil)
+	if _, err := c.GetEvent(ctx, "review"); err != nil {
+		t.Errorf("expected the failure to be cleared, got %v", err)
+	}
+}
+
+func TestRecurrence(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	for _, tc := range []struct {
+		rule []string
+		want []string
+	}{
+		{[]string{"RRULE:FREQ=DAILY;COUNT=3"}, []string{"2026-03-27", "2026-03-28", "2026-03-29"}},
+		{[]string{"RRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20260425T000000Z"}, []string{"2026-03-27", "2026-04-10", "2026-04-24"}},
+		{[]string{"RRULE:FREQ=MONTHLY;COUNT=2"}, []string{"2026-03-27", "2026-04-27"}},
+		{[]string{"RRULE:FREQ=DAILY;COUNT=3", "EXDATE;TZID=Europe/Berlin:20260328T090000"}, []string{"2026-03-27", "2026-03-29"}},
+	} {
+		rec, err := parseRecurrence(tc.rule, loc)
+		if err != nil {
+			t.Fatalf("%q: %v", tc.rule, err)
+		}
+		var got []string
+		for _, start := range rec.starts(time.Date(2026, 3, 27, 9, 0, 0, 0, loc), time.Date(2027, 1, 1, 0, 0, 0, 0, loc)) {
+			// Series keep their time of day across the DST change
+			if start.Hour() != 9 {
+				t.Errorf("%q: expected 09:00, got %s", tc.rule, start)
+			}
+			got = append(got, start.Format("2006-01-02"))
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: got %q, want %q", tc.rule, got, tc.want)
+		}
+	}
+
+	if _, err := parseRecurrence([]string{"RRULE:FREQ=MONTHLY;BYDAY=1MO"}, loc); err == nil {
+		t.Error("expected unsupported rules to be rejected")
+	}
+}
@@ -0,0 +1,208 @@
+package calendartest
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences bounds the expansion of series without COUNT or UNTIL
+const maxOccurrences = 5000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// rrule is the part of an RFC 5545 recurrence rule the fake expands: FREQ
+// of DAILY, WEEKLY, MONTHLY or YEARLY with INTERVAL, COUNT, UNTIL and, for
+// weekly series, BYDAY without ordinals
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// recurrence is the rule and the excluded starts of a series
+type recurrence struct {
+	rule    rrule
+	exdates map[int64]bool
+}
+
+// parseRecurrence reads the recurrence lines of an event as the Calendar
+// API stores them: one RRULE and any number of EXDATE lines
+func parseRecurrence(lines []string, loc *time.Location) (*recurrence, error) {
+	r := &recurrence{exdates: make(map[int64]bool)}
+	found := false
+	for _, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence line %q", line)
+		}
+		name, params, _ := strings.Cut(name, ";")
+		switch name {
+		case "RRULE":
+			rule, err := parseRRule(value, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.rule, found = rule, true
+		case "EXDATE":
+			exLoc := loc
+			if tzid, ok := strings.CutPrefix(params, "TZID="); ok {
+				l, err := time.LoadLocation(tzid)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE timezone %q", tzid)
+				}
+				exLoc = l
+			}
+			for _, v := range strings.Split(value, ",") {
+				t, err := parseICalTime(v, exLoc)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE %q", v)
+				}
+				r.exdates[t.Unix()] = true
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence line %q", line)
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("recurrence has no RRULE")
+	}
+	return r, nil
+}
+
+func parseRRule(value string, loc *time.Location) (rrule, error) {
+	rule := rrule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, v, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			switch v {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.freq = v
+			default:
+				return rrule{}, fmt.Errorf("unsupported FREQ %q", v)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return rrule{}, fmt.Errorf("invalid INTERVAL %q", v)
+			}
+			rule.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return rrule{}, fmt.Errorf("invalid COUNT %q", v)
+			}
+			rule.count = n
+		case "UNTIL":
+			t, err := parseICalTime(v, loc)
+			if err != nil {
+				return rrule{}, fmt.Errorf("invalid UNTIL %q", v)
+			}
+			rule.until = t
+		case "BYDAY":
+			for _, day := range strings.Split(v, ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return rrule{}, fmt.Errorf("unsupported BYDAY %q", day)
+				}
+				rule.byDay = append(rule.byDay, wd)
+			}
+		case "WKST":
+			// Weeks always start on Monday
+		default:
+			return rrule{}, fmt.Errorf("unsupported RRULE part %q", part)
+		}
+	}
+	if rule.freq == "" {
+		return rrule{}, fmt.Errorf("RRULE %q has no FREQ", value)
+	}
+	if len(rule.byDay) > 0 && rule.freq != "WEEKLY" {
+		return rrule{}, fmt.Errorf("BYDAY is only supported for weekly series")
+	}
+	return rule, nil
+}
+
+// parseICalTime reads an iCalendar DATE or DATE-TIME value; floating times
+// are in loc
+func parseICalTime(v string, loc *time.Location) (time.Time, error) {
+	switch {
+	case strings.HasSuffix(v, "Z"):
+		return time.Parse("20060102T150405Z", v)
+	case len(v) == len("20060102"):
+		return time.ParseInLocation("20060102", v, loc)
+	}
+	return time.ParseInLocation("20060102T150405", v, loc)
+}
+
+// starts returns the starts of the series beginning at dtstart, up to and
+// excluding limit. Excluded dates are left out but count towards COUNT.
+func (r *recurrence) starts(dtstart, limit time.Time) []time.Time {
+	var result []time.Time
+	generated := 0
+	emit := func(t time.Time) bool {
+		if !r.rule.until.IsZero() && t.After(r.rule.until) || !t.Before(limit) {
+			return false
+		}
+		if r.rule.count > 0 && generated >= r.rule.count {
+			return false
+		}
+		generated++
+		if !r.exdates[t.Unix()] {
+			result = append(result, t)
+		}
+		return generated < maxOccurrences
+	}
+
+	for period := 0; ; period++ {
+		n := period * r.rule.interval
+		switch r.rule.freq {
+		case "DAILY":
+			if !emit(dtstart.AddDate(0, 0, n)) {
+				return result
+			}
+		case "WEEKLY":
+			if len(r.rule.byDay) == 0 {
+				if !emit(dtstart.AddDate(0, 0, 7*n)) {
+					return result
+				}
+				continue
+			}
+			// Weeks start on Monday
+			monday := dtstart.AddDate(0, 0, -((int(dtstart.Weekday())+6)%7)+7*n)
+			for offset := 0; offset < 7; offset++ {
+				t := monday.AddDate(0, 0, offset)
+				if t.Before(dtstart) || !slices.Contains(r.rule.byDay, t.Weekday()) {
+					continue
+				}
+				if !emit(t) {
+					return result
+				}
+			}
+		case "MONTHLY", "YEARLY":
+			months := n
+			if r.rule.freq == "YEARLY" {
+				months = 12 * n
+			}
+			t := dtstart.AddDate(0, months, 0)
+			// Months without the day of the series, like 31 April, are
+			// skipped
+			if t.Day() != dtstart.Day() {
+				if period > maxOccurrences {
+					return result
+				}
+				continue
+			}
+			if !emit(t) {
+				return result
+			}
+		}
+	}
+}
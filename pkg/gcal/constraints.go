@@ -0,0 +1,284 @@
+package gcal
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	anchorSunrise = "sunrise"
+	anchorSunset  = "sunset"
+	anchorNoon    = "noon"
+)
+
+// geoLocation is a point on Earth in decimal degrees, north and east
+// positive
+type geoLocation struct {
+	lat, lon float64
+}
+
+// blockedWindow is a recurring daily time range in which no meetings should
+// be scheduled. It starts either at a fixed time of day (anchor is empty and
+// offset is the time since midnight) or relative to a solar event.
+type blockedWindow struct {
+	spec   string
+	anchor string
+	offset time.Duration
+	length time.Duration
+}
+
+// scheduleConstraints are the time-of-day rules that event creation and
+// updates respect unless forced
+type scheduleConstraints struct {
+	location         *geoLocation
+	notBeforeSunrise bool
+	windows          []blockedWindow
+}
+
+// timeRange is a half-open [start, end) interval
+type timeRange struct {
+	start, end time.Time
+	reason     string
+}
+
+// scheduleConstraintsFromEnv reads CALENDAR_LOCATION,
+// CALENDAR_NOT_BEFORE_SUNRISE and CALENDAR_BLOCKED_WINDOWS. It returns nil
+// when no constraints are configured.
+func scheduleConstraintsFromEnv() (*scheduleConstraints, error) {
+	c := &scheduleConstraints{
+		notBeforeSunrise: os.Getenv("CALENDAR_NOT_BEFORE_SUNRISE") == "true",
+	}
+	if v := os.Getenv("CALENDAR_LOCATION"); v != "" {
+		location, err := parseLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("CALENDAR_LOCATION: %w", err)
+		}
+		c.location = location
+	}
+	if v := os.Getenv("CALENDAR_BLOCKED_WINDOWS"); v != "" {
+		windows, err := parseBlockedWindows(v)
+		if err != nil {
+			return nil, fmt.Errorf("CALENDAR_BLOCKED_WINDOWS: %w", err)
+		}
+		c.windows = windows
+	}
+
+	if !c.notBeforeSunrise && len(c.windows) == 0 {
+		return nil, nil
+	}
+	if c.needsLocation() && c.location == nil {
+		return nil, fmt.Errorf("CALENDAR_LOCATION is required for sunrise, sunset and noon rules")
+	}
+	return c, nil
+}
+
+func parseLocation(spec string) (*geoLocation, error) {
+	parts := strings.Split(spec, ",")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected latitude,longitude, got %q", spec)
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("invalid latitude %q", parts[0])
+	}
+	lon, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return nil, fmt.Errorf("invalid longitude %q", parts[1])
+	}
+	return &geoLocation{lat: lat, lon: lon}, nil
+}
+
+// parseBlockedWindows parses a comma-separated list of windows, each written
+// as start/length. The start is a time of day (13:00) or a solar event with
+// an optional offset (sunrise, sunset+10m, noon-15m).
+func parseBlockedWindows(spec string) ([]blockedWindow, error) {
+	var windows []blockedWindow
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		startSpec, lengthSpec, found := strings.Cut(item, "/")
+		if !found {
+			return nil, fmt.Errorf("window %q: expected start/length, e.g. sunset/20m", item)
+		}
+		length, err := time.ParseDuration(lengthSpec)
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("window %q: invalid length %q", item, lengthSpec)
+		}
+
+		w := blockedWindow{spec: item, length: length}
+		if t, err := time.Parse("15:04", startSpec); err == nil {
+			w.offset = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+		} else {
+			w.anchor, w.offset, err = parseSolarAnchor(startSpec)
+			if err != nil {
+				return nil, fmt.Errorf("window %q: %w", item, err)
+			}
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func parseSolarAnchor(spec string) (string, time.Duration, error) {
+	for _, anchor := range []string{anchorSunrise, anchorSunset, anchorNoon} {
+		rest, found := strings.CutPrefix(spec, anchor)
+		if !found {
+			continue
+		}
+		if rest == "" {
+			return anchor, 0, nil
+		}
+		if rest[0] != '+' && rest[0] != '-' {
+			break
+		}
+		offset, err := time.ParseDuration(rest)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid offset %q", rest)
+		}
+		return anchor, offset, nil
+	}
+	return "", 0, fmt.Errorf("invalid start %q: expected HH:MM, sunrise, sunset or noon", spec)
+}
+
+// needsLocation reports whether any rule depends on the position of the sun
+func (c *scheduleConstraints) needsLocation() bool {
+	if c.notBeforeSunrise {
+		return true
+	}
+	for _, w := range c.windows {
+		if w.anchor != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// blockedRanges returns the times on the given day, in its location, that
+// events must not overlap
+func (c *scheduleConstraints) blockedRanges(day time.Time) []timeRange {
+	loc := day.Location()
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+
+	var sun solarDay
+	if c.location != nil {
+		sun = solarTimes(midnight, *c.location)
+	}
+
+	var ranges []timeRange
+	if c.notBeforeSunrise && sun.ok {
+		ranges = append(ranges, timeRange{
+			start:  midnight,
+			end:    sun.sunrise,
+			reason: "before sunrise at " + sun.sunrise.Format("15:04"),
+		})
+	}
+	for _, w := range c.windows {
+		var start time.Time
+		switch w.anchor {
+		case "":
+			start = midnight.Add(w.offset)
+		case anchorNoon:
+			if c.location == nil {
+				continue
+			}
+			start = sun.noon.Add(w.offset)
+		default:
+			// No sunrise or sunset during polar day and night
+			if !sun.ok {
+				continue
+			}
+			if w.anchor == anchorSunrise {
+				start = sun.sunrise.Add(w.offset)
+			} else {
+				start = sun.sunset.Add(w.offset)
+			}
+		}
+		end := start.Add(w.length)
+		ranges = append(ranges, timeRange{
+			start:  start,
+			end:    end,
+			reason: fmt.Sprintf("blocked window %s (%s-%s)", w.spec, start.Format("15:04"), end.Format("15:04")),
+		})
+	}
+	return ranges
+}
+
+// check rejects an event that overlaps any blocked range on the days it
+// touches
+func (c *scheduleConstraints) check(start, end time.Time) error {
+	if c == nil {
+		return nil
+	}
+	last := end.Format("2006-01-02")
+	for day := start; day.Format("2006-01-02") <= last; day = day.AddDate(0, 0, 1) {
+		for _, r := range c.blockedRanges(day) {
+			if start.Before(r.end) && end.After(r.start) {
+				return invalidInputf("event %s-%s falls %s; pass force=true if this is intended", start.Format("15:04"), end.Format("15:04"), r.reason)
+			}
+		}
+	}
+	return nil
+}
+
+type solarDay struct {
+	noon, sunrise, sunset time.Time
+	// ok is false when the sun does not rise or set on that day
+	ok bool
+}
+
+// solarTimes computes solar noon, sunrise and sunset for the date of day at
+// the given location using the standard sunrise equation, which is accurate
+// to about a minute outside polar regions. Results are in day's location.
+func solarTimes(day time.Time, at geoLocation) solarDay {
+	const j2000 = 2451545.0
+
+	jdn := julianDayOf(day)
+	jStar := float64(jdn) - j2000 + 0.0008 - at.lon/360
+
+	meanAnomaly := math.Mod(357.5291+0.98560028*jStar, 360)
+	m := radians(meanAnomaly)
+	center := 1.9148*math.Sin(m) + 0.0200*math.Sin(2*m) + 0.0003*math.Sin(3*m)
+	lambda := radians(math.Mod(meanAnomaly+center+180+102.9372, 360))
+	transit := j2000 + jStar + 0.0053*math.Sin(m) - 0.0069*math.Sin(2*lambda)
+
+	sinDecl := math.Sin(lambda) * math.Sin(radians(23.4397))
+	cosDecl := math.Cos(math.Asin(sinDecl))
+	phi := radians(at.lat)
+	cosHour := (math.Sin(radians(-0.833)) - math.Sin(phi)*sinDecl) / (math.Cos(phi) * cosDecl)
+
+	loc := day.Location()
+	result := solarDay{noon: julianToTime(transit).In(loc)}
+	if cosHour < -1 || cosHour > 1 {
+		return result
+	}
+
+	hourAngle := math.Acos(cosHour) * 180 / math.Pi
+	result.sunrise = julianToTime(transit - hourAngle/360).In(loc)
+	result.sunset = julianToTime(transit + hourAngle/360).In(loc)
+	result.ok = true
+	return result
+}
+
+// julianDayOf returns the Julian Day Number of the calendar date of t
+func julianDayOf(t time.Time) int {
+	const unixEpochDay = 2440588
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Unix()/86400) + unixEpochDay
+}
+
+func julianToTime(jd float64) time.Time {
+	const unixEpoch = 2440587.5
+	seconds := (jd - unixEpoch) * 86400
+	return time.Unix(0, int64(seconds*1e9)).Truncate(time.Minute)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
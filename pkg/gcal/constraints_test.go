@@ -0,0 +1,130 @@
+package gcal
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSolarTimes_London(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	day := time.Date(2026, 6, 21, 0, 0, 0, 0, loc)
+	sun := solarTimes(day, geoLocation{lat: 51.5074, lon: -0.1278})
+	if !sun.ok {
+		t.Fatal("expected sunrise and sunset in London")
+	}
+
+	// Published times for the summer solstice: sunrise 04:43, sunset 21:21
+	assertNear(t, "sunrise", sun.sunrise, time.Date(2026, 6, 21, 4, 43, 0, 0, loc))
+	assertNear(t, "sunset", sun.sunset, time.Date(2026, 6, 21, 21, 21, 0, 0, loc))
+	assertNear(t, "noon", sun.noon, time.Date(2026, 6, 21, 13, 2, 0, 0, loc))
+}
+
+func TestSolarTimes_PolarNight(t *testing.T) {
+	day := time.Date(2026, 12, 21, 0, 0, 0, 0, time.UTC)
+	if sun := solarTimes(day, geoLocation{lat: 78.22, lon: 15.65}); sun.ok {
+		t.Errorf("expected no sunrise in Svalbard in December, got %v", sun.sunrise)
+	}
+}
+
+func assertNear(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if d := got.Sub(want); d < -3*time.Minute || d > 3*time.Minute {
+		t.Errorf("%s: got %s, want about %s", name, got.Format("15:04"), want.Format("15:04"))
+	}
+}
+
+func TestParseBlockedWindows(t *testing.T) {
+	windows, err := parseBlockedWindows("13:00/30m, sunset-5m/20m,sunrise/1h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(windows) != 3 {
+		t.Fatalf("expected 3 windows, got %d", len(windows))
+	}
+	if windows[0].anchor != "" || windows[0].offset != 13*time.Hour || windows[0].length != 30*time.Minute {
+		t.Errorf("unexpected fixed window %+v", windows[0])
+	}
+	if windows[1].anchor != anchorSunset || windows[1].offset != -5*time.Minute {
+		t.Errorf("unexpected sunset window %+v", windows[1])
+	}
+
+	for _, bad := range []string{"13:00", "dusk/10m", "sunset*5m/10m", "13:00/-5m"} {
+		if _, err := parseBlockedWindows(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	got, err := parseLocation("51.5074, -0.1278")
+	if err != nil || got.lat != 51.5074 || got.lon != -0.1278 {
+		t.Errorf("unexpected result %+v, %v", got, err)
+	}
+	for _, bad := range []string{"51.5", "91,0", "0,181", "north,east"} {
+		if _, err := parseLocation(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestScheduleConstraints_Check(t *testing.T) {
+	windows, _ := parseBlockedWindows("13:00/30m,sunset/20m")
+	c := &scheduleConstraints{
+		location:         &geoLocation{lat: 51.5074, lon: -0.1278},
+		notBeforeSunrise: true,
+		windows:          windows,
+	}
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 20, hour, minute, 0, 0, time.UTC)
+	}
+
+	if err := c.check(at(10, 0), at(11, 0)); err != nil {
+		t.Errorf("expected mid-morning event to pass, got %v", err)
+	}
+	if err := c.check(at(13, 30), at(14, 0)); err != nil {
+		t.Errorf("expected event right after the window to pass, got %v", err)
+	}
+
+	err := c.check(at(5, 0), at(6, 0))
+	if err == nil || !strings.Contains(err.Error(), "before sunrise") {
+		t.Errorf("expected sunrise error, got %v", err)
+	}
+	err = c.check(at(12, 45), at(13, 15))
+	if err == nil || !strings.Contains(err.Error(), "13:00/30m") {
+		t.Errorf("expected blocked window error, got %v", err)
+	}
+	// Sunset in London around the equinox is about 18:13 UTC
+	err = c.check(at(18, 0), at(18, 30))
+	if err == nil || !strings.Contains(err.Error(), "sunset/20m") {
+		t.Errorf("expected sunset window error, got %v", err)
+	}
+	// An overnight event runs into the next morning before sunrise
+	err = c.check(at(22, 0), at(22, 0).Add(8*time.Hour))
+	if err == nil || !strings.Contains(err.Error(), "before sunrise") {
+		t.Errorf("expected overnight event to hit the next sunrise, got %v", err)
+	}
+
+	var none *scheduleConstraints
+	if err := none.check(at(3, 0), at(4, 0)); err != nil {
+		t.Errorf("nil constraints should allow everything, got %v", err)
+	}
+}
+
+func TestCheckSchedule(t *testing.T) {
+	windows, _ := parseBlockedWindows("12:00/1h")
+	c := &CalendarClient{timezone: "Europe/Berlin", constraints: &scheduleConstraints{windows: windows}}
+
+	// Windows are times of day in the calendar's timezone, 11:00 UTC here
+	if err := c.CheckSchedule(context.Background(), time.Date(2026, 3, 20, 11, 0, 0, 0, time.UTC), time.Date(2026, 3, 20, 11, 30, 0, 0, time.UTC)); err == nil {
+		t.Error("expected the blocked window to be refused")
+	}
+	if err := c.CheckSchedule(context.Background(), time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC), time.Date(2026, 3, 20, 13, 0, 0, 0, time.UTC)); err != nil {
+		t.Errorf("expected the time after the window to pass, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package gcal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/api/option"
+)
+
+// credentials describes a credentials file
+type credentials struct {
+	credType option.CredentialsType
+	// serviceAccount is the email of the service account the client acts
+	// as, which calendars have to be shared with; empty when the file
+	// doesn't name one
+	serviceAccount string
+	// tokens hands out the same tokens as the client option, for
+	// AuthStatus
+	tokens oauth2.TokenSource
+}
+
+// credentialsFileOption returns the client option authenticating with the
+// credentials in path: a service account key, or an external account
+// configuration for workload identity federation, which exchanges a token
+// of the platform the server runs on (AWS, GitHub Actions OIDC, Azure, ...)
+// for Google credentials without a long-lived key. The file has to come
+// from the operator: an external account configuration names the URLs its
+// tokens are fetched from. Changes to the file, like a rotated key, are
+// picked up while the client runs.
+func credentialsFileOption(path string) (option.ClientOption, credentials, error) {
+	ts, creds, err := newReloadingTokenSource(path)
+	if err != nil {
+		return nil, credentials{}, err
+	}
+	return option.WithTokenSource(ts), creds, nil
+}
+
+func parseCredentials(data []byte) (credentials, error) {
+	var file struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		// ImpersonationURL names the service account external and
+		// impersonated credentials act as, e.g.
+		// .../serviceAccounts/calendar@project.iam.gserviceaccount.com:generateAccessToken
+		ImpersonationURL string `json:"service_account_impersonation_url"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return credentials{}, fmt.Errorf("invalid JSON: %w", err)
+	}
+	creds := credentials{credType: option.CredentialsType(file.Type), serviceAccount: file.ClientEmail}
+	if _, account, ok := strings.Cut(file.ImpersonationURL, "/serviceAccounts/"); ok && creds.serviceAccount == "" {
+		creds.serviceAccount, _, _ = strings.Cut(account, ":")
+	}
+
+	switch creds.credType {
+	case option.ServiceAccount, option.ExternalAccount, option.ImpersonatedServiceAccount:
+		return creds, nil
+	case option.AuthorizedUser:
+		return credentials{}, fmt.Errorf("user credentials are not supported here; use an OAuth client with GOOGLE_OAUTH_TOKEN_FILE instead")
+	case "":
+		return credentials{}, fmt.Errorf("no credentials type; expected a service account key or an external account configuration")
+	default:
+		return credentials{}, fmt.Errorf("unsupported credentials type %q", file.Type)
+	}
+}
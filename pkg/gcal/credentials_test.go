@@ -0,0 +1,56 @@
+package gcal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+// externalAccountConfig is a workload identity federation configuration
+// that reads the subject token from a file, as GitHub Actions or Kubernetes
+// deployments do
+const externalAccountConfig = `{
+  "type": "external_account",
+  "audience": "//iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/ci/providers/github",
+  "subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+  "token_url": "https://sts.googleapis.com/v1/token",
+  "service_account_impersonation_url": "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/calendar@ci.iam.gserviceaccount.com:generateAccessToken",
+  "credential_source": {"file": "/var/run/secrets/token"}
+}`
+
+func TestParseCredentials(t *testing.T) {
+	for data, want := range map[string]credentials{
+		`{"type":"service_account","client_email":"sa@p.iam.gserviceaccount.com"}`: {credType: option.ServiceAccount, serviceAccount: "sa@p.iam.gserviceaccount.com"},
+		externalAccountConfig:                     {credType: option.ExternalAccount, serviceAccount: "calendar@ci.iam.gserviceaccount.com"},
+		`{"type":"impersonated_service_account"}`: {credType: option.ImpersonatedServiceAccount},
+	} {
+		if got, err := parseCredentials([]byte(data)); err != nil || got != want {
+			t.Errorf("expected %+v, got %+v, %v", want, got, err)
+		}
+	}
+
+	for data, want := range map[string]string{
+		`{"type":"authorized_user"}`: "GOOGLE_OAUTH_TOKEN_FILE",
+		`{}`:                         "no credentials type",
+		`{"type":"gdch"}`:            `unsupported credentials type "gdch"`,
+		`not json`:                   "invalid JSON",
+	} {
+		if _, err := parseCredentials([]byte(data)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected an error containing %q, got %v", data, want, err)
+		}
+	}
+}
+
+func TestNewCalendarClient_ExternalAccount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "federation.json")
+	os.WriteFile(path, []byte(externalAccountConfig), 0o600)
+
+	// No token is fetched until the first request
+	client, err := NewCalendarClient(path, "me@example.com", "")
+	if err != nil || client.CalendarID() != "me@example.com" {
+		t.Fatalf("expected a client, got %v", err)
+	}
+}
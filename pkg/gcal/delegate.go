@@ -0,0 +1,223 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	// defaultDelegateSourceURL is where the source of labeled events points
+	// unless CALENDAR_DELEGATE_URL is set
+	defaultDelegateSourceURL = "https://github.com/cherya/google-calendar-mcp"
+
+	// Private extended properties recording the last change the assistant
+	// made to an event
+	delegatedByKey     = "delegatedBy"
+	delegatedActionKey = "delegatedAction"
+	delegatedAtKey     = "delegatedAt"
+	// The MCP request that made the change, see RequestInfo
+	delegatedSessionKey = "delegatedSession"
+	delegatedRequestKey = "delegatedRequest"
+	delegatedTraceKey   = "delegatedTrace"
+
+	actionCreated   = "created"
+	actionUpdated   = "updated"
+	actionDeleted   = "deleted"
+	actionResponded = "responded"
+)
+
+// delegation labels the changes an assistant makes on someone else's
+// calendar, so that the owner and the other guests can tell them apart
+type delegation struct {
+	// label names the assistant, e.g. "Sam's assistant"
+	label     string
+	sourceURL string
+}
+
+// delegationFromEnv reads CALENDAR_DELEGATE_LABEL and CALENDAR_DELEGATE_URL.
+// It returns nil when delegated mode is off.
+func delegationFromEnv() (*delegation, error) {
+	label := strings.TrimSpace(os.Getenv("CALENDAR_DELEGATE_LABEL"))
+	if label == "" {
+		return nil, nil
+	}
+	d := &delegation{label: label, sourceURL: defaultDelegateSourceURL}
+	if v := os.Getenv("CALENDAR_DELEGATE_URL"); v != "" {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("CALENDAR_DELEGATE_URL must be an http or https URL, got %q", v)
+		}
+		d.sourceURL = v
+	}
+	return d, nil
+}
+
+// tag records action in the private properties of e, which is how
+// delegated_actions finds the event again
+func (d *delegation) tag(ctx context.Context, e *calendar.Event, action string, now time.Time) {
+	if d == nil {
+		return
+	}
+	if e.ExtendedProperties == nil {
+		e.ExtendedProperties = &calendar.EventExtendedProperties{}
+	}
+	if e.ExtendedProperties.Private == nil {
+		e.ExtendedProperties.Private = make(map[string]string)
+	}
+	e.ExtendedProperties.Private[delegatedByKey] = d.label
+	e.ExtendedProperties.Private[delegatedActionKey] = action
+	e.ExtendedProperties.Private[delegatedAtKey] = now.UTC().Format(time.RFC3339)
+
+	info := RequestInfoFrom(ctx)
+	for key, value := range map[string]string{
+		delegatedSessionKey: info.SessionID,
+		delegatedRequestKey: info.RequestID,
+		delegatedTraceKey:   info.TraceID,
+	} {
+		if value == "" {
+			// Don't leave the request of an earlier change behind
+			delete(e.ExtendedProperties.Private, key)
+			continue
+		}
+		e.ExtendedProperties.Private[key] = value
+	}
+}
+
+// labelEvent tags an event the assistant creates or edits and adds a
+// footer to its description. Only the creator of an event can set its
+// source, so that is done for new events only.
+func (d *delegation) labelEvent(ctx context.Context, e *calendar.Event, action, owner string, now time.Time) {
+	if d == nil {
+		return
+	}
+	d.tag(ctx, e, action, now)
+	e.Description = withDelegateFooter(e.Description, d.footer(action, owner))
+	if action == actionCreated {
+		e.Source = &calendar.EventSource{Title: d.label, Url: sourceURL(d.sourceURL, RequestInfoFrom(ctx))}
+	}
+}
+
+// sourceURL adds the request that created an event to the fragment of its
+// source URL, where it reaches nobody but whoever looks for it. URLs with
+// a fragment of their own are left alone.
+func sourceURL(base string, info RequestInfo) string {
+	u, err := url.Parse(base)
+	if err != nil || u.Fragment != "" || info.RequestID == "" {
+		return base
+	}
+	q := url.Values{}
+	q.Set("session", info.SessionID)
+	q.Set("request", info.RequestID)
+	if info.TraceID != "" {
+		q.Set("trace", info.TraceID)
+	}
+	u.Fragment = q.Encode()
+	return u.String()
+}
+
+// responseComment labels the comment sent with an invitation response
+func (d *delegation) responseComment(comment, owner string) string {
+	if d == nil {
+		return comment
+	}
+	note := fmt.Sprintf("(sent by %s on behalf of %s)", d.label, owner)
+	if comment == "" {
+		return note
+	}
+	return comment + " " + note
+}
+
+func (d *delegation) footer(action, owner string) string {
+	verb := "Updated"
+	if action == actionCreated {
+		verb = "Created"
+	}
+	return fmt.Sprintf("— %s by %s on behalf of %s", verb, d.label, owner)
+}
+
+// withDelegateFooter replaces the footer of an earlier change, if any, so
+// that repeated edits don't stack footers
+func withDelegateFooter(description, footer string) string {
+	description = strings.TrimRight(stripDelegateFooter(description), "\n ")
+	if description == "" {
+		return footer
+	}
+	return description + "\n\n" + footer
+}
+
+func stripDelegateFooter(description string) string {
+	start := strings.LastIndex(description, "\n\n— ")
+	tail := description
+	if start >= 0 {
+		tail = description[start+2:]
+	} else if !strings.HasPrefix(description, "— ") {
+		return description
+	}
+	if strings.Contains(tail, "\n") || !strings.Contains(tail, " on behalf of ") {
+		return description
+	}
+	if start < 0 {
+		return ""
+	}
+	return description[:start]
+}
+
+// DelegatedAction is the last change the assistant made to an event
+type DelegatedAction struct {
+	EventID string `json:"eventId"`
+	Summary string `json:"summary"`
+	Start   string `json:"start,omitempty"`
+	// Action is created, updated, deleted or responded
+	Action string `json:"action"`
+	At     string `json:"at"`
+	// SessionID, RequestID and TraceID identify the MCP request that made
+	// the change, when it was recorded
+	SessionID string `json:"sessionId,omitempty"`
+	RequestID string `json:"requestId,omitempty"`
+	TraceID   string `json:"traceId,omitempty"`
+}
+
+// ListDelegatedActions returns the events the assistant changed since the
+// given time, deleted ones included, most recent first
+func (c *CalendarClient) ListDelegatedActions(ctx context.Context, since time.Time) ([]DelegatedAction, error) {
+	if c.delegate == nil {
+		return nil, invalidInputf("delegated mode is off; set CALENDAR_DELEGATE_LABEL to label changes made on behalf of the calendar owner")
+	}
+
+	result := []DelegatedAction{}
+	err := c.serviceFor(c.calendarID).Events.List(c.calendarID).
+		PrivateExtendedProperty(delegatedByKey+"="+c.delegate.label).
+		ShowDeleted(true).
+		UpdatedMin(since.Format(time.RFC3339)).
+		Pages(ctx, func(page *calendar.Events) error {
+			for _, e := range page.Items {
+				var props map[string]string
+				if e.ExtendedProperties != nil {
+					props = e.ExtendedProperties.Private
+				}
+				result = append(result, DelegatedAction{
+					EventID:   e.Id,
+					Summary:   e.Summary,
+					Start:     EventTime(e.Start),
+					Action:    props[delegatedActionKey],
+					At:        props[delegatedAtKey],
+					SessionID: props[delegatedSessionKey],
+					RequestID: props[delegatedRequestKey],
+					TraceID:   props[delegatedTraceKey],
+				})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].At > result[j].At })
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package gcal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestDelegationFromEnv(t *testing.T) {
+	t.Setenv("CALENDAR_DELEGATE_LABEL", "")
+	if d, err := delegationFromEnv(); d != nil || err != nil {
+		t.Errorf("expected delegated mode off, got %+v, %v", d, err)
+	}
+
+	t.Setenv("CALENDAR_DELEGATE_LABEL", "Sam's assistant")
+	d, err := delegationFromEnv()
+	if err != nil || d.label != "Sam's assistant" || d.sourceURL != defaultDelegateSourceURL {
+		t.Errorf("unexpected delegation %+v, %v", d, err)
+	}
+
+	t.Setenv("CALENDAR_DELEGATE_URL", "mailto:sam@example.com")
+	if _, err := delegationFromEnv(); err == nil {
+		t.Error("expected an error for a non-http source URL")
+	}
+}
+
+func TestDelegationLabelEvent(t *testing.T) {
+	d := &delegation{label: "Sam's assistant", sourceURL: defaultDelegateSourceURL}
+	now := time.Date(2026, 3, 19, 10, 0, 0, 0, time.UTC)
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{SessionID: "s1", RequestID: "7", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"})
+	e := &calendar.Event{Description: "Agenda"}
+	d.labelEvent(ctx, e, actionCreated, "sam@example.com", now)
+	if e.Description != "Agenda\n\n— Created by Sam's assistant on behalf of sam@example.com" {
+		t.Errorf("unexpected description %q", e.Description)
+	}
+	if e.Source == nil || e.Source.Title != "Sam's assistant" {
+		t.Errorf("expected the source to name the assistant, got %+v", e.Source)
+	}
+	if want := defaultDelegateSourceURL + "#request=7&session=s1&trace=4bf92f3577b34da6a3ce929d0e0e4736"; e.Source.Url != want {
+		t.Errorf("expected the request in the source URL %s, got %s", want, e.Source.Url)
+	}
+	props := e.ExtendedProperties.Private
+	if props[delegatedByKey] != "Sam's assistant" || props[delegatedActionKey] != actionCreated || props[delegatedAtKey] != "2026-03-19T10:00:00Z" {
+		t.Errorf("unexpected properties %v", props)
+	}
+	if props[delegatedSessionKey] != "s1" || props[delegatedRequestKey] != "7" || props[delegatedTraceKey] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the request in the properties, got %v", props)
+	}
+
+	// A later edit replaces the footer rather than adding another one, and
+	// the request of the earlier change
+	d.labelEvent(WithRequestInfo(context.Background(), RequestInfo{SessionID: "s2", RequestID: "9"}), e, actionUpdated, "sam@example.com", now)
+	if e.Description != "Agenda\n\n— Updated by Sam's assistant on behalf of sam@example.com" {
+		t.Errorf("unexpected description %q", e.Description)
+	}
+	if _, ok := props[delegatedTraceKey]; ok || props[delegatedRequestKey] != "9" {
+		t.Errorf("expected the properties of the later request, got %v", props)
+	}
+
+	var off *delegation
+	untouched := &calendar.Event{Description: "Agenda"}
+	off.labelEvent(ctx, untouched, actionCreated, "sam@example.com", now)
+	if untouched.Description != "Agenda" || untouched.ExtendedProperties != nil {
+		t.Errorf("expected no labels with delegated mode off, got %+v", untouched)
+	}
+}
+
+func TestWithDelegateFooter(t *testing.T) {
+	footer := "— Updated by A on behalf of b@example.com"
+	tests := []struct {
+		description, want string
+	}{
+		{"", footer},
+		{"— Created by A on behalf of b@example.com", footer},
+		{"Notes\n\n— Created by A on behalf of b@example.com", "Notes\n\n" + footer},
+		// Dashes written by people are left alone
+		{"Notes\n\n— see the doc", "Notes\n\n— see the doc\n\n" + footer},
+	}
+	for _, tt := range tests {
+		if got := withDelegateFooter(tt.description, footer); got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.description, tt.want, got)
+		}
+	}
+}
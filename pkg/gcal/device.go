@@ -0,0 +1,113 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+)
+
+// deviceCodeLifetime is how long a device code is waited for when Google
+// doesn't say when it expires
+const deviceCodeLifetime = 30 * time.Minute
+
+// DeviceAuthorizer is implemented by services that can get a new OAuth
+// token through the device authorization flow, for machines without a
+// browser, such as CalendarClient
+type DeviceAuthorizer interface {
+	// AuthorizeDevice starts the flow; the new token is used once the user
+	// entered the code
+	AuthorizeDevice(ctx context.Context) (*DeviceAuth, error)
+}
+
+// DeviceAuth is a running device authorization: the user opens
+// VerificationURL on any device and enters UserCode, while the server waits
+// for Google to issue the token
+type DeviceAuth struct {
+	VerificationURL string
+	UserCode        string
+	// Expiry is when the code stops being accepted
+	Expiry time.Time
+	// Done receives the outcome once the user granted or denied access or
+	// the code expired
+	Done <-chan error
+}
+
+// AuthorizeDevice runs the device authorization flow for the OAuth client
+// in clientFile and saves the token to tokenFile, for a first token on a
+// machine without a browser. The client has to be of the type TVs and
+// Limited Input devices.
+func AuthorizeDevice(ctx context.Context, clientFile, tokenFile string) (*DeviceAuth, error) {
+	data, err := os.ReadFile(clientFile)
+	if err != nil {
+		return nil, err
+	}
+	config, err := google.ConfigFromJSON(data, calendar.CalendarScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OAuth client file %s: %w", clientFile, err)
+	}
+	ts := newPersistentTokenSource(context.Background(), config, &oauth2.Token{}, fileTokenStore(tokenFile))
+	return ts.authorizeDevice(ctx)
+}
+
+// authorizeDevice asks Google for a user code and waits for the token in
+// the background. The wait isn't bound to ctx, which only covers asking
+// for the code: a tool call returns the code long before the user enters
+// it.
+func (ts *persistentTokenSource) authorizeDevice(ctx context.Context) (*DeviceAuth, error) {
+	resp, err := ts.config.DeviceAuth(ctx, oauth2.AccessTypeOffline)
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && (retrieveErr.ErrorCode == "invalid_client" || retrieveErr.ErrorCode == "unauthorized_client") {
+			return nil, fmt.Errorf("Google doesn't allow the device flow for this OAuth client; create one of the type TVs and Limited Input devices: %w", err)
+		}
+		return nil, fmt.Errorf("failed to start the device authorization: %w", err)
+	}
+
+	if resp.Expiry.IsZero() {
+		resp.Expiry = time.Now().Add(deviceCodeLifetime)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		token, err := ts.config.DeviceAccessToken(ts.ctx, resp)
+		if err != nil {
+			done <- deviceError(err)
+			return
+		}
+		done <- ts.setToken(token)
+	}()
+
+	return &DeviceAuth{
+		VerificationURL: resp.VerificationURI,
+		UserCode:        resp.UserCode,
+		Expiry:          resp.Expiry,
+		Done:            done,
+	}, nil
+}
+
+// deviceError explains why no token was issued for a device code
+func deviceError(err error) error {
+	var retrieveErr *oauth2.RetrieveError
+	switch {
+	case errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "access_denied":
+		return fmt.Errorf("access was denied")
+	case errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "expired_token",
+		errors.Is(err, context.DeadlineExceeded):
+		return fmt.Errorf("the code expired before access was granted; start again")
+	}
+	return fmt.Errorf("failed to get a token for the device code: %w", err)
+}
+
+func (c *CalendarClient) AuthorizeDevice(ctx context.Context) (*DeviceAuth, error) {
+	ts, err := c.oauthTokens()
+	if err != nil {
+		return nil, err
+	}
+	return ts.authorizeDevice(ctx)
+}
@@ -0,0 +1,54 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestPersistentTokenSource_AuthorizeDevice(t *testing.T) {
+	ts, path := newTestTokenSource(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/device" {
+			fmt.Fprint(w, `{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_url":"https://www.google.com/device","expires_in":1800,"interval":1}`)
+			return
+		}
+		if r.Form.Get("device_code") != "dev-1" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		fmt.Fprint(w, `{"access_token":"device-access","refresh_token":"device-refresh","token_type":"Bearer","expires_in":3600}`)
+	}, &oauth2.Token{RefreshToken: "revoked"})
+	ts.config.Endpoint.DeviceAuthURL = ts.config.Endpoint.TokenURL + "/device"
+
+	auth, err := ts.authorizeDevice(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.VerificationURL != "https://www.google.com/device" || auth.UserCode != "ABCD-EFGH" {
+		t.Errorf("unexpected authorization %+v", auth)
+	}
+	if err := <-auth.Done; err != nil {
+		t.Fatal(err)
+	}
+	token, err := ts.Token()
+	if err != nil || token.AccessToken != "device-access" {
+		t.Errorf("expected the new token to be used, got %+v, %v", token, err)
+	}
+	if saved, err := readToken(path); err != nil || saved.RefreshToken != "device-refresh" {
+		t.Errorf("expected the new token to be saved, got %+v, %v", saved, err)
+	}
+}
+
+func TestDeviceError(t *testing.T) {
+	if err := deviceError(&oauth2.RetrieveError{ErrorCode: "access_denied"}); err.Error() != "access was denied" {
+		t.Errorf("unexpected error %q", err)
+	}
+	if err := deviceError(context.DeadlineExceeded); !strings.Contains(err.Error(), "expired") {
+		t.Errorf("unexpected error %q", err)
+	}
+}
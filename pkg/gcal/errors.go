@@ -0,0 +1,56 @@
+package gcal
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// InvalidInputError reports a request the client refuses to carry out as
+// given, such as an event that ends before it starts or a malformed date
+type InvalidInputError struct {
+	Err error
+}
+
+func (e *InvalidInputError) Error() string { return e.Err.Error() }
+func (e *InvalidInputError) Unwrap() error { return e.Err }
+
+func invalidInput(err error) error {
+	return &InvalidInputError{Err: err}
+}
+
+func invalidInputf(format string, args ...interface{}) error {
+	return invalidInput(fmt.Errorf(format, args...))
+}
+
+// CalendarNotFoundError reports that the Calendar API doesn't know a
+// calendar, which usually means that it isn't shared with the account the
+// client acts as, a common pitfall with service accounts
+type CalendarNotFoundError struct {
+	CalendarID string
+	// ServiceAccount is the email to share the calendar with, when the
+	// client acts as a service account
+	ServiceAccount string
+	Err            error
+}
+
+func (e *CalendarNotFoundError) Error() string {
+	if e.ServiceAccount != "" {
+		return fmt.Sprintf("calendar %s not found. If it exists, share it with the service account %s: in Google Calendar open its "+
+			"Settings and sharing, add the address under Share with specific people, and allow it to make changes to events", e.CalendarID, e.ServiceAccount)
+	}
+	return fmt.Sprintf("calendar %s not found, or it isn't shared with your account", e.CalendarID)
+}
+
+func (e *CalendarNotFoundError) Unwrap() error { return e.Err }
+
+// calendarError explains a 404 from a call on calendarID as a whole
+func (c *CalendarClient) calendarError(calendarID string, err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		return &CalendarNotFoundError{CalendarID: calendarID, ServiceAccount: c.identityFor(calendarID).serviceAccount, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package gcal
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// eidDomains expands the abbreviated domains of calendar IDs in event link
+// eids
+var eidDomains = map[string]string{
+	"m": "gmail.com",
+	"g": "group.calendar.google.com",
+	"v": "group.v.calendar.google.com",
+}
+
+// IsEventLink reports whether s looks like a Google Calendar event link
+// rather than an event ID
+func IsEventLink(s string) bool {
+	return strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://") ||
+		strings.HasPrefix(s, "calendar.google.com/") || strings.HasPrefix(s, "www.google.com/calendar/")
+}
+
+// ParseEventLink returns the event and calendar a Google Calendar event link
+// points to, like the htmlLink of an event,
+// https://calendar.google.com/calendar/event?eid=..., or the
+// .../r/eventedit/<eid> address of the event editor. The eid is the
+// base64-encoded "<event ID> <calendar ID>"; the calendar ID may be
+// abbreviated, e.g. "@m" for gmail.com.
+func ParseEventLink(link string) (eventID, calendarID string, err error) {
+	link = strings.TrimSpace(link)
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil || !isCalendarHost(u.Host) {
+		return "", "", invalidInputf("%q is not a Google Calendar event link", link)
+	}
+	eid := u.Query().Get("eid")
+	if eid == "" {
+		if rest, ok := cutAfter(u.Path, "/eventedit/"); ok {
+			eid = rest
+		} else if rest, ok := cutAfter(u.Path, "/event/"); ok {
+			eid = rest
+		}
+	}
+	if eid == "" {
+		return "", "", invalidInputf("event link %s has no eid; copy the link of the event itself", link)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(eid, "="))
+	if err != nil {
+		// Some links use the standard alphabet
+		decoded, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(eid, "="))
+	}
+	if err != nil {
+		return "", "", invalidInputf("event link %s has an invalid eid", link)
+	}
+	eventID, calendarID, _ = strings.Cut(string(decoded), " ")
+	if eventID == "" {
+		return "", "", invalidInputf("event link %s has an invalid eid", link)
+	}
+	if user, domain, ok := strings.Cut(calendarID, "@"); ok {
+		if full, ok := eidDomains[domain]; ok {
+			calendarID = user + "@" + full
+		}
+	}
+	return eventID, calendarID, nil
+}
+
+func isCalendarHost(host string) bool {
+	switch host {
+	case "calendar.google.com", "www.google.com", "google.com":
+		return true
+	}
+	return false
+}
+
+// cutAfter returns the path segment following sep
+func cutAfter(path, sep string) (string, bool) {
+	_, rest, ok := strings.Cut(path, sep)
+	rest, _, _ = strings.Cut(rest, "/")
+	return rest, ok && rest != ""
+}
@@ -0,0 +1,34 @@
+package gcal
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestParseEventLink(t *testing.T) {
+	eid := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := []struct {
+		link, eventID, calendarID string
+	}{
+		{"https://www.google.com/calendar/event?eid=" + eid("abc123 me@example.com"), "abc123", "me@example.com"},
+		{"https://calendar.google.com/calendar/event?eid=" + eid("abc123 jane@m"), "abc123", "jane@gmail.com"},
+		{"https://calendar.google.com/calendar/u/0/r/eventedit/" + eid("abc123_20260320T090000Z team@g"), "abc123_20260320T090000Z", "team@group.calendar.google.com"},
+		{"calendar.google.com/calendar/event?eid=" + eid("abc123") + "&ctz=Europe/Berlin", "abc123", ""},
+	}
+	for _, tt := range tests {
+		eventID, calendarID, err := ParseEventLink(tt.link)
+		if err != nil || eventID != tt.eventID || calendarID != tt.calendarID {
+			t.Errorf("%s: got %q, %q, %v", tt.link, eventID, calendarID, err)
+		}
+	}
+
+	for _, link := range []string{
+		"https://example.com/calendar/event?eid=" + eid("abc123 me@example.com"),
+		"https://calendar.google.com/calendar/r/week",
+		"https://calendar.google.com/calendar/event?eid=%%%",
+	} {
+		if _, _, err := ParseEventLink(link); err == nil {
+			t.Errorf("%s: expected an error", link)
+		}
+	}
+}
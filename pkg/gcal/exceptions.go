@@ -0,0 +1,59 @@
+package gcal
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// SeriesInstance is one occurrence of a recurring series, including
+// cancelled ones
+type SeriesInstance struct {
+	ID    string `json:"id"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// OriginalStart is when the series pattern schedules this instance
+	OriginalStart string `json:"originalStart"`
+	// Status is "cancelled" for instances that were removed from the series
+	Status string `json:"status,omitempty"`
+}
+
+// ListInstances returns the instances of a recurring series between two
+// dates (YYYY-MM-DD, inclusive), cancelled ones included
+func (c *CalendarClient) ListInstances(ctx context.Context, seriesID, startDate, endDate string) ([]SeriesInstance, error) {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, err := time.ParseInLocation("2006-01-02", startDate, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+	end, err := time.ParseInLocation("2006-01-02", endDate, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+
+	result := []SeriesInstance{}
+	err = c.serviceFor(c.calendarID).Events.Instances(c.calendarID, seriesID).
+		ShowDeleted(true).
+		TimeMin(start.Format(time.RFC3339)).
+		TimeMax(end.AddDate(0, 0, 1).Format(time.RFC3339)).
+		Pages(ctx, func(page *calendar.Events) error {
+			for _, e := range page.Items {
+				result = append(result, SeriesInstance{
+					ID:            e.Id,
+					Start:         EventTime(e.Start),
+					End:           EventTime(e.End),
+					OriginalStart: EventTime(e.OriginalStartTime),
+					Status:        e.Status,
+				})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package gcal
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// MaxFreeBusyCalendars is how many calendars one free/busy query may ask
+// about
+const MaxFreeBusyCalendars = 50
+
+// BusyCalendar is when one calendar, or the person owning it, is busy
+type BusyCalendar struct {
+	ID string `json:"id"`
+	// Busy are the busy blocks, in order and without overlaps
+	Busy []BusyBlock `json:"busy"`
+	// Error is why the calendar couldn't be checked, e.g. notFound when it
+	// doesn't exist or its free/busy information isn't shared
+	Error string `json:"error,omitempty"`
+}
+
+// BusyBlock is a busy span, in RFC 3339
+type BusyBlock struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// QueryFreeBusy returns when each of calendarIDs, which may be calendar
+// IDs or the email addresses of people, is busy between timeMin and
+// timeMax, in the order asked. Calendars that can't be checked carry an
+// error instead of failing the query.
+func (c *CalendarClient) QueryFreeBusy(ctx context.Context, calendarIDs []string, timeMin, timeMax time.Time) ([]BusyCalendar, error) {
+	if len(calendarIDs) == 0 || len(calendarIDs) > MaxFreeBusyCalendars {
+		return nil, invalidInputf("ask about 1 to %d calendars at a time", MaxFreeBusyCalendars)
+	}
+	if !timeMax.After(timeMin) {
+		return nil, invalidInputf("the end of the window must be after its start")
+	}
+
+	request := &calendar.FreeBusyRequest{
+		TimeMin:  timeMin.Format(time.RFC3339),
+		TimeMax:  timeMax.Format(time.RFC3339),
+		TimeZone: c.timezone,
+	}
+	for _, id := range calendarIDs {
+		request.Items = append(request.Items, &calendar.FreeBusyRequestItem{Id: id})
+	}
+	response, err := c.service.Freebusy.Query(request).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]BusyCalendar, 0, len(calendarIDs))
+	for _, id := range calendarIDs {
+		busy := BusyCalendar{ID: id, Busy: []BusyBlock{}}
+		if cal, ok := response.Calendars[id]; ok {
+			for _, period := range cal.Busy {
+				busy.Busy = append(busy.Busy, BusyBlock{Start: period.Start, End: period.End})
+			}
+			if len(cal.Errors) > 0 {
+				busy.Error = cal.Errors[0].Reason
+			}
+		} else {
+			busy.Error = "notFound"
+		}
+		result = append(result, busy)
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+package gcal
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// UseCredentials makes calls on calendarID go out with the credentials of
+// other instead of the client's own, e.g. a room calendar only shared with
+// a service account next to a personal calendar read with an OAuth token.
+// Everything else about the call, like the timezone, stays the client's.
+func (c *CalendarClient) UseCredentials(calendarID string, other *CalendarClient) error {
+	calendarID = strings.TrimSpace(calendarID)
+	if calendarID == "" {
+		return fmt.Errorf("no calendar ID to map credentials to")
+	}
+	if other == c {
+		return nil
+	}
+	if _, ok := c.identities[calendarID]; ok {
+		return fmt.Errorf("calendar %s is mapped to credentials twice", calendarID)
+	}
+	if c.identities == nil {
+		c.identities = make(map[string]*CalendarClient)
+	}
+	c.identities[calendarID] = other
+	return nil
+}
+
+// identityFor returns the client whose credentials calls on calendarID
+// are made with
+func (c *CalendarClient) identityFor(calendarID string) *CalendarClient {
+	if other, ok := c.identities[calendarID]; ok {
+		return other
+	}
+	return c
+}
+
+// serviceFor returns the API connection for calls on calendarID
+func (c *CalendarClient) serviceFor(calendarID string) *calendar.Service {
+	return c.identityFor(calendarID).service
+}
@@ -0,0 +1,66 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+// identityServer answers event lists with an empty page and records the
+// calendars it was asked for
+func identityServer(t *testing.T, calls *[]string) *CalendarClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls = append(*calls, r.URL.Path)
+		if strings.Contains(r.URL.Path, "rooms") {
+			http.Error(w, `{"error":{"code":404,"message":"Not Found"}}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"items":[]}`))
+	}))
+	t.Cleanup(srv.Close)
+	c, err := newCalendarClient(context.Background(), "me@example.com", "UTC", option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCalendarClient_UseCredentials(t *testing.T) {
+	var own, shared []string
+	c := identityServer(t, &own)
+	rooms := identityServer(t, &shared)
+	rooms.serviceAccount = "rooms@proj.iam.gserviceaccount.com"
+	if err := c.UseCredentials("rooms@example.com", rooms); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	c.ListCalendarEvents(ctx, "me@example.com", "2026-03-16", "2026-03-16")
+	_, err := c.ListCalendarEvents(ctx, "rooms@example.com", "2026-03-16", "2026-03-16")
+	if len(own) != 1 || len(shared) != 1 || !strings.Contains(shared[0], "rooms@example.com") {
+		t.Fatalf("expected each calendar to use its credentials, got %v and %v", own, shared)
+	}
+	var notFound *CalendarNotFoundError
+	if !errors.As(err, &notFound) || notFound.ServiceAccount != "rooms@proj.iam.gserviceaccount.com" {
+		t.Errorf("expected to be told to share with the mapped service account, got %v", err)
+	}
+
+	// Sessions acting on the mapped calendar use its credentials too
+	c.WithDefaults("rooms@example.com", "").(*CalendarClient).GetEvent(ctx, "evt-1")
+	if len(shared) != 2 {
+		t.Errorf("expected the view to keep the mapping, got %v", shared)
+	}
+
+	if err := c.UseCredentials("rooms@example.com", c); err != nil {
+		t.Errorf("expected mapping to the client itself to be a no-op, got %v", err)
+	}
+	if err := c.UseCredentials("rooms@example.com", identityServer(t, &own)); err == nil {
+		t.Error("expected a calendar mapped twice to be rejected")
+	}
+}
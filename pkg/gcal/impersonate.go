@@ -0,0 +1,38 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+// NewImpersonatedCalendarClient returns a client acting on calendarID as
+// the service account serviceAccount, with short-lived tokens the IAM
+// Credentials API issues to the caller's Application Default Credentials,
+// e.g. those of gcloud auth application-default login. No key of the
+// service account is needed, but the caller needs the Service Account
+// Token Creator role on it.
+func NewImpersonatedCalendarClient(serviceAccount, calendarID, timezone string) (*CalendarClient, error) {
+	if !strings.Contains(serviceAccount, "@") {
+		return nil, fmt.Errorf("invalid service account %q: expected an email like name@project.iam.gserviceaccount.com", serviceAccount)
+	}
+	tokens, err := impersonate.CredentialsTokenSource(context.Background(), impersonate.CredentialsConfig{
+		TargetPrincipal: serviceAccount,
+		Scopes:          []string{calendar.CalendarScope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to impersonate %s with the Application Default Credentials: %w", serviceAccount, err)
+	}
+	c, err := newCalendarClient(context.Background(), calendarID, timezone, option.WithTokenSource(tokens))
+	if err != nil {
+		return nil, err
+	}
+	c.authMode = "impersonation"
+	c.tokens = tokens
+	c.serviceAccount = serviceAccount
+	return c, nil
+}
@@ -0,0 +1,26 @@
+package gcal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewImpersonatedCalendarClient(t *testing.T) {
+	adc := filepath.Join(t.TempDir(), "adc.json")
+	os.WriteFile(adc, []byte(`{"type":"authorized_user","client_id":"id","client_secret":"secret","refresh_token":"token"}`), 0o600)
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", adc)
+
+	// No token is fetched until the first request
+	client, err := NewImpersonatedCalendarClient("calendar@p.iam.gserviceaccount.com", "me@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.AuthMode() != "impersonation" || client.serviceAccount != "calendar@p.iam.gserviceaccount.com" {
+		t.Errorf("unexpected client %q acting as %q", client.AuthMode(), client.serviceAccount)
+	}
+
+	if _, err := NewImpersonatedCalendarClient("calendar", "me@example.com", ""); err == nil {
+		t.Error("expected a service account without a domain to be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package gcal
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/oauth2"
+)
+
+// keychainService is the service the OS keychain files tokens under
+const keychainService = "google-calendar-mcp"
+
+// errNoKeychainItem reports that the keychain holds no token for an account
+var errNoKeychainItem = errors.New("no such keychain item")
+
+// keychain is a store of secrets by service and account, like the macOS
+// Keychain, the Windows Credential Manager or a Secret Service such as GNOME
+// Keyring. systemKeychain is the one of the current OS.
+type keychain interface {
+	get(service, account string) ([]byte, error)
+	set(service, account string, data []byte) error
+	String() string
+}
+
+// keychainTokenStore saves tokens to the OS keychain
+type keychainTokenStore struct {
+	keychain keychain
+	account  string
+}
+
+func (k keychainTokenStore) saveToken(token *oauth2.Token) error {
+	data, err := tokenJSON(token)
+	if err != nil {
+		return err
+	}
+	return k.keychain.set(keychainService, k.account, data)
+}
+
+// NewKeychainOAuthCalendarClient is like NewOAuthCalendarClient, but keeps
+// the token in the OS keychain instead of a plaintext file. The keychain
+// item is named after tokenFile; the first time, when there is no item yet,
+// the token is imported from tokenFile, which can be deleted afterwards.
+func NewKeychainOAuthCalendarClient(clientFile, tokenFile, calendarID, timezone string) (*CalendarClient, error) {
+	store, token, err := loadKeychainToken(systemKeychain, tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	return newOAuthCalendarClient(clientFile, token, store, calendarID, timezone)
+}
+
+// loadKeychainToken reads the token of tokenFile from kc, importing it from
+// the file when kc doesn't hold it yet
+func loadKeychainToken(kc keychain, tokenFile string) (keychainTokenStore, *oauth2.Token, error) {
+	account, err := filepath.Abs(tokenFile)
+	if err != nil {
+		return keychainTokenStore{}, nil, err
+	}
+	store := keychainTokenStore{keychain: kc, account: account}
+
+	data, err := kc.get(keychainService, account)
+	if err == nil {
+		token, err := parseToken(data, fmt.Sprintf("OAuth token of %s in the %s", account, kc))
+		return store, token, err
+	}
+	if !errors.Is(err, errNoKeychainItem) {
+		return store, nil, fmt.Errorf("failed to read the OAuth token from the %s: %w", kc, err)
+	}
+
+	token, err := readToken(tokenFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil, fmt.Errorf("the %s holds no OAuth token for %s, and there is no such file to import it from", kc, account)
+	}
+	if err != nil {
+		return store, nil, err
+	}
+	if err := store.saveToken(token); err != nil {
+		return store, nil, fmt.Errorf("failed to save the OAuth token to the %s: %w", kc, err)
+	}
+	log.Printf("Imported the OAuth token from %s into the %s; you can delete the file now", tokenFile, kc)
+	return store, token, nil
+}
@@ -0,0 +1,56 @@
+//go:build darwin
+
+package gcal
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit status of security when there is no such
+// item
+const errSecItemNotFound = 44
+
+var systemKeychain keychain = macKeychain{}
+
+// macKeychain stores secrets in the login keychain through the security
+// command
+type macKeychain struct{}
+
+func (macKeychain) String() string { return "macOS Keychain" }
+
+func (macKeychain) get(service, account string) ([]byte, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w").Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return nil, errNoKeychainItem
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(out, []byte("\n")), nil
+}
+
+func (macKeychain) set(service, account string, data []byte) error {
+	// The command goes through stdin, so that the secret never shows up in
+	// the process list
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		shellQuote(service), shellQuote(account), hex.EncodeToString(data)))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	// security -i exits cleanly even when a command fails
+	if _, err := (macKeychain{}).get(service, account); err != nil {
+		return fmt.Errorf("the item wasn't saved: %w", err)
+	}
+	return nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,61 @@
+package gcal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// memoryKeychain is a keychain in memory
+type memoryKeychain map[string][]byte
+
+func (m memoryKeychain) String() string { return "test keychain" }
+
+func (m memoryKeychain) get(service, account string) ([]byte, error) {
+	data, ok := m[service+"/"+account]
+	if !ok {
+		return nil, errNoKeychainItem
+	}
+	return data, nil
+}
+
+func (m memoryKeychain) set(service, account string, data []byte) error {
+	m[service+"/"+account] = data
+	return nil
+}
+
+func TestLoadKeychainToken(t *testing.T) {
+	kc := memoryKeychain{}
+	path := filepath.Join(t.TempDir(), "token.json")
+	if _, _, err := loadKeychainToken(kc, path); err == nil || !strings.Contains(err.Error(), "no such file to import") {
+		t.Errorf("expected an error without a token anywhere, got %v", err)
+	}
+
+	// The first run imports the file
+	if err := writeToken(path, &oauth2.Token{AccessToken: "a", RefreshToken: "r"}); err != nil {
+		t.Fatal(err)
+	}
+	store, token, err := loadKeychainToken(kc, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.RefreshToken != "r" || len(kc) != 1 {
+		t.Fatalf("expected the token to be imported, got %+v and %v", token, kc)
+	}
+
+	// Refreshed tokens go to the keychain, and later runs don't need the
+	// file anymore
+	if err := store.saveToken(&oauth2.Token{AccessToken: "b", RefreshToken: "rotated"}); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path)
+	if _, token, err := loadKeychainToken(kc, path); err != nil || token.RefreshToken != "rotated" {
+		t.Errorf("expected the rotated token from the keychain, got %+v, %v", token, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("the keychain store must not write the token file")
+	}
+}
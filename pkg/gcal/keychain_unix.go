@@ -0,0 +1,47 @@
+//go:build !darwin && !windows
+
+package gcal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+var systemKeychain keychain = secretService{}
+
+var errNoSecretTool = errors.New("secret-tool not found; install libsecret-tools (or libsecret on some distributions)")
+
+// secretService stores secrets in the Secret Service of the desktop session,
+// such as GNOME Keyring or KWallet, through secret-tool of libsecret
+type secretService struct{}
+
+func (secretService) String() string { return "Secret Service" }
+
+func (secretService) get(service, account string) ([]byte, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", service, "account", account).Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+		// secret-tool fails silently when there is no such item
+		return nil, errNoKeychainItem
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, errNoSecretTool
+	}
+	return out, err
+}
+
+func (secretService) set(service, account string, data []byte) error {
+	// secret-tool reads the secret from stdin, so that it never shows up in
+	// the process list
+	cmd := exec.Command("secret-tool", "store", "--label", "Google Calendar MCP OAuth token", "service", service, "account", account)
+	cmd.Stdin = bytes.NewReader(data)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errNoSecretTool
+		}
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+//go:build windows
+
+package gcal
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32      = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead  = advapi32.NewProc("CredReadW")
+	procCredWrite = advapi32.NewProc("CredWriteW")
+	procCredFree  = advapi32.NewProc("CredFree")
+)
+
+var systemKeychain keychain = windowsCredentials{}
+
+// windowsCredentials stores secrets as generic credentials of the Windows
+// Credential Manager
+type windowsCredentials struct{}
+
+// credential is CREDENTIALW of wincred.h
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func (windowsCredentials) String() string { return "Windows Credential Manager" }
+
+func credentialTarget(service, account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + account)
+}
+
+func (windowsCredentials) get(service, account string) ([]byte, error) {
+	target, err := credentialTarget(service, account)
+	if err != nil {
+		return nil, err
+	}
+	var cred *credential
+	if ok, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); ok == 0 {
+		if errors.Is(err, errorNotFound) {
+			return nil, errNoKeychainItem
+		}
+		return nil, err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	return append([]byte(nil), unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)...), nil
+}
+
+func (windowsCredentials) set(service, account string, data []byte) error {
+	target, err := credentialTarget(service, account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(data)),
+		CredentialBlob:     unsafe.SliceData(data),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if ok, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ok == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/compute/metadata"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// metadataTimeout bounds the calls to the metadata server while the client
+// is created
+const metadataTimeout = 5 * time.Second
+
+// OnGoogleCloud reports whether the process runs on Compute Engine, GKE,
+// Cloud Run or another platform with a metadata server
+func OnGoogleCloud() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+	return metadata.OnGCEWithContext(ctx)
+}
+
+// NewMetadataCalendarClient returns a client acting on calendarID as the
+// service account of the instance, or of the Kubernetes service account
+// with GKE Workload Identity, with tokens from the metadata server; no key
+// file is needed. The tokens are requested with the calendar scope, which
+// on Compute Engine the instance's access scopes have to allow.
+func NewMetadataCalendarClient(calendarID, timezone string) (*CalendarClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataTimeout)
+	defer cancel()
+	email, err := metadata.EmailWithContext(ctx, "default")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the service account from the metadata server: %w", err)
+	}
+
+	tokens := google.ComputeTokenSource("", calendar.CalendarScope)
+	c, err := newCalendarClient(context.Background(), calendarID, timezone, option.WithTokenSource(tokens))
+	if err != nil {
+		return nil, err
+	}
+	c.authMode = "metadata"
+	c.tokens = tokens
+	c.serviceAccount = email
+	return c, nil
+}
@@ -0,0 +1,29 @@
+package gcal
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewMetadataCalendarClient(t *testing.T) {
+	mds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" || r.URL.Path != "/computeMetadata/v1/instance/service-accounts/default/email" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, "calendar@proj.iam.gserviceaccount.com")
+	}))
+	defer mds.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(mds.URL, "http://"))
+
+	c, err := NewMetadataCalendarClient("team@example.com", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.AuthMode() != "metadata" || c.serviceAccount != "calendar@proj.iam.gserviceaccount.com" {
+		t.Errorf("unexpected client %+v", c)
+	}
+}
@@ -0,0 +1,202 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// tokenRefreshMargin is how long before it expires an access token is
+// refreshed, so that a request never goes out with a token about to expire
+const tokenRefreshMargin = 5 * time.Minute
+
+// AuthError reports that the OAuth token can't be refreshed anymore, e.g.
+// because the user revoked access or the refresh token expired. Retrying
+// doesn't help; the user has to authorize again.
+type AuthError struct {
+	Err error
+	// AuthURL is where the user grants access again, to get a new token
+	AuthURL string
+}
+
+func (e *AuthError) Error() string {
+	return fmt.Sprintf("Google authorization expired or was revoked (%v); authorize again and replace the OAuth token file", e.Err)
+}
+
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// NewOAuthCalendarClient returns a client acting on calendarID as the user
+// who authorized the OAuth client in clientFile, with the token saved in
+// tokenFile, such as the token.json of Google's Go quickstart. The access
+// token is refreshed shortly before it expires, and refreshed tokens are
+// written back to tokenFile, so that a rotated refresh token survives a
+// restart.
+func NewOAuthCalendarClient(clientFile, tokenFile, calendarID, timezone string) (*CalendarClient, error) {
+	token, err := readToken(tokenFile)
+	if err != nil {
+		return nil, err
+	}
+	return newOAuthCalendarClient(clientFile, token, fileTokenStore(tokenFile), calendarID, timezone)
+}
+
+func newOAuthCalendarClient(clientFile string, token *oauth2.Token, store tokenStore, calendarID, timezone string) (*CalendarClient, error) {
+	data, err := os.ReadFile(clientFile)
+	if err != nil {
+		return nil, err
+	}
+	config, err := google.ConfigFromJSON(data, calendar.CalendarScope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OAuth client file %s: %w", clientFile, err)
+	}
+
+	ctx := context.Background()
+	ts := newPersistentTokenSource(ctx, config, token, store)
+	c, err := newCalendarClient(ctx, calendarID, timezone, option.WithTokenSource(ts))
+	if err != nil {
+		return nil, err
+	}
+	c.authMode = "oauth"
+	c.tokens = ts
+	return c, nil
+}
+
+func readToken(path string) (*oauth2.Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if isSealedToken(data) {
+		return nil, fmt.Errorf("OAuth token file %s is encrypted; set GOOGLE_OAUTH_TOKEN_STORE=encrypted and its key", path)
+	}
+	return parseToken(data, "OAuth token file "+path)
+}
+
+// parseToken decodes a token saved as JSON in source
+func parseToken(data []byte, source string) (*oauth2.Token, error) {
+	var token oauth2.Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", source, err)
+	}
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("%s has no refresh_token", source)
+	}
+	return &token, nil
+}
+
+// tokenStore is where refreshed OAuth tokens are saved
+type tokenStore interface {
+	saveToken(token *oauth2.Token) error
+}
+
+// fileTokenStore saves tokens to the JSON file at its path
+type fileTokenStore string
+
+func (f fileTokenStore) saveToken(token *oauth2.Token) error {
+	return writeToken(string(f), token)
+}
+
+// persistentTokenSource refreshes the OAuth token ahead of its expiry and
+// saves every new token to a store
+type persistentTokenSource struct {
+	ctx    context.Context
+	config *oauth2.Config
+	store  tokenStore
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newPersistentTokenSource(ctx context.Context, config *oauth2.Config, token *oauth2.Token, store tokenStore) *persistentTokenSource {
+	return &persistentTokenSource{ctx: ctx, config: config, store: store, token: token}
+}
+
+func (ts *persistentTokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token.AccessToken != "" && (ts.token.Expiry.IsZero() || time.Until(ts.token.Expiry) > tokenRefreshMargin) {
+		return ts.token, nil
+	}
+
+	// A source made from the refresh token alone always refreshes, where
+	// one made from the whole token would hand out the current one until
+	// seconds before it expires
+	token, err := ts.config.TokenSource(ts.ctx, &oauth2.Token{RefreshToken: ts.token.RefreshToken}).Token()
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && permanentTokenError(retrieveErr.ErrorCode) {
+			return nil, &AuthError{
+				Err:     errors.New(retrieveErr.ErrorCode),
+				AuthURL: ts.authURL(),
+			}
+		}
+		return nil, fmt.Errorf("failed to refresh the OAuth token: %w", err)
+	}
+
+	ts.token = token
+	if err := ts.store.saveToken(token); err != nil {
+		// The token works for this process; only a restart would need it
+		log.Printf("Failed to save the refreshed OAuth token: %v", err)
+	}
+	return token, nil
+}
+
+// permanentTokenError reports whether an OAuth error code means that the
+// refresh token, or the client itself, is no longer accepted
+func permanentTokenError(code string) bool {
+	switch code {
+	case "invalid_grant", "invalid_client", "unauthorized_client":
+		return true
+	}
+	return false
+}
+
+// writeToken replaces the token file atomically, so that a crash while
+// writing can't leave it truncated
+func writeToken(path string, token *oauth2.Token) error {
+	data, err := tokenJSON(token)
+	if err != nil {
+		return err
+	}
+	return writeTokenFile(path, data)
+}
+
+// writeTokenFile replaces the private file at path with data atomically
+func writeTokenFile(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func tokenJSON(token *oauth2.Token) ([]byte, error) {
+	return json.MarshalIndent(token, "", "  ")
+}
@@ -0,0 +1,104 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestTokenSource(t *testing.T, handler http.HandlerFunc, token *oauth2.Token) (*persistentTokenSource, string) {
+	t.Helper()
+	endpoint := httptest.NewServer(handler)
+	t.Cleanup(endpoint.Close)
+	config := &oauth2.Config{ClientID: "id", ClientSecret: "secret", Endpoint: oauth2.Endpoint{TokenURL: endpoint.URL}}
+	path := filepath.Join(t.TempDir(), "token.json")
+	return newPersistentTokenSource(context.Background(), config, token, fileTokenStore(path)), path
+}
+
+func TestPersistentTokenSource_Refresh(t *testing.T) {
+	refreshes := 0
+	ts, path := newTestTokenSource(t, func(w http.ResponseWriter, r *http.Request) {
+		refreshes++
+		r.ParseForm()
+		if r.Form.Get("refresh_token") != "old-refresh" {
+			t.Errorf("unexpected refresh token %q", r.Form.Get("refresh_token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","token_type":"Bearer","expires_in":3600}`)
+	}, &oauth2.Token{AccessToken: "old-access", RefreshToken: "old-refresh", Expiry: time.Now().Add(time.Minute)})
+
+	// The token expires within the margin, so it is refreshed already
+	token, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "new-access" || refreshes != 1 {
+		t.Fatalf("expected a refreshed token, got %+v after %d refreshes", token, refreshes)
+	}
+	if _, err := ts.Token(); err != nil || refreshes != 1 {
+		t.Errorf("expected the new token to be reused, got %v after %d refreshes", err, refreshes)
+	}
+
+	saved, err := readToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.AccessToken != "new-access" || saved.RefreshToken != "new-refresh" {
+		t.Errorf("expected the rotated token to be saved, got %+v", saved)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the token file to be private, got %v", info.Mode())
+	}
+	if matches, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".token.json.*")); len(matches) != 0 {
+		t.Errorf("expected no temporary files, got %v", matches)
+	}
+}
+
+func TestPersistentTokenSource_Revoked(t *testing.T) {
+	ts, path := newTestTokenSource(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`)
+	}, &oauth2.Token{RefreshToken: "revoked"})
+
+	_, err := ts.Token()
+	var authErr *AuthError
+	if !errors.As(err, &authErr) {
+		t.Fatalf("expected an AuthError, got %v", err)
+	}
+	if !strings.Contains(authErr.AuthURL, "access_type=offline") {
+		t.Errorf("expected a URL to authorize again, got %q", authErr.AuthURL)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("a failed refresh should not write the token file")
+	}
+}
+
+func TestPersistentTokenSource_Transient(t *testing.T) {
+	ts, _ := newTestTokenSource(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, &oauth2.Token{RefreshToken: "refresh"})
+
+	_, err := ts.Token()
+	var authErr *AuthError
+	if err == nil || errors.As(err, &authErr) {
+		t.Errorf("expected a temporary error, got %v", err)
+	}
+}
+
+func TestReadToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	os.WriteFile(path, []byte(`{"access_token":"a"}`), 0o600)
+	if _, err := readToken(path); err == nil {
+		t.Error("expected an error for a token without refresh_token")
+	}
+}
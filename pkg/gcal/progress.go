@@ -0,0 +1,50 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	progressKey struct{}
+	pageKey     struct{}
+)
+
+// ProgressFunc receives progress updates from long-running calendar calls.
+// total is zero when the final count is not known in advance.
+type ProgressFunc func(progress, total int, message string)
+
+// WithProgress returns a context whose calendar calls report their progress
+// to fn
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// ReportProgress forwards a progress update to the reporter attached to
+// ctx, if there is one
+func ReportProgress(ctx context.Context, progress, total int, message string) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		fn(progress, total, message)
+	}
+}
+
+// PageFunc receives each page of events as soon as it has been fetched,
+// with the number of events fetched so far
+type PageFunc func(fetched int, page []CalendarEvent)
+
+// WithPageReporter returns a context whose listings hand every page of
+// events to fn as it arrives
+func WithPageReporter(ctx context.Context, fn PageFunc) context.Context {
+	return context.WithValue(ctx, pageKey{}, fn)
+}
+
+// ReportPage hands a page of events to the streaming reporter attached to
+// ctx. Without one it sends a plain progress update. Service
+// implementations call it for every page they fetch.
+func ReportPage(ctx context.Context, fetched int, page []CalendarEvent) {
+	if fn, ok := ctx.Value(pageKey{}).(PageFunc); ok {
+		fn(fetched, page)
+		return
+	}
+	ReportProgress(ctx, fetched, 0, fmt.Sprintf("Fetched %d events", fetched))
+}
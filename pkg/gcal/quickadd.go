@@ -0,0 +1,43 @@
+package gcal
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// QuickAddEvent creates an event on calendarID from a sentence such as
+// "Lunch with Sam Friday 1pm", leaving it to Google to work out the title
+// and time. Google reads the sentence in the calendar's own timezone and
+// picks a time even for vague text, so the created event is returned for
+// the caller to check. Schedule constraints aren't applied, as the time is
+// only known once the event exists.
+func (c *CalendarClient) QuickAddEvent(ctx context.Context, calendarID, text string) (*calendar.Event, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, invalidInputf("the text of the event is empty")
+	}
+
+	created, err := c.serviceFor(calendarID).Events.QuickAdd(calendarID, text).Context(ctx).Do()
+	if err != nil {
+		return nil, c.calendarError(calendarID, err)
+	}
+	ReportCreated(ctx)
+
+	// QuickAdd takes no event body, so delegated events are labelled
+	// afterwards; the event exists either way
+	if c.delegate != nil {
+		patch := &calendar.Event{Description: created.Description, ExtendedProperties: created.ExtendedProperties}
+		c.delegate.labelEvent(ctx, patch, actionCreated, c.calendarID, time.Now())
+		labelled, err := c.serviceFor(calendarID).Events.Patch(calendarID, created.Id, patch).Context(ctx).Do()
+		if err != nil {
+			log.Printf("Failed to label quick-added event %s as delegated: %v", created.Id, err)
+			return created, nil
+		}
+		created = labelled
+	}
+	return created, nil
+}
@@ -0,0 +1,61 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+func TestCalendarClient_QuickAddEvent(t *testing.T) {
+	var text string
+	var patch calendar.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events/quickAdd"):
+			text = r.URL.Query().Get("text")
+			w.Write([]byte(`{"id":"evt-1","summary":"Lunch with Sam","start":{"dateTime":"2026-03-20T13:00:00+01:00"},"end":{"dateTime":"2026-03-20T14:00:00+01:00"}}`))
+		case r.Method == http.MethodPatch:
+			json.NewDecoder(r.Body).Decode(&patch)
+			w.Write([]byte(`{"id":"evt-1","summary":"Lunch with Sam"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+	}))
+	defer srv.Close()
+	c, err := newCalendarClient(context.Background(), "me@example.com", "Europe/Berlin", option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	event, err := c.QuickAddEvent(ctx, "me@example.com", " Lunch with Sam Friday 1pm ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "Lunch with Sam Friday 1pm" || event.Start.DateTime != "2026-03-20T13:00:00+01:00" {
+		t.Errorf("unexpected event %+v for text %q", event, text)
+	}
+	if patch.ExtendedProperties != nil {
+		t.Error("expected no label outside delegated mode")
+	}
+
+	c.delegate = &delegation{label: "Sam's assistant"}
+	if _, err := c.QuickAddEvent(ctx, "me@example.com", "Lunch with Sam Friday 1pm"); err != nil {
+		t.Fatal(err)
+	}
+	if patch.ExtendedProperties == nil || patch.ExtendedProperties.Private[delegatedByKey] != "Sam's assistant" {
+		t.Errorf("expected the event to be labelled as delegated, got %+v", patch.ExtendedProperties)
+	}
+
+	var invalid *InvalidInputError
+	if _, err := c.QuickAddEvent(ctx, "me@example.com", "  "); !errors.As(err, &invalid) {
+		t.Errorf("expected empty text to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// RawMethod is a Calendar API method RawRequest can call
+type RawMethod struct {
+	Name string
+	// Mutating methods change calendar data
+	Mutating bool
+	// params are the RawParams fields the method takes, by JSON name
+	params []string
+}
+
+// rawMethods is the set of API methods RawRequest passes through. Methods
+// that return a page of results take pageToken and return nextPageToken as
+// the API does; RawRequest never follows pages itself.
+var rawMethods = []RawMethod{
+	{Name: "calendarList.get", params: []string{"calendarId"}},
+	{Name: "calendarList.list", params: []string{"maxResults", "pageToken", "showDeleted"}},
+	{Name: "calendars.get", params: []string{"calendarId"}},
+	{Name: "colors.get"},
+	{Name: "events.delete", Mutating: true, params: []string{"calendarId", "eventId", "sendUpdates"}},
+	{Name: "events.get", params: []string{"calendarId", "eventId"}},
+	{Name: "events.insert", Mutating: true, params: []string{"calendarId", "body", "sendUpdates"}},
+	{Name: "events.instances", params: []string{"calendarId", "eventId", "timeMin", "timeMax", "maxResults", "pageToken", "showDeleted"}},
+	{Name: "events.list", params: []string{"calendarId", "timeMin", "timeMax", "q", "orderBy", "singleEvents", "updatedMin", "maxResults", "pageToken", "showDeleted"}},
+	{Name: "events.patch", Mutating: true, params: []string{"calendarId", "eventId", "body", "sendUpdates"}},
+	{Name: "freebusy.query", params: []string{"body"}},
+	{Name: "settings.get", params: []string{"setting"}},
+	{Name: "settings.list", params: []string{"maxResults", "pageToken"}},
+}
+
+// RawMethods returns the methods RawRequest supports, sorted by name
+func RawMethods() []RawMethod {
+	methods := append([]RawMethod(nil), rawMethods...)
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name })
+	return methods
+}
+
+// LookupRawMethod returns the RawRequest method called name
+func LookupRawMethod(name string) (RawMethod, bool) {
+	for _, m := range rawMethods {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return RawMethod{}, false
+}
+
+// Params returns the JSON names of the parameters the method takes
+func (m RawMethod) Params() []string {
+	return append([]string(nil), m.params...)
+}
+
+// RawParams are the parameters of a RawRequest, named as in the Calendar
+// API reference. Each method reads the ones it lists; calendarId defaults
+// to the primary calendar.
+type RawParams struct {
+	CalendarID   string `json:"calendarId,omitempty"`
+	EventID      string `json:"eventId,omitempty"`
+	Setting      string `json:"setting,omitempty"`
+	TimeMin      string `json:"timeMin,omitempty"`
+	TimeMax      string `json:"timeMax,omitempty"`
+	Q            string `json:"q,omitempty"`
+	OrderBy      string `json:"orderBy,omitempty"`
+	SingleEvents bool   `json:"singleEvents,omitempty"`
+	UpdatedMin   string `json:"updatedMin,omitempty"`
+	MaxResults   int64  `json:"maxResults,omitempty"`
+	PageToken    string `json:"pageToken,omitempty"`
+	ShowDeleted  bool   `json:"showDeleted,omitempty"`
+	SendUpdates  string `json:"sendUpdates,omitempty"`
+	// Body is the request body of events.insert, events.patch and
+	// freebusy.query
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// RawRequest calls a Calendar API method from RawMethods and returns its
+// response as the API sent it, for capabilities no dedicated method wraps.
+// Events written through it are still held to the schedule constraints,
+// but delegated mode can't label them, so it refuses mutating methods
+// then.
+func (c *CalendarClient) RawRequest(ctx context.Context, method string, params RawParams) (json.RawMessage, error) {
+	m, ok := LookupRawMethod(method)
+	if !ok {
+		return nil, invalidInputf("unsupported method %q", method)
+	}
+	if m.Mutating && c.delegate != nil {
+		return nil, invalidInputf("%s is not available in delegated mode", method)
+	}
+	for _, name := range m.needs() {
+		if !params.has(name) {
+			return nil, invalidInputf("%s needs %s", method, name)
+		}
+	}
+	calendarID := params.CalendarID
+	if calendarID == "" {
+		calendarID = c.calendarID
+	}
+
+	var result interface{}
+	var err error
+	switch method {
+	case "calendarList.get":
+		result, err = c.serviceFor(calendarID).CalendarList.Get(calendarID).Context(ctx).Do()
+	case "calendarList.list":
+		call := c.service.CalendarList.List().ShowDeleted(params.ShowDeleted).PageToken(params.PageToken)
+		if params.MaxResults > 0 {
+			call = call.MaxResults(params.MaxResults)
+		}
+		result, err = call.Context(ctx).Do()
+	case "calendars.get":
+		result, err = c.serviceFor(calendarID).Calendars.Get(calendarID).Context(ctx).Do()
+	case "colors.get":
+		result, err = c.service.Colors.Get().Context(ctx).Do()
+	case "events.delete":
+		call := c.serviceFor(calendarID).Events.Delete(calendarID, params.EventID)
+		if params.SendUpdates != "" {
+			call = call.SendUpdates(params.SendUpdates)
+		}
+		result, err = struct{}{}, call.Context(ctx).Do()
+	case "events.get":
+		result, err = c.serviceFor(calendarID).Events.Get(calendarID, params.EventID).Context(ctx).Do()
+	case "events.insert", "events.patch":
+		var event calendar.Event
+		if err := json.Unmarshal(params.Body, &event); err != nil {
+			return nil, invalidInputf("invalid event body: %v", err)
+		}
+		if err := c.checkRawEvent(&event); err != nil {
+			return nil, err
+		}
+		if method == "events.insert" {
+			call := c.serviceFor(calendarID).Events.Insert(calendarID, &event)
+			if params.SendUpdates != "" {
+				call = call.SendUpdates(params.SendUpdates)
+			}
+			result, err = call.Context(ctx).Do()
+			if err == nil {
+				ReportCreated(ctx)
+			}
+		} else {
+			call := c.serviceFor(calendarID).Events.Patch(calendarID, params.EventID, &event)
+			if params.SendUpdates != "" {
+				call = call.SendUpdates(params.SendUpdates)
+			}
+			result, err = call.Context(ctx).Do()
+		}
+	case "events.instances":
+		call := c.serviceFor(calendarID).Events.Instances(calendarID, params.EventID).ShowDeleted(params.ShowDeleted).PageToken(params.PageToken)
+		if params.TimeMin != "" {
+			call = call.TimeMin(params.TimeMin)
+		}
+		if params.TimeMax != "" {
+			call = call.TimeMax(params.TimeMax)
+		}
+		if params.MaxResults > 0 {
+			call = call.MaxResults(params.MaxResults)
+		}
+		result, err = call.Context(ctx).Do()
+	case "events.list":
+		call := c.serviceFor(calendarID).Events.List(calendarID).ShowDeleted(params.ShowDeleted).SingleEvents(params.SingleEvents).PageToken(params.PageToken)
+		if params.TimeMin != "" {
+			call = call.TimeMin(params.TimeMin)
+		}
+		if params.TimeMax != "" {
+			call = call.TimeMax(params.TimeMax)
+		}
+		if params.Q != "" {
+			call = call.Q(params.Q)
+		}
+		if params.OrderBy != "" {
+			call = call.OrderBy(params.OrderBy)
+		}
+		if params.UpdatedMin != "" {
+			call = call.UpdatedMin(params.UpdatedMin)
+		}
+		if params.MaxResults > 0 {
+			call = call.MaxResults(params.MaxResults)
+		}
+		result, err = call.Context(ctx).Do()
+	case "freebusy.query":
+		var query calendar.FreeBusyRequest
+		if err := json.Unmarshal(params.Body, &query); err != nil {
+			return nil, invalidInputf("invalid freebusy body: %v", err)
+		}
+		result, err = c.service.Freebusy.Query(&query).Context(ctx).Do()
+	case "settings.get":
+		result, err = c.service.Settings.Get(params.Setting).Context(ctx).Do()
+	case "settings.list":
+		call := c.service.Settings.List().PageToken(params.PageToken)
+		if params.MaxResults > 0 {
+			call = call.MaxResults(params.MaxResults)
+		}
+		result, err = call.Context(ctx).Do()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+// needs returns the parameters the method can't do without
+func (m RawMethod) needs() []string {
+	var needs []string
+	for _, name := range m.params {
+		switch name {
+		case "eventId", "setting", "body":
+			needs = append(needs, name)
+		}
+	}
+	return needs
+}
+
+func (p RawParams) has(name string) bool {
+	switch name {
+	case "eventId":
+		return p.EventID != ""
+	case "setting":
+		return p.Setting != ""
+	case "body":
+		return len(p.Body) > 0
+	}
+	return true
+}
+
+// checkRawEvent holds an event body written through RawRequest to the
+// schedule constraints
+func (c *CalendarClient) checkRawEvent(e *calendar.Event) error {
+	if c.constraints == nil {
+		return nil
+	}
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		return err
+	}
+	return c.checkConstraints(e, loc)
+}
@@ -0,0 +1,44 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestRawMethods(t *testing.T) {
+	methods := RawMethods()
+	if !sort.SliceIsSorted(methods, func(i, j int) bool { return methods[i].Name < methods[j].Name }) {
+		t.Error("expected methods sorted by name")
+	}
+	m, ok := LookupRawMethod("events.patch")
+	if !ok || !m.Mutating {
+		t.Errorf("expected events.patch to be a mutating method, got %+v, %t", m, ok)
+	}
+	if _, ok := LookupRawMethod("events.import"); ok {
+		t.Error("events.import should not be supported")
+	}
+}
+
+func TestRawRequest_Refused(t *testing.T) {
+	c := &CalendarClient{calendarID: "primary@example.com"}
+	delegated := &CalendarClient{calendarID: "primary@example.com", delegate: &delegation{label: "Sam's assistant"}}
+
+	for name, tc := range map[string]struct {
+		client *CalendarClient
+		method string
+		params RawParams
+	}{
+		"unsupported method": {c, "events.import", RawParams{}},
+		"missing event ID":   {c, "events.get", RawParams{}},
+		"missing body":       {c, "freebusy.query", RawParams{}},
+		"delegated write":    {delegated, "events.delete", RawParams{EventID: "abc"}},
+	} {
+		_, err := tc.client.RawRequest(context.Background(), tc.method, tc.params)
+		var invalid *InvalidInputError
+		if !errors.As(err, &invalid) {
+			t.Errorf("%s: expected an invalid input error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+)
+
+// authState is the state parameter of the authorization URLs the server
+// hands out
+const authState = "google-calendar-mcp"
+
+// errNotOAuth is returned when asked to authorize a client that doesn't use
+// an OAuth user token
+var errNotOAuth = errors.New("only OAuth user tokens can be authorized again; this client uses other credentials")
+
+// Reauthorizer is implemented by services that can replace a revoked OAuth
+// token while running, such as CalendarClient
+type Reauthorizer interface {
+	// AuthURL returns where the user grants access again
+	AuthURL(ctx context.Context) (string, error)
+	// Reauthorize exchanges the authorization code the user got for a new
+	// token, which is saved and used from then on
+	Reauthorize(ctx context.Context, code string) error
+}
+
+// authURL returns where the user authorizes the OAuth client again
+func (ts *persistentTokenSource) authURL() string {
+	return ts.config.AuthCodeURL(authState, oauth2.AccessTypeOffline, oauth2.ApprovalForce)
+}
+
+// reauthorize replaces the token with one exchanged for an authorization
+// code, and saves it like a refreshed one
+func (ts *persistentTokenSource) reauthorize(ctx context.Context, code string) error {
+	token, err := ts.config.Exchange(ctx, code)
+	if err != nil {
+		var retrieveErr *oauth2.RetrieveError
+		if errors.As(err, &retrieveErr) && retrieveErr.ErrorCode == "invalid_grant" {
+			return invalidInputf("Google didn't accept the authorization code; it may have been used already or expired, so authorize again")
+		}
+		return fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+	return ts.setToken(token)
+}
+
+// setToken switches to a token the user just granted and saves it
+func (ts *persistentTokenSource) setToken(token *oauth2.Token) error {
+	if token.RefreshToken == "" {
+		return fmt.Errorf("Google returned no refresh token; remove the app's access at https://myaccount.google.com/permissions and authorize again")
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.token = token
+	if err := ts.store.saveToken(token); err != nil {
+		// The token works for this process; only a restart would need it
+		log.Printf("Failed to save the new OAuth token: %v", err)
+	}
+	return nil
+}
+
+// AuthCode returns the authorization code in what the user pasted: the code
+// itself, or the whole address the browser was sent to after granting
+// access
+func AuthCode(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if !strings.Contains(input, "://") {
+		return input, nil
+	}
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", invalidInputf("invalid redirect address: %v", err)
+	}
+	query := u.Query()
+	if e := query.Get("error"); e != "" {
+		return "", invalidInputf("authorization was not granted: %s", e)
+	}
+	if query.Get("code") == "" {
+		return "", invalidInputf("the address has no code parameter; copy it from the browser after allowing access")
+	}
+	return query.Get("code"), nil
+}
+
+func (c *CalendarClient) oauthTokens() (*persistentTokenSource, error) {
+	ts, ok := c.tokens.(*persistentTokenSource)
+	if !ok {
+		return nil, errNotOAuth
+	}
+	return ts, nil
+}
+
+func (c *CalendarClient) AuthURL(context.Context) (string, error) {
+	ts, err := c.oauthTokens()
+	if err != nil {
+		return "", err
+	}
+	return ts.authURL(), nil
+}
+
+func (c *CalendarClient) Reauthorize(ctx context.Context, code string) error {
+	ts, err := c.oauthTokens()
+	if err != nil {
+		return err
+	}
+	return ts.reauthorize(ctx, code)
+}
@@ -0,0 +1,49 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestPersistentTokenSource_Reauthorize(t *testing.T) {
+	ts, path := newTestTokenSource(t, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("code") != "4/abc" {
+			t.Errorf("unexpected token request %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"fresh-access","refresh_token":"fresh-refresh","token_type":"Bearer","expires_in":3600}`)
+	}, &oauth2.Token{RefreshToken: "revoked"})
+
+	if err := ts.reauthorize(context.Background(), "4/abc"); err != nil {
+		t.Fatal(err)
+	}
+	token, err := ts.Token()
+	if err != nil || token.AccessToken != "fresh-access" {
+		t.Errorf("expected the new token to be used, got %+v, %v", token, err)
+	}
+	saved, err := readToken(path)
+	if err != nil || saved.RefreshToken != "fresh-refresh" {
+		t.Errorf("expected the new token to be saved, got %+v, %v", saved, err)
+	}
+}
+
+func TestAuthCode(t *testing.T) {
+	for input, want := range map[string]string{
+		" 4/abc ": "4/abc",
+		"http://localhost/?state=google-calendar-mcp&code=4/abc&scope=x": "4/abc",
+	} {
+		if got, err := AuthCode(input); err != nil || got != want {
+			t.Errorf("AuthCode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"http://localhost/?error=access_denied", "http://localhost/"} {
+		if _, err := AuthCode(input); err == nil {
+			t.Errorf("AuthCode(%q): expected an error", input)
+		}
+	}
+}
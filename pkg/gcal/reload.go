@@ -0,0 +1,122 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+)
+
+// credentialsCheckInterval is how often the credentials file is checked
+// for changes while tokens are being requested
+const credentialsCheckInterval = 30 * time.Second
+
+// errNotReloadable is returned when asked to reload the credentials of a
+// client that wasn't created from a credentials file
+var errNotReloadable = errors.New("this client doesn't read its credentials from a file")
+
+// CredentialsReloader is implemented by services that can switch to
+// rotated credentials while running, such as CalendarClient
+type CredentialsReloader interface {
+	// ReloadCredentials reads the credentials file again and uses it for
+	// the tokens requested from then on
+	ReloadCredentials() error
+}
+
+// reloadingTokenSource hands out tokens for the credentials file at path
+// and switches to the new credentials when the file changes, so that a
+// rotated key is picked up without a restart. Until the new file can be
+// read, the previous credentials are kept.
+type reloadingTokenSource struct {
+	path string
+	now  func() time.Time
+
+	mu     sync.Mutex
+	tokens oauth2.TokenSource
+	// modTime and size identify the version of the file tokens come from
+	modTime time.Time
+	size    int64
+	// checked is when the file was last looked at
+	checked time.Time
+}
+
+func newReloadingTokenSource(path string) (*reloadingTokenSource, credentials, error) {
+	ts := &reloadingTokenSource{path: path, now: time.Now}
+	creds, err := ts.load()
+	if err != nil {
+		return nil, credentials{}, err
+	}
+	return ts, creds, nil
+}
+
+// load reads the credentials file and switches to its credentials
+func (ts *reloadingTokenSource) load() (credentials, error) {
+	info, err := os.Stat(ts.path)
+	if err != nil {
+		return credentials{}, err
+	}
+	data, err := os.ReadFile(ts.path)
+	if err != nil {
+		return credentials{}, err
+	}
+	creds, err := parseCredentials(data)
+	if err != nil {
+		return credentials{}, fmt.Errorf("credentials file %s: %w", ts.path, err)
+	}
+	gcreds, err := google.CredentialsFromJSONWithType(context.Background(), data, google.CredentialsType(creds.credType), calendar.CalendarScope)
+	if err != nil {
+		return credentials{}, fmt.Errorf("credentials file %s: %w", ts.path, err)
+	}
+	ts.tokens = gcreds.TokenSource
+	ts.modTime, ts.size = info.ModTime(), info.Size()
+	ts.checked = ts.now()
+	creds.tokens = ts
+	return creds, nil
+}
+
+// changed reports whether the file differs from the version in use
+func (ts *reloadingTokenSource) changed() bool {
+	info, err := os.Stat(ts.path)
+	// A file being replaced may be missing for a moment
+	return err == nil && (!info.ModTime().Equal(ts.modTime) || info.Size() != ts.size)
+}
+
+func (ts *reloadingTokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	if ts.now().Sub(ts.checked) >= credentialsCheckInterval {
+		ts.checked = ts.now()
+		if ts.changed() {
+			if _, err := ts.load(); err != nil {
+				log.Printf("Keeping the previous credentials: %v", err)
+			} else {
+				log.Printf("Reloaded the credentials from %s", ts.path)
+			}
+		}
+	}
+	tokens := ts.tokens
+	ts.mu.Unlock()
+	return tokens.Token()
+}
+
+// reload reads the file again whether or not it looks changed
+func (ts *reloadingTokenSource) reload() error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	_, err := ts.load()
+	return err
+}
+
+func (c *CalendarClient) ReloadCredentials() error {
+	ts, ok := c.tokens.(*reloadingTokenSource)
+	if !ok {
+		return errNotReloadable
+	}
+	return ts.reload()
+}
@@ -0,0 +1,50 @@
+package gcal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloadingTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.json")
+	os.WriteFile(path, []byte(`{"type":"service_account","client_email":"sa@p.iam.gserviceaccount.com","private_key_id":"1"}`), 0o600)
+	client, err := NewCalendarClient(path, "me@example.com", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := client.tokens.(*reloadingTokenSource)
+	now := time.Now()
+	ts.now = func() time.Time { return now }
+	first := ts.tokens
+
+	// A rotated key is picked up at the next check
+	os.WriteFile(path, []byte(`{"type":"service_account","client_email":"sa@p.iam.gserviceaccount.com","private_key_id":"rotated"}`), 0o600)
+	ts.Token()
+	if ts.tokens != first {
+		t.Fatal("expected the file not to be checked again right away")
+	}
+	now = now.Add(credentialsCheckInterval)
+	ts.Token()
+	if ts.tokens == first {
+		t.Fatal("expected the rotated key to be loaded")
+	}
+
+	// A broken file leaves the previous credentials in place
+	second := ts.tokens
+	os.WriteFile(path, []byte(`{"type":"authorized_user"}`), 0o600)
+	if err := client.ReloadCredentials(); err == nil {
+		t.Error("expected the broken file to be reported")
+	}
+	now = now.Add(credentialsCheckInterval)
+	ts.Token()
+	if ts.tokens != second {
+		t.Error("expected the previous credentials to be kept")
+	}
+
+	if err := (&CalendarClient{}).ReloadCredentials(); !errors.Is(err, errNotReloadable) {
+		t.Errorf("expected errNotReloadable for a client without a credentials file, got %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package gcal
+
+import (
+	"context"
+	"strings"
+)
+
+type requestInfoKey struct{}
+
+// RequestInfo identifies the MCP request a calendar call is made for, so
+// that logs and the changes recorded in delegated mode can be traced back
+// to the client's request
+type RequestInfo struct {
+	SessionID string
+	RequestID string
+	// TraceID is the W3C trace ID the client sent along, if any
+	TraceID string
+}
+
+// WithRequestInfo returns a context whose calendar calls are made for the
+// request info describes
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request info attached to ctx, zero without
+// one
+func RequestInfoFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// String formats the info for log lines, e.g. "session X, request 7"
+func (r RequestInfo) String() string {
+	var parts []string
+	for _, p := range [][2]string{{"session", r.SessionID}, {"request", r.RequestID}, {"trace", r.TraceID}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+" "+p[1])
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,42 @@
+package gcal
+
+import (
+	"context"
+	"time"
+)
+
+// maxSearchResults bounds the events a search returns; a query matching
+// more than that is too broad to be useful
+const maxSearchResults = 250
+
+// SearchEvents returns the events of calendarID whose summary, description,
+// location or guests contain query, as Google's search matches them, in
+// order of their start. startDate and endDate (YYYY-MM-DD, inclusive)
+// bound the search; either may be empty to search the whole past or
+// future.
+func (c *CalendarClient) SearchEvents(ctx context.Context, calendarID, query, startDate, endDate string) ([]CalendarEvent, error) {
+	if query == "" {
+		return nil, invalidInputf("query is empty")
+	}
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var timeMin, timeMax string
+	if startDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", startDate, loc)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		timeMin = start.Format(time.RFC3339)
+	}
+	if endDate != "" {
+		end, err := time.ParseInLocation("2006-01-02", endDate, loc)
+		if err != nil {
+			return nil, invalidInput(err)
+		}
+		timeMax = end.AddDate(0, 0, 1).Format(time.RFC3339)
+	}
+	return c.listEvents(ctx, calendarID, query, timeMin, timeMax, maxSearchResults)
+}
@@ -0,0 +1,286 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// Occurrences of a recurring event a change or a deletion applies to
+const (
+	ApplyToThisEvent        = "this_event"
+	ApplyToThisAndFollowing = "this_and_following"
+	ApplyToAll              = "all"
+)
+
+// SeriesScope picks the occurrences of a recurring event a change or a
+// deletion applies to. The zero value acts on whatever the event ID names,
+// one occurrence or a whole series, as the Calendar API does.
+type SeriesScope struct {
+	// ApplyTo is one of the ApplyTo constants
+	ApplyTo string
+	// Occurrence is the date, YYYY-MM-DD, of the occurrence meant when the
+	// event ID is that of a whole series
+	Occurrence string
+}
+
+// resolveScope finds the series event belongs to and the occurrence scope
+// means. series is nil for events that don't repeat, and occurrence is nil
+// when a whole series is meant.
+func (c *CalendarClient) resolveScope(ctx context.Context, calendarID string, event *calendar.Event, scope SeriesScope) (series, occurrence *calendar.Event, err error) {
+	switch scope.ApplyTo {
+	case ApplyToThisEvent, ApplyToThisAndFollowing, ApplyToAll:
+	default:
+		return nil, nil, invalidInputf("apply_to must be %s, %s or %s", ApplyToThisEvent, ApplyToThisAndFollowing, ApplyToAll)
+	}
+
+	switch {
+	case event.RecurringEventId != "":
+		series, err := c.serviceFor(calendarID).Events.Get(calendarID, event.RecurringEventId).Context(ctx).Do()
+		if err != nil {
+			return nil, nil, err
+		}
+		return series, event, nil
+	case len(event.Recurrence) == 0:
+		return nil, event, nil
+	case scope.ApplyTo == ApplyToAll:
+		return event, nil, nil
+	case scope.Occurrence == "":
+		return nil, nil, invalidInputf("event %s is a recurring series; give the date of the occurrence meant, or the ID of the occurrence", event.Id)
+	}
+
+	occurrence, err = c.findOccurrence(ctx, calendarID, event, scope.Occurrence)
+	if err != nil {
+		return nil, nil, err
+	}
+	return event, occurrence, nil
+}
+
+// findOccurrence returns the occurrence of series taking place on date
+func (c *CalendarClient) findOccurrence(ctx context.Context, calendarID string, series *calendar.Event, date string) (*calendar.Event, error) {
+	loc, err := time.LoadLocation(c.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	day, err := time.ParseInLocation("2006-01-02", date, loc)
+	if err != nil {
+		return nil, invalidInput(err)
+	}
+	instances, err := c.serviceFor(calendarID).Events.Instances(calendarID, series.Id).
+		TimeMin(day.Format(time.RFC3339)).
+		TimeMax(day.AddDate(0, 0, 1).Format(time.RFC3339)).
+		Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range instances.Items {
+		if e.Status != "cancelled" {
+			return e, nil
+		}
+	}
+	return nil, invalidInputf("the series %s has no occurrence on %s", series.Id, date)
+}
+
+// updateOccurrences applies updates to the occurrences of a recurring event
+// their scope selects
+func (c *CalendarClient) updateOccurrences(ctx context.Context, calendarID string, event *calendar.Event, updates EventUpdates) (*calendar.Event, error) {
+	series, occurrence, err := c.resolveScope(ctx, calendarID, event, updates.Scope)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case series == nil, updates.Scope.ApplyTo == ApplyToThisEvent:
+		return c.updateStored(ctx, calendarID, occurrence, updates)
+	case updates.Scope.ApplyTo == ApplyToThisAndFollowing && !firstOccurrence(series, occurrence):
+		return c.splitSeries(ctx, calendarID, series, occurrence, updates)
+	case updates.Scope.ApplyTo == ApplyToAll && updates.Date != nil:
+		// Moving the series would drop or shift occurrences before the new
+		// date rather than move each one
+		return nil, invalidInputf("a new date can't be given for all occurrences; change start_time and end_time only, or use %s", ApplyToThisAndFollowing)
+	}
+	return c.updateStored(ctx, calendarID, series, updates)
+}
+
+// DeleteOccurrences deletes the occurrences of a recurring event of any
+// calendar the credentials can write to that scope selects; an event that
+// doesn't repeat is deleted whatever the scope
+func (c *CalendarClient) DeleteOccurrences(ctx context.Context, calendarID, eventID string, scope SeriesScope) error {
+	if scope.ApplyTo == "" {
+		return c.deleteStored(ctx, calendarID, eventID)
+	}
+	event, err := c.serviceFor(calendarID).Events.Get(calendarID, eventID).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	series, occurrence, err := c.resolveScope(ctx, calendarID, event, scope)
+	if err != nil {
+		return err
+	}
+	switch {
+	case series == nil, scope.ApplyTo == ApplyToThisEvent:
+		return c.deleteStored(ctx, calendarID, occurrence.Id)
+	case scope.ApplyTo == ApplyToThisAndFollowing && !firstOccurrence(series, occurrence):
+		return c.endSeries(ctx, calendarID, series, occurrence)
+	}
+	return c.deleteStored(ctx, calendarID, series.Id)
+}
+
+// splitSeries ends series before occurrence and continues it from there as
+// a new series with the updates applied, which is how Google Calendar
+// changes an occurrence and the following ones. Occurrences changed on
+// their own after the split stay with the old series.
+func (c *CalendarClient) splitSeries(ctx context.Context, calendarID string, series, occurrence *calendar.Event, updates EventUpdates) (*calendar.Event, error) {
+	at, allDay, err := originalStart(occurrence)
+	if err != nil {
+		return nil, err
+	}
+	done, err := c.occurrencesBefore(ctx, calendarID, series, at)
+	if err != nil {
+		return nil, err
+	}
+	_, after := SplitRecurrence(series.Recurrence, at, allDay, done)
+
+	following := *series
+	following.Id, following.ICalUID, following.Etag, following.HtmlLink = "", "", "", ""
+	following.Created, following.Updated, following.Sequence = "", "", 0
+	following.Recurrence = after
+	if allDay {
+		start, _ := time.Parse("2006-01-02", series.Start.Date)
+		end, _ := time.Parse("2006-01-02", series.End.Date)
+		following.Start = &calendar.EventDateTime{Date: at.Format("2006-01-02")}
+		following.End = &calendar.EventDateTime{Date: at.Add(end.Sub(start)).Format("2006-01-02")}
+	} else {
+		start, _ := time.Parse(time.RFC3339, series.Start.DateTime)
+		end, _ := time.Parse(time.RFC3339, series.End.DateTime)
+		following.Start = &calendar.EventDateTime{DateTime: at.Format(time.RFC3339), TimeZone: series.Start.TimeZone}
+		following.End = &calendar.EventDateTime{DateTime: at.Add(end.Sub(start)).Format(time.RFC3339), TimeZone: series.End.TimeZone}
+	}
+	// Invalid updates are rejected before the series is touched
+	if err := c.applyUpdates(&following, updates); err != nil {
+		return nil, err
+	}
+	c.delegate.labelEvent(ctx, &following, actionCreated, c.calendarID, time.Now())
+
+	svc := c.serviceFor(calendarID)
+	created, err := svc.Events.Insert(calendarID, &following).Context(ctx).Do()
+	if err != nil {
+		return nil, c.calendarError(calendarID, err)
+	}
+	ReportCreated(ctx)
+	if err := c.endSeries(ctx, calendarID, series, occurrence); err != nil {
+		// Don't leave the following occurrences twice in the calendar
+		svc.Events.Delete(calendarID, created.Id).Context(ctx).Do()
+		return nil, err
+	}
+	return created, nil
+}
+
+// endSeries makes series stop before occurrence
+func (c *CalendarClient) endSeries(ctx context.Context, calendarID string, series, occurrence *calendar.Event) error {
+	at, allDay, err := originalStart(occurrence)
+	if err != nil {
+		return err
+	}
+	before, _ := SplitRecurrence(series.Recurrence, at, allDay, 0)
+	patch := &calendar.Event{Recurrence: before}
+	c.delegate.tag(ctx, patch, actionUpdated, time.Now())
+	_, err = c.serviceFor(calendarID).Events.Patch(calendarID, series.Id, patch).Context(ctx).Do()
+	return err
+}
+
+// occurrencesBefore counts the occurrences series had before at, cancelled
+// ones included, which a COUNT in its rule covers. Series without a COUNT
+// aren't listed.
+func (c *CalendarClient) occurrencesBefore(ctx context.Context, calendarID string, series *calendar.Event, at time.Time) (int, error) {
+	if !strings.Contains(strings.Join(series.Recurrence, "\n"), "COUNT=") {
+		return 0, nil
+	}
+	done := 0
+	err := c.serviceFor(calendarID).Events.Instances(calendarID, series.Id).
+		ShowDeleted(true).
+		Pages(ctx, func(page *calendar.Events) error {
+			for _, e := range page.Items {
+				if start, _, err := originalStart(e); err == nil && start.Before(at) {
+					done++
+				}
+			}
+			return nil
+		})
+	return done, err
+}
+
+// firstOccurrence reports whether occurrence is the one a series starts
+// with, so that it and the following ones are the whole series
+func firstOccurrence(series, occurrence *calendar.Event) bool {
+	at, _, err := originalStart(occurrence)
+	if err != nil || series.Start == nil {
+		return false
+	}
+	if series.Start.Date != "" {
+		return at.Format("2006-01-02") == series.Start.Date
+	}
+	start, err := time.Parse(time.RFC3339, series.Start.DateTime)
+	return err == nil && start.Equal(at)
+}
+
+// originalStart returns when the series pattern schedules an occurrence,
+// and whether the series is all-day
+func originalStart(occurrence *calendar.Event) (time.Time, bool, error) {
+	t := occurrence.OriginalStartTime
+	if t == nil {
+		t = occurrence.Start
+	}
+	if t == nil {
+		return time.Time{}, false, fmt.Errorf("occurrence %s has no start", occurrence.Id)
+	}
+	if t.DateTime == "" {
+		day, err := time.Parse("2006-01-02", t.Date)
+		return day, true, err
+	}
+	start, err := time.Parse(time.RFC3339, t.DateTime)
+	return start, false, err
+}
+
+// SplitRecurrence divides the recurrence lines of a series at the
+// occurrence scheduled at: before ends the series just ahead of it, after
+// continues the series from it on. done is the number of occurrences
+// before at, which a COUNT in the rule is reduced by. Lines other than
+// RRULE, such as EXDATE, are kept in both.
+func SplitRecurrence(rules []string, at time.Time, allDay bool, done int) (before, after []string) {
+	until := at.Add(-time.Second).UTC().Format("20060102T150405Z")
+	if allDay {
+		until = at.AddDate(0, 0, -1).Format("20060102")
+	}
+	for _, line := range rules {
+		rule, ok := strings.CutPrefix(line, "RRULE:")
+		if !ok {
+			before = append(before, line)
+			after = append(after, line)
+			continue
+		}
+		var ended, continued []string
+		for _, part := range strings.Split(rule, ";") {
+			key, value, _ := strings.Cut(part, "=")
+			switch key {
+			case "UNTIL":
+				continued = append(continued, part)
+			case "COUNT":
+				if n, err := strconv.Atoi(value); err == nil && n > done {
+					continued = append(continued, "COUNT="+strconv.Itoa(n-done))
+				} else {
+					continued = append(continued, "COUNT=1")
+				}
+			default:
+				ended = append(ended, part)
+				continued = append(continued, part)
+			}
+		}
+		before = append(before, "RRULE:"+strings.Join(append(ended, "UNTIL="+until), ";"))
+		after = append(after, "RRULE:"+strings.Join(continued, ";"))
+	}
+	return before, after
+}
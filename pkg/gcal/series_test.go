@@ -0,0 +1,35 @@
+package gcal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestSplitRecurrence(t *testing.T) {
+	at := time.Date(2026, 3, 23, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+	rules := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=10", "EXDATE;TZID=Europe/Berlin:20260316T100000"}
+	before, after := SplitRecurrence(rules, at, false, 3)
+	if want := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;UNTIL=20260323T085959Z", rules[1]}; !reflect.DeepEqual(before, want) {
+		t.Errorf("before: got %q, want %q", before, want)
+	}
+	if want := []string{"RRULE:FREQ=WEEKLY;BYDAY=MO;COUNT=7", rules[1]}; !reflect.DeepEqual(after, want) {
+		t.Errorf("after: got %q, want %q", after, want)
+	}
+
+	before, after = SplitRecurrence([]string{"RRULE:FREQ=DAILY;UNTIL=20260401"}, time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC), true, 0)
+	if before[0] != "RRULE:FREQ=DAILY;UNTIL=20260322" || after[0] != "RRULE:FREQ=DAILY;UNTIL=20260401" {
+		t.Errorf("unexpected all-day split %q, %q", before, after)
+	}
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	series := &calendar.Event{Start: &calendar.EventDateTime{DateTime: "2026-03-09T10:00:00+01:00"}}
+	first := &calendar.Event{OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-03-09T09:00:00Z"}}
+	later := &calendar.Event{OriginalStartTime: &calendar.EventDateTime{DateTime: "2026-03-16T10:00:00+01:00"}}
+	if !firstOccurrence(series, first) || firstOccurrence(series, later) {
+		t.Error("expected only the occurrence at the series start to be the first")
+	}
+}
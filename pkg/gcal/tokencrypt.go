@@ -0,0 +1,231 @@
+package gcal
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/cloudkms/v1"
+)
+
+// Encryption schemes of sealed token files
+const (
+	sealedWithPassphrase = "scrypt-aes-256-gcm"
+	sealedWithKMS        = "gcp-kms"
+)
+
+// scrypt parameters recommended for interactive logins as of 2017; deriving
+// the key takes about 100ms, once per start and refresh
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptSaltSz = 16
+)
+
+// TokenEncryption is the key an OAuth token file is encrypted with at rest,
+// for headless hosts without a keychain. Set either field.
+type TokenEncryption struct {
+	// Passphrase derives an AES-256-GCM key with scrypt
+	Passphrase string
+	// KMSKey is the resource name of a Cloud KMS symmetric key, like
+	// projects/p/locations/global/keyRings/r/cryptoKeys/k, used with
+	// application default credentials
+	KMSKey string
+}
+
+// sealedToken is the content of an encrypted token file
+type sealedToken struct {
+	Encryption string `json:"encryption"`
+	KMSKey     string `json:"kms_key,omitempty"`
+	Salt       []byte `json:"salt,omitempty"`
+	Nonce      []byte `json:"nonce,omitempty"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func isSealedToken(data []byte) bool {
+	var sealed sealedToken
+	return json.Unmarshal(data, &sealed) == nil && sealed.Encryption != ""
+}
+
+// tokenCipher encrypts and decrypts token files
+type tokenCipher interface {
+	seal(ctx context.Context, plaintext []byte) (*sealedToken, error)
+	open(ctx context.Context, sealed *sealedToken) ([]byte, error)
+}
+
+func newTokenCipher(ctx context.Context, enc TokenEncryption) (tokenCipher, error) {
+	switch {
+	case enc.Passphrase != "" && enc.KMSKey != "":
+		return nil, errors.New("encrypt the OAuth token with either a passphrase or a KMS key, not both")
+	case enc.Passphrase != "":
+		return passphraseCipher(enc.Passphrase), nil
+	case enc.KMSKey != "":
+		srv, err := cloudkms.NewService(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the Cloud KMS client: %w", err)
+		}
+		return kmsCipher{keys: srv.Projects.Locations.KeyRings.CryptoKeys, key: enc.KMSKey}, nil
+	}
+	return nil, errors.New("an encrypted OAuth token needs a passphrase or a KMS key")
+}
+
+// passphraseCipher encrypts with a key derived from a passphrase, with a
+// new salt every time
+type passphraseCipher string
+
+func (p passphraseCipher) aead(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(p), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (p passphraseCipher) seal(_ context.Context, plaintext []byte) (*sealedToken, error) {
+	salt := make([]byte, scryptSaltSz)
+	rand.Read(salt)
+	aead, err := p.aead(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	return &sealedToken{
+		Encryption: sealedWithPassphrase,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(sealedWithPassphrase)),
+	}, nil
+}
+
+func (p passphraseCipher) open(_ context.Context, sealed *sealedToken) ([]byte, error) {
+	if sealed.Encryption != sealedWithPassphrase {
+		return nil, fmt.Errorf("the token is encrypted with %s, not a passphrase", sealed.Encryption)
+	}
+	aead, err := p.aead(sealed.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	plaintext, err := aead.Open(nil, sealed.Nonce, sealed.Ciphertext, []byte(sealedWithPassphrase))
+	if err != nil {
+		return nil, errors.New("wrong passphrase, or the file was modified")
+	}
+	return plaintext, nil
+}
+
+// kmsCipher encrypts with a Cloud KMS key, which never leaves KMS
+type kmsCipher struct {
+	keys *cloudkms.ProjectsLocationsKeyRingsCryptoKeysService
+	key  string
+}
+
+func (k kmsCipher) seal(ctx context.Context, plaintext []byte) (*sealedToken, error) {
+	resp, err := k.keys.Encrypt(k.key, &cloudkms.EncryptRequest{Plaintext: base64.StdEncoding.EncodeToString(plaintext)}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("KMS encryption failed: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(resp.Ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	return &sealedToken{Encryption: sealedWithKMS, KMSKey: k.key, Ciphertext: ciphertext}, nil
+}
+
+func (k kmsCipher) open(ctx context.Context, sealed *sealedToken) ([]byte, error) {
+	if sealed.Encryption != sealedWithKMS {
+		return nil, fmt.Errorf("the token is encrypted with %s, not a KMS key", sealed.Encryption)
+	}
+	// The ciphertext names the key version itself; the key given has to be
+	// the one it was encrypted with
+	resp, err := k.keys.Decrypt(k.key, &cloudkms.DecryptRequest{Ciphertext: base64.StdEncoding.EncodeToString(sealed.Ciphertext)}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("KMS decryption failed: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// encryptedTokenStore saves tokens to a file, encrypted
+type encryptedTokenStore struct {
+	ctx    context.Context
+	path   string
+	cipher tokenCipher
+}
+
+func (e encryptedTokenStore) saveToken(token *oauth2.Token) error {
+	data, err := tokenJSON(token)
+	if err != nil {
+		return err
+	}
+	sealed, err := e.cipher.seal(e.ctx, data)
+	if err != nil {
+		return err
+	}
+	if data, err = json.MarshalIndent(sealed, "", "  "); err != nil {
+		return err
+	}
+	return writeTokenFile(e.path, data)
+}
+
+// loadToken decrypts the token file in memory. A plaintext file is read as
+// is and encrypted in place.
+func (e encryptedTokenStore) loadToken() (*oauth2.Token, error) {
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		return nil, err
+	}
+	if !isSealedToken(data) {
+		token, err := parseToken(data, "OAuth token file "+e.path)
+		if err != nil {
+			return nil, err
+		}
+		if err := e.saveToken(token); err != nil {
+			return nil, fmt.Errorf("failed to encrypt the OAuth token file %s: %w", e.path, err)
+		}
+		log.Printf("Encrypted the OAuth token file %s", e.path)
+		return token, nil
+	}
+
+	var sealed sealedToken
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, err
+	}
+	plaintext, err := e.cipher.open(e.ctx, &sealed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the OAuth token file %s: %w", e.path, err)
+	}
+	return parseToken(plaintext, "OAuth token file "+e.path)
+}
+
+// NewEncryptedOAuthCalendarClient is like NewOAuthCalendarClient, but keeps
+// tokenFile encrypted with enc. The token is only decrypted in memory; a
+// plaintext tokenFile is encrypted on the first start.
+func NewEncryptedOAuthCalendarClient(clientFile, tokenFile, calendarID, timezone string, enc TokenEncryption) (*CalendarClient, error) {
+	ctx := context.Background()
+	c, err := newTokenCipher(ctx, enc)
+	if err != nil {
+		return nil, err
+	}
+	store := encryptedTokenStore{ctx: ctx, path: tokenFile, cipher: c}
+	token, err := store.loadToken()
+	if err != nil {
+		return nil, err
+	}
+	return newOAuthCalendarClient(clientFile, token, store, calendarID, timezone)
+}
@@ -0,0 +1,55 @@
+package gcal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestEncryptedTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := writeToken(path, &oauth2.Token{AccessToken: "a", RefreshToken: "secret-refresh"}); err != nil {
+		t.Fatal(err)
+	}
+	store := encryptedTokenStore{ctx: context.Background(), path: path, cipher: passphraseCipher("correct horse")}
+
+	// A plaintext file is read and encrypted in place
+	token, err := store.loadToken()
+	if err != nil || token.RefreshToken != "secret-refresh" {
+		t.Fatalf("expected the plaintext token, got %+v, %v", token, err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret-refresh") || !isSealedToken(data) {
+		t.Fatalf("expected the file to be encrypted, got %s", data)
+	}
+
+	if err := store.saveToken(&oauth2.Token{AccessToken: "b", RefreshToken: "rotated"}); err != nil {
+		t.Fatal(err)
+	}
+	if token, err := store.loadToken(); err != nil || token.RefreshToken != "rotated" {
+		t.Errorf("expected the rotated token, got %+v, %v", token, err)
+	}
+
+	wrong := encryptedTokenStore{ctx: context.Background(), path: path, cipher: passphraseCipher("battery staple")}
+	if _, err := wrong.loadToken(); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected a wrong passphrase error, got %v", err)
+	}
+	if _, err := readToken(path); err == nil || !strings.Contains(err.Error(), "is encrypted") {
+		t.Errorf("expected the plaintext reader to point at encryption, got %v", err)
+	}
+}
+
+func TestNewTokenCipher(t *testing.T) {
+	for name, enc := range map[string]TokenEncryption{
+		"none": {},
+		"both": {Passphrase: "p", KMSKey: "projects/p/locations/global/keyRings/r/cryptoKeys/k"},
+	} {
+		if _, err := newTokenCipher(context.Background(), enc); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
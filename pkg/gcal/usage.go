@@ -0,0 +1,24 @@
+package gcal
+
+import "context"
+
+type createdKey struct{}
+
+// CreatedFunc is told about every event a calendar call creates, so that
+// the caller can keep count against Google's calendar usage limits
+type CreatedFunc func()
+
+// WithCreatedReporter returns a context whose calendar calls call fn for
+// every event they create
+func WithCreatedReporter(ctx context.Context, fn CreatedFunc) context.Context {
+	return context.WithValue(ctx, createdKey{}, fn)
+}
+
+// ReportCreated tells the reporter attached to ctx, if there is one, that an
+// event was created. Service implementations call it for every event they
+// insert.
+func ReportCreated(ctx context.Context) {
+	if fn, ok := ctx.Value(createdKey{}).(CreatedFunc); ok {
+		fn()
+	}
+}
@@ -0,0 +1,68 @@
+package gcal
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// CreateOutOfOffice blocks the time from start to end with an out-of-office
+// event. With autoDecline, Google Calendar also declines invitations that
+// arrive later for that time.
+func (c *CalendarClient) CreateOutOfOffice(ctx context.Context, summary string, start, end time.Time, autoDecline bool, message string) (*calendar.Event, error) {
+	mode := "declineNone"
+	if autoDecline {
+		mode = "declineOnlyNewConflictingInvitations"
+	}
+	event := &calendar.Event{
+		Summary:   summary,
+		EventType: "outOfOffice",
+		Start: &calendar.EventDateTime{
+			DateTime: start.Format(time.RFC3339),
+			TimeZone: c.timezone,
+		},
+		End: &calendar.EventDateTime{
+			DateTime: end.Format(time.RFC3339),
+			TimeZone: c.timezone,
+		},
+		OutOfOfficeProperties: &calendar.EventOutOfOfficeProperties{
+			AutoDeclineMode: mode,
+			DeclineMessage:  message,
+		},
+	}
+	c.delegate.labelEvent(ctx, event, actionCreated, c.calendarID, time.Now())
+	created, err := c.serviceFor(c.calendarID).Events.Insert(c.calendarID, event).Context(ctx).Do()
+	if err != nil {
+		return nil, c.calendarError(c.calendarID, err)
+	}
+	ReportCreated(ctx)
+	return created, nil
+}
+
+// RespondToEvent sets the calendar owner's response to an invitation, e.g.
+// "declined", and notifies the other guests
+func (c *CalendarClient) RespondToEvent(ctx context.Context, eventID, status, comment string) error {
+	existing, err := c.serviceFor(c.calendarID).Events.Get(c.calendarID, eventID).Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, a := range existing.Attendees {
+		if a.Self {
+			a.ResponseStatus = status
+			a.Comment = c.delegate.responseComment(comment, c.calendarID)
+			found = true
+		}
+	}
+	if !found {
+		return invalidInputf("you are not a guest of event %s", eventID)
+	}
+
+	patch := &calendar.Event{Attendees: existing.Attendees}
+	c.delegate.tag(ctx, patch, actionResponded, time.Now())
+	_, err = c.serviceFor(c.calendarID).Events.Patch(c.calendarID, eventID, patch).
+		SendUpdates("all").Context(ctx).Do()
+	return err
+}
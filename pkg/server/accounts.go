@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// accountArgDescription describes the account argument tools take when
+// several accounts are configured
+const accountArgDescription = "Account to act as, one of those list_accounts reports; defaults to the first"
+
+// accounts returns the account registry the server acts on, nil when it
+// acts on a single account
+func (s *Server) accounts() *gcal.Accounts {
+	accounts, _ := s.settings().calendar.(*gcal.Accounts)
+	return accounts
+}
+
+// accountService returns the client the calls naming account go to, the
+// default account's for ""
+func (s *Server) accountService(account string) gcal.Service {
+	if accounts := s.accounts(); accounts != nil && account != "" {
+		if svc, ok := accounts.Account(account); ok {
+			return svc
+		}
+	}
+	return s.settings().calendar
+}
+
+// primaryCalendar returns the calendar the account of ctx acts on when a
+// call names none
+func (s *Server) primaryCalendar(ctx context.Context) string {
+	return s.accountService(gcal.AccountFrom(ctx)).CalendarID()
+}
+
+// accountToolDefinition adds the account argument to the schema of t
+func accountToolDefinition(t toolDefinition, names []string) toolDefinition {
+	return withArgument(t, "account", map[string]interface{}{
+		"type":        "string",
+		"description": accountArgDescription,
+		"enum":        names,
+	})
+}
+
+// withArgument adds an argument to the schema of t without modifying the
+// schema shared with other sessions
+func withArgument(t toolDefinition, name string, prop map[string]interface{}) toolDefinition {
+	properties := make(map[string]interface{})
+	for k, v := range t.inputSchema["properties"].(map[string]interface{}) {
+		properties[k] = v
+	}
+	properties[name] = prop
+
+	schema := make(map[string]interface{})
+	for k, v := range t.inputSchema {
+		schema[k] = v
+	}
+	schema["properties"] = properties
+	t.inputSchema = schema
+	return t
+}
+
+// accountArg returns the account the arguments of a tool call name, "" for
+// the default one
+func (s *Server) accountArg(args json.RawMessage) (string, error) {
+	var given struct {
+		Account string `json:"account"`
+	}
+	if len(args) > 0 {
+		// Malformed arguments are reported by the tool itself
+		json.Unmarshal(args, &given)
+	}
+	if given.Account == "" {
+		return "", nil
+	}
+	accounts := s.accounts()
+	if accounts == nil {
+		return "", fmt.Errorf("account is only accepted when several accounts are configured")
+	}
+	if _, ok := accounts.Account(given.Account); !ok {
+		return "", fmt.Errorf("unknown account %q; configured accounts: %s", given.Account, strings.Join(accounts.Names(), ", "))
+	}
+	return given.Account, nil
+}
+
+type accountInfo struct {
+	Name       string `json:"name"`
+	CalendarID string `json:"calendarId"`
+	Default    bool   `json:"default"`
+}
+
+type accountList struct {
+	Accounts []accountInfo `json:"accounts"`
+}
+
+// listAccountsInput is the arguments of list_accounts, which takes none
+type listAccountsInput struct{}
+
+func (s *Server) callListAccounts(context.Context, listAccountsInput) (accountList, error) {
+	accounts := s.accounts()
+	if accounts == nil {
+		return accountList{Accounts: []accountInfo{{Name: "default", CalendarID: s.settings().calendar.CalendarID(), Default: true}}}, nil
+	}
+	var list accountList
+	for i, name := range accounts.Names() {
+		svc, _ := accounts.Account(name)
+		list.Accounts = append(list.Accounts, accountInfo{Name: name, CalendarID: svc.CalendarID(), Default: i == 0})
+	}
+	return list, nil
+}
+
+func (l accountList) toolText(*Server) string {
+	if len(l.Accounts) == 1 {
+		return fmt.Sprintf("One account is configured, acting on %s.", l.Accounts[0].CalendarID)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Accounts (%d); pass account to a tool to act as one of them:\n", len(l.Accounts))
+	for _, a := range l.Accounts {
+		fmt.Fprintf(&b, "- %s: %s", a.Name, a.CalendarID)
+		if a.Default {
+			b.WriteString(" (default)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func newAccountsServer(t *testing.T) (*Server, *fakeCalendar, *fakeCalendar) {
+	t.Helper()
+	work, personal := &fakeCalendar{}, &fakeCalendar{}
+	accounts := gcal.NewAccounts()
+	accounts.Add("work", work)
+	accounts.Add("personal", personal.WithDefaults("me@home.example", ""))
+	s := New(accounts, &bytes.Buffer{})
+	s.state = stateReady
+	return s, work, personal
+}
+
+func TestAccounts_ToolCall(t *testing.T) {
+	s, work, personal := newAccountsServer(t)
+
+	call := func(args string) *JSONRPCResponse {
+		params, _ := json.Marshal(map[string]interface{}{"name": toolDeleteEvent, "arguments": json.RawMessage(args)})
+		return s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+	}
+	call(`{"event_id":"evt-1","account":"personal"}`)
+	if personal.deletedID != "evt-1" || work.deletedID != "" {
+		t.Errorf("expected the personal account to delete the event, got %q and %q", personal.deletedID, work.deletedID)
+	}
+	call(`{"event_id":"evt-2"}`)
+	if work.deletedID != "evt-2" {
+		t.Errorf("expected the default account without account, got %q", work.deletedID)
+	}
+	if resp := call(`{"event_id":"evt-3","account":"other"}`); resp.Error == nil || !strings.Contains(resp.Error.Message, "work, personal") {
+		t.Errorf("expected an unknown account to be rejected naming the accounts, got %+v", resp.Error)
+	}
+
+	single := newTestServer(&fakeCalendar{})
+	params, _ := json.Marshal(map[string]interface{}{"name": toolDeleteEvent, "arguments": map[string]string{"event_id": "evt-1", "account": "work"}})
+	if resp := single.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params}); resp.Error == nil {
+		t.Error("expected account to be rejected with a single account")
+	}
+}
+
+func TestAccounts_ToolsList(t *testing.T) {
+	s, _, _ := newAccountsServer(t)
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/list"})
+	for _, tool := range resp.Result.(map[string]interface{})["tools"].([]map[string]interface{}) {
+		properties := tool["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})
+		_, ok := properties["account"]
+		if local := tool["name"] == toolServerVersion || tool["name"] == toolListAccounts; ok == local {
+			t.Errorf("%s: unexpected account argument presence %t", tool["name"], ok)
+		}
+	}
+	if _, ok := listEventsInputSchema["properties"].(map[string]interface{})["account"]; ok {
+		t.Error("the shared schema should not be modified")
+	}
+}
+
+func TestCallListAccounts(t *testing.T) {
+	s, _, _ := newAccountsServer(t)
+	resp := s.callTool(context.Background(), &toolCall{name: toolListAccounts, id: float64(1)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"- work: test@example.com (default)", "- personal: me@home.example\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	resp = newTestServer(&fakeCalendar{}).callTool(context.Background(), &toolCall{name: toolListAccounts, id: float64(1)})
+	if text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string); !strings.Contains(text, "One account") {
+		t.Errorf("unexpected text for a single account: %s", text)
+	}
+}
+
+func TestAccounts_AccessRoles(t *testing.T) {
+	s, work, personal := newAccountsServer(t)
+	work.calendarList, work.calendarRoles = []string{"test@example.com"}, map[string]string{"test@example.com": "reader"}
+	personal.calendarList, personal.calendarRoles = []string{"me@home.example"}, map[string]string{"me@home.example": "owner"}
+	s.checkCalendars(context.Background())
+
+	call := func(args string) *JSONRPCResponse {
+		params, _ := json.Marshal(map[string]interface{}{"name": toolDeleteEvent, "arguments": json.RawMessage(args)})
+		return s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+	}
+	// Each call is checked against the account it names
+	if resp := call(`{"event_id":"evt-1","account":"personal"}`); resp.Error != nil {
+		t.Errorf("expected the personal account to be writable, got %+v", resp.Error)
+	}
+	if resp := call(`{"event_id":"evt-2"}`); resp.Error == nil || !strings.Contains(resp.Error.Message, "read-only access to calendar test@example.com") {
+		t.Errorf("expected the default account to be refused, got %+v", resp.Error)
+	}
+	if personal.deletedID != "evt-1" || work.deletedID != "" {
+		t.Errorf("unexpected deletions: %q and %q", personal.deletedID, work.deletedID)
+	}
+
+	// Mutating tools stay listed while some account can use them
+	tools := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "tools/list"}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	listed := false
+	for _, tool := range tools {
+		listed = listed || tool["name"] == toolDeleteEvent
+	}
+	if !listed {
+		t.Error("expected delete_event to be listed")
+	}
+
+	personal.calendarRoles["me@home.example"] = "reader"
+	s.checkCalendars(context.Background())
+	if resp := call(`{"event_id":"evt-3","account":"personal"}`); resp.Error == nil || !strings.Contains(resp.Error.Message, "read-only access to calendar me@home.example") {
+		t.Errorf("expected the personal account to be refused, got %+v", resp.Error)
+	}
+}
+
+func TestAccounts_PrimaryCalendar(t *testing.T) {
+	s, work, personal := newAccountsServer(t)
+	ctx := gcal.WithAccount(context.Background(), "personal")
+
+	if id, err := s.resolveCalendar(ctx, ""); err != nil || id != "me@home.example" {
+		t.Errorf("expected the personal account's calendar, got %q, %v", id, err)
+	}
+	if id, err := s.resolveCalendar(context.Background(), ""); err != nil || id != "test@example.com" {
+		t.Errorf("expected the default account's calendar, got %q, %v", id, err)
+	}
+	if _, err := s.listCalendarRange(ctx, "me@home.example", "2026-03-16", "2026-03-17"); err != nil || personal.lastStart != "2026-03-16" || work.lastStart != "" {
+		t.Errorf("expected the personal calendar to be listed as the primary one, got %v", err)
+	}
+
+	// A link to an event on the named account's own calendar
+	link := "https://www.google.com/calendar/event?eid=" + base64.RawURLEncoding.EncodeToString([]byte("a1b2c3d4 me@home.example"))
+	params, _ := json.Marshal(map[string]interface{}{"name": toolDeleteEvent, "arguments": map[string]string{"event_id": link, "account": "personal"}})
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params}); resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if personal.deletedID != "a1b2c3d4" || work.deletedID != "" {
+		t.Errorf("unexpected deletions: %q and %q", personal.deletedID, work.deletedID)
+	}
+}
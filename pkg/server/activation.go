@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket
+// activation; see sd_listen_fds(3)
+const listenFDsStart = 3
+
+// SystemdListener returns the socket systemd passed to the process when it
+// was socket-activated, so that the sse and tcp transports can serve it
+// instead of listening themselves. It returns nil when the process wasn't
+// activated: LISTEN_FDS is unset or LISTEN_PID names another process. The
+// variables are cleared either way, so that child processes don't take the
+// socket for theirs. Only one socket is supported.
+func SystemdListener() (net.Listener, error) {
+	pid, fds := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	if fds == "" || pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(fds)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fds)
+	}
+	if n > 1 {
+		return nil, fmt.Errorf("systemd passed %d sockets; configure the socket unit with a single ListenStream", n)
+	}
+
+	f := os.NewFile(listenFDsStart, "systemd socket")
+	defer f.Close()
+	l, err := net.FileListener(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use the socket from systemd: %w", err)
+	}
+	return l, nil
+}
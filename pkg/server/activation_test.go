@@ -0,0 +1,34 @@
+package server
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestSystemdListener_NotActivated(t *testing.T) {
+	for name, env := range map[string][2]string{
+		"no LISTEN_FDS": {"", ""},
+		"other process": {strconv.Itoa(os.Getpid() + 1), "1"},
+	} {
+		t.Setenv("LISTEN_PID", env[0])
+		t.Setenv("LISTEN_FDS", env[1])
+		l, err := SystemdListener()
+		if l != nil || err != nil {
+			t.Errorf("%s: expected no listener, got %v, %v", name, l, err)
+		}
+		if os.Getenv("LISTEN_FDS") != "" {
+			t.Errorf("%s: expected LISTEN_FDS to be cleared", name)
+		}
+	}
+}
+
+func TestSystemdListener_Invalid(t *testing.T) {
+	for _, fds := range []string{"two", "0", "2"} {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", fds)
+		if _, err := SystemdListener(); err == nil {
+			t.Errorf("expected an error for LISTEN_FDS=%s", fds)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// alternateCalendar converts Gregorian dates into another calendar system
+// for annotating event output. New systems plug in by implementing this
+// interface and registering in alternateCalendars.
+type alternateCalendar interface {
+	// Name labels the annotation, e.g. "Hebrew"
+	Name() string
+	// FormatDate renders the civil date of t in this calendar
+	FormatDate(t time.Time) string
+}
+
+var alternateCalendars = map[string]alternateCalendar{
+	"chinese": chineseCalendar{},
+	"hebrew":  hebrewCalendar{},
+	"hijri":   hijriCalendar{},
+}
+
+// parseAlternateCalendars resolves a comma-separated list of calendar names
+func parseAlternateCalendars(list string) ([]alternateCalendar, error) {
+	var result []alternateCalendar
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		cal, ok := alternateCalendars[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown calendar %q", name)
+		}
+		result = append(result, cal)
+	}
+	return result, nil
+}
+
+// alternateDates renders the configured alternate calendar dates for an
+// event start, given as an RFC 3339 timestamp or a YYYY-MM-DD date
+func (s *Server) alternateDates(start string) string {
+	if len(s.altCalendars) == 0 || len(start) < len("2006-01-02") {
+		return ""
+	}
+
+	date, err := time.Parse("2006-01-02", start[:len("2006-01-02")])
+	if err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, cal := range s.altCalendars {
+		fmt.Fprintf(&b, "  %s: %s\n", cal.Name(), cal.FormatDate(date))
+	}
+	return b.String()
+}
+
+// julianDayNumber returns the Julian Day Number of a Gregorian date
+func julianDayNumber(year, month, day int) int {
+	a := (14 - month) / 12
+	y := year + 4800 - a
+	m := month + 12*a - 3
+	return day + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+func julianDayOf(t time.Time) int {
+	return julianDayNumber(t.Year(), int(t.Month()), t.Day())
+}
+
+// hijriCalendar implements the arithmetical (tabular) Islamic calendar.
+// Dates can differ by a day from calendars based on moon sighting.
+type hijriCalendar struct{}
+
+var hijriMonths = []string{
+	"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Ula", "Jumada al-Akhirah",
+	"Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah",
+}
+
+func (hijriCalendar) Name() string { return "Hijri" }
+
+func (hijriCalendar) FormatDate(t time.Time) string {
+	year, month, day := hijriFromJulianDay(julianDayOf(t))
+	return fmt.Sprintf("%d %s %d AH", day, hijriMonths[month-1], year)
+}
+
+func hijriFromJulianDay(jd int) (year, month, day int) {
+	l := jd - 1948440 + 10632
+	n := (l - 1) / 10631
+	l = l - 10631*n + 354
+	j := ((10985-l)/5316)*((50*l)/17719) + (l/5670)*((43*l)/15238)
+	l = l - ((30-j)/15)*((17719*j)/50) - (j/16)*((15238*j)/43) + 29
+	month = (24 * l) / 709
+	day = l - (709*month)/24
+	year = 30*n + j - 30
+	return year, month, day
+}
+
+// hebrewCalendar implements the arithmetic Hebrew calendar, following
+// Reingold and Dershowitz, "Calendrical Calculations"
+type hebrewCalendar struct{}
+
+// hebrewEpoch is 1 Tishrei AM 1 as a fixed day number (R.D.)
+const hebrewEpoch = -1373427
+
+var hebrewMonths = []string{
+	"Nisan", "Iyyar", "Sivan", "Tammuz", "Av", "Elul",
+	"Tishrei", "Heshvan", "Kislev", "Tevet", "Shevat", "Adar", "Adar II",
+}
+
+func (hebrewCalendar) Name() string { return "Hebrew" }
+
+func (hebrewCalendar) FormatDate(t time.Time) string {
+	year, month, day := hebrewFromFixed(julianDayOf(t) - 1721425)
+
+	name := hebrewMonths[month-1]
+	if month == 12 && hebrewLeapYear(year) {
+		name = "Adar I"
+	}
+	return fmt.Sprintf("%d %s %d", day, name, year)
+}
+
+func hebrewLeapYear(year int) bool {
+	return floorMod(7*year+1, 19) < 7
+}
+
+func hebrewLastMonth(year int) int {
+	if hebrewLeapYear(year) {
+		return 13
+	}
+	return 12
+}
+
+func hebrewElapsedDays(year int) int {
+	monthsElapsed := floorDiv(235*year-234, 19)
+	partsElapsed := 12084 + 13753*monthsElapsed
+	day := 29*monthsElapsed + floorDiv(partsElapsed, 25920)
+	if floorMod(3*(day+1), 7) < 3 {
+		return day + 1
+	}
+	return day
+}
+
+func hebrewYearLengthCorrection(year int) int {
+	ny0 := hebrewElapsedDays(year - 1)
+	ny1 := hebrewElapsedDays(year)
+	ny2 := hebrewElapsedDays(year + 1)
+	switch {
+	case ny2-ny1 == 356:
+		return 2
+	case ny1-ny0 == 382:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func hebrewNewYear(year int) int {
+	return hebrewEpoch + hebrewElapsedDays(year) + hebrewYearLengthCorrection(year)
+}
+
+func hebrewMonthLength(month, year int) int {
+	yearLength := hebrewNewYear(year+1) - hebrewNewYear(year)
+	switch {
+	case month == 2 || month == 4 || month == 6 || month == 10 || month == 13:
+		return 29
+	case month == 12 && !hebrewLeapYear(year):
+		return 29
+	case month == 8 && yearLength%10 != 5:
+		return 29
+	case month == 9 && yearLength%10 == 3:
+		return 29
+	default:
+		return 30
+	}
+}
+
+// fixedFromHebrew returns the fixed day number of a Hebrew date. Years
+// start in Tishrei (month 7), so months before it belong to the later part
+// of the year.
+func fixedFromHebrew(year, month, day int) int {
+	days := hebrewNewYear(year) + day - 1
+	if month < 7 {
+		for m := 7; m <= hebrewLastMonth(year); m++ {
+			days += hebrewMonthLength(m, year)
+		}
+		for m := 1; m < month; m++ {
+			days += hebrewMonthLength(m, year)
+		}
+	} else {
+		for m := 7; m < month; m++ {
+			days += hebrewMonthLength(m, year)
+		}
+	}
+	return days
+}
+
+func hebrewFromFixed(date int) (year, month, day int) {
+	approx := floorDiv(98496*(date-hebrewEpoch), 35975351) + 1
+	year = approx - 1
+	for hebrewNewYear(year+1) <= date {
+		year++
+	}
+
+	month = 1
+	if date < fixedFromHebrew(year, 1, 1) {
+		month = 7
+	}
+	for date > fixedFromHebrew(year, month, hebrewMonthLength(month, year)) {
+		month++
+	}
+
+	day = date - fixedFromHebrew(year, month, 1) + 1
+	return year, month, day
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+func floorMod(a, b int) int {
+	return a - b*floorDiv(a, b)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestHebrewCalendar(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2026-09-12", "1 Tishrei 5787"},
+		{"2026-04-02", "15 Nisan 5786"},
+		{"2024-03-24", "14 Adar II 5784"},
+		{"2024-02-23", "14 Adar I 5784"},
+	}
+
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		if got := (hebrewCalendar{}).FormatDate(d); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestHijriCalendar(t *testing.T) {
+	// The Islamic epoch, 16 July 622 Julian
+	d := time.Date(622, 7, 19, 0, 0, 0, 0, time.UTC)
+	if got := (hijriCalendar{}).FormatDate(d); got != "1 Muharram 1 AH" {
+		t.Errorf("epoch: got %q", got)
+	}
+
+	d = time.Date(2025, 6, 27, 0, 0, 0, 0, time.UTC)
+	if got := (hijriCalendar{}).FormatDate(d); got != "1 Muharram 1447 AH" {
+		t.Errorf("2025-06-27: got %q", got)
+	}
+}
+
+func TestParseAlternateCalendars(t *testing.T) {
+	cals, err := parseAlternateCalendars("Hebrew, hijri, Chinese")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cals) != 3 {
+		t.Errorf("expected 3 calendars, got %d", len(cals))
+	}
+
+	if _, err := parseAlternateCalendars("mayan"); err == nil {
+		t.Error("expected error for unknown calendar")
+	}
+}
+
+func TestFormatEvents_AlternateDates(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.altCalendars = []alternateCalendar{hebrewCalendar{}}
+
+	text := s.formatEvents([]gcal.CalendarEvent{{ID: "1", Summary: "Dinner", Start: "2026-09-12T19:00:00+03:00"}})
+	if !contains(text, "Hebrew: 1 Tishrei 5787") {
+		t.Errorf("expected Hebrew date annotation, got %q", text)
+	}
+}
@@ -0,0 +1,358 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	defaultAnalysisDays = 7
+	maxAnalysisDays     = 90
+
+	// focusBlockMin is the shortest free block that counts as usable for
+	// deep work; shorter gaps are fragmented time
+	focusBlockMin = time.Hour
+)
+
+// workHours is the part of the day the analytics consider
+type workHours struct {
+	start, end time.Duration
+	days       map[time.Weekday]bool
+}
+
+var defaultWorkHours = workHours{
+	start: 9 * time.Hour,
+	end:   17 * time.Hour,
+	days: map[time.Weekday]bool{
+		time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+	},
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// parseWorkHours parses a range like 09:00-17:00
+func parseWorkHours(spec string) (time.Duration, time.Duration, error) {
+	from, to, found := strings.Cut(spec, "-")
+	if !found {
+		return 0, 0, fmt.Errorf("expected HH:MM-HH:MM, got %q", spec)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start %q", from)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end %q", to)
+	}
+	if !end.After(start) {
+		return 0, 0, fmt.Errorf("end %s must be after start %s", to, from)
+	}
+	return sinceMidnight(start), sinceMidnight(end), nil
+}
+
+// parseWorkDays parses a comma-separated list of weekdays like mon,tue,wed
+func parseWorkDays(spec string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		day, ok := weekdayNames[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q (expected mon, tue, wed, thu, fri, sat or sun)", name)
+		}
+		days[day] = true
+	}
+	return days, nil
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+}
+
+type freeBlock struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
+}
+
+type dayStats struct {
+	Date                string      `json:"date"`
+	Meetings            int         `json:"meetings"`
+	BusyMinutes         int         `json:"busyMinutes"`
+	FreeBlocks          []freeBlock `json:"freeBlocks"`
+	LongestFocusMinutes int         `json:"longestFocusMinutes"`
+}
+
+// timeAnalysis summarizes how the working hours of a date range are used
+type timeAnalysis struct {
+	StartDate string     `json:"startDate"`
+	EndDate   string     `json:"endDate"`
+	Days      []dayStats `json:"days"`
+
+	Meetings    int `json:"meetings"`
+	BusyMinutes int `json:"busyMinutes"`
+	FreeMinutes int `json:"freeMinutes"`
+	// FragmentationScore is the percentage of free working time that sits
+	// in blocks shorter than focusBlockMin
+	FragmentationScore  int    `json:"fragmentationScore"`
+	FreeBlockCount      int    `json:"freeBlockCount"`
+	LongestFocusMinutes int    `json:"longestFocusMinutes"`
+	LongestFocusDate    string `json:"longestFocusDate,omitempty"`
+
+	// MeetingPersonHours is the time all events took from everyone invited
+	MeetingPersonHours float64       `json:"meetingPersonHours"`
+	EstimatedCost      *float64      `json:"estimatedCost,omitempty"`
+	CostliestMeetings  []meetingCost `json:"costliestMeetings,omitempty"`
+
+	// Categories are the hours per configured category, most first; only
+	// set when CALENDAR_CATEGORIES is
+	Categories []categoryHours `json:"categories,omitempty"`
+	// BudgetAlerts are the categories over CALENDAR_CATEGORY_BUDGETS, with
+	// the weekly budgets scaled to the length of the range
+	BudgetAlerts []budgetAlert `json:"budgetAlerts,omitempty"`
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// timedIntervals returns the spans of timed events in loc; all-day events
+// don't block working time and are skipped
+func timedIntervals(events []gcal.CalendarEvent, loc *time.Location) []interval {
+	var result []interval
+	for _, e := range events {
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil || !end.After(start) {
+			continue
+		}
+		result = append(result, interval{start: start.In(loc), end: end.In(loc)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].start.Before(result[j].start) })
+	return result
+}
+
+// analyzeTime computes busy time and free blocks within working hours for
+// days consecutive days starting at first
+func analyzeTime(events []gcal.CalendarEvent, first time.Time, days int, hours workHours) timeAnalysis {
+	loc := first.Location()
+	busy := timedIntervals(events, loc)
+
+	analysis := timeAnalysis{
+		StartDate: first.Format("2006-01-02"),
+		EndDate:   first.AddDate(0, 0, days-1).Format("2006-01-02"),
+		Days:      []dayStats{},
+	}
+	fragmented := 0
+
+	for i := 0; i < days; i++ {
+		day := first.AddDate(0, 0, i)
+		if !hours.days[day.Weekday()] {
+			continue
+		}
+		midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		workStart := midnight.Add(hours.start)
+		workEnd := midnight.Add(hours.end)
+
+		stats := dayStats{Date: day.Format("2006-01-02"), FreeBlocks: []freeBlock{}}
+		cursor := workStart
+		for _, b := range busy {
+			if !b.start.Before(workEnd) || !b.end.After(workStart) {
+				continue
+			}
+			stats.Meetings++
+			start, end := maxTime(b.start, workStart), minTime(b.end, workEnd)
+			if start.After(cursor) {
+				stats.addFree(cursor, start)
+			}
+			if end.After(cursor) {
+				stats.BusyMinutes += int(end.Sub(maxTime(start, cursor)).Minutes())
+				cursor = end
+			}
+		}
+		if workEnd.After(cursor) {
+			stats.addFree(cursor, workEnd)
+		}
+
+		for _, f := range stats.FreeBlocks {
+			analysis.FreeMinutes += f.Minutes
+			if time.Duration(f.Minutes)*time.Minute < focusBlockMin {
+				fragmented += f.Minutes
+			}
+		}
+		analysis.Meetings += stats.Meetings
+		analysis.BusyMinutes += stats.BusyMinutes
+		analysis.FreeBlockCount += len(stats.FreeBlocks)
+		if stats.LongestFocusMinutes > analysis.LongestFocusMinutes {
+			analysis.LongestFocusMinutes = stats.LongestFocusMinutes
+			analysis.LongestFocusDate = stats.Date
+		}
+		analysis.Days = append(analysis.Days, stats)
+	}
+
+	if analysis.FreeMinutes > 0 {
+		analysis.FragmentationScore = fragmented * 100 / analysis.FreeMinutes
+	}
+	return analysis
+}
+
+// addCosts fills in the meeting load of the analyzed events
+func (a *timeAnalysis) addCosts(events []gcal.CalendarEvent, rate *hourlyRate) {
+	total, costs := meetingCosts(events, rate)
+	a.MeetingPersonHours = total
+	if rate != nil {
+		cost := total * rate.amount
+		a.EstimatedCost = &cost
+	}
+	if len(costs) > costliestMeetingsLimit {
+		costs = costs[:costliestMeetingsLimit]
+	}
+	a.CostliestMeetings = costs
+}
+
+func (d *dayStats) addFree(start, end time.Time) {
+	minutes := int(end.Sub(start).Minutes())
+	if minutes <= 0 {
+		return
+	}
+	d.FreeBlocks = append(d.FreeBlocks, freeBlock{
+		Start:   start.Format("15:04"),
+		End:     end.Format("15:04"),
+		Minutes: minutes,
+	})
+	d.LongestFocusMinutes = max(d.LongestFocusMinutes, minutes)
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// analyzeTimeInput is the arguments of analyze_time
+type analyzeTimeInput struct {
+	// First day in YYYY-MM-DD format (default: today)
+	StartDate string `json:"start_date"`
+	// Number of days to analyze (default: 7, max: 90)
+	Days int `json:"days" jsonschema:"default=defaultAnalysisDays,maximum=maxAnalysisDays"`
+	calendarArg
+}
+
+func (s *Server) callAnalyzeTime(ctx context.Context, input analyzeTimeInput) (timeAnalysis, error) {
+	if input.Days == 0 {
+		input.Days = defaultAnalysisDays
+	}
+	if input.Days < 0 || input.Days > maxAnalysisDays {
+		return timeAnalysis{}, badArgumentf("days must be between 1 and %d", maxAnalysisDays)
+	}
+
+	first := time.Now().In(s.settings().location)
+	if input.StartDate != "" {
+		if err := s.normalizeDateArg(&input.StartDate); err != nil {
+			return timeAnalysis{}, badArgument(err)
+		}
+		t, err := time.ParseInLocation("2006-01-02", input.StartDate, s.settings().location)
+		if err != nil {
+			return timeAnalysis{}, badArgument(err)
+		}
+		first = t
+	}
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, s.settings().location)
+
+	end := first.AddDate(0, 0, input.Days-1)
+	events, err := s.listCalendarRange(ctx, input.Calendar, first.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return timeAnalysis{}, err
+	}
+
+	analysis := analyzeTime(events, first, input.Days, s.workHours)
+	analysis.addCosts(events, s.hourlyRate)
+	if s.categories != nil {
+		analysis.Categories = summarizePeriod(events, analysis.StartDate, analysis.EndDate, s.categoryOf).Categories
+	}
+	// Budgets are for the user's own time, not a teammate's
+	if s.budgets != nil && input.Calendar == "" {
+		analysis.BudgetAlerts = s.budgets.alerts(analysis.Categories, input.Days)
+	}
+	return analysis, nil
+}
+
+func (a timeAnalysis) toolText(s *Server) string { return s.formatAnalysis(a) }
+
+func (s *Server) formatAnalysis(a timeAnalysis) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time analysis %s to %s (working hours %s-%s)\n\n", a.StartDate, a.EndDate,
+		formatClock(s.workHours.start), formatClock(s.workHours.end))
+
+	if len(a.Days) == 0 {
+		b.WriteString("No working days in this range.\n")
+		return b.String()
+	}
+
+	for _, d := range a.Days {
+		day, _ := time.Parse("2006-01-02", d.Date)
+		fmt.Fprintf(&b, "%s %s: %d meeting(s), %s busy, %d free block(s), longest focus %s\n",
+			day.Format("Mon"), d.Date, d.Meetings, formatMinutes(d.BusyMinutes), len(d.FreeBlocks), formatMinutes(d.LongestFocusMinutes))
+	}
+
+	fmt.Fprintf(&b, "\nFragmentation: %d%% of free time is in blocks shorter than %s (%d free block(s), %s free)\n",
+		a.FragmentationScore, gcal.FormatDuration(focusBlockMin), a.FreeBlockCount, formatMinutes(a.FreeMinutes))
+	if a.LongestFocusDate != "" {
+		fmt.Fprintf(&b, "Longest focus window: %s on %s\n", formatMinutes(a.LongestFocusMinutes), a.LongestFocusDate)
+	}
+
+	fmt.Fprintf(&b, "Meeting load: %s person-hours", formatHours(a.MeetingPersonHours))
+	if s.hourlyRate != nil {
+		fmt.Fprintf(&b, " (~%s)", s.hourlyRate.format(a.MeetingPersonHours))
+	}
+	b.WriteString("\n")
+	if len(a.CostliestMeetings) > 0 {
+		b.WriteString("Most expensive meetings:\n")
+		for _, c := range a.CostliestMeetings {
+			fmt.Fprintf(&b, "- %s: %d time(s), %s person-hours", s.sanitize(c.Summary), c.Occurrences, formatHours(c.PersonHours))
+			if s.hourlyRate != nil {
+				fmt.Fprintf(&b, " (~%s)", s.hourlyRate.format(c.PersonHours))
+			}
+			b.WriteString("\n")
+		}
+	}
+	if len(a.Categories) > 0 {
+		b.WriteString("Hours by category:\n")
+		for _, c := range a.Categories {
+			fmt.Fprintf(&b, "- %s: %s hours, %d meeting(s)\n", s.sanitize(c.Name), formatHours(c.Hours), c.Meetings)
+		}
+	}
+	if len(a.BudgetAlerts) > 0 {
+		b.WriteString("Over budget:\n")
+		for _, alert := range a.BudgetAlerts {
+			fmt.Fprintf(&b, "- %s: %s hours of %s\n", s.sanitize(alert.Category), formatHours(alert.Hours), formatHours(alert.BudgetHours))
+		}
+	}
+	return b.String()
+}
+
+func formatMinutes(minutes int) string {
+	return gcal.FormatDuration(time.Duration(minutes) * time.Minute)
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
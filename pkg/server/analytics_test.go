@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestAnalyzeTime_Fragmentation(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		// Monday: 09:30-10:00 and 10:30-11:00 leave 30m gaps, then 11:00-17:00 free
+		{ID: "1", Start: "2026-03-16T09:30:00Z", End: "2026-03-16T10:00:00Z"},
+		{ID: "2", Start: "2026-03-16T10:30:00Z", End: "2026-03-16T11:00:00Z"},
+		// Overlapping meetings are counted once towards busy time
+		{ID: "3", Start: "2026-03-16T10:45:00Z", End: "2026-03-16T11:00:00Z"},
+		// All-day events don't block working time
+		{ID: "4", Start: "2026-03-17", End: "2026-03-18"},
+	}
+	first := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	a := analyzeTime(events, first, 2, defaultWorkHours)
+
+	if len(a.Days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(a.Days))
+	}
+	monday := a.Days[0]
+	if monday.Meetings != 3 || monday.BusyMinutes != 60 {
+		t.Errorf("unexpected Monday stats: %+v", monday)
+	}
+	if len(monday.FreeBlocks) != 3 || monday.LongestFocusMinutes != 360 {
+		t.Errorf("unexpected Monday free blocks: %+v", monday.FreeBlocks)
+	}
+	tuesday := a.Days[1]
+	if tuesday.Meetings != 0 || tuesday.LongestFocusMinutes != 480 {
+		t.Errorf("unexpected Tuesday stats: %+v", tuesday)
+	}
+
+	// 60 of 900 free minutes are in blocks shorter than an hour
+	if a.FreeMinutes != 900 || a.FragmentationScore != 6 {
+		t.Errorf("expected 900 free minutes and score 6, got %d and %d", a.FreeMinutes, a.FragmentationScore)
+	}
+	if a.LongestFocusDate != "2026-03-17" {
+		t.Errorf("expected longest focus on Tuesday, got %s", a.LongestFocusDate)
+	}
+}
+
+func TestAnalyzeTime_SkipsNonWorkingDays(t *testing.T) {
+	// 2026-03-21 is a Saturday
+	first := time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)
+
+	a := analyzeTime(nil, first, 2, defaultWorkHours)
+
+	if len(a.Days) != 0 || a.FragmentationScore != 0 {
+		t.Errorf("expected no working days, got %+v", a)
+	}
+}
+
+func TestParseWorkHoursAndDays(t *testing.T) {
+	start, end, err := parseWorkHours("08:30-16:00")
+	if err != nil || start != 8*time.Hour+30*time.Minute || end != 16*time.Hour {
+		t.Errorf("unexpected result %v %v %v", start, end, err)
+	}
+	for _, bad := range []string{"08:30", "17:00-09:00", "9-17"} {
+		if _, _, err := parseWorkHours(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+
+	days, err := parseWorkDays("sun, Mon,tue,wed,thu")
+	if err != nil || len(days) != 5 || !days[time.Sunday] || days[time.Friday] {
+		t.Errorf("unexpected days %v, %v", days, err)
+	}
+	if _, err := parseWorkDays("mon,funday"); err == nil {
+		t.Error("expected error for unknown weekday")
+	}
+}
+
+func TestCallAnalyzeTime(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Standup", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T09:15:00Z"},
+	}}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]interface{}{"start_date": "16.03.2026", "days": 5})
+	resp := s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastStart != "2026-03-16" || fake.lastEnd != "2026-03-20" {
+		t.Errorf("expected range 2026-03-16..2026-03-20, got %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Mon 2026-03-16: 1 meeting(s), 15m busy") || !strings.Contains(text, "Fragmentation:") {
+		t.Errorf("unexpected analysis text:\n%s", text)
+	}
+
+	resp = s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(2), args: json.RawMessage(`{"days":365}`)})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for too many days, got %+v", resp.Error)
+	}
+}
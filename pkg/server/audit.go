@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	resourceAudit = "audit://recent"
+
+	// maxAuditEntries is how many of the latest calls audit://recent keeps
+	maxAuditEntries = 200
+)
+
+// auditEntry records one call of a mutating tool. The JSON form is the
+// format of audit://recent; fields are only ever added to it.
+type auditEntry struct {
+	// Seq numbers the entries of the process from 1 without gaps, so that
+	// a reader can tell which entries it has seen and whether it missed
+	// some
+	Seq       int64           `json:"seq"`
+	At        string          `json:"at"`
+	Tool      string          `json:"tool"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	RequestID string          `json:"requestId,omitempty"`
+	TraceID   string          `json:"traceId,omitempty"`
+	// Status is "ok"; "failed" when the tool reported that none of its
+	// changes went through; or "error", with Error set, when the call
+	// failed as a whole
+	Status string       `json:"status"`
+	Error  *errorDetail `json:"error,omitempty"`
+}
+
+// auditLog keeps the latest calls of mutating tools, so that a supervising
+// client can review what the server did. Sessions of the TCP transport
+// share one log, and every session subscribed to audit://recent is told
+// about new entries at once.
+type auditLog struct {
+	now func() time.Time
+
+	mu       sync.Mutex
+	entries  []auditEntry
+	seq      int64
+	watchers map[*Server]bool
+}
+
+func newAuditLog() *auditLog {
+	return &auditLog{now: time.Now, watchers: make(map[*Server]bool)}
+}
+
+// record adds an entry for a call of tool that ended with status and err
+func (l *auditLog) record(ctx context.Context, tool string, args json.RawMessage, status string, err error) {
+	info := gcal.RequestInfoFrom(ctx)
+	entry := auditEntry{
+		At:        l.now().UTC().Format(time.RFC3339),
+		Tool:      tool,
+		Arguments: args,
+		SessionID: info.SessionID,
+		RequestID: info.RequestID,
+		TraceID:   info.TraceID,
+		Status:    status,
+	}
+	if err != nil {
+		detail := describeError(err)
+		entry.Error = &detail
+	}
+
+	l.mu.Lock()
+	l.seq++
+	entry.Seq = l.seq
+	l.entries = append(l.entries, entry)
+	if len(l.entries) > maxAuditEntries {
+		l.entries = append([]auditEntry(nil), l.entries[len(l.entries)-maxAuditEntries:]...)
+	}
+	watchers := make([]*Server, 0, len(l.watchers))
+	for s := range l.watchers {
+		watchers = append(watchers, s)
+	}
+	l.mu.Unlock()
+
+	for _, s := range watchers {
+		s.resourceUpdated(resourceAudit)
+	}
+}
+
+func auditStatus(out toolOutput, err error) string {
+	if err != nil {
+		return "error"
+	}
+	if p, ok := out.(partialOutput); ok && p.allFailed() {
+		return "failed"
+	}
+	return "ok"
+}
+
+// recent returns the kept entries, oldest first
+func (l *auditLog) recent() []auditEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]auditEntry{}, l.entries...)
+}
+
+// watch has new entries reported to s
+func (l *auditLog) watch(s *Server) {
+	l.mu.Lock()
+	l.watchers[s] = true
+	l.mu.Unlock()
+}
+
+func (l *auditLog) unwatch(s *Server) {
+	l.mu.Lock()
+	delete(l.watchers, s)
+	l.mu.Unlock()
+}
+
+// readAuditResource renders audit://recent
+func (s *Server) readAuditResource() (string, error) {
+	data, err := json.Marshal(map[string]interface{}{"entries": s.audit.recent()})
+	return string(data), err
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func readAuditEntries(t *testing.T, s *Server) []auditEntry {
+	t.Helper()
+	text, err := s.readResource(context.Background(), resourceAudit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Entries []auditEntry `json:"entries"`
+	}
+	if err := json.Unmarshal([]byte(text), &log); err != nil {
+		t.Fatalf("audit://recent is not JSON: %v\n%s", err, text)
+	}
+	return log.Entries
+}
+
+func TestAuditLog_RecordsMutatingCalls(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	ctx := gcal.WithRequestInfo(context.Background(), gcal.RequestInfo{SessionID: "abc", RequestID: "7"})
+
+	s.callTool(ctx, &toolCall{name: toolListEvents, id: float64(1)})
+	args := json.RawMessage(`{"event_id":"evt-del"}`)
+	s.callTool(ctx, &toolCall{name: toolDeleteEvent, id: float64(2), args: args})
+	fake.deleteErr = errors.New("boom")
+	s.callTool(ctx, &toolCall{name: toolDeleteEvent, id: float64(3), args: args})
+	// Rejected arguments change nothing and are not recorded
+	s.callTool(ctx, &toolCall{name: toolDeleteEvent, id: float64(4), args: json.RawMessage(`{}`)})
+
+	entries := readAuditEntries(t, s)
+	if len(entries) != 2 {
+		t.Fatalf("expected the two delete_event calls, got %+v", entries)
+	}
+	first, second := entries[0], entries[1]
+	if first.Seq != 1 || first.Tool != toolDeleteEvent || first.Status != "ok" || first.Error != nil {
+		t.Errorf("unexpected first entry %+v", first)
+	}
+	if string(first.Arguments) != `{"event_id":"evt-del"}` || first.SessionID != "abc" || first.RequestID != "7" {
+		t.Errorf("expected the arguments and request info, got %+v", first)
+	}
+	if second.Seq != 2 || second.Status != "error" || second.Error == nil || second.Error.Message != "boom" {
+		t.Errorf("expected a failed call, got %+v", second)
+	}
+}
+
+func TestAuditLog_Limit(t *testing.T) {
+	l := newAuditLog()
+	for range maxAuditEntries + 5 {
+		l.record(context.Background(), toolDeleteEvent, nil, "ok", nil)
+	}
+	entries := l.recent()
+	if len(entries) != maxAuditEntries || entries[0].Seq != 6 || entries[len(entries)-1].Seq != maxAuditEntries+5 {
+		t.Errorf("expected the last %d entries, got %d from %d", maxAuditEntries, len(entries), entries[0].Seq)
+	}
+}
+
+func TestAuditLog_NotifiesSubscribers(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	out := &bytes.Buffer{}
+	supervisor := s.newSession(out)
+	supervisor.state = stateReady
+
+	params, _ := json.Marshal(map[string]string{"uri": resourceAudit})
+	supervisor.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/subscribe", Params: params})
+	out.Reset()
+
+	args := json.RawMessage(`{"event_id":"evt-del"}`)
+	s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(1), args: args})
+	if !strings.Contains(out.String(), `"notifications/resources/updated"`) || !strings.Contains(out.String(), resourceAudit) {
+		t.Errorf("expected the other session to be notified, got %q", out.String())
+	}
+	if entries := readAuditEntries(t, supervisor); len(entries) != 1 {
+		t.Errorf("expected the sessions to share the log, got %+v", entries)
+	}
+}
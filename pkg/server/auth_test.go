@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testIssuer serves a discovery document and the keys of an OIDC issuer
+type testIssuer struct {
+	*httptest.Server
+	rsaKey     *rsa.PrivateKey
+	ecKey      *ecdsa.PrivateKey
+	keyFetches int
+	// down makes fetching the keys fail
+	down bool
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := &testIssuer{rsaKey: rsaKey, ecKey: ecKey}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.URL, "jwks_uri": issuer.URL + "/keys"})
+	})
+	mux.HandleFunc("GET /keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.keyFetches++
+		if issuer.down {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		}})
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func (i *testIssuer) sign(t *testing.T, alg, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var signature []byte
+	switch alg {
+	case "RS256":
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, i.rsaKey, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case "ES256":
+		r, s, err := ecdsa.Sign(rand.Reader, i.ecKey, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	issuer := newTestIssuer(t)
+	now := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	v, err := newOIDCVerifier(issuer.URL+"/", "calendar-mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.now = func() time.Time { return now }
+
+	claims := func(changes map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{"iss": issuer.URL, "aud": "calendar-mcp", "exp": now.Add(time.Hour).Unix(), "sub": "alex"}
+		for k, val := range changes {
+			if val == nil {
+				delete(c, k)
+			} else {
+				c[k] = val
+			}
+		}
+		return c
+	}
+
+	valid := map[string]string{
+		"RS256":           issuer.sign(t, "RS256", "rsa", claims(nil)),
+		"ES256":           issuer.sign(t, "ES256", "ec", claims(nil)),
+		"audience list":   issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"aud": []string{"other", "calendar-mcp"}})),
+		"within the skew": issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})),
+		"already started": issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"nbf": now.Add(-time.Hour).Unix()})),
+	}
+	for name, token := range valid {
+		if err := v.verify(context.Background(), token); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+
+	tampered := issuer.sign(t, "RS256", "rsa", claims(nil))
+	tampered = tampered[:len(tampered)-4] + "AAAA"
+	invalid := map[string]string{
+		"other issuer":      issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"iss": "https://evil.example.com"})),
+		"other audience":    issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"aud": "someone-else"})),
+		"no audience":       issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"aud": nil})),
+		"expired":           issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		"no expiry":         issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"exp": nil})),
+		"not yet valid":     issuer.sign(t, "RS256", "rsa", claims(map[string]interface{}{"nbf": now.Add(time.Hour).Unix()})),
+		"key of other type": issuer.sign(t, "ES256", "rsa", claims(nil)),
+		"unknown key":       issuer.sign(t, "RS256", "rotated", claims(nil)),
+		"tampered":          tampered,
+		"not a JWT":         "static-token",
+	}
+	for name, token := range invalid {
+		if err := v.verify(context.Background(), token); err == nil {
+			t.Errorf("%s: expected the token to be rejected", name)
+		}
+	}
+
+	// Keys are fetched once, and an unknown key ID doesn't make every
+	// request fetch them again
+	if issuer.keyFetches != 1 {
+		t.Errorf("expected the keys to be fetched once, got %d", issuer.keyFetches)
+	}
+	now = now.Add(jwksRefreshInterval)
+	v.verify(context.Background(), invalid["unknown key"])
+	if issuer.keyFetches != 2 {
+		t.Errorf("expected the keys to be fetched again after %s, got %d fetches", jwksRefreshInterval, issuer.keyFetches)
+	}
+
+	if _, err := newOIDCVerifier(issuer.URL, ""); err == nil {
+		t.Error("expected an error without an audience")
+	}
+}
+
+func TestOIDCVerifier_FetchFailure(t *testing.T) {
+	issuer := newTestIssuer(t)
+	v, err := newOIDCVerifier(issuer.URL, "calendar-mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := issuer.sign(t, "RS256", "rsa", map[string]interface{}{"iss": issuer.URL, "aud": "calendar-mcp", "exp": time.Now().Add(time.Hour).Unix()})
+
+	issuer.down = true
+	if err := v.verify(context.Background(), token); err == nil {
+		t.Fatal("expected an error while the keys can't be fetched")
+	}
+	// A failed fetch doesn't hold off the next one
+	issuer.down = false
+	if err := v.verify(context.Background(), token); err != nil {
+		t.Errorf("expected the token to be accepted once the issuer is back, got %v", err)
+	}
+	if issuer.keyFetches != 2 {
+		t.Errorf("expected 2 key fetches, got %d", issuer.keyFetches)
+	}
+}
+
+func TestHTTPAuth(t *testing.T) {
+	issuer := newTestIssuer(t)
+	s := newTestServer(&fakeCalendar{})
+	oidc, err := newOIDCVerifier(issuer.URL, "calendar-mcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.auth = &httpAuth{token: "s3cret", oidc: oidc}
+	srv := httptest.NewServer(s.SSEHandler())
+	defer srv.Close()
+
+	claims := map[string]interface{}{"iss": issuer.URL, "aud": "calendar-mcp", "exp": time.Now().Add(time.Hour).Unix()}
+	jwt := issuer.sign(t, "RS256", "rsa", claims)
+	claims["aud"] = "another-app"
+	otherApp := issuer.sign(t, "RS256", "rsa", claims)
+	for header, want := range map[string]int{
+		"":                   http.StatusUnauthorized,
+		"Bearer wrong":       http.StatusUnauthorized,
+		"Basic czNjcmV0":     http.StatusUnauthorized,
+		"Bearer s3cret":      http.StatusNotFound,
+		"bearer " + jwt:      http.StatusNotFound,
+		"Bearer " + otherApp: http.StatusUnauthorized,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/messages?sessionId=none", strings.NewReader("{}"))
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		// Authenticated requests reach the transport, which doesn't know
+		// the session
+		if resp.StatusCode != want {
+			t.Errorf("%q: expected status %d, got %d", header, want, resp.StatusCode)
+		}
+		if want == http.StatusUnauthorized && !strings.HasPrefix(resp.Header.Get("WWW-Authenticate"), "Bearer") {
+			t.Errorf("%q: expected a Bearer challenge, got %q", header, resp.Header.Get("WWW-Authenticate"))
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// authStatusInput is the arguments of auth_status, which takes none
+type authStatusInput struct{}
+
+// calendarAccess is what the server may do on a calendar
+type calendarAccess struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	// AccessRole is owner, writer, reader or freeBusyReader; empty for
+	// configured calendars missing from the calendar list
+	AccessRole string `json:"accessRole,omitempty"`
+	// Configured marks the calendars the server was set up to act on
+	Configured bool `json:"configured,omitempty"`
+}
+
+// authStatusReport is the output of auth_status
+type authStatusReport struct {
+	AuthMode  string   `json:"authMode"`
+	Principal string   `json:"principal,omitempty"`
+	Scopes    []string `json:"scopes"`
+	// ScopeWarnings describe where the scopes don't match the tools the
+	// server offers
+	ScopeWarnings []string `json:"scopeWarnings,omitempty"`
+	// TokenExpiry is when the current access token expires (RFC 3339)
+	TokenExpiry string           `json:"tokenExpiry,omitempty"`
+	Calendars   []calendarAccess `json:"calendars"`
+	// CalendarsError is why the calendar list couldn't be read
+	CalendarsError string `json:"calendarsError,omitempty"`
+}
+
+func (s *Server) callAuthStatus(ctx context.Context, _ authStatusInput) (authStatusReport, error) {
+	reporter, ok := s.settings().calendar.(gcal.AuthStatusReporter)
+	if !ok {
+		return authStatusReport{}, fmt.Errorf("this calendar backend can't describe its authentication")
+	}
+	status, err := reporter.AuthStatus(ctx)
+	if err != nil {
+		return authStatusReport{}, err
+	}
+	report := authStatusReport{AuthMode: status.Mode, Principal: status.Principal, Scopes: status.Scopes, Calendars: []calendarAccess{}}
+	if report.Scopes == nil {
+		report.Scopes = []string{}
+	}
+	report.ScopeWarnings = s.auditScopes(status.Scopes)
+	if !status.Expiry.IsZero() {
+		report.TokenExpiry = status.Expiry.In(s.settings().location).Format(time.RFC3339)
+	}
+
+	configured := s.settings().calendar.Calendars()
+	listed := make(map[string]bool)
+	calendars, err := s.settings().calendar.ListCalendars(ctx)
+	if err != nil {
+		report.CalendarsError = describeError(err).Message
+	}
+	for _, c := range calendars {
+		listed[c.ID] = true
+		report.Calendars = append(report.Calendars, calendarAccess{ID: c.ID, Name: c.Summary, AccessRole: c.AccessRole, Configured: slices.Contains(configured, c.ID)})
+	}
+	for _, id := range configured {
+		// "primary" is listed under the owner's email
+		if !listed[id] && id != "primary" {
+			report.Calendars = append(report.Calendars, calendarAccess{ID: id, Configured: true})
+		}
+	}
+	return report, nil
+}
+
+func (r authStatusReport) toolText(s *Server) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Authenticated with %s", r.AuthMode)
+	if r.Principal != "" {
+		fmt.Fprintf(&b, " as %s", r.Principal)
+	}
+	b.WriteString(".\n")
+	if len(r.Scopes) > 0 {
+		fmt.Fprintf(&b, "Scopes: %s\n", strings.Join(r.Scopes, ", "))
+		for _, w := range r.ScopeWarnings {
+			fmt.Fprintf(&b, "Warning, %s\n", w)
+		}
+	}
+	if r.TokenExpiry != "" {
+		fmt.Fprintf(&b, "Access token expires at %s and is renewed automatically.\n", r.TokenExpiry)
+	}
+
+	if r.CalendarsError != "" {
+		fmt.Fprintf(&b, "\nThe calendar list couldn't be read: %s\n", r.CalendarsError)
+	}
+	if len(r.Calendars) > 0 {
+		b.WriteString("\nCalendars:\n")
+	}
+	for _, c := range r.Calendars {
+		fmt.Fprintf(&b, "- %s", c.ID)
+		if c.Name != "" && c.Name != c.ID {
+			fmt.Fprintf(&b, " (%s)", s.sanitize(c.Name))
+		}
+		switch {
+		case c.AccessRole == "":
+			b.WriteString(": not in the calendar list; it may not be shared with you")
+		case c.AccessRole == "reader" || c.AccessRole == "freeBusyReader":
+			fmt.Fprintf(&b, ": %s, read-only", c.AccessRole)
+		default:
+			fmt.Fprintf(&b, ": %s", c.AccessRole)
+		}
+		if c.Configured {
+			b.WriteString(" [configured]")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestCallAuthStatus(t *testing.T) {
+	fake := &fakeCalendar{
+		authStatus: gcal.AuthStatus{
+			Mode:      "service_account",
+			Principal: "calendar@proj.iam.gserviceaccount.com",
+			Scopes:    []string{"https://www.googleapis.com/auth/calendar.readonly"},
+			Expiry:    time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC),
+		},
+		calendarList:   []string{"test@example.com", "team@example.com"},
+		calendarRoles:  map[string]string{"test@example.com": "owner", "team@example.com": "reader"},
+		extraCalendars: map[string][]gcal.CalendarEvent{"oncall@example.com": nil},
+	}
+	s := newTestServer(fake)
+	s.setProtocolVersion(protocolVersion20250618)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolAuthStatus, id: float64(1)})
+	result := resp.Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{
+		"Authenticated with service_account as calendar@proj.iam.gserviceaccount.com",
+		"can't change events",
+		"- test@example.com: owner [configured]",
+		"- team@example.com: reader, read-only\n",
+		"- oncall@example.com: not in the calendar list",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	report := result["structuredContent"].(authStatusReport)
+	if report.TokenExpiry == "" || len(report.Calendars) != 3 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// isBatch reports whether a message is a JSON-RPC batch (a JSON array)
+func isBatch(line []byte) bool {
+	trimmed := bytes.TrimLeft(line, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// handleBatch dispatches every member of a JSON-RPC batch concurrently and
+// replies with a single array holding all responses, as required by the
+// JSON-RPC 2.0 spec. Batches made only of notifications get no reply.
+func (s *Server) handleBatch(line []byte) {
+	var members []json.RawMessage
+	if err := json.Unmarshal(line, &members); err != nil {
+		s.sendError(nil, -32700, "Parse error", err.Error())
+		return
+	}
+
+	if len(members) == 0 {
+		s.sendError(nil, -32600, "Invalid Request", "empty batch")
+		return
+	}
+
+	responses := make([]*JSONRPCResponse, len(members))
+	requests := make(map[int]JSONRPCRequest, len(members))
+	for i, raw := range members {
+		var req JSONRPCRequest
+		err := json.Unmarshal(raw, &req)
+		// Replies to requests of the server have no method
+		if err == nil && isClientResponse(req) {
+			s.handleClientResponse(raw)
+			continue
+		}
+		if err != nil || req.Method == "" {
+			responses[i] = &JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error: &RPCError{
+					Code:    -32600,
+					Message: "Invalid Request",
+				},
+			}
+			continue
+		}
+
+		switch {
+		case req.Method == "initialize":
+			responses[i] = &JSONRPCResponse{
+				JSONRPC: "2.0",
+				ID:      req.ID,
+				Error: &RPCError{
+					Code:    -32600,
+					Message: "Invalid Request",
+					Data:    "initialize must not be part of a batch",
+				},
+			}
+		case isInitializedNotification(req.Method):
+			// Completes the handshake before the other members run, so
+			// they aren't rejected depending on scheduling
+			s.handleRequest(req)
+		default:
+			requests[i] = req
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req JSONRPCRequest) {
+			defer wg.Done()
+			responses[i] = s.handleRequest(req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	var encoded [][]byte
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if data := encodeResponse(resp); data != nil {
+			encoded = append(encoded, data)
+		}
+	}
+
+	if len(encoded) == 0 {
+		return
+	}
+
+	data := append([]byte{'['}, bytes.Join(encoded, []byte{','})...)
+	s.writeLine(append(data, ']'))
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_Batch(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	input := `{"jsonrpc":"2.0","id":1,"method":"initialize"}` + "\n" +
+		`[{"jsonrpc":"2.0","id":2,"method":"tools/list"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":3,"method":"ping"}]` + "\n"
+	if err := s.Run(strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var responses []JSONRPCResponse
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &responses); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out.String(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses (notification gets none), got %d", len(responses))
+	}
+	if responses[0].ID != float64(2) || responses[0].Error != nil || responses[1].ID != float64(3) {
+		t.Errorf("unexpected responses: %+v", responses)
+	}
+}
+
+func TestHandleBatch_Empty(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	s.handleBatch([]byte(`[]`))
+
+	var resp JSONRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("expected a single error object, got %q", out.String())
+	}
+	if resp.Error == nil || resp.Error.Code != -32600 {
+		t.Errorf("expected invalid request error, got %+v", resp)
+	}
+}
+
+func TestHandleBatch_InvalidMember(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := newReadyServer(&fakeCalendar{}, out)
+
+	s.handleBatch([]byte(`[1, {"jsonrpc":"2.0","id":5,"method":"unknown"}]`))
+
+	var responses []JSONRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out.String(), err)
+	}
+	if len(responses) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(responses))
+	}
+	if responses[0].Error == nil || responses[0].Error.Code != -32600 {
+		t.Errorf("expected invalid request for non-object member, got %+v", responses[0])
+	}
+	if responses[1].Error == nil || responses[1].Error.Code != -32601 {
+		t.Errorf("expected method not found, got %+v", responses[1])
+	}
+}
+
+func TestHandleBatch_OnlyNotifications(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	s.handleBatch([]byte(`[{"jsonrpc":"2.0","method":"initialized"}]`))
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output for a notification-only batch, got %q", out.String())
+	}
+}
+
+func TestHandleBatch_RejectsInitialize(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	s.handleBatch([]byte(`[{"jsonrpc":"2.0","id":1,"method":"initialize"}]`))
+
+	var responses []JSONRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out.String(), err)
+	}
+	if responses[0].Error == nil || responses[0].Error.Code != -32600 {
+		t.Errorf("expected invalid request, got %+v", responses[0])
+	}
+}
+
+func TestHandleBatch_ClientResponse(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := newReadyServer(&fakeCalendar{}, out)
+	ch := make(chan clientResponse, 1)
+	s.pending["srv-1"] = ch
+
+	s.handleBatch([]byte(`[{"jsonrpc":"2.0","id":"srv-1","result":{"roots":[]}},{"jsonrpc":"2.0","id":2,"method":"ping"}]`))
+
+	select {
+	case resp := <-ch:
+		if string(resp.Result) != `{"roots":[]}` {
+			t.Errorf("unexpected result delivered: %s", resp.Result)
+		}
+	default:
+		t.Fatal("expected the client response to reach the waiting request")
+	}
+	var responses []JSONRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &responses); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", out.String(), err)
+	}
+	if len(responses) != 1 {
+		t.Fatalf("expected only the ping to be answered, got %+v", responses)
+	}
+	if responses[0].ID != float64(2) || responses[0].Error != nil {
+		t.Errorf("unexpected response: %+v", responses[0])
+	}
+}
+
+func TestHandleBatch_DuplicateClientResponse(t *testing.T) {
+	s := newReadyServer(&fakeCalendar{}, &bytes.Buffer{})
+	ch := make(chan clientResponse, 1)
+	s.pending["srv-1"] = ch
+
+	done := make(chan struct{})
+	go func() {
+		s.handleBatch([]byte(`[{"jsonrpc":"2.0","id":"srv-1","result":{"n":1}},{"jsonrpc":"2.0","id":"srv-1","result":{"n":2}}]`))
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected a duplicate client response not to block the input loop")
+	}
+
+	if resp := <-ch; string(resp.Result) != `{"n":1}` {
+		t.Errorf("expected the first response to be delivered, got %s", resp.Result)
+	}
+	if _, ok := s.pending["srv-1"]; ok {
+		t.Error("expected the answered request to no longer be pending")
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// circuitBreaker stops tool calls from reaching the calendar backend after
+// it failed failures times in a row, so that they fail at once instead of
+// each waiting for the backend to time out. After the cool-down one call is
+// let through; if it succeeds the breaker closes again, otherwise it stays
+// open for another cool-down. Sessions of the TCP transport share one
+// breaker, as they share the backend.
+type circuitBreaker struct {
+	failures int
+	cooldown time.Duration
+	now      func() time.Time
+
+	mu sync.Mutex
+	// failed is the number of calls in a row that failed on the backend
+	failed    int
+	openUntil time.Time
+	// probing is set while the call after a cool-down is in flight
+	probing bool
+}
+
+func newCircuitBreaker(failures int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{failures: failures, cooldown: cooldown, now: time.Now}
+}
+
+// allow returns an error when the breaker is open and the call must not
+// reach the backend. A nil breaker allows every call.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed < b.failures {
+		return nil
+	}
+	if wait := b.openUntil.Sub(b.now()); wait > 0 || b.probing {
+		retry := "once the current call finishes"
+		if wait > 0 {
+			retry = "in " + wait.Round(time.Second).String()
+		}
+		return withErrorCode(errCodeBackendUnavailable,
+			fmt.Errorf("calendar backend unavailable: the last %d calls failed; retry %s", b.failed, retry))
+	}
+	b.probing = true
+	return nil
+}
+
+// record notes the outcome of a call allow let through
+func (b *circuitBreaker) record(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	// Cancelled calls and rejected arguments tell nothing about the backend
+	var argErr *argumentError
+	if errors.Is(err, context.Canceled) || errors.As(err, &argErr) {
+		return
+	}
+	if !backendFailure(err) {
+		if b.failed >= b.failures {
+			log.Printf("Calendar backend is reachable again")
+		}
+		b.failed = 0
+		return
+	}
+	b.failed++
+	if b.failed >= b.failures {
+		if b.failed == b.failures {
+			log.Printf("Calendar backend failed %d calls in a row; failing calls for %s", b.failed, b.cooldown)
+		}
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// backendFailure reports whether a tool call failed because the backend
+// didn't answer properly, rather than because of the request
+func backendFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch errorCode(err) {
+	case errCodeBackendError, errCodeTimeout:
+		return true
+	}
+	return false
+}
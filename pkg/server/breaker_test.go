@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(3, 30*time.Second)
+	b.now = func() time.Time { return now }
+	unavailable := &googleapi.Error{Code: 503}
+
+	for i := 0; i < 3; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("call %d: expected the breaker to be closed, got %v", i, err)
+		}
+		b.record(unavailable)
+	}
+	err := b.allow()
+	if errorCode(err) != errCodeBackendUnavailable || !strings.Contains(err.Error(), "calendar backend unavailable") || !strings.Contains(err.Error(), "30s") {
+		t.Fatalf("expected the breaker to open after 3 failures, got %v", err)
+	}
+
+	// After the cool-down one call probes the backend while the others
+	// still fail
+	now = now.Add(31 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected a probe after the cool-down, got %v", err)
+	}
+	if err := b.allow(); err == nil {
+		t.Error("expected other calls to fail while the probe is in flight")
+	}
+	b.record(unavailable)
+	if err := b.allow(); err == nil {
+		t.Error("expected a failed probe to reopen the breaker")
+	}
+
+	now = now.Add(31 * time.Second)
+	b.allow()
+	b.record(nil)
+	if err := b.allow(); err != nil {
+		t.Errorf("expected a successful probe to close the breaker, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_IgnoredErrors(t *testing.T) {
+	b := newCircuitBreaker(1, time.Minute)
+	for _, err := range []error{
+		context.Canceled,
+		badArgumentf("days must be at most 90"),
+		&googleapi.Error{Code: 404},
+		errors.New("boom"),
+	} {
+		b.allow()
+		b.record(err)
+	}
+	if err := b.allow(); err != nil {
+		t.Errorf("expected only backend failures to open the breaker, got %v", err)
+	}
+
+	var none *circuitBreaker
+	none.record(&googleapi.Error{Code: 503})
+	if err := none.allow(); err != nil {
+		t.Errorf("expected a nil breaker to allow calls, got %v", err)
+	}
+}
+
+func TestCircuitBreaker_Tools(t *testing.T) {
+	fake := &fakeCalendar{err: &googleapi.Error{Code: 503}}
+	s := newTestServer(fake)
+	s.breaker = newCircuitBreaker(2, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		s.callTool(context.Background(), &toolCall{id: float64(1), name: toolListEvents})
+	}
+	fake.err = nil
+	text := s.callTool(context.Background(), &toolCall{id: float64(2), name: toolListEvents}).Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "BACKEND_UNAVAILABLE") {
+		t.Errorf("expected the call to fail fast, got %q", text)
+	}
+	if result := s.callTool(context.Background(), &toolCall{id: float64(3), name: toolServerVersion}).Result.(map[string]interface{}); result["isError"] == true {
+		t.Error("local tools should work while the breaker is open")
+	}
+}
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+const maxBroadcastCalendars = 10
+
+// broadcastResult is the outcome of creating or editing the event in one
+// calendar
+type broadcastResult struct {
+	CalendarID string `json:"calendarId"`
+	EventID    string `json:"eventId,omitempty"`
+	Link       string `json:"link,omitempty"`
+	// Error and ErrorCode are set when the calendar rejected the event
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"errorCode,omitempty"`
+}
+
+type broadcastReport struct {
+	BroadcastID string            `json:"broadcastId"`
+	Created     int               `json:"created"`
+	Failed      int               `json:"failed"`
+	Results     []broadcastResult `json:"results"`
+}
+
+func newBroadcastID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// broadcastEventInput is the arguments of create_event_on_calendars
+type broadcastEventInput struct {
+	// Calendars to create the event on: emails, calendar IDs or names from
+	// your calendar list
+	Calendars []string `json:"calendars" jsonschema:"required,maxItems=maxBroadcastCalendars"`
+	newEventArgs
+}
+
+func (s *Server) callBroadcastEvent(ctx context.Context, input broadcastEventInput) (broadcastReport, error) {
+	if len(input.Calendars) == 0 || input.Summary == "" || input.Date == "" || input.StartTime == "" || input.EndTime == "" {
+		return broadcastReport{}, badArgumentf("calendars, summary, date, start_time, and end_time are required")
+	}
+	if len(input.Calendars) > maxBroadcastCalendars {
+		return broadcastReport{}, badArgumentf("at most %d calendars can be given", maxBroadcastCalendars)
+	}
+	if err := s.normalizeDateArg(&input.Date); err != nil {
+		return broadcastReport{}, badArgument(err)
+	}
+
+	// Resolve every calendar before creating anything, so that a typo in
+	// one name doesn't leave the event on only some of them
+	var ids []string
+	seen := make(map[string]bool)
+	for _, name := range input.Calendars {
+		id, err := s.resolveCalendar(ctx, name)
+		if err != nil {
+			return broadcastReport{}, err
+		}
+		if err := s.checkWritable(ctx, id); err != nil {
+			return broadcastReport{}, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	report := broadcastReport{BroadcastID: newBroadcastID(), Results: []broadcastResult{}}
+	draft := gcal.EventDraft{
+		Summary:     input.Summary,
+		Description: input.Description,
+		Date:        input.Date,
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Force:       input.Force,
+		Tags: map[string]string{
+			gcal.BroadcastIDKey:        report.BroadcastID,
+			gcal.BroadcastCalendarsKey: strings.Join(ids, ","),
+		},
+	}
+	for i, id := range ids {
+		gcal.ReportProgress(ctx, i, len(ids), "Creating the event on "+id)
+		event, err := s.settings().calendar.CreateCalendarEvent(ctx, id, draft)
+		if err != nil {
+			// Invalid times are rejected the same way by every calendar
+			if i == 0 && errorCode(err) == errCodeInvalidArgument {
+				return broadcastReport{}, err
+			}
+			report.Failed++
+			report.Results = append(report.Results, broadcastResult{CalendarID: id, Error: err.Error(), ErrorCode: errorCode(err)})
+			continue
+		}
+		report.Created++
+		report.Results = append(report.Results, broadcastResult{CalendarID: id, EventID: event.Id, Link: event.HtmlLink})
+	}
+
+	return report, nil
+}
+
+func (r broadcastReport) toolText(s *Server) string { return s.formatBroadcast(r) }
+func (r broadcastReport) allFailed() bool           { return r.Created == 0 }
+
+func (s *Server) formatBroadcast(r broadcastReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Event created on %d of %d calendar(s)\nBroadcast ID: %s\n\n", r.Created, len(r.Results), r.BroadcastID)
+	for _, res := range r.Results {
+		if res.Error != "" {
+			fmt.Fprintf(&b, "- %s: failed [%s]: %s\n", res.CalendarID, res.ErrorCode, res.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: created, ID %s\n", res.CalendarID, res.EventID)
+		if res.Link != "" {
+			fmt.Fprintf(&b, "  Link: %s\n", res.Link)
+		}
+	}
+	return b.String()
+}
+
+// linkedCopies reads the broadcast ID and the linked calendars from the
+// private properties of one copy
+func linkedCopies(e *calendar.Event) (string, []string) {
+	if e.ExtendedProperties == nil {
+		return "", nil
+	}
+	props := e.ExtendedProperties.Private
+	if props[gcal.BroadcastIDKey] == "" {
+		return "", nil
+	}
+	var calendars []string
+	for _, id := range strings.Split(props[gcal.BroadcastCalendarsKey], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			calendars = append(calendars, id)
+		}
+	}
+	return props[gcal.BroadcastIDKey], calendars
+}
+
+// linkedEditReport is the outcome of edit_linked_events, one result per
+// copy
+type linkedEditReport struct {
+	BroadcastID string            `json:"broadcastId"`
+	Updated     int               `json:"updated"`
+	Failed      int               `json:"failed"`
+	Results     []broadcastResult `json:"results"`
+}
+
+// editLinkedInput is the arguments of edit_linked_events
+type editLinkedInput struct {
+	// ID of the copy on your calendar
+	EventID string `json:"event_id"`
+	eventRefArg
+	// Broadcast ID reported by create_event_on_calendars, when your calendar
+	// has no copy; requires calendars
+	BroadcastID string `json:"broadcast_id"`
+	// With broadcast_id, the calendars holding the copies
+	Calendars []string `json:"calendars"`
+	eventChangeArgs
+}
+
+func (s *Server) callEditLinkedEvents(ctx context.Context, input editLinkedInput) (linkedEditReport, error) {
+	eventID, err := s.eventIDArg(ctx, input.EventID, input.EventRef)
+	if err != nil {
+		return linkedEditReport{}, err
+	}
+	if eventID == "" && (input.BroadcastID == "" || len(input.Calendars) == 0) {
+		return linkedEditReport{}, badArgumentf("event_id or event_ref of a copy on your calendar is required, or broadcast_id together with calendars")
+	}
+	if input.Summary == nil && input.Description == nil && input.Date == nil && input.StartTime == nil && input.EndTime == nil {
+		return linkedEditReport{}, badArgumentf("nothing to change: pass summary, description, date, start_time or end_time")
+	}
+	if err := s.normalizeDateArg(input.Date); err != nil {
+		return linkedEditReport{}, badArgument(err)
+	}
+
+	broadcastID, calendars := input.BroadcastID, input.Calendars
+	if eventID != "" {
+		event, err := s.settings().calendar.GetEvent(ctx, eventID)
+		if err != nil {
+			return linkedEditReport{}, err
+		}
+		broadcastID, calendars = linkedCopies(event)
+		if broadcastID == "" {
+			return linkedEditReport{}, invalidInputf("event %s was not created with %s; use %s instead", eventID, s.exposedToolName(toolBroadcastEvent), s.exposedToolName(toolUpdateEvent))
+		}
+	} else {
+		var resolved []string
+		for _, name := range calendars {
+			id, err := s.resolveCalendar(ctx, name)
+			if err != nil {
+				return linkedEditReport{}, err
+			}
+			resolved = append(resolved, id)
+		}
+		calendars = resolved
+	}
+
+	updates := gcal.EventUpdates{
+		Summary:     input.Summary,
+		Description: input.Description,
+		Date:        input.Date,
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Force:       input.Force,
+	}
+	report := linkedEditReport{BroadcastID: broadcastID, Results: []broadcastResult{}}
+	for i, id := range calendars {
+		gcal.ReportProgress(ctx, i, len(calendars), "Updating the copy on "+id)
+		copies, err := s.settings().calendar.ListLinkedEvents(ctx, id, broadcastID)
+		if err == nil && len(copies) == 0 {
+			err = withErrorCode(errCodeEventNotFound, fmt.Errorf("no copy found, it may have been deleted"))
+		}
+		if err != nil {
+			report.Failed++
+			report.Results = append(report.Results, broadcastResult{CalendarID: id, Error: err.Error(), ErrorCode: errorCode(err)})
+			continue
+		}
+		// A copy on a calendar the account may only read fails like one
+		// the API refuses, without stopping the others
+		writable := s.checkWritable(ctx, id)
+		for _, linked := range copies {
+			if writable != nil {
+				report.Failed++
+				report.Results = append(report.Results, broadcastResult{CalendarID: id, EventID: linked.ID, Error: writable.Error(), ErrorCode: errorCode(writable)})
+				continue
+			}
+			event, err := s.settings().calendar.UpdateCalendarEvent(ctx, id, linked.ID, updates)
+			if err != nil {
+				report.Failed++
+				report.Results = append(report.Results, broadcastResult{CalendarID: id, EventID: linked.ID, Error: err.Error(), ErrorCode: errorCode(err)})
+				continue
+			}
+			report.Updated++
+			report.Results = append(report.Results, broadcastResult{CalendarID: id, EventID: event.Id, Link: event.HtmlLink})
+		}
+	}
+
+	return report, nil
+}
+
+func (r linkedEditReport) toolText(s *Server) string { return s.formatLinkedEdit(r) }
+func (r linkedEditReport) allFailed() bool           { return r.Updated == 0 }
+
+func (s *Server) formatLinkedEdit(r linkedEditReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Updated %d of %d linked event(s)\nBroadcast ID: %s\n\n", r.Updated, len(r.Results), r.BroadcastID)
+	for _, res := range r.Results {
+		if res.Error != "" {
+			fmt.Fprintf(&b, "- %s: failed [%s]: %s\n", res.CalendarID, res.ErrorCode, res.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "- %s: updated, ID %s\n", res.CalendarID, res.EventID)
+	}
+	return b.String()
+}
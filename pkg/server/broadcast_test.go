@@ -0,0 +1,162 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+func broadcastArgs(calendars ...string) json.RawMessage {
+	args, _ := json.Marshal(map[string]interface{}{
+		"calendars":  calendars,
+		"summary":    "Launch review",
+		"date":       "2026-03-19",
+		"start_time": "10:00",
+		"end_time":   "11:00",
+	})
+	return args
+}
+
+func TestCallBroadcastEvent(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:  []string{"room-1@resource.calendar.google.com"},
+		calendarNames: map[string]string{"room-1@resource.calendar.google.com": "Room 1"},
+		createErrs: map[string]error{
+			"project@group.calendar.google.com": &googleapi.Error{Code: 403, Message: "Forbidden"},
+		},
+	}
+	s := newTestServer(fake)
+
+	args := broadcastArgs("team@example.com", "Room 1", "project@group.calendar.google.com", "team@example.com")
+	resp := s.callTool(context.Background(), &toolCall{name: toolBroadcastEvent, id: float64(1), args: args})
+	result := resp.Result.(map[string]interface{})
+	if result["isError"] == true {
+		t.Fatalf("unexpected error result: %v", result)
+	}
+
+	if len(fake.drafts) != 2 {
+		t.Fatalf("expected the event on 2 calendars, got %v", fake.drafts)
+	}
+	team, room := fake.drafts["team@example.com"], fake.drafts["room-1@resource.calendar.google.com"]
+	if team.Tags[gcal.BroadcastIDKey] == "" || team.Tags[gcal.BroadcastIDKey] != room.Tags[gcal.BroadcastIDKey] {
+		t.Errorf("expected the copies to share a broadcast ID, got %v and %v", team.Tags, room.Tags)
+	}
+	if got := team.Tags[gcal.BroadcastCalendarsKey]; got != "team@example.com,room-1@resource.calendar.google.com,project@group.calendar.google.com" {
+		t.Errorf("unexpected linked calendars %s", got)
+	}
+
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"created on 2 of 3", "team@example.com: created, ID evt-team@example.com", "project@group.calendar.google.com: failed [PERMISSION_DENIED]"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %s", want, text)
+		}
+	}
+}
+
+func TestCallBroadcastEvent_AllFailed(t *testing.T) {
+	fake := &fakeCalendar{createErrs: map[string]error{"team@example.com": &googleapi.Error{Code: 500}}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolBroadcastEvent, id: float64(1), args: broadcastArgs("team@example.com")})
+	if resp.Result.(map[string]interface{})["isError"] != true {
+		t.Error("expected an error result when no calendar accepted the event")
+	}
+}
+
+func TestCallBroadcastEvent_UnknownCalendar(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolBroadcastEvent, id: float64(1), args: broadcastArgs("team@example.com", "Nobody")})
+	if resp.Result.(map[string]interface{})["isError"] != true {
+		t.Error("expected an error result for an unknown calendar")
+	}
+	if len(fake.drafts) != 0 {
+		t.Errorf("expected nothing to be created, got %v", fake.drafts)
+	}
+
+	if resp := s.callTool(context.Background(), &toolCall{name: toolBroadcastEvent, id: float64(2), args: json.RawMessage(`{"summary":"x"}`)}); resp.Error == nil {
+		t.Error("expected invalid params without calendars")
+	}
+}
+
+func TestCallEditLinkedEvents(t *testing.T) {
+	fake := &fakeCalendar{
+		fetched: &calendar.Event{Id: "evt-1", ExtendedProperties: &calendar.EventExtendedProperties{Private: map[string]string{
+			gcal.BroadcastIDKey:        "b1",
+			gcal.BroadcastCalendarsKey: "test@example.com,room@example.com,project@example.com",
+		}}},
+		linked: map[string][]gcal.CalendarEvent{
+			"test@example.com": {{ID: "evt-1"}},
+			"room@example.com": {{ID: "evt-2"}},
+		},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"event_id": "evt-1", "start_time": "15:00"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolEditLinked, id: float64(1), args: args})
+	result := resp.Result.(map[string]interface{})
+	if result["isError"] == true {
+		t.Fatalf("unexpected error result: %v", result)
+	}
+
+	for _, id := range []string{"test@example.com", "room@example.com"} {
+		if u, ok := fake.linkedUpdates[id]; !ok || u.StartTime == nil || *u.StartTime != "15:00" {
+			t.Errorf("expected the copy on %s to move to 15:00, got %+v", id, u)
+		}
+	}
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Updated 2 of 3") || !strings.Contains(text, "project@example.com: failed [EVENT_NOT_FOUND]") {
+		t.Errorf("unexpected report %s", text)
+	}
+}
+
+func TestCallEditLinkedEvents_ReadOnlyCopy(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:  []string{"test@example.com", "room@example.com"},
+		calendarRoles: map[string]string{"test@example.com": "owner", "room@example.com": "reader"},
+		linked: map[string][]gcal.CalendarEvent{
+			"test@example.com": {{ID: "evt-1"}},
+			"room@example.com": {{ID: "evt-2"}},
+		},
+	}
+	s := newTestServer(fake)
+	s.checkCalendars(context.Background())
+
+	args, _ := json.Marshal(map[string]interface{}{"broadcast_id": "b1", "calendars": []string{"test@example.com", "room@example.com"}, "summary": "Renamed"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolEditLinked, id: float64(1), args: args})
+	result := resp.Result.(map[string]interface{})
+	if result["isError"] == true {
+		t.Fatalf("unexpected error result: %v", result)
+	}
+	if _, ok := fake.linkedUpdates["room@example.com"]; ok {
+		t.Error("expected the copy on the read-only calendar to be left alone")
+	}
+	if _, ok := fake.linkedUpdates["test@example.com"]; !ok {
+		t.Error("expected the copy on the writable calendar to be updated")
+	}
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Updated 1 of 2") || !strings.Contains(text, "room@example.com: failed [PERMISSION_DENIED]") {
+		t.Errorf("unexpected report %s", text)
+	}
+}
+
+func TestCallEditLinkedEvents_NotLinked(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{"event_id": "evt-1", "summary": "Renamed"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolEditLinked, id: float64(1), args: args})
+	if resp.Result.(map[string]interface{})["isError"] != true {
+		t.Error("expected an error result for an event without linked copies")
+	}
+
+	args, _ = json.Marshal(map[string]string{"event_id": "evt-1"})
+	if resp := s.callTool(context.Background(), &toolCall{name: toolEditLinked, id: float64(2), args: args}); resp.Error == nil {
+		t.Error("expected invalid params without any change")
+	}
+}
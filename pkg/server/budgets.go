@@ -0,0 +1,143 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+// categoryBudgets are the weekly hours allowed per category, keyed by the
+// lower-cased category name
+type categoryBudgets map[string]float64
+
+// budgetAlert is a category that went over its budget
+type budgetAlert struct {
+	Category    string  `json:"category"`
+	Hours       float64 `json:"hours"`
+	BudgetHours float64 `json:"budgetHours"`
+}
+
+// parseCategoryBudgets parses comma-separated category=hours pairs, e.g.
+// "customer=5h, 1:1=3h". Hours may be a plain number or a duration such as
+// 90m. Every category must be one of the taxonomy's, or "other".
+func parseCategoryBudgets(spec string, categories taxonomy) (categoryBudgets, error) {
+	known := map[string]bool{uncategorized: true}
+	for _, r := range categories {
+		known[strings.ToLower(r.category)] = true
+	}
+
+	budgets := make(categoryBudgets)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		category, value, found := strings.Cut(item, "=")
+		category, value = strings.ToLower(strings.TrimSpace(category)), strings.TrimSpace(value)
+		if !found || category == "" || value == "" {
+			return nil, fmt.Errorf("budget %q: expected category=hours, e.g. customer=5h", item)
+		}
+		if !known[category] {
+			return nil, fmt.Errorf("budget %q: unknown category %q", item, category)
+		}
+		hours, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			d, derr := time.ParseDuration(value)
+			if derr != nil {
+				return nil, fmt.Errorf("budget %q: invalid hours %q", item, value)
+			}
+			hours = d.Hours()
+		}
+		if hours <= 0 {
+			return nil, fmt.Errorf("budget %q: hours must be positive", item)
+		}
+		budgets[category] = hours
+	}
+	if len(budgets) == 0 {
+		return nil, fmt.Errorf("no budgets given")
+	}
+	return budgets, nil
+}
+
+// alerts returns the categories over budget in a range of days, with the
+// weekly budgets scaled to its length. Alerts are sorted by how far over
+// budget they are, most first.
+func (b categoryBudgets) alerts(categories []categoryHours, days int) []budgetAlert {
+	var alerts []budgetAlert
+	for _, c := range categories {
+		weekly, ok := b[strings.ToLower(c.Name)]
+		if !ok {
+			continue
+		}
+		budget := weekly * float64(days) / 7
+		if c.Hours > budget {
+			alerts = append(alerts, budgetAlert{Category: c.Name, Hours: c.Hours, BudgetHours: roundHours(budget)})
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Hours-alerts[i].BudgetHours > alerts[j].Hours-alerts[j].BudgetHours
+	})
+	return alerts
+}
+
+// weekStart returns midnight of the Monday of t's week
+func weekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// budgetWarning tells whether a newly created event takes its category over
+// the weekly budget of the week it falls in. It returns an empty string when
+// the category has no budget or is within it; failing to read the week only
+// skips the check.
+func (s *Server) budgetWarning(ctx context.Context, event *calendar.Event) string {
+	if s.budgets == nil {
+		return ""
+	}
+	category := s.categoryOf(gcal.CalendarEvent{Summary: event.Summary})
+	budget, ok := s.budgets[strings.ToLower(category)]
+	if !ok {
+		return ""
+	}
+	d, ok := gcal.EventDuration(event)
+	if !ok {
+		return ""
+	}
+	start, err := time.Parse(time.RFC3339, event.Start.DateTime)
+	if err != nil {
+		return ""
+	}
+
+	monday := weekStart(start.In(s.settings().location))
+	sunday := monday.AddDate(0, 0, 6)
+	events, err := s.settings().calendar.ListEventsRange(ctx, monday.Format("2006-01-02"), sunday.Format("2006-01-02"))
+	if err != nil {
+		log.Printf("Failed to check the %s budget: %v", category, err)
+		return ""
+	}
+
+	hours := d.Hours()
+	var others []gcal.CalendarEvent
+	for _, e := range events {
+		if e.ID != event.Id {
+			others = append(others, e)
+		}
+	}
+	for _, c := range summarizePeriod(others, "", "", s.categoryOf).Categories {
+		if strings.EqualFold(c.Name, category) {
+			hours += c.Hours
+		}
+	}
+	if hours <= budget {
+		return ""
+	}
+	return fmt.Sprintf("Warning: this puts %s at %s hours in the week of %s, over its weekly budget of %s hours",
+		s.sanitize(category), formatHours(hours), monday.Format("2006-01-02"), formatHours(budget))
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestParseCategoryBudgets(t *testing.T) {
+	categories, _ := parseTaxonomy(`customer=acme; 1:1=\b1:1\b`)
+	budgets, err := parseCategoryBudgets("Customer=5h, 1:1=90m, other=10", categories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if budgets["customer"] != 5 || budgets["1:1"] != 1.5 || budgets["other"] != 10 {
+		t.Errorf("unexpected budgets %v", budgets)
+	}
+
+	for _, bad := range []string{"", "customer", "customer=", "customer=lots", "customer=0", "hiring=5h"} {
+		if _, err := parseCategoryBudgets(bad, categories); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCategoryBudgetsAlerts(t *testing.T) {
+	budgets := categoryBudgets{"customer": 5, "1:1": 2}
+	categories := []categoryHours{{Name: "customer", Hours: 6}, {Name: "1:1", Hours: 4}, {Name: "other", Hours: 20}}
+
+	alerts := budgets.alerts(categories, 7)
+	if len(alerts) != 2 || alerts[0].Category != "1:1" || alerts[1].Category != "customer" || alerts[1].BudgetHours != 5 {
+		t.Errorf("unexpected alerts %+v", alerts)
+	}
+	// Over two weeks the budgets double
+	if alerts := budgets.alerts(categories, 14); len(alerts) != 0 {
+		t.Errorf("expected no alerts over two weeks, got %+v", alerts)
+	}
+}
+
+func TestBudgetsInAnalysis(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Acme demo", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T12:00:00Z"},
+		{ID: "2", Summary: "Acme review", Start: "2026-03-17T09:00:00Z", End: "2026-03-17T12:00:00Z"},
+	}}
+	s := newTestServer(fake)
+	s.categories, _ = parseTaxonomy("customer=acme")
+	s.budgets, _ = parseCategoryBudgets("customer=5h", s.categories)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(1), args: json.RawMessage(`{"start_date":"2026-03-16","days":7}`)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Over budget:\n- customer: 6 hours of 5\n") {
+		t.Errorf("expected a budget alert in:\n%s", text)
+	}
+
+	// A teammate's calendar isn't held to the user's budgets
+	fake.extraCalendars = map[string][]gcal.CalendarEvent{"team@example.com": fake.events}
+	resp = s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(2), args: json.RawMessage(`{"start_date":"2026-03-16","days":7,"calendar":"team@example.com"}`)})
+	text = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if strings.Contains(text, "Over budget") {
+		t.Errorf("expected no budget alerts for a teammate's calendar:\n%s", text)
+	}
+}
+
+func TestBudgetWarningOnCreate(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Acme demo", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T13:00:00Z"},
+		{ID: "2", Summary: "Sam 1:1", Start: "2026-03-16T14:00:00Z", End: "2026-03-16T15:00:00Z"},
+	}}
+	s := newTestServer(fake)
+	s.categories, _ = parseTaxonomy(`customer=acme; 1:1=\b1:1\b`)
+	s.budgets, _ = parseCategoryBudgets("customer=5h", s.categories)
+
+	create := func(summary, start, end string) string {
+		fake.created = &calendar.Event{Id: "new", Summary: summary, Start: &calendar.EventDateTime{DateTime: start}, End: &calendar.EventDateTime{DateTime: end}}
+		args, _ := json.Marshal(map[string]string{"summary": summary, "date": start[:10], "start_time": start[11:16], "end_time": end[11:16]})
+		resp := s.callTool(context.Background(), &toolCall{name: toolCreateEvent, id: float64(1), args: args})
+		return resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	}
+
+	// Thursday's call lands in the same Monday-to-Sunday week
+	text := create("Acme escalation", "2026-03-19T10:00:00Z", "2026-03-19T11:30:00Z")
+	if !strings.Contains(text, "Warning: this puts customer at 5.5 hours in the week of 2026-03-16, over its weekly budget of 5 hours") {
+		t.Errorf("expected a budget warning in:\n%s", text)
+	}
+	if fake.lastStart != "2026-03-16" || fake.lastEnd != "2026-03-22" {
+		t.Errorf("expected the week to be read, got %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	for _, summary := range []string{"Acme sync", "Sam 1:1"} {
+		if text := create(summary, "2026-03-19T10:00:00Z", "2026-03-19T11:00:00Z"); strings.Contains(text, "Warning") {
+			t.Errorf("%s: expected no warning:\n%s", summary, text)
+		}
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	for day, want := range map[string]string{"2026-03-16": "2026-03-16", "2026-03-19": "2026-03-16", "2026-03-22": "2026-03-16", "2026-03-23": "2026-03-23"} {
+		d, _ := time.ParseInLocation("2006-01-02", day, loc)
+		if got := weekStart(d.Add(15 * time.Hour)); got.Format("2006-01-02") != want || got.Hour() != 0 {
+			t.Errorf("weekStart(%s) = %s, want %s", day, got, want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// resolveCalendar turns the calendar argument of a tool into a calendar ID.
+// An email address or calendar ID is used as is, so a teammate's calendar
+// can be read whenever they share it, even if it isn't in the calendar
+// list. Anything else is looked up by name in the calendar list. An empty
+// argument means the primary calendar.
+func (s *Server) resolveCalendar(ctx context.Context, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return s.primaryCalendar(ctx), nil
+	}
+	if strings.Contains(name, "@") {
+		return name, nil
+	}
+
+	calendars, err := s.settings().calendar.ListCalendars(ctx)
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, c := range calendars {
+		if strings.EqualFold(c.Summary, name) || strings.EqualFold(c.ID, name) {
+			return c.ID, nil
+		}
+		if strings.HasPrefix(strings.ToLower(c.Summary), strings.ToLower(name)) {
+			matches = append(matches, c.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", invalidInputf("no calendar named %q in your calendar list; use the person's email address instead", name)
+	case 1:
+		return matches[0], nil
+	}
+	return "", invalidInputf("%q matches several calendars (%s); use an email address", name, strings.Join(matches, ", "))
+}
+
+// calendarResourceURI identifies the upcoming events of one calendar, e.g.
+// calendar://team@example.com/upcoming
+func calendarResourceURI(calendarID string) string {
+	return "calendar://" + url.PathEscape(calendarID) + "/upcoming"
+}
+
+// parseCalendarResourceURI extracts the calendar ID of a
+// calendarResourceURI. calendar://events/upcoming has the same shape but is
+// the primary calendar's resource, not one of a calendar named "events".
+func parseCalendarResourceURI(uri string) (string, bool) {
+	rest, found := strings.CutPrefix(uri, "calendar://")
+	if !found || uri == resourceUpcomingEvents {
+		return "", false
+	}
+	escaped, found := strings.CutSuffix(rest, "/upcoming")
+	if !found || strings.Contains(escaped, "/") {
+		return "", false
+	}
+	calendarID, err := url.PathUnescape(escaped)
+	if err != nil || calendarID == "" {
+		return "", false
+	}
+	return calendarID, true
+}
+
+// calendarSync is the calendar list as of the last sync, shared by every
+// session of the TCP transport
+type calendarSync struct {
+	mu sync.Mutex
+	// ids is nil until the first sync
+	ids []string
+	// roles holds the access role of each calendar by account, "" for
+	// the default one; see gcal.CalendarInfo.AccessRole
+	roles map[string]map[string]string
+}
+
+// calendarResources lists a resource per calendar seen by the last
+// calendar list sync
+func (s *Server) calendarResources() []map[string]interface{} {
+	s.calendarList.mu.Lock()
+	defer s.calendarList.mu.Unlock()
+
+	resources := make([]map[string]interface{}, 0, len(s.calendarList.ids))
+	for _, id := range s.calendarList.ids {
+		resources = append(resources, map[string]interface{}{
+			"uri":         calendarResourceURI(id),
+			"name":        "Upcoming events: " + id,
+			"description": fmt.Sprintf("Events of calendar %s for the next %d days", id, upcomingResourceDays),
+			"mimeType":    "text/plain",
+		})
+	}
+	return resources
+}
+
+func (s *Server) knownCalendar(calendarID string) bool {
+	s.calendarList.mu.Lock()
+	defer s.calendarList.mu.Unlock()
+	return slices.Contains(s.calendarList.ids, calendarID) || slices.Contains(s.settings().calendar.Calendars(), calendarID)
+}
+
+func (s *Server) readCalendarResource(ctx context.Context, calendarID string) (string, error) {
+	today := time.Now().In(s.settings().location)
+	events, err := s.settings().calendar.ListCalendarEvents(ctx, calendarID,
+		today.Format("2006-01-02"), today.AddDate(0, 0, upcomingResourceDays-1).Format("2006-01-02"))
+	if err != nil {
+		return "", err
+	}
+	return s.formatEvents(events), nil
+}
+
+// listCalendarRange lists a date range of the calendar a tool's calendar
+// argument names
+func (s *Server) listCalendarRange(ctx context.Context, name, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	calendarID, err := s.resolveCalendar(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if calendarID == s.primaryCalendar(ctx) {
+		return s.settings().calendar.ListEventsRange(ctx, startDate, endDate)
+	}
+	events, err := s.settings().calendar.ListCalendarEvents(ctx, calendarID, startDate, endDate)
+	var notFound *gcal.CalendarNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return nil, fmt.Errorf("calendar %s (is it shared with you?): %w", calendarID, err)
+	}
+	return events, err
+}
+
+// syncCalendars periodically re-reads the user's calendar list and tells
+// the client when calendars were added or removed, so it can refresh its
+// resource index
+func (s *Server) syncCalendars() {
+	s.checkCalendars(context.Background())
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.checkCalendars(context.Background())
+	}
+}
+
+func (s *Server) checkCalendars(ctx context.Context) {
+	cal := s.configured().calendar
+	ids, roles, err := readCalendarLists(ctx, cal, nil)
+	if err != nil {
+		log.Printf("Failed to sync calendar list: %v", err)
+		return
+	}
+	s.storeCalendarList(cal, ids, roles)
+}
+
+// readCalendarLists reads the calendar list of every account of cal: the
+// IDs of the default account's calendars and the access roles by account.
+// pace, when set, is called before reading the list of each further
+// account, which is skipped when it returns false.
+func readCalendarLists(ctx context.Context, cal gcal.Service, pace func() bool) ([]string, map[string]map[string]string, error) {
+	calendars, err := cal.ListCalendars(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]string, 0, len(calendars))
+	roles := map[string]map[string]string{"": accessRoles(calendars)}
+	for _, c := range calendars {
+		ids = append(ids, c.ID)
+	}
+	// The other accounts only matter for the roles, which calls naming
+	// them are checked against
+	if accounts, ok := cal.(*gcal.Accounts); ok {
+		for _, name := range accounts.Names()[1:] {
+			if pace != nil && !pace() {
+				break
+			}
+			svc, _ := accounts.Account(name)
+			calendars, err := svc.ListCalendars(ctx)
+			if err != nil {
+				log.Printf("Failed to sync calendar list of account %s: %v", name, err)
+				continue
+			}
+			roles[name] = accessRoles(calendars)
+		}
+	}
+	return ids, roles, nil
+}
+
+// storeCalendarList records a calendar list read from cal and tells the
+// client what changed
+func (s *Server) storeCalendarList(cal gcal.Service, ids []string, roles map[string]map[string]string) {
+	wasReadOnly := s.primariesReadOnly(cal)
+	s.calendarList.mu.Lock()
+	// The first sync only records the calendars
+	changed := s.calendarList.ids != nil && !slices.Equal(s.calendarList.ids, ids)
+	s.calendarList.ids = ids
+	for name, known := range s.calendarList.roles {
+		// Keep what is known of accounts whose list couldn't be read
+		if _, ok := roles[name]; !ok {
+			roles[name] = known
+		}
+	}
+	s.calendarList.roles = roles
+	s.calendarList.mu.Unlock()
+
+	if changed {
+		s.sendNotification("notifications/resources/list_changed", nil)
+	}
+	// Mutating tools appear or disappear with write access to the primary
+	// calendars
+	if readOnly := s.primariesReadOnly(cal); readOnly != wasReadOnly {
+		log.Printf("Primary calendar access is now read-only: %t", readOnly)
+		s.sendNotification("notifications/tools/list_changed", nil)
+	}
+}
+
+// accessRoles maps each calendar of a calendar list to its access role
+func accessRoles(calendars []gcal.CalendarInfo) map[string]string {
+	roles := make(map[string]string, len(calendars))
+	for _, c := range calendars {
+		roles[c.ID] = c.AccessRole
+	}
+	return roles
+}
+
+// calendarReadOnly reports whether the last calendar list sync found that
+// account, "" for the default one, may only read calendarID. Calendars
+// missing from the list, such as teammates' calendars read by email, are
+// assumed writable; Google rejects writes to them if they aren't.
+func (s *Server) calendarReadOnly(account, calendarID string) bool {
+	if accounts, ok := s.configured().calendar.(*gcal.Accounts); ok && account == accounts.Names()[0] {
+		account = ""
+	}
+	s.calendarList.mu.Lock()
+	role := s.calendarList.roles[account][calendarID]
+	s.calendarList.mu.Unlock()
+	switch role {
+	case "reader", "freeBusyReader":
+		return true
+	}
+	return false
+}
+
+// primariesReadOnly reports whether every account of cal may only read its
+// primary calendar
+func (s *Server) primariesReadOnly(cal gcal.Service) bool {
+	if !s.calendarReadOnly("", cal.CalendarID()) {
+		return false
+	}
+	if accounts, ok := cal.(*gcal.Accounts); ok {
+		for _, name := range accounts.Names()[1:] {
+			svc, _ := accounts.Account(name)
+			if !s.calendarReadOnly(name, svc.CalendarID()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkWritable rejects writes to a calendar the account of ctx may only
+// read
+func (s *Server) checkWritable(ctx context.Context, calendarID string) error {
+	if s.calendarReadOnly(gcal.AccountFrom(ctx), calendarID) {
+		return withErrorCode(errCodePermissionDenied, fmt.Errorf("you have read-only access to calendar %s; ask its owner for edit access", calendarID))
+	}
+	return nil
+}
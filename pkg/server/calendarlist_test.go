@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestCheckCalendars_NotifiesListChanged(t *testing.T) {
+	fake := &fakeCalendar{calendarList: []string{"team@example.com", "test@example.com"}}
+	out := &bytes.Buffer{}
+	s := New(fake, out)
+
+	s.checkCalendars(context.Background())
+	s.checkCalendars(context.Background())
+	if out.Len() != 0 {
+		t.Fatalf("expected no notification while the list is unchanged, got %q", out.String())
+	}
+
+	fake.calendarList = []string{"test@example.com"}
+	s.checkCalendars(context.Background())
+
+	var notification JSONRPCNotification
+	if err := json.Unmarshal(out.Bytes(), &notification); err != nil {
+		t.Fatalf("expected a notification, got %q: %v", out.String(), err)
+	}
+	if notification.Method != "notifications/resources/list_changed" {
+		t.Errorf("unexpected method %q", notification.Method)
+	}
+}
+
+func TestHandleResourcesList_Calendars(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:   []string{"team@example.com", "test@example.com"},
+		extraCalendars: map[string][]gcal.CalendarEvent{"team@example.com": {{ID: "1", Summary: "Offsite"}}},
+	}
+	s := newTestServer(fake)
+	s.checkCalendars(context.Background())
+
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/list"})
+	resources := resp.Result.(map[string]interface{})["resources"].([]map[string]interface{})
+	if len(resources) != 4 || resources[2]["uri"] != "calendar://team@example.com/upcoming" {
+		t.Fatalf("unexpected resources: %v", resources)
+	}
+
+	params, _ := json.Marshal(map[string]string{"uri": "calendar://team@example.com/upcoming"})
+	resp = s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "resources/read", Params: params})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	contents := resp.Result.(map[string]interface{})["contents"].([]map[string]string)
+	if !strings.Contains(contents[0]["text"], "Offsite") {
+		t.Errorf("expected the calendar's events, got %q", contents[0]["text"])
+	}
+
+	params, _ = json.Marshal(map[string]string{"uri": "calendar://gone@example.com/upcoming"})
+	resp = s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(3), Method: "resources/read", Params: params})
+	if resp.Error == nil {
+		t.Error("expected an error for a calendar that isn't in the list")
+	}
+}
+
+func TestParseCalendarResourceURI(t *testing.T) {
+	id, ok := parseCalendarResourceURI(calendarResourceURI("a/b@example.com"))
+	if !ok || id != "a/b@example.com" {
+		t.Errorf("round trip failed: %q %v", id, ok)
+	}
+	for _, bad := range []string{resourceUpcomingEvents, "calendar:///upcoming", "calendar://x/events/upcoming"} {
+		if _, ok := parseCalendarResourceURI(bad); ok {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestResolveCalendar(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList: []string{"maria@example.com", "mark@example.com", "team@group.calendar.google.com"},
+		calendarNames: map[string]string{
+			"maria@example.com":              "Maria Garcia",
+			"mark@example.com":               "Mark Lee",
+			"team@group.calendar.google.com": "Team",
+		},
+	}
+	s := newTestServer(fake)
+
+	tests := []struct {
+		name, want string
+	}{
+		{"", "test@example.com"},
+		{"ana@example.com", "ana@example.com"},
+		{"maria garcia", "maria@example.com"},
+		{"Maria", "maria@example.com"},
+		{"team", "team@group.calendar.google.com"},
+	}
+	for _, tt := range tests {
+		got, err := s.resolveCalendar(context.Background(), tt.name)
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %s, got %s (%v)", tt.name, tt.want, got, err)
+		}
+	}
+
+	for _, name := range []string{"Ma", "Nobody"} {
+		if _, err := s.resolveCalendar(context.Background(), name); errorCode(err) != errCodeInvalidArgument {
+			t.Errorf("%q: expected an invalid argument error, got %v", name, err)
+		}
+	}
+}
+
+func TestCallListEventsRange_TeammateCalendar(t *testing.T) {
+	fake := &fakeCalendar{
+		extraCalendars: map[string][]gcal.CalendarEvent{
+			"maria@example.com": {{ID: "1", Summary: "Customer call", CalendarID: "maria@example.com"}},
+		},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-03-19", "end_date": "2026-03-19", "calendar": "maria@example.com"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEventsRange, id: float64(1), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Customer call") {
+		t.Errorf("expected the teammate's events, got %s", text)
+	}
+	if fake.lastStart != "" {
+		t.Error("expected the primary calendar not to be read")
+	}
+}
+
+func TestCalendarAccessRole(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:  []string{"team@example.com", "test@example.com"},
+		calendarRoles: map[string]string{"team@example.com": "reader", "test@example.com": "owner"},
+	}
+	out := &bytes.Buffer{}
+	s := newReadyServer(fake, out)
+	s.checkCalendars(context.Background())
+
+	// Writes to a calendar the user may only read are refused up front
+	args, _ := json.Marshal(map[string]interface{}{
+		"calendars": []string{"test@example.com", "team@example.com"}, "summary": "Offsite",
+		"date": "2026-03-20", "start_time": "10:00", "end_time": "11:00",
+	})
+	resp := s.callTool(context.Background(), &toolCall{name: toolBroadcastEvent, id: float64(1), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "PERMISSION_DENIED") || !strings.Contains(text, "read-only access to calendar team@example.com") {
+		t.Errorf("expected a read-only access error, got %q", text)
+	}
+	if len(fake.drafts) != 0 {
+		t.Errorf("expected nothing to be created, got %v", fake.drafts)
+	}
+
+	// Losing write access to the primary calendar hides the mutating tools
+	fake.calendarRoles["test@example.com"] = "freeBusyReader"
+	out.Reset()
+	s.checkCalendars(context.Background())
+	if !strings.Contains(out.String(), "notifications/tools/list_changed") {
+		t.Errorf("expected a tools/list_changed notification, got %q", out.String())
+	}
+	tools := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "tools/list"}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	for _, tool := range tools {
+		if tool["name"] == toolCreateEvent {
+			t.Error("expected create_event to be hidden")
+		}
+	}
+	params, _ := json.Marshal(map[string]interface{}{"name": toolDeleteEvent, "arguments": map[string]string{"event_id": "evt-1"}})
+	call := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(3), Method: "tools/call", Params: params})
+	if call.Error == nil || !strings.Contains(call.Error.Message, "read-only access to calendar test@example.com") {
+		t.Errorf("expected delete_event to be refused, got %+v", call.Error)
+	}
+	if fake.deletedID != "" {
+		t.Error("the event should not be deleted")
+	}
+}
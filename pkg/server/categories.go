@@ -0,0 +1,83 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// uncategorized is the category of events no rule matches
+const uncategorized = "other"
+
+// categoryRule puts events whose title or a guest's email matches pattern
+// into category
+type categoryRule struct {
+	category string
+	pattern  *regexp.Regexp
+}
+
+// taxonomy is the ordered list of rules from CALENDAR_CATEGORIES; the first
+// matching rule wins
+type taxonomy []categoryRule
+
+// parseTaxonomy parses rules separated by semicolons, each written as
+// category=pattern, e.g. "1:1=\b1:1\b|one on one; customer=@acme\.com".
+// Patterns are case-insensitive regular expressions; a category may have
+// several rules.
+func parseTaxonomy(spec string) (taxonomy, error) {
+	var rules taxonomy
+	for _, item := range strings.Split(spec, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		category, pattern, found := strings.Cut(item, "=")
+		category, pattern = strings.TrimSpace(category), strings.TrimSpace(pattern)
+		if !found || category == "" || pattern == "" {
+			return nil, fmt.Errorf("rule %q: expected category=pattern, e.g. customer=@acme\\.com", item)
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", item, err)
+		}
+		rules = append(rules, categoryRule{category: category, pattern: re})
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules given")
+	}
+	return rules, nil
+}
+
+// categorize returns the category of the first rule matching e, or an
+// empty string
+func (t taxonomy) categorize(e gcal.CalendarEvent) string {
+	for _, r := range t {
+		if r.pattern.MatchString(e.Summary) {
+			return r.category
+		}
+		for _, g := range e.Guests {
+			if r.pattern.MatchString(g.Email) {
+				return r.category
+			}
+		}
+	}
+	return ""
+}
+
+// categoryOf names the category an event counts towards in reports: its
+// taxonomy category when one is configured, otherwise its title, so that
+// the instances of a recurring meeting add up
+func (s *Server) categoryOf(e gcal.CalendarEvent) string {
+	if s.categories != nil {
+		if c := s.categories.categorize(e); c != "" {
+			return c
+		}
+		return uncategorized
+	}
+	if name := strings.TrimSpace(e.Summary); name != "" {
+		return name
+	}
+	return "(untitled)"
+}
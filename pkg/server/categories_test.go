@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestParseTaxonomy(t *testing.T) {
+	rules, err := parseTaxonomy(`1:1=\b1:1\b|one on one; customer=@acme\.com ; customer=demo;personal=gym|dentist`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rules) != 4 || rules[0].category != "1:1" || rules[2].category != "customer" {
+		t.Errorf("unexpected rules %+v", rules)
+	}
+
+	for _, bad := range []string{"", "customer", "=acme", "customer=", "customer=(acme"} {
+		if _, err := parseTaxonomy(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestTaxonomyCategorize(t *testing.T) {
+	rules, _ := parseTaxonomy(`1:1=\b1:1\b; customer=@acme\.com|demo; personal=gym`)
+	tests := map[string]gcal.CalendarEvent{
+		"1:1":      {Summary: "Alex / Sam 1:1"},
+		"customer": {Summary: "Quarterly review", Guests: []gcal.Guest{{Email: "me@example.com"}, {Email: "pat@ACME.com"}}},
+		"personal": {Summary: "GYM"},
+		"":         {Summary: "Team sync"},
+	}
+	for want, e := range tests {
+		if got := rules.categorize(e); got != want {
+			t.Errorf("categorize(%q) = %q, want %q", e.Summary, got, want)
+		}
+	}
+	// The first matching rule wins
+	if got := rules.categorize(gcal.CalendarEvent{Summary: "1:1 before the demo"}); got != "1:1" {
+		t.Errorf("expected the first rule to win, got %q", got)
+	}
+}
+
+func TestCategories_InAnalyticsAndListings(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Acme demo", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:30:00Z"},
+		{ID: "2", Summary: "Sam 1:1", Start: "2026-03-16T11:00:00Z", End: "2026-03-16T11:30:00Z"},
+		{ID: "3", Summary: "All hands", Start: "2026-03-16T13:00:00Z", End: "2026-03-16T14:00:00Z"},
+	}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(1), args: json.RawMessage(`{"start_date":"2026-03-16","days":1}`)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if strings.Contains(text, "Hours by category") {
+		t.Errorf("expected no categories without a taxonomy:\n%s", text)
+	}
+
+	s.categories, _ = parseTaxonomy(`1:1=\b1:1\b; customer=acme`)
+	resp = s.callTool(context.Background(), &toolCall{name: toolAnalyzeTime, id: float64(2), args: json.RawMessage(`{"start_date":"2026-03-16","days":1}`)})
+	text = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"Hours by category:\n- customer: 1.5 hours, 1 meeting(s)\n- other: 1 hours, 1 meeting(s)\n- 1:1: 0.5 hours"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	listing := s.formatEvents(fake.events)
+	if !strings.Contains(listing, "- Acme demo\n  Start: 2026-03-16T09:00:00Z\n  End: 2026-03-16T10:30:00Z\n  Category: customer\n") || strings.Count(listing, "Category:") != 2 {
+		t.Errorf("expected categories in the listing:\n%s", listing)
+	}
+}
@@ -0,0 +1,300 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// chineseCalendar implements the Chinese lunisolar calendar by its
+// astronomical rules, following Reingold and Dershowitz, "Calendrical
+// Calculations": months start on the day of the new moon in China, the
+// month holding the winter solstice is the 11th, and in years with 13
+// months the first month without a major solar term is the leap month.
+// New moons and solar terms are computed to within about a minute, so a
+// date can differ from published almanacs when one of them falls right at
+// midnight in China.
+type chineseCalendar struct{}
+
+var chineseAnimals = []string{
+	"Rat", "Ox", "Tiger", "Rabbit", "Dragon", "Snake",
+	"Horse", "Goat", "Monkey", "Rooster", "Dog", "Pig",
+}
+
+const (
+	meanSynodicMonth = 29.530588861
+	meanTropicalYear = 365.242189
+	// j2000 is the Julian Day of 1 January 2000, 12:00 TT
+	j2000 = 2451545.0
+)
+
+func (chineseCalendar) Name() string { return "Chinese" }
+
+func (chineseCalendar) FormatDate(t time.Time) string {
+	month, leap, day := chineseFromJulianDay(julianDayOf(t))
+
+	// Months 11 and 12 that run into January or February belong to the
+	// year that started the previous spring
+	year := t.Year()
+	if month >= 11 && t.Month() <= time.February {
+		year--
+	}
+	animal := chineseAnimals[floorMod(year-4, 12)]
+	if leap {
+		return fmt.Sprintf("day %d of leap month %d, Year of the %s", day, month, animal)
+	}
+	return fmt.Sprintf("day %d of month %d, Year of the %s", day, month, animal)
+}
+
+// chineseFromJulianDay returns the month, whether it is a leap month, and
+// the day of the month of the date with Julian Day Number jd
+func chineseFromJulianDay(jd int) (month int, leap bool, day int) {
+	s1 := chineseWinterSolsticeOnOrBefore(jd)
+	s2 := chineseWinterSolsticeOnOrBefore(s1 + 370)
+	m12 := chineseNewMoonOnOrAfter(s1 + 1)
+	nextM11 := chineseNewMoonBefore(s2 + 1)
+	m := chineseNewMoonBefore(jd + 1)
+	leapYear := lunations(m12, nextM11) == 12
+
+	month = lunations(m12, m)
+	if leapYear && chinesePriorLeapMonth(m12, m) {
+		month--
+	}
+	month = floorMod(month-1, 12) + 1
+	leap = leapYear && chineseNoMajorSolarTerm(m) && !chinesePriorLeapMonth(m12, chineseNewMoonBefore(m))
+	return month, leap, jd - m + 1
+}
+
+// lunations is the number of months from the one starting on day from to
+// the one starting on day to
+func lunations(from, to int) int {
+	return int(math.Round(float64(to-from) / meanSynodicMonth))
+}
+
+// chinesePriorLeapMonth reports whether there is a month without a major
+// solar term from the month starting on day first up to the one starting
+// on day m
+func chinesePriorLeapMonth(first, m int) bool {
+	for ; m >= first; m = chineseNewMoonBefore(m) {
+		if chineseNoMajorSolarTerm(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// chineseNoMajorSolarTerm reports whether the month starting on day m has
+// no major solar term, the sun entering no new 30° sign during it
+func chineseNoMajorSolarTerm(m int) bool {
+	return chineseMajorSolarTerm(m) == chineseMajorSolarTerm(chineseNewMoonOnOrAfter(m+1))
+}
+
+// chineseMajorSolarTerm numbers the last major solar term at the start of
+// day jd in China
+func chineseMajorSolarTerm(jd int) int {
+	s := solarLongitude(chineseMidnight(jd))
+	return floorMod(2+int(math.Floor(s/30)), 12) + 1
+}
+
+// chineseWinterSolsticeOnOrBefore returns the day in China of the last
+// winter solstice on or before day jd
+func chineseWinterSolsticeOnOrBefore(jd int) int {
+	approx := estimatePriorSolarLongitude(270, chineseMidnight(jd+1))
+	day := int(math.Floor(approx)) - 1
+	for solarLongitude(chineseMidnight(day+1)) <= 270 {
+		day++
+	}
+	return chineseDay(chineseMidnight(day))
+}
+
+// chineseNewMoonOnOrAfter returns the day in China of the first new moon
+// on or after the start of day jd
+func chineseNewMoonOnOrAfter(jd int) int {
+	return chineseDay(newMoonAtOrAfter(chineseMidnight(jd)))
+}
+
+// chineseNewMoonBefore returns the day in China of the last new moon
+// before the start of day jd
+func chineseNewMoonBefore(jd int) int {
+	return chineseDay(newMoonBefore(chineseMidnight(jd)))
+}
+
+// chineseOffset is the offset of Chinese time from UT in days: UTC+8 from
+// 1929, the mean solar time of Beijing before
+func chineseOffset(jd int) float64 {
+	if jd >= julianDayNumber(1929, 1, 1) {
+		return 8.0 / 24
+	}
+	return 1397.0 / 180 / 24
+}
+
+// chineseMidnight returns the Julian Day, UT, of the start of day jd in
+// China
+func chineseMidnight(jd int) float64 {
+	return float64(jd) - 0.5 - chineseOffset(jd)
+}
+
+// chineseDay returns the day number of the day in China of the moment t,
+// a Julian Day in UT
+func chineseDay(t float64) int {
+	jd := int(math.Floor(t + 0.5))
+	return int(math.Floor(t + 0.5 + chineseOffset(jd)))
+}
+
+// estimatePriorSolarLongitude estimates the last moment before t, both
+// Julian Days in UT, at which the sun was at longitude lambda
+func estimatePriorSolarLongitude(lambda, t float64) float64 {
+	rate := meanTropicalYear / 360
+	tau := t - rate*math.Mod(solarLongitude(t)-lambda+360, 360)
+	delta := math.Mod(solarLongitude(tau)-lambda+540, 360) - 180
+	return math.Min(t, tau-rate*delta)
+}
+
+// solarLongitude returns the apparent longitude of the sun in degrees at
+// t, a Julian Day in UT, using the series of Reingold and Dershowitz
+func solarLongitude(t float64) float64 {
+	c := (t + deltaT(t) - j2000) / 36525
+	var sum float64
+	for _, term := range solarLongitudeTerms {
+		sum += term[0] * sinDeg(term[1]+term[2]*c)
+	}
+	lambda := 282.7771834 + 36000.76953744*c + 0.000005729577951308232*sum
+	aberration := 0.0000974*cosDeg(177.63+35999.01848*c) - 0.005575
+	nutation := -0.004778*sinDeg(124.90-1934.134*c+0.002063*c*c) - 0.0003667*sinDeg(201.11+72001.5377*c+0.00057*c*c)
+	return math.Mod(math.Mod(lambda+aberration+nutation, 360)+360, 360)
+}
+
+// solarLongitudeTerms are the periodic terms of solarLongitude: amplitude,
+// phase in degrees and rate in degrees per Julian century
+var solarLongitudeTerms = [][3]float64{
+	{403406, 270.54861, 0.9287892}, {195207, 340.19128, 35999.1376958},
+	{119433, 63.91854, 35999.4089666}, {112392, 331.26220, 35998.7287385},
+	{3891, 317.843, 71998.20261}, {2819, 86.631, 71998.4403},
+	{1721, 240.052, 36000.35726}, {660, 310.26, 71997.4812},
+	{350, 247.23, 32964.4678}, {334, 260.87, -19.4410},
+	{314, 297.82, 445267.1117}, {268, 343.14, 45036.8840},
+	{242, 166.79, 3.1008}, {234, 81.53, 22518.4434},
+	{158, 3.50, -19.9739}, {132, 132.75, 65928.9345},
+	{129, 182.95, 9038.0293}, {114, 162.03, 3034.7684},
+	{99, 29.8, 33718.148}, {93, 266.4, 3034.448},
+	{86, 249.2, -2280.773}, {78, 157.6, 29929.992},
+	{72, 257.8, 31556.493}, {68, 185.1, 149.588},
+	{64, 69.9, 9037.750}, {46, 8.0, 107997.405},
+	{38, 197.1, -4444.176}, {37, 250.4, 151.771},
+	{32, 65.3, 67555.316}, {29, 162.7, 31556.080},
+	{28, 341.5, -4561.540}, {27, 291.6, 107996.706},
+	{27, 98.5, 1221.655}, {25, 146.7, 62894.167},
+	{24, 110.0, 31437.369}, {21, 5.2, 14578.298},
+	{21, 342.6, -31931.757}, {20, 230.9, 34777.243},
+	{18, 256.1, 1221.999}, {17, 45.3, 62894.511},
+	{14, 242.9, -4442.039}, {13, 115.2, 107997.909},
+	{13, 151.8, 119.066}, {13, 285.3, 16859.071},
+	{12, 53.3, -4.578}, {10, 126.6, 26895.292},
+	{10, 205.7, -39.127}, {10, 85.9, 12297.536},
+	{10, 146.1, 90073.778},
+}
+
+// newMoonAtOrAfter returns the first new moon at or after t, both Julian
+// Days in UT
+func newMoonAtOrAfter(t float64) float64 {
+	k := math.Floor((t-2451550.09766)/meanSynodicMonth) - 1
+	for newMoon(k) < t {
+		k++
+	}
+	return newMoon(k)
+}
+
+// newMoonBefore returns the last new moon before t, both Julian Days in UT
+func newMoonBefore(t float64) float64 {
+	k := math.Floor((t-2451550.09766)/meanSynodicMonth) + 1
+	for newMoon(k) >= t {
+		k--
+	}
+	return newMoon(k)
+}
+
+// newMoon returns the Julian Day, UT, of the new moon k lunations after
+// that of 6 January 2000, following Meeus, "Astronomical Algorithms",
+// chapter 49
+func newMoon(k float64) float64 {
+	t := k / 1236.85
+	jde := 2451550.09766 + meanSynodicMonth*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := 2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t
+	mm := 201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t
+	f := 160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t
+
+	jde += -0.40720*sinDeg(mm) +
+		0.17241*e*sinDeg(m) +
+		0.01608*sinDeg(2*mm) +
+		0.01039*sinDeg(2*f) +
+		0.00739*e*sinDeg(mm-m) -
+		0.00514*e*sinDeg(mm+m) +
+		0.00208*e*e*sinDeg(2*m) -
+		0.00111*sinDeg(mm-2*f) -
+		0.00057*sinDeg(mm+2*f) +
+		0.00056*e*sinDeg(2*mm+m) -
+		0.00042*sinDeg(3*mm) +
+		0.00042*e*sinDeg(m+2*f) +
+		0.00038*e*sinDeg(m-2*f) -
+		0.00024*e*sinDeg(2*mm-m) -
+		0.00017*sinDeg(omega) -
+		0.00007*sinDeg(mm+2*m) +
+		0.00004*sinDeg(2*mm-2*f) +
+		0.00004*sinDeg(3*m) +
+		0.00003*sinDeg(mm+m-2*f) +
+		0.00003*sinDeg(2*mm+2*f) -
+		0.00003*sinDeg(mm+m+2*f) +
+		0.00003*sinDeg(mm-m+2*f) -
+		0.00002*sinDeg(mm-m-2*f) -
+		0.00002*sinDeg(3*mm+m) +
+		0.00002*sinDeg(4*mm)
+
+	// Planetary arguments
+	jde += 0.000325 * sinDeg(299.77+0.107408*k-0.009173*t*t)
+	for _, term := range [][3]float64{
+		{0.000165, 251.88, 0.016321},
+		{0.000164, 251.83, 26.651886}, {0.000126, 349.42, 36.412478},
+		{0.000110, 84.66, 18.206239}, {0.000062, 141.74, 53.303771},
+		{0.000060, 207.14, 2.453732}, {0.000056, 154.84, 7.306860},
+		{0.000047, 34.52, 27.261239}, {0.000042, 207.19, 0.121824},
+		{0.000040, 291.34, 1.844379}, {0.000037, 161.72, 24.198154},
+		{0.000035, 239.56, 25.513099}, {0.000023, 331.55, 3.592518},
+	} {
+		jde += term[0] * sinDeg(term[1]+term[2]*k)
+	}
+	return jde - deltaT(jde)
+}
+
+// deltaT estimates TT - UT in days at t, a Julian Day, with the
+// polynomials of Espenak and Meeus
+func deltaT(t float64) float64 {
+	y := 2000 + (t-j2000)/meanTropicalYear
+	var seconds float64
+	switch {
+	case y >= 1986 && y < 2005:
+		u := y - 2000
+		seconds = 63.86 + 0.3345*u - 0.060374*u*u + 0.0017275*u*u*u + 0.000651814*u*u*u*u + 0.00002373599*u*u*u*u*u
+	case y >= 2005 && y < 2050:
+		u := y - 2000
+		seconds = 62.92 + 0.32217*u + 0.005589*u*u
+	case y >= 1961 && y < 1986:
+		u := y - 1975
+		seconds = 45.45 + 1.067*u - u*u/260 - u*u*u/718
+	case y >= 1941 && y < 1961:
+		u := y - 1950
+		seconds = 29.07 + 0.407*u - u*u/233 + u*u*u/2547
+	case y >= 1920 && y < 1941:
+		u := y - 1920
+		seconds = 21.20 + 0.84493*u - 0.076100*u*u + 0.0020936*u*u*u
+	default:
+		u := (y - 1820) / 100
+		seconds = -20 + 32*u*u
+	}
+	return seconds / 86400
+}
+
+func sinDeg(degrees float64) float64 { return math.Sin(degrees * math.Pi / 180) }
+
+func cosDeg(degrees float64) float64 { return math.Cos(degrees * math.Pi / 180) }
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChineseCalendar(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		// New Year
+		{"2020-01-25", "day 1 of month 1, Year of the Rat"},
+		{"2023-01-22", "day 1 of month 1, Year of the Rabbit"},
+		{"2024-02-10", "day 1 of month 1, Year of the Dragon"},
+		{"2025-01-29", "day 1 of month 1, Year of the Snake"},
+		{"2026-02-17", "day 1 of month 1, Year of the Horse"},
+		// The end of a year falls in the Gregorian year after it started
+		{"2026-02-16", "day 29 of month 12, Year of the Snake"},
+		// Leap months
+		{"2020-05-23", "day 1 of leap month 4, Year of the Rat"},
+		{"2023-03-22", "day 1 of leap month 2, Year of the Rabbit"},
+		{"2025-07-25", "day 1 of leap month 6, Year of the Snake"},
+		{"2025-08-23", "day 1 of month 7, Year of the Snake"},
+		// The month after a leap month keeps its number
+		{"2023-04-20", "day 1 of month 3, Year of the Rabbit"},
+		// A leap 11th month, found by the astronomical rules alone
+		{"2033-12-22", "day 1 of leap month 11, Year of the Ox"},
+		// Mid-Autumn Festival
+		{"2026-09-25", "day 15 of month 8, Year of the Horse"},
+	}
+
+	for _, tt := range tests {
+		d, _ := time.Parse("2006-01-02", tt.date)
+		if got := (chineseCalendar{}).FormatDate(d); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestSolarLongitude(t *testing.T) {
+	// The December solstice of 2024 was at 09:20 UTC
+	jd := float64(time.Date(2024, 12, 21, 9, 20, 0, 0, time.UTC).Unix())/86400 + 2440587.5
+	if got := solarLongitude(jd); got < 269.99 || got > 270.01 {
+		t.Errorf("expected 270°, got %f", got)
+	}
+}
+
+func TestNewMoon(t *testing.T) {
+	// The new moon of 29 January 2025 was at 12:36 UTC
+	jd := float64(time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC).Unix())/86400 + 2440587.5
+	got := time.Unix(int64((newMoonAtOrAfter(jd)-2440587.5)*86400), 0).UTC()
+	if want := time.Date(2025, 1, 29, 12, 36, 0, 0, time.UTC); got.Sub(want).Abs() > 2*time.Minute {
+		t.Errorf("expected about %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// topCategories is how many meeting categories a comparison reports
+const topCategories = 5
+
+// categoryHours is the meeting time of one category in a period, see
+// categoryOf
+type categoryHours struct {
+	Name     string  `json:"name"`
+	Meetings int     `json:"meetings"`
+	Hours    float64 `json:"hours"`
+}
+
+type periodStats struct {
+	StartDate  string          `json:"startDate"`
+	EndDate    string          `json:"endDate"`
+	Meetings   int             `json:"meetings"`
+	Hours      float64         `json:"hours"`
+	Categories []categoryHours `json:"categories"`
+}
+
+type categoryDelta struct {
+	Name          string  `json:"name"`
+	Hours         float64 `json:"hours"`
+	BaselineHours float64 `json:"baselineHours"`
+	HoursDelta    float64 `json:"hoursDelta"`
+}
+
+type periodComparison struct {
+	Period        periodStats `json:"period"`
+	Baseline      periodStats `json:"baseline"`
+	MeetingsDelta int         `json:"meetingsDelta"`
+	HoursDelta    float64     `json:"hoursDelta"`
+	// HoursChangePercent is omitted when the baseline had no meetings
+	HoursChangePercent *float64        `json:"hoursChangePercent,omitempty"`
+	Categories         []categoryDelta `json:"categories"`
+}
+
+// summarizePeriod totals the timed events that take the user's time,
+// counting events present in several calendars once. Categories are named
+// by category and sorted by hours, most first.
+func summarizePeriod(events []gcal.CalendarEvent, startDate, endDate string, category func(gcal.CalendarEvent) string) periodStats {
+	stats := periodStats{StartDate: startDate, EndDate: endDate, Categories: []categoryHours{}}
+	byName := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, e := range events {
+		if !blocksTime(e) || seen[e.ID] {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil || !end.After(start) {
+			continue
+		}
+		seen[e.ID] = true
+
+		hours := end.Sub(start).Hours()
+		stats.Meetings++
+		stats.Hours += hours
+
+		name := category(e)
+		key := strings.ToLower(name)
+		i, ok := byName[key]
+		if !ok {
+			i = len(stats.Categories)
+			byName[key] = i
+			stats.Categories = append(stats.Categories, categoryHours{Name: name})
+		}
+		stats.Categories[i].Meetings++
+		stats.Categories[i].Hours += hours
+	}
+
+	stats.Hours = roundHours(stats.Hours)
+	for i := range stats.Categories {
+		stats.Categories[i].Hours = roundHours(stats.Categories[i].Hours)
+	}
+	sort.SliceStable(stats.Categories, func(i, j int) bool { return stats.Categories[i].Hours > stats.Categories[j].Hours })
+	return stats
+}
+
+func comparePeriods(period, baseline periodStats) periodComparison {
+	c := periodComparison{
+		Period:        period,
+		Baseline:      baseline,
+		MeetingsDelta: period.Meetings - baseline.Meetings,
+		HoursDelta:    roundDelta(period.Hours - baseline.Hours),
+		Categories:    []categoryDelta{},
+	}
+	if baseline.Hours > 0 {
+		pct := math.Round((period.Hours - baseline.Hours) / baseline.Hours * 100)
+		c.HoursChangePercent = &pct
+	}
+
+	// Rank categories by their time in both periods together
+	index := make(map[string]int)
+	add := func(categories []categoryHours, baseline bool) {
+		for _, cat := range categories {
+			key := strings.ToLower(cat.Name)
+			i, ok := index[key]
+			if !ok {
+				i = len(c.Categories)
+				index[key] = i
+				c.Categories = append(c.Categories, categoryDelta{Name: cat.Name})
+			}
+			if baseline {
+				c.Categories[i].BaselineHours = cat.Hours
+			} else {
+				c.Categories[i].Hours = cat.Hours
+			}
+		}
+	}
+	add(period.Categories, false)
+	add(baseline.Categories, true)
+	for i := range c.Categories {
+		c.Categories[i].HoursDelta = roundDelta(c.Categories[i].Hours - c.Categories[i].BaselineHours)
+	}
+	sort.SliceStable(c.Categories, func(i, j int) bool {
+		return c.Categories[i].Hours+c.Categories[i].BaselineHours > c.Categories[j].Hours+c.Categories[j].BaselineHours
+	})
+	if len(c.Categories) > topCategories {
+		c.Categories = c.Categories[:topCategories]
+	}
+	return c
+}
+
+// comparePeriodsInput is the arguments of compare_periods
+type comparePeriodsInput struct {
+	// First day of the period in YYYY-MM-DD format (default: Monday of this
+	// week)
+	StartDate string `json:"start_date"`
+	// Last day of the period in YYYY-MM-DD format (default: 6 days after
+	// start_date, max range: 90 days)
+	EndDate string `json:"end_date"`
+	// First day of the range to compare with (default: the same number of
+	// days right before the period)
+	CompareStartDate string `json:"compare_start_date"`
+	// Last day of the range to compare with; required with
+	// compare_start_date
+	CompareEndDate string `json:"compare_end_date"`
+	calendarArg
+}
+
+func (s *Server) callComparePeriods(ctx context.Context, input comparePeriodsInput) (periodComparison, error) {
+	for _, date := range []*string{&input.StartDate, &input.EndDate, &input.CompareStartDate, &input.CompareEndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return periodComparison{}, badArgument(err)
+		}
+	}
+	if (input.CompareStartDate == "") != (input.CompareEndDate == "") {
+		return periodComparison{}, badArgumentf("compare_start_date and compare_end_date must be given together")
+	}
+
+	// Default to this week, Monday to Sunday
+	if input.StartDate == "" {
+		input.StartDate = weekStart(time.Now().In(s.settings().location)).Format("2006-01-02")
+	}
+	start, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		return periodComparison{}, badArgument(err)
+	}
+	if input.EndDate == "" {
+		input.EndDate = start.AddDate(0, 0, 6).Format("2006-01-02")
+	}
+	if err := checkRange(input.StartDate, input.EndDate, maxAnalysisDays); err != nil {
+		return periodComparison{}, err
+	}
+
+	// The baseline defaults to as many days right before the period
+	if input.CompareStartDate == "" {
+		end, _ := time.Parse("2006-01-02", input.EndDate)
+		days := int(end.Sub(start).Hours()/24) + 1
+		input.CompareStartDate = start.AddDate(0, 0, -days).Format("2006-01-02")
+		input.CompareEndDate = start.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if err := checkRange(input.CompareStartDate, input.CompareEndDate, maxAnalysisDays); err != nil {
+		return periodComparison{}, err
+	}
+
+	period, err := s.listCalendarRange(ctx, input.Calendar, input.StartDate, input.EndDate)
+	if err != nil {
+		return periodComparison{}, err
+	}
+	baseline, err := s.listCalendarRange(ctx, input.Calendar, input.CompareStartDate, input.CompareEndDate)
+	if err != nil {
+		return periodComparison{}, err
+	}
+
+	c := comparePeriods(
+		summarizePeriod(period, input.StartDate, input.EndDate, s.categoryOf),
+		summarizePeriod(baseline, input.CompareStartDate, input.CompareEndDate, s.categoryOf),
+	)
+	return c, nil
+}
+
+func (c periodComparison) toolText(s *Server) string { return s.formatComparison(c) }
+
+func (s *Server) formatComparison(c periodComparison) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s to %s compared with %s to %s\n\n", c.Period.StartDate, c.Period.EndDate, c.Baseline.StartDate, c.Baseline.EndDate)
+	fmt.Fprintf(&b, "Meetings: %d vs %d (%s)\n", c.Period.Meetings, c.Baseline.Meetings, signed(float64(c.MeetingsDelta)))
+	fmt.Fprintf(&b, "Hours in meetings: %s vs %s (%s", formatHours(c.Period.Hours), formatHours(c.Baseline.Hours), signed(c.HoursDelta))
+	if c.HoursChangePercent != nil {
+		fmt.Fprintf(&b, ", %s%%", signed(*c.HoursChangePercent))
+	}
+	b.WriteString(")\n")
+
+	if len(c.Categories) > 0 {
+		b.WriteString("\nTop categories (hours):\n")
+		for _, cat := range c.Categories {
+			fmt.Fprintf(&b, "- %s: %s vs %s (%s)\n", s.sanitize(cat.Name), formatHours(cat.Hours), formatHours(cat.BaselineHours), signed(cat.HoursDelta))
+		}
+	}
+	return b.String()
+}
+
+// roundDelta rounds a change to a tenth, symmetrically around zero
+func roundDelta(v float64) float64 {
+	r := math.Round(v*10) / 10
+	if r == 0 {
+		// Avoid printing -0
+		return 0
+	}
+	return r
+}
+
+// signed formats a change with an explicit sign
+func signed(v float64) string {
+	v = roundDelta(v)
+	if v > 0 {
+		return "+" + strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
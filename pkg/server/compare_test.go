@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestSummarizePeriod(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		{ID: "1", Summary: "Standup", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T09:30:00Z"},
+		{ID: "2", Summary: "standup ", Start: "2026-03-17T09:00:00Z", End: "2026-03-17T09:30:00Z"},
+		{ID: "3", Summary: "Planning", Start: "2026-03-17T13:00:00Z", End: "2026-03-17T15:00:00Z"},
+		// The same event from a second calendar counts once
+		{ID: "3", Summary: "Planning", Start: "2026-03-17T13:00:00Z", End: "2026-03-17T15:00:00Z", CalendarID: "team"},
+		// Free, declined and all-day events don't take time
+		{ID: "4", Summary: "Optional talk", Start: "2026-03-18T13:00:00Z", End: "2026-03-18T14:00:00Z", Transparency: "transparent"},
+		{ID: "5", Summary: "Skipped", Start: "2026-03-18T15:00:00Z", End: "2026-03-18T16:00:00Z", Guests: []gcal.Guest{{Email: "me@example.com", ResponseStatus: "declined", Self: true}}},
+		{ID: "6", Summary: "Holiday", Start: "2026-03-19", End: "2026-03-20"},
+	}
+
+	s := newTestServer(&fakeCalendar{})
+	p := summarizePeriod(events, "2026-03-16", "2026-03-22", s.categoryOf)
+
+	if p.Meetings != 3 || p.Hours != 3 {
+		t.Errorf("expected 3 meetings and 3 hours, got %d and %v", p.Meetings, p.Hours)
+	}
+	if len(p.Categories) != 2 || p.Categories[0].Name != "Planning" || p.Categories[1].Name != "Standup" || p.Categories[1].Meetings != 2 || p.Categories[1].Hours != 1 {
+		t.Errorf("unexpected categories %+v", p.Categories)
+	}
+}
+
+func TestComparePeriods(t *testing.T) {
+	period := periodStats{Meetings: 4, Hours: 6, Categories: []categoryHours{{Name: "Planning", Hours: 4}, {Name: "Standup", Hours: 2}}}
+	baseline := periodStats{Meetings: 5, Hours: 8, Categories: []categoryHours{{Name: "Interviews", Hours: 5}, {Name: "standup", Hours: 3}}}
+
+	c := comparePeriods(period, baseline)
+
+	if c.MeetingsDelta != -1 || c.HoursDelta != -2 || c.HoursChangePercent == nil || *c.HoursChangePercent != -25 {
+		t.Errorf("unexpected deltas %+v", c)
+	}
+	if len(c.Categories) != 3 || c.Categories[0].Name != "Standup" || c.Categories[0].HoursDelta != -1 || c.Categories[2].Name != "Planning" || c.Categories[2].BaselineHours != 0 {
+		t.Errorf("unexpected categories %+v", c.Categories)
+	}
+
+	if empty := comparePeriods(period, periodStats{}); empty.HoursChangePercent != nil {
+		t.Errorf("expected no percentage without a baseline, got %v", *empty.HoursChangePercent)
+	}
+}
+
+func TestCallComparePeriods(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Review", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:30:00Z"},
+	}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolComparePeriods, id: float64(1), args: json.RawMessage(`{"start_date":"2026-03-16","end_date":"2026-03-29"}`)})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	// The baseline is the two weeks before, fetched last
+	if fake.lastStart != "2026-03-02" || fake.lastEnd != "2026-03-15" {
+		t.Errorf("unexpected baseline %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"2026-03-16 to 2026-03-29 compared with 2026-03-02 to 2026-03-15", "Meetings: 1 vs 1 (0)", "- Review: 1.5 vs 1.5 (0)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	for _, args := range []string{`{"compare_start_date":"2026-03-01"}`, `{"start_date":"2026-03-16","end_date":"2026-03-01"}`} {
+		resp = s.callTool(context.Background(), &toolCall{name: toolComparePeriods, id: float64(2), args: json.RawMessage(args)})
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected invalid params, got %+v", args, resp.Error)
+		}
+	}
+}
+
+func TestSigned(t *testing.T) {
+	for v, want := range map[float64]string{2.5: "+2.5", -2.25: "-2.3", 0: "0", -0.04: "0"} {
+		if got := signed(v); got != want {
+			t.Errorf("signed(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// LoadEnv configures the server from the CALENDAR_* environment variables
+// the binary documents, leaving the defaults of New for those that aren't
+// set. It stops at the first invalid setting.
+func (s *Server) LoadEnv() error {
+	if suffix := os.Getenv("CALENDAR_SERVER_NAME_SUFFIX"); suffix != "" {
+		s.name = serverName + "-" + suffix
+	}
+	s.toolPrefix = os.Getenv("CALENDAR_TOOL_PREFIX")
+	switch order := os.Getenv("CALENDAR_DATE_ORDER"); order {
+	case "", dateOrderDMY, dateOrderMDY:
+		s.dateOrder = order
+	default:
+		return fmt.Errorf("invalid CALENDAR_DATE_ORDER %q: expected %s or %s", order, dateOrderDMY, dateOrderMDY)
+	}
+	if v := os.Getenv("CALENDAR_VERBOSITY"); v != "" {
+		verbosity, err := parseVerbosity(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_VERBOSITY: %w", err)
+		}
+		s.configure(func(settings *sessionSettings) { settings.verbosity = verbosity })
+	}
+	if v := os.Getenv("CALENDAR_POLL_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return fmt.Errorf("invalid CALENDAR_POLL_INTERVAL %q", v)
+		}
+		s.pollInterval = interval
+	}
+	switch v := os.Getenv("CALENDAR_RECONCILE_AT"); v {
+	case "":
+	case "off":
+		s.reconcileAt = ""
+	default:
+		if _, err := time.Parse("15:04", v); err != nil {
+			return fmt.Errorf("invalid CALENDAR_RECONCILE_AT %q: expected HH:MM or off", v)
+		}
+		s.reconcileAt = v
+	}
+	if v := os.Getenv("CALENDAR_MAX_FIELD_LENGTH"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid CALENDAR_MAX_FIELD_LENGTH %q", v)
+		}
+		s.maxFieldLength = n
+	}
+	if v := os.Getenv("CALENDAR_ALT_CALENDARS"); v != "" {
+		cals, err := parseAlternateCalendars(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_ALT_CALENDARS: %w", err)
+		}
+		s.altCalendars = cals
+	}
+	if loc, err := time.LoadLocation(os.Getenv("CALENDAR_TIMEZONE")); err == nil {
+		s.configure(func(settings *sessionSettings) { settings.location = loc })
+	}
+	if v := os.Getenv("CALENDAR_WORK_HOURS"); v != "" {
+		start, end, err := parseWorkHours(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_WORK_HOURS: %w", err)
+		}
+		s.workHours.start, s.workHours.end = start, end
+	}
+	if v := os.Getenv("CALENDAR_WORK_DAYS"); v != "" {
+		days, err := parseWorkDays(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_WORK_DAYS: %w", err)
+		}
+		s.workHours.days = days
+	}
+	if v := os.Getenv("CALENDAR_HOURLY_RATE"); v != "" {
+		rate, err := parseHourlyRate(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_HOURLY_RATE: %w", err)
+		}
+		s.hourlyRate = rate
+	}
+	if v := os.Getenv("CALENDAR_CATEGORIES"); v != "" {
+		categories, err := parseTaxonomy(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_CATEGORIES: %w", err)
+		}
+		s.categories = categories
+	}
+	if v := os.Getenv("CALENDAR_CATEGORY_BUDGETS"); v != "" {
+		if s.categories == nil {
+			return fmt.Errorf("CALENDAR_CATEGORY_BUDGETS needs CALENDAR_CATEGORIES")
+		}
+		budgets, err := parseCategoryBudgets(v, s.categories)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_CATEGORY_BUDGETS: %w", err)
+		}
+		s.budgets = budgets
+	}
+	if v := os.Getenv("CALENDAR_RAW_METHODS"); v != "" {
+		policy, err := parseRawPolicy(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_RAW_METHODS: %w", err)
+		}
+		s.rawPolicy = policy
+	}
+	if v := os.Getenv("CALENDAR_STATUS_MARKERS"); v != "" {
+		markers, err := parseStatusMarkers(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_STATUS_MARKERS: %w", err)
+		}
+		s.markers = markers
+	}
+	if v := os.Getenv("CALENDAR_WEBHOOKS"); v != "" {
+		triggers, err := parseWebhookTriggers(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_WEBHOOKS: %w", err)
+		}
+		s.webhooks = triggers
+	}
+	if v := os.Getenv("CALENDAR_RATE_LIMIT"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil || rate <= 0 {
+			return fmt.Errorf("invalid CALENDAR_RATE_LIMIT %q", v)
+		}
+		s.limiter = newCallLimiter(rate, int(math.Ceil(rate)))
+	}
+	if v := os.Getenv("CALENDAR_TOOL_TIMEOUTS"); v != "" {
+		timeouts, err := parseToolTimeouts(v)
+		if err != nil {
+			return fmt.Errorf("invalid CALENDAR_TOOL_TIMEOUTS: %w", err)
+		}
+		s.toolTimeouts = timeouts
+	}
+	if v := os.Getenv("CALENDAR_BREAKER_FAILURES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid CALENDAR_BREAKER_FAILURES %q", v)
+		}
+		s.breaker = nil
+		if n > 0 {
+			s.breaker = newCircuitBreaker(n, defaultBreakerCooldown)
+		}
+	}
+	if v := os.Getenv("CALENDAR_BREAKER_COOLDOWN"); v != "" {
+		cooldown, err := time.ParseDuration(v)
+		if err != nil || cooldown <= 0 {
+			return fmt.Errorf("invalid CALENDAR_BREAKER_COOLDOWN %q", v)
+		}
+		if s.breaker != nil {
+			s.breaker.cooldown = cooldown
+		}
+	}
+	if v := os.Getenv("CALENDAR_DAILY_EVENT_LIMIT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid CALENDAR_DAILY_EVENT_LIMIT %q", v)
+		}
+		s.usage = nil
+		if n > 0 {
+			s.usage = newCreationUsage(n)
+		}
+	}
+	if token, issuer := os.Getenv("CALENDAR_AUTH_TOKEN"), os.Getenv("CALENDAR_OIDC_ISSUER"); token != "" || issuer != "" {
+		s.auth = &httpAuth{token: token}
+		if issuer != "" {
+			oidc, err := newOIDCVerifier(issuer, os.Getenv("CALENDAR_OIDC_AUDIENCE"))
+			if err != nil {
+				return fmt.Errorf("CALENDAR_OIDC_ISSUER needs CALENDAR_OIDC_AUDIENCE: %w", err)
+			}
+			s.auth.oidc = oidc
+		}
+	}
+	s.readOnly.Store(os.Getenv("CALENDAR_READ_ONLY") == "true")
+	return nil
+}
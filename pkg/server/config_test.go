@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("CALENDAR_SERVER_NAME_SUFFIX", "work")
+	t.Setenv("CALENDAR_TOOL_PREFIX", "work_")
+	t.Setenv("CALENDAR_TIMEZONE", "Europe/Berlin")
+	t.Setenv("CALENDAR_POLL_INTERVAL", "30s")
+	t.Setenv("CALENDAR_RECONCILE_AT", "04:30")
+	t.Setenv("CALENDAR_CATEGORIES", "customer=acme")
+	t.Setenv("CALENDAR_CATEGORY_BUDGETS", "customer=5h")
+	t.Setenv("CALENDAR_RATE_LIMIT", "2")
+	t.Setenv("CALENDAR_READ_ONLY", "true")
+	t.Setenv("CALENDAR_TOOL_TIMEOUTS", "30s,find_conflicts=1m")
+	t.Setenv("CALENDAR_BREAKER_COOLDOWN", "1m")
+
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	if err := s.LoadEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if s.name != "google-calendar-work" || s.toolPrefix != "work_" || s.settings().location.String() != "Europe/Berlin" || s.pollInterval != 30*time.Second {
+		t.Errorf("unexpected configuration: %s %s %s %s", s.name, s.toolPrefix, s.settings().location, s.pollInterval)
+	}
+	if s.reconcileAt != "04:30" {
+		t.Errorf("unexpected reconciliation time %q", s.reconcileAt)
+	}
+	if s.budgets["customer"] != 5 || s.limiter == nil || !s.isReadOnly() {
+		t.Errorf("unexpected configuration: %v %v %t", s.budgets, s.limiter, s.isReadOnly())
+	}
+	if s.toolTimeouts.of(toolFindConflicts) != time.Minute || s.toolTimeouts.of(toolListEvents) != 30*time.Second || s.breaker.cooldown != time.Minute {
+		t.Errorf("unexpected configuration: %v %v", s.toolTimeouts, s.breaker.cooldown)
+	}
+
+	t.Setenv("CALENDAR_BREAKER_FAILURES", "0")
+	if err := s.LoadEnv(); err != nil || s.breaker != nil {
+		t.Errorf("expected 0 to turn the circuit breaker off, got %v, %v", s.breaker, err)
+	}
+	t.Setenv("CALENDAR_DAILY_EVENT_LIMIT", "0")
+	if err := s.LoadEnv(); err != nil || s.usage != nil {
+		t.Errorf("expected 0 to turn the usage warnings off, got %v, %v", s.usage, err)
+	}
+
+	t.Setenv("CALENDAR_RECONCILE_AT", "off")
+	if err := s.LoadEnv(); err != nil || s.reconcileAt != "" {
+		t.Errorf("expected off to turn the reconciliation off, got %q, %v", s.reconcileAt, err)
+	}
+}
+
+func TestLoadEnv_Invalid(t *testing.T) {
+	for name, value := range map[string]string{
+		"CALENDAR_DATE_ORDER":        "ymd",
+		"CALENDAR_POLL_INTERVAL":     "soon",
+		"CALENDAR_RECONCILE_AT":      "3am",
+		"CALENDAR_WORK_HOURS":        "9-5",
+		"CALENDAR_CATEGORY_BUDGETS":  "customer=5h",
+		"CALENDAR_RATE_LIMIT":        "-1",
+		"CALENDAR_TOOL_TIMEOUTS":     "list_event=10s",
+		"CALENDAR_BREAKER_FAILURES":  "many",
+		"CALENDAR_DAILY_EVENT_LIMIT": "lots",
+		"CALENDAR_OIDC_ISSUER":       "https://accounts.google.com",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+			err := New(&fakeCalendar{}, &bytes.Buffer{}).LoadEnv()
+			if err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("expected an error naming %s, got %v", name, err)
+			}
+		})
+	}
+}
+
+func TestSetFraming(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	if err := s.SetFraming(FramingContentLength); err != nil || s.framing != FramingContentLength {
+		t.Errorf("expected content-length framing, got %q, %v", s.framing, err)
+	}
+	if err := s.SetFraming("xml"); err == nil {
+		t.Error("expected an error for unknown framing")
+	}
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	defaultConflictDays = 7
+	maxConflictDays     = 90
+)
+
+// conflict is a pair of overlapping events that both claim the user's time
+type conflict struct {
+	First          gcal.CalendarEvent `json:"first"`
+	Second         gcal.CalendarEvent `json:"second"`
+	OverlapStart   string             `json:"overlapStart"`
+	OverlapEnd     string             `json:"overlapEnd"`
+	OverlapMinutes int                `json:"overlapMinutes"`
+	// Resolution suggests how to fix the conflict, when one of the events
+	// can be moved
+	Resolution *resolution `json:"resolution,omitempty"`
+}
+
+type dayConflicts struct {
+	Date      string     `json:"date"`
+	Conflicts []conflict `json:"conflicts"`
+}
+
+type conflictReport struct {
+	StartDate string         `json:"startDate"`
+	EndDate   string         `json:"endDate"`
+	Calendars []string       `json:"calendars"`
+	Days      []dayConflicts `json:"days"`
+	Total     int            `json:"total"`
+}
+
+type timedEvent struct {
+	gcal.CalendarEvent
+	start, end time.Time
+}
+
+// blocksTime reports whether an event occupies the user's time: it is not
+// marked as free and the user hasn't declined it
+func blocksTime(e gcal.CalendarEvent) bool {
+	if e.Transparency == "transparent" {
+		return false
+	}
+	for _, g := range e.Guests {
+		if g.Self && g.ResponseStatus == "declined" {
+			return false
+		}
+	}
+	return true
+}
+
+// findConflicts returns overlapping pairs of timed events grouped by the day
+// the overlap starts, in loc. Events present in several calendars are
+// counted once.
+func findConflicts(events []gcal.CalendarEvent, loc *time.Location) []dayConflicts {
+	seen := make(map[string]bool)
+	var timed []timedEvent
+	for _, e := range events {
+		if !blocksTime(e) || seen[e.ID] {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil || !end.After(start) {
+			continue
+		}
+		seen[e.ID] = true
+		timed = append(timed, timedEvent{CalendarEvent: e, start: start.In(loc), end: end.In(loc)})
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return timed[i].start.Before(timed[j].start) })
+
+	byDay := make(map[string][]conflict)
+	for i, a := range timed {
+		for _, b := range timed[i+1:] {
+			if !b.start.Before(a.end) {
+				// Sorted by start, so no later event overlaps a either
+				break
+			}
+			overlapStart, overlapEnd := b.start, minTime(a.end, b.end)
+			day := overlapStart.Format("2006-01-02")
+			byDay[day] = append(byDay[day], conflict{
+				First:          a.CalendarEvent,
+				Second:         b.CalendarEvent,
+				OverlapStart:   overlapStart.Format(time.RFC3339),
+				OverlapEnd:     overlapEnd.Format(time.RFC3339),
+				OverlapMinutes: int(overlapEnd.Sub(overlapStart).Minutes()),
+			})
+		}
+	}
+
+	days := make([]dayConflicts, 0, len(byDay))
+	for date, conflicts := range byDay {
+		days = append(days, dayConflicts{Date: date, Conflicts: conflicts})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
+
+// listAllCalendars fetches a date range from every configured calendar
+func (s *Server) listAllCalendars(ctx context.Context, startDate, endDate string) ([]gcal.CalendarEvent, error) {
+	var events []gcal.CalendarEvent
+	for _, id := range s.settings().calendar.Calendars() {
+		list, err := s.settings().calendar.ListCalendarEvents(ctx, id, startDate, endDate)
+		if err != nil {
+			return nil, fmt.Errorf("calendar %s: %w", id, err)
+		}
+		events = append(events, list...)
+	}
+	return events, nil
+}
+
+// findConflictsInput is the arguments of find_conflicts
+type findConflictsInput struct {
+	// Start date in YYYY-MM-DD format (default: today)
+	StartDate string `json:"start_date"`
+	// End date in YYYY-MM-DD format (default: 6 days after start_date, max
+	// range: 90 days)
+	EndDate string `json:"end_date"`
+}
+
+func (s *Server) callFindConflicts(ctx context.Context, input findConflictsInput) (conflictReport, error) {
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return conflictReport{}, badArgument(err)
+		}
+	}
+
+	today := time.Now().In(s.settings().location)
+	if input.StartDate == "" {
+		input.StartDate = today.Format("2006-01-02")
+	}
+	if input.EndDate == "" {
+		start, _ := time.Parse("2006-01-02", input.StartDate)
+		input.EndDate = start.AddDate(0, 0, defaultConflictDays-1).Format("2006-01-02")
+	}
+	if err := checkRange(input.StartDate, input.EndDate, maxConflictDays); err != nil {
+		return conflictReport{}, err
+	}
+
+	events, err := s.listAllCalendars(ctx, input.StartDate, input.EndDate)
+	if err != nil {
+		return conflictReport{}, err
+	}
+
+	report := conflictReport{
+		StartDate: input.StartDate,
+		EndDate:   input.EndDate,
+		Calendars: s.settings().calendar.Calendars(),
+		Days:      findConflicts(events, s.settings().location),
+	}
+	lastDay, _ := time.ParseInLocation("2006-01-02", input.EndDate, s.settings().location)
+	for _, d := range report.Days {
+		report.Total += len(d.Conflicts)
+		for i := range d.Conflicts {
+			d.Conflicts[i].Resolution = s.suggestResolution(ctx, d.Conflicts[i], events, time.Now(), lastDay)
+		}
+	}
+	return report, nil
+}
+
+// checkRange validates a date range given as YYYY-MM-DD dates, failing with
+// an argument error
+func checkRange(startDate, endDate string, maxDays int) error {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return badArgument(err)
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return badArgument(err)
+	}
+	if end.Before(start) {
+		return badArgumentf("end_date must not be before start_date")
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxDays {
+		return badArgumentf("range must be at most %d days", maxDays)
+	}
+	return nil
+}
+
+func (r conflictReport) toolText(s *Server) string { return s.formatConflicts(r) }
+
+func (s *Server) formatConflicts(r conflictReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Conflicts %s to %s across %d calendar(s)", r.StartDate, r.EndDate, len(r.Calendars))
+	if r.Total == 0 {
+		b.WriteString(": none found.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, ": %d found\n", r.Total)
+
+	for _, d := range r.Days {
+		fmt.Fprintf(&b, "\n%s:\n", d.Date)
+		for _, c := range d.Conflicts {
+			fmt.Fprintf(&b, "- %s overlaps %s by %s\n", s.conflictEvent(c.First), s.conflictEvent(c.Second), formatMinutes(c.OverlapMinutes))
+			b.WriteString(s.formatResolution(c.Resolution))
+		}
+	}
+	return b.String()
+}
+
+func (s *Server) conflictEvent(e gcal.CalendarEvent) string {
+	text := fmt.Sprintf("%q (%s-%s", s.sanitize(e.Summary), clockOf(e.Start, s.settings().location), clockOf(e.End, s.settings().location))
+	if e.CalendarID != "" && e.CalendarID != s.settings().calendar.CalendarID() {
+		text += ", " + e.CalendarID
+	}
+	return text + ", ID " + e.ID + ")"
+}
+
+// clockOf returns the HH:MM part of an RFC 3339 timestamp in loc
+func clockOf(timestamp string, loc *time.Location) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.In(loc).Format("15:04")
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"github.com/cherya/google-calendar-mcp/pkg/gcal/calendartest"
+)
+
+func TestFindConflicts(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		{ID: "a", Summary: "Standup", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+		{ID: "b", Summary: "Dentist", CalendarID: "personal", Start: "2026-03-16T10:30:00Z", End: "2026-03-16T11:30:00Z"},
+		{ID: "c", Summary: "Lunch", Start: "2026-03-16T12:00:00Z", End: "2026-03-16T13:00:00Z"},
+		// Free and declined events don't conflict
+		{ID: "d", Summary: "Focus", Transparency: "transparent", Start: "2026-03-16T12:00:00Z", End: "2026-03-16T13:00:00Z"},
+		{ID: "e", Summary: "Skipped", Start: "2026-03-16T12:30:00Z", End: "2026-03-16T13:00:00Z",
+			Guests: []gcal.Guest{{Email: "me@example.com", Self: true, ResponseStatus: "declined"}}},
+		// The same event seen through two calendars is one event
+		{ID: "c", Summary: "Lunch", CalendarID: "personal", Start: "2026-03-16T12:00:00Z", End: "2026-03-16T13:00:00Z"},
+		{ID: "f", Summary: "Offsite", Start: "2026-03-17", End: "2026-03-18"},
+		{ID: "g", Summary: "Late call", Start: "2026-03-17T23:30:00Z", End: "2026-03-18T01:00:00Z"},
+		{ID: "h", Summary: "Early call", Start: "2026-03-18T00:00:00Z", End: "2026-03-18T00:30:00Z"},
+	}
+
+	days := findConflicts(events, time.UTC)
+
+	if len(days) != 2 {
+		t.Fatalf("expected conflicts on two days, got %+v", days)
+	}
+	first := days[0]
+	if first.Date != "2026-03-16" || len(first.Conflicts) != 1 {
+		t.Fatalf("unexpected first day %+v", first)
+	}
+	c := first.Conflicts[0]
+	if c.First.ID != "a" || c.Second.ID != "b" || c.OverlapMinutes != 30 {
+		t.Errorf("unexpected conflict %+v", c)
+	}
+	// Overlaps are grouped by the day they start
+	if days[1].Date != "2026-03-18" || days[1].Conflicts[0].First.ID != "g" {
+		t.Errorf("unexpected second day %+v", days[1])
+	}
+}
+
+func TestCallFindConflicts_AllCalendars(t *testing.T) {
+	fake := &fakeCalendar{
+		events: []gcal.CalendarEvent{{ID: "a", Summary: "Standup", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"}},
+		extraCalendars: map[string][]gcal.CalendarEvent{
+			"personal@example.com": {{ID: "b", Summary: "Dentist", CalendarID: "personal@example.com", Start: "2026-03-16T10:30:00Z", End: "2026-03-16T11:30:00Z"}},
+		},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-03-16"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolFindConflicts, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastEnd != "2026-03-22" {
+		t.Errorf("expected a 7-day default range, got end %s", fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "across 2 calendar(s): 1 found") || !strings.Contains(text, "personal@example.com") {
+		t.Errorf("unexpected report:\n%s", text)
+	}
+
+	args, _ = json.Marshal(map[string]string{"start_date": "2026-03-16", "end_date": "2026-03-01"})
+	resp = s.callTool(context.Background(), &toolCall{name: toolFindConflicts, id: float64(2), args: args})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for a reversed range, got %+v", resp.Error)
+	}
+}
+
+func TestCallFindConflicts_RecurringSeries(t *testing.T) {
+	cal, err := calendartest.New(calendartest.Fixture{
+		Calendars: []gcal.CalendarInfo{{ID: "personal@example.com", AccessRole: "owner"}},
+		Events: []calendartest.Event{
+			{ID: "standup", Summary: "Standup", Start: "2026-03-09T10:00:00Z", End: "2026-03-09T10:30:00Z", Recurrence: []string{"RRULE:FREQ=DAILY;COUNT=10"}},
+			{ID: "gym", Calendar: "personal@example.com", Summary: "Gym", Start: "2026-03-17T10:15:00Z", End: "2026-03-17T11:00:00Z"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := New(cal, &bytes.Buffer{})
+	s.state = stateReady
+	s.setProtocolVersion(protocolVersion20250618)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-03-16", "end_date": "2026-03-20"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolFindConflicts, id: float64(1), args: args})
+	report := resp.Result.(map[string]interface{})["structuredContent"].(conflictReport)
+	if len(report.Days) != 1 || report.Days[0].Date != "2026-03-17" || report.Days[0].Conflicts[0].First.ID != "standup_20260317T100000Z" {
+		t.Errorf("expected the standup of the 17th to conflict with the gym, got %+v", report.Days)
+	}
+}
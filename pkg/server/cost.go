@@ -0,0 +1,136 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// costliestMeetingsLimit caps how many meetings analyze_time lists as the
+// most expensive
+const costliestMeetingsLimit = 3
+
+// hourlyRate is the configured cost of one person-hour
+type hourlyRate struct {
+	amount   float64
+	currency string
+}
+
+// parseHourlyRate parses an amount with an optional currency, e.g. 75 or
+// "75 EUR"
+func parseHourlyRate(spec string) (*hourlyRate, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("expected an amount and optional currency, got %q", spec)
+	}
+	amount, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("invalid amount %q", fields[0])
+	}
+	rate := &hourlyRate{amount: amount}
+	if len(fields) == 2 {
+		rate.currency = fields[1]
+	}
+	return rate, nil
+}
+
+func (r *hourlyRate) format(personHours float64) string {
+	cost := strconv.FormatFloat(personHours*r.amount, 'f', 0, 64)
+	if r.currency == "" {
+		return cost
+	}
+	return cost + " " + r.currency
+}
+
+// meetingCost is the aggregated cost of meetings sharing a title
+type meetingCost struct {
+	Summary       string   `json:"summary"`
+	Occurrences   int      `json:"occurrences"`
+	PersonHours   float64  `json:"personHours"`
+	EstimatedCost *float64 `json:"estimatedCost,omitempty"`
+}
+
+// personHours returns the time an event takes from everyone invited. Events
+// without an attendee list only take the owner's time.
+func personHours(e gcal.CalendarEvent) (float64, bool) {
+	start, err := time.Parse(time.RFC3339, e.Start)
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(time.RFC3339, e.End)
+	if err != nil || !end.After(start) {
+		return 0, false
+	}
+	return float64(max(e.Attendees, 1)) * end.Sub(start).Hours(), true
+}
+
+// meetingCosts totals person-hours and groups meetings with more than one
+// attendee by title, most expensive first
+func meetingCosts(events []gcal.CalendarEvent, rate *hourlyRate) (float64, []meetingCost) {
+	total := 0.0
+	byTitle := make(map[string]*meetingCost)
+	for _, e := range events {
+		hours, ok := personHours(e)
+		if !ok {
+			continue
+		}
+		total += hours
+		if e.Attendees < 2 {
+			continue
+		}
+		c := byTitle[e.Summary]
+		if c == nil {
+			c = &meetingCost{Summary: e.Summary}
+			byTitle[e.Summary] = c
+		}
+		c.Occurrences++
+		c.PersonHours += hours
+	}
+
+	costs := make([]meetingCost, 0, len(byTitle))
+	for _, c := range byTitle {
+		c.PersonHours = roundHours(c.PersonHours)
+		if rate != nil {
+			cost := c.PersonHours * rate.amount
+			c.EstimatedCost = &cost
+		}
+		costs = append(costs, *c)
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].PersonHours != costs[j].PersonHours {
+			return costs[i].PersonHours > costs[j].PersonHours
+		}
+		return costs[i].Summary < costs[j].Summary
+	})
+	return roundHours(total), costs
+}
+
+// eventCost renders the cost line shown under meetings with several
+// attendees
+func (s *Server) eventCost(e gcal.CalendarEvent) string {
+	if e.Attendees < 2 {
+		return ""
+	}
+	hours, ok := personHours(e)
+	if !ok {
+		return ""
+	}
+
+	line := fmt.Sprintf("  Cost: %d attendees, %s person-hours", e.Attendees, formatHours(hours))
+	if s.hourlyRate != nil {
+		line += " (~" + s.hourlyRate.format(hours) + ")"
+	}
+	return line + "\n"
+}
+
+func roundHours(h float64) float64 {
+	return float64(int(h*10+0.5)) / 10
+}
+
+func formatHours(h float64) string {
+	return strconv.FormatFloat(roundHours(h), 'f', -1, 64)
+}
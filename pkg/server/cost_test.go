@@ -0,0 +1,68 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestParseHourlyRate(t *testing.T) {
+	rate, err := parseHourlyRate("75 EUR")
+	if err != nil || rate.amount != 75 || rate.currency != "EUR" {
+		t.Errorf("unexpected rate %+v, %v", rate, err)
+	}
+	if got := rate.format(2.5); got != "188 EUR" {
+		t.Errorf("expected 188 EUR, got %s", got)
+	}
+
+	for _, bad := range []string{"", "-5", "lots", "75 EUR extra"} {
+		if _, err := parseHourlyRate(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestMeetingCosts(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		{Summary: "Weekly sync", Attendees: 6, Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+		{Summary: "Weekly sync", Attendees: 6, Start: "2026-03-23T10:00:00Z", End: "2026-03-23T11:00:00Z"},
+		{Summary: "1:1", Attendees: 2, Start: "2026-03-17T10:00:00Z", End: "2026-03-17T10:30:00Z"},
+		// Solo time counts towards the total but isn't a meeting to cut
+		{Summary: "Focus", Start: "2026-03-18T09:00:00Z", End: "2026-03-18T11:00:00Z"},
+		{Summary: "Holiday", Attendees: 10, Start: "2026-03-19", End: "2026-03-20"},
+	}
+
+	total, costs := meetingCosts(events, &hourlyRate{amount: 100})
+
+	if total != 15 {
+		t.Errorf("expected 15 person-hours, got %v", total)
+	}
+	if len(costs) != 2 || costs[0].Summary != "Weekly sync" || costs[0].Occurrences != 2 || costs[0].PersonHours != 12 {
+		t.Fatalf("unexpected costs %+v", costs)
+	}
+	if costs[0].EstimatedCost == nil || *costs[0].EstimatedCost != 1200 {
+		t.Errorf("expected estimated cost 1200, got %v", costs[0].EstimatedCost)
+	}
+}
+
+func TestFormatEvents_MeetingCost(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	events := []gcal.CalendarEvent{
+		{ID: "1", Summary: "Planning", Attendees: 4, Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:30:00Z"},
+		{ID: "2", Summary: "Solo", Start: "2026-03-16T12:00:00Z", End: "2026-03-16T13:00:00Z"},
+	}
+
+	text := s.formatEvents(events)
+	if !strings.Contains(text, "Cost: 4 attendees, 6 person-hours\n") {
+		t.Errorf("expected cost line without a rate, got:\n%s", text)
+	}
+	if strings.Count(text, "Cost:") != 1 {
+		t.Errorf("events without other attendees should have no cost line:\n%s", text)
+	}
+
+	s.hourlyRate = &hourlyRate{amount: 50, currency: "USD"}
+	if text := s.formatEvents(events); !strings.Contains(text, "6 person-hours (~300 USD)") {
+		t.Errorf("expected cost estimate, got:\n%s", text)
+	}
+}
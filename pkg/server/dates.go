@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Day/month order used to read DD/MM/YYYY style dates
+const (
+	dateOrderDMY = "dmy"
+	dateOrderMDY = "mdy"
+)
+
+var (
+	isoDatePattern    = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	dottedDatePattern = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})\.(\d{4})$`)
+	slashDatePattern  = regexp.MustCompile(`^(\d{1,2})/(\d{1,2})/(\d{4})$`)
+)
+
+// normalizeDate converts a date argument to YYYY-MM-DD. Besides ISO dates
+// it accepts DD.MM.YYYY, which is always day-first, and slash dates, whose
+// order comes from order (dateOrderDMY or dateOrderMDY). Without a
+// configured order a slash date is only accepted when it is unambiguous,
+// i.e. one of the first two parts is greater than 12.
+func normalizeDate(input, order string) (string, error) {
+	if isoDatePattern.MatchString(input) {
+		return validDate(input, 0, 0, 0)
+	}
+
+	if m := dottedDatePattern.FindStringSubmatch(input); m != nil {
+		return validDate(input, atoi(m[3]), atoi(m[2]), atoi(m[1]))
+	}
+
+	if m := slashDatePattern.FindStringSubmatch(input); m != nil {
+		first, second, year := atoi(m[1]), atoi(m[2]), atoi(m[3])
+
+		switch {
+		case order == dateOrderDMY:
+			return validDate(input, year, second, first)
+		case order == dateOrderMDY:
+			return validDate(input, year, first, second)
+		case first > 12 && second <= 12:
+			return validDate(input, year, second, first)
+		case second > 12 && first <= 12:
+			return validDate(input, year, first, second)
+		case first == second:
+			return validDate(input, year, first, second)
+		default:
+			return "", fmt.Errorf("ambiguous date %q: could be DD/MM/YYYY or MM/DD/YYYY; use YYYY-MM-DD or DD.MM.YYYY", input)
+		}
+	}
+
+	return "", fmt.Errorf("invalid date %q: expected YYYY-MM-DD, DD.MM.YYYY or DD/MM/YYYY", input)
+}
+
+// validDate checks that the date exists and formats it as YYYY-MM-DD. A
+// zero year means input is already in ISO form.
+func validDate(input string, year, month, day int) (string, error) {
+	iso := input
+	if year != 0 {
+		iso = fmt.Sprintf("%04d-%02d-%02d", year, month, day)
+	}
+
+	if _, err := time.Parse("2006-01-02", iso); err != nil {
+		return "", fmt.Errorf("invalid date %q: no such day", input)
+	}
+	return iso, nil
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// normalizeDateArg normalizes an optional date argument in place
+func (s *Server) normalizeDateArg(date *string) error {
+	if date == nil || *date == "" {
+		return nil
+	}
+	normalized, err := normalizeDate(*date, s.dateOrder)
+	if err != nil {
+		return err
+	}
+	*date = normalized
+	return nil
+}
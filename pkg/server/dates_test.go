@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestNormalizeDate(t *testing.T) {
+	tests := []struct {
+		input   string
+		order   string
+		want    string
+		wantErr bool
+	}{
+		{"2026-03-05", "", "2026-03-05", false},
+		{"05.03.2026", "", "2026-03-05", false},
+		{"5.3.2026", dateOrderMDY, "2026-03-05", false},
+		{"05/03/2026", dateOrderDMY, "2026-03-05", false},
+		{"05/03/2026", dateOrderMDY, "2026-05-03", false},
+		{"25/03/2026", "", "2026-03-25", false},
+		{"03/25/2026", "", "2026-03-25", false},
+		{"04/04/2026", "", "2026-04-04", false},
+		{"05/03/2026", "", "", true},
+		{"31.02.2026", "", "", true},
+		{"2026-13-01", "", "", true},
+		{"March 5", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeDate(tt.input, tt.order)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("normalizeDate(%q, %q) error = %v, wantErr %v", tt.input, tt.order, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("normalizeDate(%q, %q) = %q, want %q", tt.input, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestCallListEventsRange_LocalizedDates(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	s.dateOrder = dateOrderDMY
+
+	args, _ := json.Marshal(map[string]string{"start_date": "01.03.2026", "end_date": "31/03/2026"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEventsRange, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastStart != "2026-03-01" || fake.lastEnd != "2026-03-31" {
+		t.Errorf("expected normalized dates, got %s - %s", fake.lastStart, fake.lastEnd)
+	}
+}
+
+func TestCallCreateEvent_AmbiguousDate(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{
+		"summary":    "Lunch",
+		"date":       "05/03/2026",
+		"start_time": "12:00",
+		"end_time":   "13:00",
+	})
+	resp := s.callTool(context.Background(), &toolCall{name: toolCreateEvent, id: float64(1), args: args})
+
+	if resp.Error == nil || !contains(resp.Error.Message, "ambiguous") {
+		t.Errorf("expected ambiguous date error, got %+v", resp.Error)
+	}
+}
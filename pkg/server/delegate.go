@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	defaultDelegatedDays = 7
+	// maxDelegatedDays stays within how far back the Calendar API accepts
+	// updatedMin
+	maxDelegatedDays = 28
+)
+
+// delegatedReport lists what the assistant did on the owner's calendar
+type delegatedReport struct {
+	Since   string                 `json:"since"`
+	Actions []gcal.DelegatedAction `json:"actions"`
+}
+
+// delegatedActionsInput is the arguments of delegated_actions
+type delegatedActionsInput struct {
+	// Number of past days to report (default: 7, max: 28)
+	Days int `json:"days" jsonschema:"default=defaultDelegatedDays,maximum=maxDelegatedDays"`
+}
+
+func (s *Server) callDelegatedActions(ctx context.Context, input delegatedActionsInput) (delegatedReport, error) {
+	if input.Days == 0 {
+		input.Days = defaultDelegatedDays
+	}
+	if input.Days < 0 || input.Days > maxDelegatedDays {
+		return delegatedReport{}, badArgumentf("days must be between 1 and %d", maxDelegatedDays)
+	}
+
+	since := time.Now().In(s.settings().location).AddDate(0, 0, -input.Days)
+	actions, err := s.settings().calendar.ListDelegatedActions(ctx, since)
+	if err != nil {
+		return delegatedReport{}, err
+	}
+
+	report := delegatedReport{Since: since.Format(time.RFC3339), Actions: actions}
+	return report, nil
+}
+
+func (r delegatedReport) toolText(s *Server) string { return s.formatDelegatedReport(r) }
+
+func (s *Server) formatDelegatedReport(r delegatedReport) string {
+	if len(r.Actions) == 0 {
+		return fmt.Sprintf("No changes made on behalf of the calendar owner since %s.", r.Since)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Changes made on behalf of the calendar owner since %s: %d\n\n", r.Since, len(r.Actions))
+	for _, a := range r.Actions {
+		fmt.Fprintf(&b, "- %s %s %s", a.At, a.Action, s.sanitize(a.Summary))
+		if a.Start != "" {
+			fmt.Fprintf(&b, " (%s)", a.Start)
+		}
+		fmt.Fprintf(&b, "\n  ID: %s\n", a.EventID)
+		if a.RequestID != "" {
+			info := gcal.RequestInfo{SessionID: a.SessionID, RequestID: a.RequestID, TraceID: a.TraceID}
+			fmt.Fprintf(&b, "  Made for: %s\n", info)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestCallDelegatedActions(t *testing.T) {
+	fake := &fakeCalendar{delegated: []gcal.DelegatedAction{
+		{EventID: "1", Summary: "Customer call", Start: "2026-03-20T10:00:00Z", Action: "created", At: "2026-03-19T09:00:00Z"},
+		{EventID: "2", Summary: "Old sync", Action: "deleted", At: "2026-03-18T09:00:00Z", SessionID: "abc", RequestID: "7"},
+	}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolDelegated, id: float64(1)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"2026-03-19T09:00:00Z created Customer call (2026-03-20T10:00:00Z)", "deleted Old sync", "ID: 2", "Made for: session abc, request 7"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %s", want, text)
+		}
+	}
+
+	args, _ := json.Marshal(map[string]int{"days": maxDelegatedDays + 1})
+	if resp := s.callTool(context.Background(), &toolCall{name: toolDelegated, id: float64(2), args: args}); resp.Error == nil {
+		t.Error("expected an error for too many days")
+	}
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const startupCheckTimeout = 10 * time.Second
+
+// startupDiagnostics is written to stderr as a single JSON line when the
+// server starts, so launchers can detect misconfiguration without parsing
+// free-form logs
+type startupDiagnostics struct {
+	Status   string            `json:"status"`
+	Server   string            `json:"server"`
+	Version  string            `json:"version"`
+	AuthMode string            `json:"auth_mode"`
+	ReadOnly bool              `json:"read_only"`
+	Checks   []diagnosticCheck `json:"checks"`
+	Tools    []string          `json:"tools"`
+	// Warnings point out problems that don't stop the server, such as
+	// OAuth scopes that don't match its tools
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type diagnosticCheck struct {
+	Name  string `json:"name"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// ConfigChecker is implemented by calendar backends that can verify their
+// configuration, such as gcal.CalendarClient
+type ConfigChecker interface {
+	AuthMode() string
+	CheckTimezone() error
+	CheckAccess(ctx context.Context) error
+}
+
+func (s *Server) startupDiagnostics(ctx context.Context, cal ConfigChecker) startupDiagnostics {
+	ctx, cancel := context.WithTimeout(ctx, startupCheckTimeout)
+	defer cancel()
+
+	accessErr := cal.CheckAccess(ctx)
+	// The diagnostics line is for scripts; whoever reads the log gets told
+	// how to fix the most common setup mistake
+	var notFound *gcal.CalendarNotFoundError
+	if errors.As(accessErr, &notFound) {
+		log.Print(notFound)
+	}
+	checks := []diagnosticCheck{
+		newDiagnosticCheck("timezone", cal.CheckTimezone()),
+		newDiagnosticCheck("calendar_access", accessErr),
+	}
+
+	status := "ok"
+	for _, c := range checks {
+		if !c.OK {
+			status = "degraded"
+		}
+	}
+
+	var tools []string
+	for _, t := range toolDefinitions {
+		if !s.toolAvailable(t) {
+			continue
+		}
+		tools = append(tools, s.exposedToolName(t.name))
+	}
+
+	var warnings []string
+	if reporter, ok := cal.(gcal.AuthStatusReporter); ok && accessErr == nil {
+		if auth, err := reporter.AuthStatus(ctx); err == nil {
+			warnings = s.auditScopes(auth.Scopes)
+		}
+	}
+	for _, w := range warnings {
+		log.Printf("Scope audit: %s", w)
+	}
+
+	return startupDiagnostics{
+		Status:   status,
+		Server:   s.name,
+		Version:  version,
+		AuthMode: cal.AuthMode(),
+		ReadOnly: s.isReadOnly(),
+		Checks:   checks,
+		Tools:    tools,
+		Warnings: warnings,
+	}
+}
+
+func newDiagnosticCheck(name string, err error) diagnosticCheck {
+	if err != nil {
+		return diagnosticCheck{Name: name, Error: err.Error()}
+	}
+	return diagnosticCheck{Name: name, OK: true}
+}
+
+// WriteDiagnostics checks the configuration of cal, usually the calendar
+// client the server was created with, and writes the startup diagnostics to
+// w
+func (s *Server) WriteDiagnostics(ctx context.Context, w io.Writer, cal ConfigChecker) error {
+	return writeDiagnostics(w, s.startupDiagnostics(ctx, cal))
+}
+
+// Doctor checks the configuration of cal like the startup diagnostics do,
+// and reports the result to w for a person to read. It returns whether
+// every check passed.
+func (s *Server) Doctor(ctx context.Context, w io.Writer, cal ConfigChecker) bool {
+	d := s.startupDiagnostics(ctx, cal)
+	fmt.Fprintf(w, "%s %s, authenticating with %s\n", d.Server, d.Version, d.AuthMode)
+	for _, c := range d.Checks {
+		if c.OK {
+			fmt.Fprintf(w, "ok      %s\n", c.Name)
+		} else {
+			fmt.Fprintf(w, "FAILED  %s: %s\n", c.Name, c.Error)
+		}
+	}
+	for _, warning := range d.Warnings {
+		fmt.Fprintf(w, "warning %s\n", warning)
+	}
+	if d.Status != "ok" {
+		fmt.Fprintln(w, "Fix the failed checks and run the doctor again.")
+		return false
+	}
+	fmt.Fprintf(w, "All checks passed; %d tools are available.\n", len(d.Tools))
+	return true
+}
+
+func writeDiagnostics(w io.Writer, d startupDiagnostics) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
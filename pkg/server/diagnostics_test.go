@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeChecker struct {
+	accessErr error
+}
+
+func (f fakeChecker) AuthMode() string                  { return "service_account" }
+func (f fakeChecker) CheckTimezone() error              { return nil }
+func (f fakeChecker) CheckAccess(context.Context) error { return f.accessErr }
+
+func TestStartupDiagnostics_OK(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	d := s.startupDiagnostics(context.Background(), fakeChecker{})
+
+	if d.Status != "ok" {
+		t.Errorf("expected status ok, got %s", d.Status)
+	}
+	for _, name := range d.Tools {
+		if name == toolSummarize {
+			t.Error("sampling tools should not be listed before a client connects")
+		}
+	}
+	// Neither the sampling tool nor the disabled raw API tool is listed
+	if len(d.Tools) != len(toolDefinitions)-2 {
+		t.Errorf("expected %d tools, got %v", len(toolDefinitions)-2, d.Tools)
+	}
+}
+
+func TestStartupDiagnostics_Degraded(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.readOnly.Store(true)
+	d := s.startupDiagnostics(context.Background(), fakeChecker{accessErr: errors.New("404 Not Found")})
+
+	if d.Status != "degraded" {
+		t.Errorf("expected status degraded, got %s", d.Status)
+	}
+	for _, c := range d.Checks {
+		if c.Name == "calendar_access" && (c.OK || c.Error == "") {
+			t.Errorf("expected failed calendar_access check, got %+v", c)
+		}
+	}
+	for _, name := range d.Tools {
+		if name == toolDeleteEvent {
+			t.Error("mutating tools should not be listed in read-only mode")
+		}
+	}
+}
+
+func TestWriteDiagnostics_SingleJSONLine(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := writeDiagnostics(buf, startupDiagnostics{Status: "ok"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("expected exactly one line, got %q", buf.String())
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Errorf("expected valid JSON: %v", err)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	buf := &bytes.Buffer{}
+	if !s.Doctor(context.Background(), buf, fakeChecker{}) || !strings.Contains(buf.String(), "All checks passed") {
+		t.Errorf("expected a passing report, got:\n%s", buf)
+	}
+
+	buf.Reset()
+	if s.Doctor(context.Background(), buf, fakeChecker{accessErr: errors.New("404 Not Found")}) {
+		t.Error("expected the doctor to fail")
+	}
+	if !strings.Contains(buf.String(), "FAILED  calendar_access: 404 Not Found") {
+		t.Errorf("expected the failed check, got:\n%s", buf)
+	}
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// maxSnapshots is how many diff_range snapshots a session keeps; older
+// tokens stop working
+const maxSnapshots = 20
+
+// rangeSnapshot is the state of a date range as diff_range last saw it
+type rangeSnapshot struct {
+	token     string
+	calendar  string
+	startDate string
+	endDate   string
+	taken     time.Time
+	events    []gcal.CalendarEvent
+}
+
+// diffRangeInput is the arguments of diff_range
+type diffRangeInput struct {
+	// Start date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted); defaults to the range of the snapshot
+	StartDate string `json:"start_date"`
+	// End date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted); defaults to the range of the snapshot
+	EndDate string `json:"end_date"`
+	// Token returned by an earlier diff_range call to compare against; omit it to take the first snapshot
+	Snapshot string `json:"snapshot"`
+	calendarArg
+}
+
+// movedEvent is an event whose start or end changed since the snapshot
+type movedEvent struct {
+	Event         gcal.CalendarEvent `json:"event"`
+	PreviousStart string             `json:"previousStart"`
+	PreviousEnd   string             `json:"previousEnd"`
+}
+
+// rangeDiff is what changed in a range since a snapshot
+type rangeDiff struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	Calendar  string `json:"calendar,omitempty"`
+	// Snapshot is the token of the current state, to pass to the next call
+	Snapshot string `json:"snapshot"`
+	// Since is when the compared snapshot was taken; empty when this call
+	// took the first one
+	Since     string               `json:"since,omitempty"`
+	Added     []gcal.CalendarEvent `json:"added"`
+	Removed   []gcal.CalendarEvent `json:"removed"`
+	Moved     []movedEvent         `json:"moved"`
+	Unchanged int                  `json:"unchanged"`
+}
+
+func (s *Server) callDiffRange(ctx context.Context, input diffRangeInput) (rangeDiff, error) {
+	var previous *rangeSnapshot
+	if input.Snapshot != "" {
+		snap, ok := s.findSnapshot(input.Snapshot)
+		if !ok {
+			return rangeDiff{}, badArgumentf("unknown snapshot %q: it expired or belongs to another session; call diff_range without snapshot to take a new one", input.Snapshot)
+		}
+		previous = &snap
+		if input.StartDate == "" && input.EndDate == "" {
+			input.StartDate, input.EndDate = snap.startDate, snap.endDate
+		}
+		if input.Calendar == "" {
+			input.Calendar = snap.calendar
+		}
+	}
+	if input.StartDate == "" || input.EndDate == "" {
+		return rangeDiff{}, badArgumentf("start_date and end_date are required without a snapshot")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return rangeDiff{}, badArgument(err)
+		}
+	}
+	if previous != nil && (previous.startDate != input.StartDate || previous.endDate != input.EndDate || previous.calendar != input.Calendar) {
+		return rangeDiff{}, badArgumentf("snapshot %s is of %s to %s; compare the same range, or omit snapshot to start over", previous.token, previous.startDate, previous.endDate)
+	}
+
+	events, err := s.listCalendarRange(ctx, input.Calendar, input.StartDate, input.EndDate)
+	if err != nil {
+		return rangeDiff{}, err
+	}
+
+	current := s.saveSnapshot(rangeSnapshot{calendar: input.Calendar, startDate: input.StartDate, endDate: input.EndDate, events: events})
+	diff := rangeDiff{StartDate: input.StartDate, EndDate: input.EndDate, Calendar: input.Calendar, Snapshot: current.token}
+	if previous == nil {
+		diff.Added, diff.Removed, diff.Moved = []gcal.CalendarEvent{}, []gcal.CalendarEvent{}, []movedEvent{}
+		diff.Unchanged = len(events)
+		return diff, nil
+	}
+	diff.Since = previous.taken.In(s.settings().location).Format(time.RFC3339)
+	diff.Added, diff.Removed, diff.Moved, diff.Unchanged = diffEvents(previous.events, events)
+	return diff, nil
+}
+
+// diffEvents compares two listings of the same range by event
+func diffEvents(before, after []gcal.CalendarEvent) (added, removed []gcal.CalendarEvent, moved []movedEvent, unchanged int) {
+	key := func(e gcal.CalendarEvent) string { return e.CalendarID + "/" + e.ID }
+	old := make(map[string]gcal.CalendarEvent, len(before))
+	for _, e := range before {
+		old[key(e)] = e
+	}
+
+	added, removed, moved = []gcal.CalendarEvent{}, []gcal.CalendarEvent{}, []movedEvent{}
+	seen := make(map[string]bool, len(after))
+	for _, e := range after {
+		seen[key(e)] = true
+		prev, ok := old[key(e)]
+		switch {
+		case !ok:
+			added = append(added, e)
+		case prev.Start != e.Start || prev.End != e.End:
+			moved = append(moved, movedEvent{Event: e, PreviousStart: prev.Start, PreviousEnd: prev.End})
+		default:
+			unchanged++
+		}
+	}
+	for _, e := range before {
+		if !seen[key(e)] {
+			removed = append(removed, e)
+		}
+	}
+	return added, removed, moved, unchanged
+}
+
+// saveSnapshot stores snap under a new token, dropping the oldest snapshot
+// beyond maxSnapshots
+func (s *Server) saveSnapshot(snap rangeSnapshot) rangeSnapshot {
+	snap.token = "snap-" + strings.ToLower(rand.Text()[:12])
+	snap.taken = time.Now()
+
+	s.snapshotsMu.Lock()
+	defer s.snapshotsMu.Unlock()
+	s.snapshots = append(s.snapshots, snap)
+	if len(s.snapshots) > maxSnapshots {
+		s.snapshots = append([]rangeSnapshot(nil), s.snapshots[len(s.snapshots)-maxSnapshots:]...)
+	}
+	return snap
+}
+
+func (s *Server) findSnapshot(token string) (rangeSnapshot, bool) {
+	s.snapshotsMu.Lock()
+	defer s.snapshotsMu.Unlock()
+	for _, snap := range s.snapshots {
+		if snap.token == token {
+			return snap, true
+		}
+	}
+	return rangeSnapshot{}, false
+}
+
+func (d rangeDiff) toolText(s *Server) string { return s.formatRangeDiff(d) }
+
+func (s *Server) formatRangeDiff(d rangeDiff) string {
+	var b strings.Builder
+	if d.Since == "" {
+		fmt.Fprintf(&b, "Took a snapshot of %d events between %s and %s.\n", d.Unchanged, d.StartDate, d.EndDate)
+		fmt.Fprintf(&b, "Call diff_range with snapshot %q later to see what changed.\n", d.Snapshot)
+		return b.String()
+	}
+
+	if len(d.Added)+len(d.Removed)+len(d.Moved) == 0 {
+		fmt.Fprintf(&b, "No changes between %s and %s since %s (%d events).\n", d.StartDate, d.EndDate, d.Since, d.Unchanged)
+	} else {
+		fmt.Fprintf(&b, "Changes between %s and %s since %s:\n", d.StartDate, d.EndDate, d.Since)
+	}
+	if len(d.Added) > 0 {
+		fmt.Fprintf(&b, "\nAdded (%d):\n", len(d.Added))
+		for _, e := range d.Added {
+			fmt.Fprintf(&b, "- %s (%s)\n  ID: %s\n", s.sanitize(e.Summary), e.Start, e.ID)
+		}
+	}
+	if len(d.Removed) > 0 {
+		fmt.Fprintf(&b, "\nRemoved (%d):\n", len(d.Removed))
+		for _, e := range d.Removed {
+			fmt.Fprintf(&b, "- %s (was %s)\n  ID: %s\n", s.sanitize(e.Summary), e.Start, e.ID)
+		}
+	}
+	if len(d.Moved) > 0 {
+		fmt.Fprintf(&b, "\nMoved (%d):\n", len(d.Moved))
+		for _, m := range d.Moved {
+			fmt.Fprintf(&b, "- %s: %s – %s, was %s – %s\n  ID: %s\n", s.sanitize(m.Event.Summary), m.Event.Start, m.Event.End, m.PreviousStart, m.PreviousEnd, m.Event.ID)
+		}
+	}
+	fmt.Fprintf(&b, "\nNext snapshot: %s\n", d.Snapshot)
+	return b.String()
+}
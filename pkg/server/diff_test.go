@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func callDiffRange(t *testing.T, s *Server, args map[string]string) (rangeDiff, string) {
+	t.Helper()
+	data, _ := json.Marshal(args)
+	resp := s.callTool(context.Background(), &toolCall{name: toolDiffRange, id: float64(1), args: data})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	result := resp.Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	diff, ok := result["structuredContent"].(rangeDiff)
+	if !ok {
+		t.Fatalf("expected a rangeDiff, got %s", text)
+	}
+	return diff, text
+}
+
+func TestCallDiffRange(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Standup", Start: "2026-03-20T09:00:00Z", End: "2026-03-20T09:15:00Z"},
+		{ID: "2", Summary: "Review", Start: "2026-03-20T14:00:00Z", End: "2026-03-20T15:00:00Z"},
+		{ID: "3", Summary: "Old sync", Start: "2026-03-21T10:00:00Z", End: "2026-03-21T11:00:00Z"},
+	}}
+	s := newTestServer(fake)
+	s.setProtocolVersion(protocolVersion20250618)
+
+	first, text := callDiffRange(t, s, map[string]string{"start_date": "2026-03-20", "end_date": "2026-03-22"})
+	if first.Snapshot == "" || first.Since != "" || first.Unchanged != 3 || !strings.Contains(text, first.Snapshot) {
+		t.Fatalf("expected a first snapshot of 3 events, got %+v\n%s", first, text)
+	}
+
+	fake.events = []gcal.CalendarEvent{
+		{ID: "1", Summary: "Standup", Start: "2026-03-20T09:00:00Z", End: "2026-03-20T09:15:00Z"},
+		{ID: "2", Summary: "Review", Start: "2026-03-20T16:00:00Z", End: "2026-03-20T17:00:00Z"},
+		{ID: "4", Summary: "Offsite", Start: "2026-03-22T09:00:00Z", End: "2026-03-22T17:00:00Z"},
+	}
+	diff, text := callDiffRange(t, s, map[string]string{"snapshot": first.Snapshot})
+	if fake.lastStart != "2026-03-20" || fake.lastEnd != "2026-03-22" {
+		t.Errorf("expected the range of the snapshot, got %s to %s", fake.lastStart, fake.lastEnd)
+	}
+	if len(diff.Added) != 1 || diff.Added[0].ID != "4" || len(diff.Removed) != 1 || diff.Removed[0].ID != "3" || diff.Unchanged != 1 {
+		t.Errorf("unexpected diff %+v", diff)
+	}
+	if len(diff.Moved) != 1 || diff.Moved[0].Event.ID != "2" || diff.Moved[0].PreviousStart != "2026-03-20T14:00:00Z" {
+		t.Errorf("expected Review to have moved, got %+v", diff.Moved)
+	}
+	for _, want := range []string{"Added (1):\n- Offsite", "Removed (1):\n- Old sync", "- Review: 2026-03-20T16:00:00Z", "Next snapshot: " + diff.Snapshot} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if diff.Snapshot == first.Snapshot {
+		t.Error("expected a new snapshot token")
+	}
+
+	// The new token compares against the state it was handed out for
+	if again, text := callDiffRange(t, s, map[string]string{"snapshot": diff.Snapshot}); again.Unchanged != 3 || !strings.Contains(text, "No changes") {
+		t.Errorf("expected no changes, got %+v\n%s", again, text)
+	}
+}
+
+func TestCallDiffRange_InvalidSnapshot(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.setProtocolVersion(protocolVersion20250618)
+	first, _ := callDiffRange(t, s, map[string]string{"start_date": "2026-03-20", "end_date": "2026-03-22"})
+
+	for name, args := range map[string]map[string]string{
+		"unknown token": {"snapshot": "snap-nope"},
+		"other range":   {"snapshot": first.Snapshot, "start_date": "2026-03-01", "end_date": "2026-03-22"},
+		"no range":      {},
+	} {
+		data, _ := json.Marshal(args)
+		if resp := s.callTool(context.Background(), &toolCall{name: toolDiffRange, id: float64(2), args: data}); resp.Error == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	s.resetSession("next")
+	data, _ := json.Marshal(map[string]string{"snapshot": first.Snapshot})
+	if resp := s.callTool(context.Background(), &toolCall{name: toolDiffRange, id: float64(3), args: data}); resp.Error == nil {
+		t.Error("expected snapshots to be forgotten with the session")
+	}
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/googleapi"
+)
+
+// Error codes reported with failed tool calls, so that agents can branch on
+// the kind of failure instead of parsing the message
+const (
+	errCodeInvalidArgument    = "INVALID_ARGUMENT"
+	errCodeEventNotFound      = "EVENT_NOT_FOUND"
+	errCodeCalendarNotFound   = "CALENDAR_NOT_FOUND"
+	errCodePermissionDenied   = "PERMISSION_DENIED"
+	errCodeUnauthenticated    = "UNAUTHENTICATED"
+	errCodeRateLimited        = "RATE_LIMITED"
+	errCodeConflict           = "CONFLICT"
+	errCodeBackendError       = "BACKEND_ERROR"
+	errCodeBackendUnavailable = "BACKEND_UNAVAILABLE"
+	errCodeTimeout            = "TIMEOUT"
+	errCodeSamplingFailed     = "SAMPLING_FAILED"
+	errCodeUnknown            = "UNKNOWN"
+)
+
+// codedError attaches an error code to errors that don't come from the
+// Calendar API
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+func withErrorCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// invalidInputf reports a request the server refuses to carry out as given,
+// such as an event that ends before it starts
+func invalidInputf(format string, args ...interface{}) error {
+	return withErrorCode(errCodeInvalidArgument, fmt.Errorf(format, args...))
+}
+
+// errorDetail is the structured form of a failed tool call
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// HTTPStatus and Reason come from Calendar API errors
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	// Retryable tells the caller that the same call may succeed later
+	Retryable bool `json:"retryable"`
+	// Recovery walks the user through fixing credentials that stopped
+	// working
+	Recovery *authRecovery `json:"recovery,omitempty"`
+}
+
+func describeError(err error) errorDetail {
+	detail := errorDetail{Code: errorCode(err), Message: err.Error()}
+
+	// The HTTP client wraps the error with the request URL, which tells the
+	// user nothing about what to do
+	var authErr *gcal.AuthError
+	if errors.As(err, &authErr) {
+		detail.Message = authErr.Error()
+	}
+	var notFound *gcal.CalendarNotFoundError
+	if errors.As(err, &notFound) {
+		detail.Message = notFound.Error()
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		detail.HTTPStatus = apiErr.Code
+		if len(apiErr.Errors) > 0 {
+			detail.Reason = apiErr.Errors[0].Reason
+		}
+	}
+
+	switch detail.Code {
+	case errCodeRateLimited, errCodeBackendError, errCodeBackendUnavailable, errCodeTimeout:
+		detail.Retryable = true
+	case errCodeUnauthenticated:
+		detail.Recovery = recoveryFor(err)
+	}
+	return detail
+}
+
+// errorCode classifies an error from a tool handler
+func errorCode(err error) string {
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+
+	var invalid *gcal.InvalidInputError
+	if errors.As(err, &invalid) {
+		return errCodeInvalidArgument
+	}
+
+	var authErr *gcal.AuthError
+	if errors.As(err, &authErr) {
+		return errCodeUnauthenticated
+	}
+
+	var notFound *gcal.CalendarNotFoundError
+	if errors.As(err, &notFound) {
+		return errCodeCalendarNotFound
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return googleErrorCode(apiErr)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errCodeTimeout
+	}
+
+	// The backend couldn't be reached at all, e.g. the connection was
+	// refused
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return errCodeBackendError
+	}
+	return errCodeUnknown
+}
+
+func googleErrorCode(err *googleapi.Error) string {
+	for _, item := range err.Errors {
+		switch item.Reason {
+		case "rateLimitExceeded", "userRateLimitExceeded", "quotaExceeded":
+			return errCodeRateLimited
+		}
+	}
+
+	switch {
+	case err.Code == http.StatusNotFound || err.Code == http.StatusGone:
+		return errCodeEventNotFound
+	case err.Code == http.StatusTooManyRequests:
+		return errCodeRateLimited
+	case err.Code == http.StatusForbidden:
+		return errCodePermissionDenied
+	case err.Code == http.StatusUnauthorized:
+		return errCodeUnauthenticated
+	case err.Code == http.StatusConflict || err.Code == http.StatusPreconditionFailed:
+		return errCodeConflict
+	case err.Code == http.StatusBadRequest:
+		return errCodeInvalidArgument
+	case err.Code >= 500:
+		return errCodeBackendError
+	}
+	return errCodeUnknown
+}
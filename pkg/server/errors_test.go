@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/googleapi"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"not found", &googleapi.Error{Code: 404}, errCodeEventNotFound},
+		{"gone", &googleapi.Error{Code: 410}, errCodeEventNotFound},
+		{"forbidden", &googleapi.Error{Code: 403}, errCodePermissionDenied},
+		{"rate limit reason", &googleapi.Error{Code: 403, Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}, errCodeRateLimited},
+		{"too many requests", &googleapi.Error{Code: 429}, errCodeRateLimited},
+		{"unauthorized", &googleapi.Error{Code: 401}, errCodeUnauthenticated},
+		{"precondition", &googleapi.Error{Code: 412}, errCodeConflict},
+		{"backend", &googleapi.Error{Code: 503}, errCodeBackendError},
+		{"wrapped api error", fmt.Errorf("update: %w", &googleapi.Error{Code: 404}), errCodeEventNotFound},
+		{"calendar not shared", fmt.Errorf("list: %w", &gcal.CalendarNotFoundError{CalendarID: "team@example.com", Err: &googleapi.Error{Code: 404}}), errCodeCalendarNotFound},
+		{"validation", &gcal.InvalidInputError{Err: errors.New("end time must be after start time")}, errCodeInvalidArgument},
+		{"timeout", fmt.Errorf("list: %w", context.DeadlineExceeded), errCodeTimeout},
+		{"other", errors.New("boom"), errCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorResponse_Code(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	err := &googleapi.Error{Code: 404, Message: "Not Found"}
+
+	result := s.errorResponse(float64(1), err).Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if !contains(text, "Error [EVENT_NOT_FOUND]:") {
+		t.Errorf("expected error code in text, got %q", text)
+	}
+	if _, ok := result["structuredContent"]; ok {
+		t.Error("structuredContent should not be sent on 2024-11-05")
+	}
+
+	s.setProtocolVersion(protocolVersion20250618)
+	result = s.errorResponse(float64(1), err).Result.(map[string]interface{})
+	detail := result["structuredContent"].(map[string]interface{})["error"].(errorDetail)
+	if detail.Code != errCodeEventNotFound || detail.HTTPStatus != 404 || detail.Retryable {
+		t.Errorf("unexpected structured error %+v", detail)
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	rateLimited := &googleapi.Error{Code: 403, Message: "Rate Limit Exceeded", Errors: []googleapi.ErrorItem{{Reason: "rateLimitExceeded"}}}
+	detail := describeError(fmt.Errorf("list: %w", rateLimited))
+	if detail.Code != errCodeRateLimited || detail.HTTPStatus != 403 || detail.Reason != "rateLimitExceeded" || !detail.Retryable {
+		t.Errorf("unexpected detail %+v", detail)
+	}
+
+	detail = describeError(invalidInputf("bad date"))
+	if detail.Code != errCodeInvalidArgument || detail.HTTPStatus != 0 || detail.Retryable || detail.Message != "bad date" {
+		t.Errorf("unexpected detail %+v", detail)
+	}
+
+	authErr := &url.Error{Op: "Get", URL: "https://www.googleapis.com/calendar/v3/calendars/primary/events", Err: &gcal.AuthError{Err: errors.New("invalid_grant")}}
+	detail = describeError(authErr)
+	if detail.Code != errCodeUnauthenticated || detail.Retryable || strings.Contains(detail.Message, "googleapis") {
+		t.Errorf("expected a clear authorization error, got %+v", detail)
+	}
+	if detail.Recovery == nil || detail.Recovery.Command != doctorCommand || !slices.Contains(detail.Recovery.Settings, "GOOGLE_OAUTH_TOKEN_FILE") {
+		t.Errorf("expected OAuth recovery steps, got %+v", detail.Recovery)
+	}
+
+	s := newTestServer(&fakeCalendar{})
+	text := s.errorResponse(float64(1), &googleapi.Error{Code: 503}).Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !contains(text, "Error [BACKEND_ERROR]") || !contains(text, "retry later") {
+		t.Errorf("expected a retry hint, got %q", text)
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// eventIDDescription documents the event_id argument of the tools that act on
+// a single event
+const eventIDDescription = "Event ID (use list_events to find IDs), or a Google Calendar link to the event as pasted from the browser (calendar.google.com/calendar/event?eid=...)"
+
+// eventRefDescription documents the event_ref argument of the tools that
+// act on a single event
+const eventRefDescription = "Position of the event in the last list_events or list_events_range result, e.g. \"#2\" for the second one; use instead of event_id"
+
+// rememberListing keeps the events of the latest listing so that follow-up
+// calls can refer to them by position
+func (s *Server) rememberListing(events []gcal.CalendarEvent) {
+	s.refsMu.Lock()
+	defer s.refsMu.Unlock()
+	s.lastListed = append([]gcal.CalendarEvent(nil), events...)
+}
+
+// resolveEventRef turns a reference like "#2" into the ID of the second
+// event of the last listing
+func (s *Server) resolveEventRef(ctx context.Context, ref string) (string, error) {
+	digits, ok := strings.CutPrefix(strings.TrimSpace(ref), "#")
+	n, err := strconv.Atoi(digits)
+	if !ok || err != nil || n < 1 {
+		return "", invalidInputf("event_ref must look like #1, got %q", ref)
+	}
+
+	s.refsMu.Lock()
+	defer s.refsMu.Unlock()
+	if s.lastListed == nil {
+		return "", invalidInputf("no events have been listed yet; call list_events first or pass event_id")
+	}
+	if n > len(s.lastListed) {
+		return "", invalidInputf("event_ref %s is out of range: the last listing had %d event(s)", ref, len(s.lastListed))
+	}
+	e := s.lastListed[n-1]
+	if e.CalendarID != "" && e.CalendarID != s.primaryCalendar(ctx) {
+		return "", invalidInputf("event_ref %s is on calendar %s; only events of your own calendar can be used here", ref, e.CalendarID)
+	}
+	return e.ID, nil
+}
+
+// eventIDArg picks the event a call is about from its event_id and
+// event_ref arguments, for tools that only act on events of the primary
+// calendar. event_id may also be a pasted event link. It returns an empty
+// ID when neither is given.
+func (s *Server) eventIDArg(ctx context.Context, id, ref string) (string, error) {
+	eventID, calendarID, err := s.eventArg(ctx, id, ref)
+	if err != nil {
+		return "", err
+	}
+	if calendarID != "" {
+		return "", invalidInputf("the link is to an event on calendar %s; only events of your own calendar can be used here", calendarID)
+	}
+	return eventID, nil
+}
+
+// eventArg is eventIDArg for tools that can act on events of any calendar
+// of the user. calendarID is set when event_id links to an event on
+// another calendar of the calendar list, and empty for the primary one.
+func (s *Server) eventArg(ctx context.Context, id, ref string) (eventID, calendarID string, err error) {
+	if gcal.IsEventLink(id) {
+		if id, calendarID, err = s.resolveEventLink(ctx, id); err != nil {
+			return "", "", err
+		}
+	}
+	if ref == "" {
+		return id, calendarID, nil
+	}
+	resolved, err := s.resolveEventRef(ctx, ref)
+	if err != nil {
+		return "", "", err
+	}
+	if id != "" && (id != resolved || calendarID != "") {
+		return "", "", invalidInputf("event_id %s and event_ref %s point to different events; pass only one", id, ref)
+	}
+	return resolved, "", nil
+}
+
+// onCalendar returns a client acting on calendarID in place of the primary
+// calendar, the session calendar itself for ""
+func (s *Server) onCalendar(calendarID string) gcal.Service {
+	if calendarID == "" {
+		return s.settings().calendar
+	}
+	return s.settings().calendar.WithDefaults(calendarID, "")
+}
+
+// resolveEventLink returns the ID of the event a Google Calendar event link
+// points to, and the calendar it is on when that isn't the primary one
+func (s *Server) resolveEventLink(ctx context.Context, link string) (eventID, calendarID string, err error) {
+	eventID, calendarID, err = gcal.ParseEventLink(link)
+	if err != nil {
+		return "", "", err
+	}
+	// A configured "primary" can't be told apart from other calendars
+	own := s.primaryCalendar(ctx)
+	if calendarID == "" || !strings.Contains(own, "@") || strings.EqualFold(calendarID, own) {
+		return eventID, "", nil
+	}
+	if !s.knownCalendar(calendarID) {
+		return "", "", invalidInputf("the link is to an event on calendar %s, which isn't in your calendar list; ask its owner to share it with you", calendarID)
+	}
+	return eventID, calendarID, nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestEventRef_FollowUpCall(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "a1b2c3d4e5f6g7h8i9j0", Summary: "Standup"},
+		{ID: "k1l2m3n4o5p6q7r8s9t0", Summary: "Planning"},
+	}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Ref: #2\n  ID: k1l2m3n4o5p6q7r8s9t0") {
+		t.Errorf("expected numbered events, got %s", text)
+	}
+
+	args, _ := json.Marshal(map[string]string{"event_ref": "#2"})
+	resp = s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(2), args: args})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.deletedID != "k1l2m3n4o5p6q7r8s9t0" {
+		t.Errorf("expected the second event to be deleted, got %q", fake.deletedID)
+	}
+}
+
+func TestEventRef_Invalid(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	if _, err := s.resolveEventRef(context.Background(), "#1"); err == nil {
+		t.Error("expected an error before any listing")
+	}
+
+	s.rememberListing([]gcal.CalendarEvent{
+		{ID: "own", CalendarID: "test@example.com"},
+		{ID: "theirs", CalendarID: "maria@example.com"},
+	})
+	for _, ref := range []string{"2", "#0", "#x", "#3", "#2"} {
+		if _, err := s.resolveEventRef(context.Background(), ref); errorCode(err) != errCodeInvalidArgument {
+			t.Errorf("%q: expected an invalid argument error, got %v", ref, err)
+		}
+	}
+
+	if id, err := s.eventIDArg(context.Background(), "own", "#1"); err != nil || id != "own" {
+		t.Errorf("expected matching event_id and event_ref to be accepted, got %q, %v", id, err)
+	}
+	if _, err := s.eventIDArg(context.Background(), "other", "#1"); err == nil {
+		t.Error("expected an error when event_id and event_ref disagree")
+	}
+}
+
+func TestEventIDArg_Link(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	eid := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	args, _ := json.Marshal(map[string]string{"event_id": "https://www.google.com/calendar/event?eid=" + eid("a1b2c3d4 test@example.com")})
+	if resp := s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(1), args: args}); resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.deletedID != "a1b2c3d4" {
+		t.Errorf("expected the linked event to be deleted, got %q", fake.deletedID)
+	}
+
+	if _, err := s.eventIDArg(context.Background(), "https://calendar.google.com/calendar/event?eid="+eid("a1b2c3d4 maria@m"), ""); errorCode(err) != errCodeInvalidArgument {
+		t.Errorf("expected events of other calendars to be refused, got %v", err)
+	}
+	if _, err := s.eventIDArg(context.Background(), "https://calendar.google.com/calendar/r/week", ""); errorCode(err) != errCodeInvalidArgument {
+		t.Errorf("expected a link without eid to be refused, got %v", err)
+	}
+}
+
+func TestEventLink_OtherCalendar(t *testing.T) {
+	fake := &fakeCalendar{
+		extraCalendars: map[string][]gcal.CalendarEvent{"team@example.com": nil},
+		updated:        &calendar.Event{Id: "a1b2c3d4", Summary: "Offsite"},
+	}
+	s := newTestServer(fake)
+	link := "https://calendar.google.com/calendar/event?eid=" + base64.RawURLEncoding.EncodeToString([]byte("a1b2c3d4 team@example.com"))
+
+	// Events on other calendars of the list are read and changed there
+	for _, tt := range []struct {
+		tool string
+		args map[string]string
+	}{
+		{toolGetEvent, map[string]string{"event_id": link}},
+		{toolUpdateEvent, map[string]string{"event_id": link, "summary": "Offsite"}},
+		{toolDeleteEvent, map[string]string{"event_id": link}},
+		{toolDeleteEvent, map[string]string{"event_id": link, "apply_to": "this_event"}},
+	} {
+		fake.viewCalendar = ""
+		args, _ := json.Marshal(tt.args)
+		if resp := s.callTool(context.Background(), &toolCall{name: tt.tool, id: float64(1), args: args}); resp.Error != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.tool, resp.Error)
+		}
+		if fake.viewCalendar != "team@example.com" {
+			t.Errorf("%s: expected the call to go to team@example.com, got %q", tt.tool, fake.viewCalendar)
+		}
+	}
+	if fake.deletedID != "a1b2c3d4" {
+		t.Errorf("expected the linked event to be deleted, got %q", fake.deletedID)
+	}
+
+	// Writes still need write access to that calendar
+	fake.calendarList = []string{"test@example.com", "team@example.com"}
+	fake.calendarRoles = map[string]string{"team@example.com": "reader"}
+	s.checkCalendars(context.Background())
+	args, _ := json.Marshal(map[string]string{"event_id": link})
+	resp := s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(2), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "read-only access to calendar team@example.com") {
+		t.Errorf("expected a read-only access error, got %q", text)
+	}
+}
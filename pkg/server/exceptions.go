@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+const defaultExceptionDays = 90
+
+// seriesException is an instance that deviates from the series pattern
+type seriesException struct {
+	// Date is when the pattern scheduled the instance
+	Date string `json:"date"`
+	// Kind is cancelled, moved or resized
+	Kind string `json:"kind"`
+	// Start and End are the actual times of moved or resized instances
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	ID    string `json:"id"`
+}
+
+type exceptionReport struct {
+	SeriesID   string            `json:"seriesId"`
+	Summary    string            `json:"summary"`
+	StartDate  string            `json:"startDate"`
+	EndDate    string            `json:"endDate"`
+	Instances  int               `json:"instances"`
+	Cancelled  int               `json:"cancelled"`
+	Moved      int               `json:"moved"`
+	Resized    int               `json:"resized"`
+	Exceptions []seriesException `json:"exceptions"`
+}
+
+// findExceptions compares every instance with the slot the pattern gives
+// it. length is the duration of the series itself.
+func findExceptions(instances []gcal.SeriesInstance, length time.Duration) exceptionReport {
+	report := exceptionReport{Instances: len(instances), Exceptions: []seriesException{}}
+	for _, in := range instances {
+		exception := seriesException{Date: instanceDate(in.OriginalStart), ID: in.ID, Kind: exceptionKind(in, length)}
+		switch exception.Kind {
+		case "cancelled":
+			report.Cancelled++
+		case "moved":
+			report.Moved++
+		case "resized":
+			report.Resized++
+		default:
+			continue
+		}
+		if exception.Kind != "cancelled" {
+			exception.Start, exception.End = in.Start, in.End
+		}
+		report.Exceptions = append(report.Exceptions, exception)
+	}
+	return report
+}
+
+// exceptionKind tells how an instance deviates from the series pattern:
+// cancelled, moved or resized, or not at all. length is the duration of
+// the series itself.
+func exceptionKind(in gcal.SeriesInstance, length time.Duration) string {
+	if in.Status == "cancelled" {
+		return "cancelled"
+	}
+	start, err := time.Parse(time.RFC3339, in.Start)
+	if err != nil {
+		return ""
+	}
+	end, err := time.Parse(time.RFC3339, in.End)
+	if err != nil {
+		return ""
+	}
+	original, err := time.Parse(time.RFC3339, in.OriginalStart)
+	switch {
+	case err == nil && !start.Equal(original):
+		return "moved"
+	case length > 0 && end.Sub(start) != length:
+		return "resized"
+	}
+	return ""
+}
+
+func instanceDate(timestamp string) string {
+	if len(timestamp) < len("2006-01-02") {
+		return timestamp
+	}
+	return timestamp[:len("2006-01-02")]
+}
+
+// recurringExceptionsInput is the arguments of recurring_exceptions
+type recurringExceptionsInput struct {
+	// ID of the recurring series or of any of its instances
+	EventID string `json:"event_id" jsonschema:"required"`
+	// Start date in YYYY-MM-DD format (default: 90 days ago)
+	StartDate string `json:"start_date"`
+	// End date in YYYY-MM-DD format (default: today)
+	EndDate string `json:"end_date"`
+}
+
+func (s *Server) callRecurringExceptions(ctx context.Context, input recurringExceptionsInput) (exceptionReport, error) {
+	if input.EventID == "" {
+		return exceptionReport{}, badArgumentf("event_id is required (the ID of the series or of any of its instances)")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return exceptionReport{}, badArgument(err)
+		}
+	}
+	now := time.Now().In(s.settings().location)
+	if input.EndDate == "" {
+		input.EndDate = now.Format("2006-01-02")
+	}
+	if input.StartDate == "" {
+		input.StartDate = now.AddDate(0, 0, -defaultExceptionDays).Format("2006-01-02")
+	}
+
+	series, err := s.seriesOf(ctx, input.EventID)
+	if err != nil {
+		return exceptionReport{}, err
+	}
+
+	instances, err := s.settings().calendar.ListInstances(ctx, series.Id, input.StartDate, input.EndDate)
+	if err != nil {
+		return exceptionReport{}, err
+	}
+
+	length, _ := gcal.EventDuration(series)
+	report := findExceptions(instances, length)
+	report.SeriesID, report.Summary = series.Id, series.Summary
+	report.StartDate, report.EndDate = input.StartDate, input.EndDate
+	return report, nil
+}
+
+// seriesOf returns the recurring series eventID is, or is an instance of
+func (s *Server) seriesOf(ctx context.Context, eventID string) (*calendar.Event, error) {
+	event, err := s.settings().calendar.GetEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event.RecurringEventId != "" {
+		return s.settings().calendar.GetEvent(ctx, event.RecurringEventId)
+	}
+	if len(event.Recurrence) == 0 {
+		return nil, invalidInputf("event %s is not part of a recurring series", eventID)
+	}
+	return event, nil
+}
+
+func (r exceptionReport) toolText(s *Server) string { return s.formatExceptions(r) }
+
+func (s *Server) formatExceptions(r exceptionReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s to %s: %d instance(s)", s.sanitize(r.Summary), r.StartDate, r.EndDate, r.Instances)
+	if r.Instances == 0 {
+		b.WriteString("\n")
+		return b.String()
+	}
+	held := r.Instances - r.Cancelled
+	fmt.Fprintf(&b, ", %d held as scheduled or moved (%d%%)\n", held, held*100/r.Instances)
+	fmt.Fprintf(&b, "Cancelled: %d, moved: %d, length changed: %d\n", r.Cancelled, r.Moved, r.Resized)
+
+	if len(r.Exceptions) > 0 {
+		b.WriteString("\nExceptions:\n")
+	}
+	for _, e := range r.Exceptions {
+		switch e.Kind {
+		case "cancelled":
+			fmt.Fprintf(&b, "- %s cancelled\n", e.Date)
+		case "moved":
+			fmt.Fprintf(&b, "- %s moved to %s %s-%s\n", e.Date, instanceDate(e.Start), clockOf(e.Start, s.settings().location), clockOf(e.End, s.settings().location))
+		default:
+			fmt.Fprintf(&b, "- %s ran %s-%s\n", e.Date, clockOf(e.Start, s.settings().location), clockOf(e.End, s.settings().location))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func weeklyInstances() []gcal.SeriesInstance {
+	return []gcal.SeriesInstance{
+		{ID: "w_1", Start: "2026-03-02T10:00:00Z", End: "2026-03-02T10:30:00Z", OriginalStart: "2026-03-02T10:00:00Z"},
+		{ID: "w_2", Status: "cancelled", OriginalStart: "2026-03-09T10:00:00Z"},
+		{ID: "w_3", Start: "2026-03-17T14:00:00Z", End: "2026-03-17T14:30:00Z", OriginalStart: "2026-03-16T10:00:00Z"},
+		{ID: "w_4", Start: "2026-03-23T10:00:00Z", End: "2026-03-23T11:00:00Z", OriginalStart: "2026-03-23T10:00:00Z"},
+	}
+}
+
+func TestFindExceptions(t *testing.T) {
+	report := findExceptions(weeklyInstances(), 30*time.Minute)
+
+	if report.Instances != 4 || report.Cancelled != 1 || report.Moved != 1 || report.Resized != 1 {
+		t.Fatalf("unexpected counts %+v", report)
+	}
+	want := []seriesException{
+		{Date: "2026-03-09", Kind: "cancelled", ID: "w_2"},
+		{Date: "2026-03-16", Kind: "moved", Start: "2026-03-17T14:00:00Z", End: "2026-03-17T14:30:00Z", ID: "w_3"},
+		{Date: "2026-03-23", Kind: "resized", Start: "2026-03-23T10:00:00Z", End: "2026-03-23T11:00:00Z", ID: "w_4"},
+	}
+	for i := range want {
+		if report.Exceptions[i] != want[i] {
+			t.Errorf("exception %d: expected %+v, got %+v", i, want[i], report.Exceptions[i])
+		}
+	}
+}
+
+func TestCallRecurringExceptions(t *testing.T) {
+	fake := &fakeCalendar{
+		fetched: &calendar.Event{
+			Id:         "w",
+			Summary:    "Weekly sync",
+			Recurrence: []string{"RRULE:FREQ=WEEKLY"},
+			Start:      &calendar.EventDateTime{DateTime: "2026-01-05T10:00:00Z"},
+			End:        &calendar.EventDateTime{DateTime: "2026-01-05T10:30:00Z"},
+		},
+		instances: weeklyInstances(),
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"event_id": "w", "start_date": "2026-03-01", "end_date": "2026-03-31"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolExceptions, id: float64(1), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Weekly sync, 2026-03-01 to 2026-03-31: 4 instance(s), 3 held as scheduled or moved (75%)") ||
+		!strings.Contains(text, "- 2026-03-09 cancelled") || !strings.Contains(text, "- 2026-03-16 moved to 2026-03-17 14:00-14:30") {
+		t.Errorf("unexpected report:\n%s", text)
+	}
+	if fake.lastStart != "2026-03-01" || fake.lastEnd != "2026-03-31" {
+		t.Errorf("unexpected range %s to %s", fake.lastStart, fake.lastEnd)
+	}
+
+	// A one-off event has no exceptions to report
+	fake.fetched = &calendar.Event{Id: "once"}
+	args, _ = json.Marshal(map[string]string{"event_id": "once"})
+	resp = s.callTool(context.Background(), &toolCall{name: toolExceptions, id: float64(2), args: args})
+	text = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Error [INVALID_ARGUMENT]") {
+		t.Errorf("expected an invalid argument error, got %s", text)
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// maxMessageSize is the largest JSON-RPC message accepted on input
+const maxMessageSize = 1024 * 1024
+
+const (
+	// FramingNewline separates messages with newlines, as MCP's stdio
+	// transport specifies
+	FramingNewline = "newline"
+	// FramingContentLength precedes every message with LSP-style headers,
+	// for hosts that speak that instead
+	FramingContentLength = "content-length"
+)
+
+// errMessageTooLarge is returned by readMessage for a message that exceeds
+// maxMessageSize. The rest of the message has already been discarded, so the
+// next call continues with the following message.
+var errMessageTooLarge = errors.New("message exceeds maximum size")
+
+// errInvalidFrame is returned by readMessage for a Content-Length framed
+// message whose headers can't be used. Without the length the next message
+// can't be found, so the input is unusable from there on.
+var errInvalidFrame = errors.New("invalid message headers")
+
+// messageReader splits the input into JSON-RPC messages, newline-delimited
+// unless framing is FramingContentLength
+type messageReader struct {
+	r       *bufio.Reader
+	framing string
+}
+
+func newMessageReader(in io.Reader) *messageReader {
+	return &messageReader{r: bufio.NewReaderSize(in, 64*1024)}
+}
+
+// readMessage returns the next message without its trailing newline or
+// headers. It returns io.EOF once the input is exhausted.
+func (m *messageReader) readMessage() ([]byte, error) {
+	if m.framing == FramingContentLength {
+		return m.readFramed()
+	}
+
+	var line []byte
+	for {
+		chunk, err := m.r.ReadSlice('\n')
+		if len(line)+len(chunk) > maxMessageSize+1 {
+			// Resynchronize on the next newline
+			if !errors.Is(err, bufio.ErrBufferFull) {
+				return nil, errMessageTooLarge
+			}
+			if err := m.discardLine(); err != nil && err != io.EOF {
+				return nil, err
+			}
+			return nil, errMessageTooLarge
+		}
+		line = append(line, chunk...)
+
+		switch {
+		case err == nil:
+			return bytes.TrimRight(line, "\r\n"), nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF && len(line) > 0:
+			return line, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (m *messageReader) discardLine() error {
+	for {
+		_, err := m.r.ReadSlice('\n')
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return err
+		}
+	}
+}
+
+// readFramed reads a message preceded by headers such as
+// "Content-Length: 42", ended by an empty line. Headers other than
+// Content-Length are ignored.
+func (m *messageReader) readFramed() ([]byte, error) {
+	length, headers := -1, 0
+	for {
+		line, err := m.r.ReadSlice('\n')
+		switch {
+		case err == io.EOF && headers == 0 && len(line) == 0:
+			return nil, io.EOF
+		case err == io.EOF:
+			return nil, io.ErrUnexpectedEOF
+		case errors.Is(err, bufio.ErrBufferFull):
+			return nil, fmt.Errorf("%w: header line too long", errInvalidFrame)
+		case err != nil:
+			return nil, err
+		}
+
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			// Tolerate blank lines between messages
+			if headers == 0 {
+				continue
+			}
+			break
+		}
+		headers++
+
+		name, value, found := strings.Cut(string(line), ":")
+		if !found {
+			return nil, fmt.Errorf("%w: %q", errInvalidFrame, line)
+		}
+		if textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(name)) == "Content-Length" {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: bad Content-Length %q", errInvalidFrame, value)
+			}
+			length = n
+		}
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("%w: missing Content-Length", errInvalidFrame)
+	}
+	if length > maxMessageSize {
+		if _, err := m.r.Discard(length); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return nil, errMessageTooLarge
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(m.r, body); err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return bytes.TrimSpace(body), nil
+}
+
+// unexpectedEOF reports input that ends in the middle of a message
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
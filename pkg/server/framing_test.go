@@ -0,0 +1,153 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestMessageReader_Lines(t *testing.T) {
+	r := newMessageReader(strings.NewReader("{\"a\":1}\r\n\n{\"b\":2}"))
+
+	want := []string{`{"a":1}`, ``, `{"b":2}`}
+	for _, w := range want {
+		line, err := r.readMessage()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(line) != w {
+			t.Errorf("expected %q, got %q", w, line)
+		}
+	}
+
+	if _, err := r.readMessage(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestMessageReader_OversizedMessageResyncs(t *testing.T) {
+	input := strings.Repeat("x", maxMessageSize+10) + "\n{\"ok\":true}\n"
+	r := newMessageReader(strings.NewReader(input))
+
+	if _, err := r.readMessage(); !errors.Is(err, errMessageTooLarge) {
+		t.Fatalf("expected errMessageTooLarge, got %v", err)
+	}
+
+	line, err := r.readMessage()
+	if err != nil {
+		t.Fatalf("unexpected error after resync: %v", err)
+	}
+	if string(line) != `{"ok":true}` {
+		t.Errorf("expected next message after oversized one, got %q", line)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestRun_ReportsOversizedMessage(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	input := strings.Repeat("x", maxMessageSize+1) + "\n" + `{"jsonrpc":"2.0","id":1,"method":"initialize"}` + "\n"
+	if err := s.Run(strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 responses, got %d: %q", len(lines), out.String())
+	}
+
+	var errResp JSONRPCResponse
+	json.Unmarshal([]byte(lines[0]), &errResp)
+	if errResp.Error == nil || errResp.Error.Code != -32600 {
+		t.Errorf("expected invalid request error, got %s", lines[0])
+	}
+
+	var initResp JSONRPCResponse
+	json.Unmarshal([]byte(lines[1]), &initResp)
+	if initResp.Error != nil || initResp.Result == nil {
+		t.Errorf("expected initialize to succeed after resync, got %s", lines[1])
+	}
+}
+
+func TestRun_ReturnsReadError(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	if err := s.Run(failingReader{}); err == nil {
+		t.Error("expected read error to be returned")
+	}
+}
+
+func framed(body string) string {
+	return fmt.Sprintf("Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func TestMessageReader_ContentLength(t *testing.T) {
+	input := framed("{\"a\":\n1}") +
+		"\r\ncontent-length: 7\r\nContent-Type: application/vscode-jsonrpc; charset=utf-8\r\n\r\n{\"b\":2}" +
+		"Content-Length: " + fmt.Sprint(maxMessageSize+1) + "\r\n\r\n" + strings.Repeat("x", maxMessageSize+1) +
+		framed(`{"c":3}`)
+	r := newMessageReader(strings.NewReader(input))
+	r.framing = FramingContentLength
+
+	for _, want := range []string{"{\"a\":\n1}", `{"b":2}`} {
+		msg, err := r.readMessage()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(msg) != want {
+			t.Errorf("expected %q, got %q", want, msg)
+		}
+	}
+	if _, err := r.readMessage(); !errors.Is(err, errMessageTooLarge) {
+		t.Fatalf("expected errMessageTooLarge, got %v", err)
+	}
+	if msg, err := r.readMessage(); err != nil || string(msg) != `{"c":3}` {
+		t.Errorf("expected the message after the oversized one, got %q, %v", msg, err)
+	}
+	if _, err := r.readMessage(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestMessageReader_InvalidFrames(t *testing.T) {
+	tests := map[string]error{
+		"{\"a\":1}\n\n":                    errInvalidFrame,
+		"Content-Type: text/plain\r\n\r\n": errInvalidFrame,
+		"Content-Length: ten\r\n\r\n":      errInvalidFrame,
+		"Content-Length: 10\r\n\r\n{}":     io.ErrUnexpectedEOF,
+		"Content-Length: 10\r\n":           io.ErrUnexpectedEOF,
+	}
+	for input, want := range tests {
+		r := newMessageReader(strings.NewReader(input))
+		r.framing = FramingContentLength
+		if _, err := r.readMessage(); !errors.Is(err, want) {
+			t.Errorf("%q: expected %v, got %v", input, want, err)
+		}
+	}
+}
+
+func TestRun_ContentLengthFraming(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+	s.framing = FramingContentLength
+
+	if err := s.Run(strings.NewReader(framed(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := newMessageReader(out)
+	r.framing = FramingContentLength
+	msg, err := r.readMessage()
+	if err != nil || string(msg) != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("expected a framed ping reply, got %q, %v (output %q)", msg, err, out.String())
+	}
+}
@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	maxFreeBusyCalendars = gcal.MaxFreeBusyCalendars
+	// maxFreeBusyDays is the longest window Google answers free/busy
+	// queries for
+	maxFreeBusyDays = 60
+)
+
+// freeBusyInput is the arguments of freebusy_query
+type freeBusyInput struct {
+	// Calendars to check: email addresses of people or rooms, calendar IDs
+	// or names from your calendar list
+	Calendars []string `json:"calendars" jsonschema:"required,maxItems=maxFreeBusyCalendars"`
+	// First day of the window in YYYY-MM-DD format (default: today)
+	StartDate string `json:"start_date"`
+	// Last day of the window in YYYY-MM-DD format (default: start_date, max
+	// range: 60 days)
+	EndDate string `json:"end_date"`
+	// Start of the window on start_date in HH:MM format (default: 00:00)
+	StartTime string `json:"start_time"`
+	// End of the window on end_date in HH:MM format (default: the end of
+	// the day)
+	EndTime string `json:"end_time"`
+}
+
+// freeBusyReport is when each calendar is busy in a window
+type freeBusyReport struct {
+	TimeMin   string              `json:"time_min"`
+	TimeMax   string              `json:"time_max"`
+	Calendars []gcal.BusyCalendar `json:"calendars"`
+}
+
+func (s *Server) callFreeBusyQuery(ctx context.Context, input freeBusyInput) (freeBusyReport, error) {
+	if len(input.Calendars) == 0 {
+		return freeBusyReport{}, badArgumentf("calendars is required")
+	}
+	if len(input.Calendars) > maxFreeBusyCalendars {
+		return freeBusyReport{}, badArgumentf("at most %d calendars can be given", maxFreeBusyCalendars)
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return freeBusyReport{}, badArgument(err)
+		}
+	}
+	if input.StartDate == "" {
+		input.StartDate = time.Now().In(s.settings().location).Format("2006-01-02")
+	}
+	if input.EndDate == "" {
+		input.EndDate = input.StartDate
+	}
+	if err := checkRange(input.StartDate, input.EndDate, maxFreeBusyDays); err != nil {
+		return freeBusyReport{}, err
+	}
+
+	timeMin, err := time.ParseInLocation("2006-01-02", input.StartDate, s.settings().location)
+	if err != nil {
+		return freeBusyReport{}, badArgument(err)
+	}
+	lastDay, err := time.ParseInLocation("2006-01-02", input.EndDate, s.settings().location)
+	if err != nil {
+		return freeBusyReport{}, badArgument(err)
+	}
+	timeMax := lastDay.AddDate(0, 0, 1)
+	if input.StartTime != "" {
+		if timeMin, err = atClock(timeMin, input.StartTime); err != nil {
+			return freeBusyReport{}, badArgumentf("invalid start_time: %v", err)
+		}
+	}
+	if input.EndTime != "" {
+		if timeMax, err = atClock(lastDay, input.EndTime); err != nil {
+			return freeBusyReport{}, badArgumentf("invalid end_time: %v", err)
+		}
+	}
+	if !timeMax.After(timeMin) {
+		return freeBusyReport{}, badArgumentf("the window ends before it starts")
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, name := range input.Calendars {
+		id, err := s.resolveCalendar(ctx, name)
+		if err != nil {
+			return freeBusyReport{}, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	calendars, err := s.settings().calendar.QueryFreeBusy(ctx, ids, timeMin, timeMax)
+	if err != nil {
+		return freeBusyReport{}, err
+	}
+	return freeBusyReport{
+		TimeMin:   timeMin.Format(time.RFC3339),
+		TimeMax:   timeMax.Format(time.RFC3339),
+		Calendars: calendars,
+	}, nil
+}
+
+// atClock returns day at the time of an HH:MM argument
+func atClock(day time.Time, clock string) (time.Time, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(clock))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected HH:MM, got %q", clock)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), 0, 0, day.Location()), nil
+}
+
+func (r freeBusyReport) toolText(s *Server) string { return s.formatFreeBusy(r) }
+
+// allFailed makes the call an error when no calendar could be checked
+func (r freeBusyReport) allFailed() bool {
+	for _, c := range r.Calendars {
+		if c.Error == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Server) formatFreeBusy(r freeBusyReport) string {
+	start, _ := time.Parse(time.RFC3339, r.TimeMin)
+	end, _ := time.Parse(time.RFC3339, r.TimeMax)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Busy times from %s to %s (%s)\n", start.In(s.settings().location).Format("Mon 2 Jan 15:04"), end.In(s.settings().location).Format("Mon 2 Jan 15:04"), s.settings().location)
+
+	for _, c := range r.Calendars {
+		fmt.Fprintf(&b, "\n%s:\n", c.ID)
+		switch {
+		case c.Error == "notFound":
+			b.WriteString("- unknown, or its free/busy information isn't shared with you\n")
+		case c.Error != "":
+			fmt.Fprintf(&b, "- couldn't be checked: %s\n", c.Error)
+		case len(c.Busy) == 0:
+			b.WriteString("- free the whole time\n")
+		}
+		for _, block := range c.Busy {
+			fmt.Fprintf(&b, "- %s\n", s.formatBusyBlock(block))
+		}
+	}
+	return b.String()
+}
+
+// formatBusyBlock shows a busy block in the server's timezone, with the end
+// day only when it differs from the start day
+func (s *Server) formatBusyBlock(block gcal.BusyBlock) string {
+	start, err := time.Parse(time.RFC3339, block.Start)
+	if err != nil {
+		return block.Start + " - " + block.End
+	}
+	end, err := time.Parse(time.RFC3339, block.End)
+	if err != nil {
+		return block.Start + " - " + block.End
+	}
+	start, end = start.In(s.settings().location), end.In(s.settings().location)
+	text := fmt.Sprintf("%s-", start.Format("Mon 2 Jan 15:04"))
+	if start.Format("2006-01-02") == end.Format("2006-01-02") {
+		return text + end.Format("15:04")
+	}
+	return text + end.Format("Mon 2 Jan 15:04")
+}
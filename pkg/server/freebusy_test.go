@@ -0,0 +1,61 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestCallFreeBusyQuery(t *testing.T) {
+	fake := &fakeCalendar{busy: map[string][]gcal.BusyBlock{
+		"ana@example.com": {
+			{Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:30:00Z"},
+			{Start: "2026-03-16T16:00:00Z", End: "2026-03-17T09:00:00Z"},
+		},
+		"room-4@example.com": {},
+	}}
+	s := newTestServer(fake)
+
+	resp := callWithArgs(s, toolFreeBusy, `{"calendars":["ana@example.com","room-4@example.com","bob@example.com"],"start_date":"16.03.2026","start_time":"09:00","end_time":"18:00"}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if !fake.freeBusyMin.Equal(time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)) || !fake.freeBusyMax.Equal(time.Date(2026, 3, 16, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected window %s to %s", fake.freeBusyMin, fake.freeBusyMax)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{
+		"Busy times from Mon 16 Mar 09:00 to Mon 16 Mar 18:00 (UTC)",
+		"ana@example.com:\n- Mon 16 Mar 10:00-11:30\n- Mon 16 Mar 16:00-Tue 17 Mar 09:00\n",
+		"room-4@example.com:\n- free the whole time\n",
+		"bob@example.com:\n- unknown, or its free/busy information isn't shared with you\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	// Without times the window covers whole days
+	callWithArgs(s, toolFreeBusy, `{"calendars":["ana@example.com"],"start_date":"2026-03-16","end_date":"2026-03-17"}`)
+	if !fake.freeBusyMax.Equal(time.Date(2026, 3, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the window to end after the last day, got %s", fake.freeBusyMax)
+	}
+
+	resp = callWithArgs(s, toolFreeBusy, `{"calendars":["bob@example.com"]}`)
+	if resp.Error != nil || resp.Result.(map[string]interface{})["isError"] != true {
+		t.Errorf("expected an error result when no calendar could be checked, got %+v", resp)
+	}
+
+	for _, args := range []string{
+		`{"calendars":[]}`,
+		`{"calendars":["ana@example.com"],"start_date":"2026-03-16","end_time":"25:00"}`,
+		`{"calendars":["ana@example.com"],"start_date":"2026-03-16","start_time":"12:00","end_time":"11:00"}`,
+		`{"calendars":["ana@example.com"],"start_date":"2026-03-01","end_date":"2026-06-01"}`,
+	} {
+		if resp := callWithArgs(s, toolFreeBusy, args); resp.Error == nil {
+			t.Errorf("%s: expected an invalid argument error", args)
+		}
+	}
+}
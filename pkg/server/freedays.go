@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+)
+
+const defaultFreeDaysRange = 14
+
+// freeStreak is a run of consecutive meeting-free days. Days outside the
+// considered weekdays don't interrupt it.
+type freeStreak struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Days  int    `json:"days"`
+}
+
+type freeDaysReport struct {
+	StartDate string `json:"startDate"`
+	EndDate   string `json:"endDate"`
+	// Considered is the number of days checked: the working days of the
+	// range, or all of them with weekends included
+	Considered    int         `json:"considered"`
+	FreeDays      []string    `json:"freeDays"`
+	LongestStreak *freeStreak `json:"longestStreak,omitempty"`
+	// weekends is whether weekends were considered, for the text
+	weekends bool
+}
+
+// meetingFreeDays finds the days without meetings during working hours
+func meetingFreeDays(a timeAnalysis) freeDaysReport {
+	report := freeDaysReport{StartDate: a.StartDate, EndDate: a.EndDate, Considered: len(a.Days), FreeDays: []string{}}
+
+	var current freeStreak
+	for _, d := range a.Days {
+		if d.Meetings > 0 {
+			current = freeStreak{}
+			continue
+		}
+		report.FreeDays = append(report.FreeDays, d.Date)
+		if current.Days == 0 {
+			current.Start = d.Date
+		}
+		current.End = d.Date
+		current.Days++
+		if report.LongestStreak == nil || current.Days > report.LongestStreak.Days {
+			longest := current
+			report.LongestStreak = &longest
+		}
+	}
+	return report
+}
+
+// meetingFreeDaysInput is the arguments of meeting_free_days
+type meetingFreeDaysInput struct {
+	// First day in YYYY-MM-DD format (default: today)
+	StartDate string `json:"start_date"`
+	// Number of days to check (default: 14, max: 90)
+	Days int `json:"days" jsonschema:"default=defaultFreeDaysRange,maximum=maxAnalysisDays"`
+	calendarArg
+	// Also check days outside the working week; by default they are skipped
+	// and don't break a streak
+	IncludeWeekends bool `json:"include_weekends"`
+}
+
+func (s *Server) callMeetingFreeDays(ctx context.Context, input meetingFreeDaysInput) (freeDaysReport, error) {
+	if input.Days == 0 {
+		input.Days = defaultFreeDaysRange
+	}
+	if input.Days < 0 || input.Days > maxAnalysisDays {
+		return freeDaysReport{}, badArgumentf("days must be between 1 and %d", maxAnalysisDays)
+	}
+
+	first := time.Now().In(s.settings().location)
+	if input.StartDate != "" {
+		if err := s.normalizeDateArg(&input.StartDate); err != nil {
+			return freeDaysReport{}, badArgument(err)
+		}
+		t, err := time.ParseInLocation("2006-01-02", input.StartDate, s.settings().location)
+		if err != nil {
+			return freeDaysReport{}, badArgument(err)
+		}
+		first = t
+	}
+	first = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, s.settings().location)
+
+	end := first.AddDate(0, 0, input.Days-1)
+	events, err := s.listCalendarRange(ctx, input.Calendar, first.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return freeDaysReport{}, err
+	}
+
+	hours := s.workHours
+	if input.IncludeWeekends {
+		hours.days = maps.Clone(hours.days)
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			hours.days[d] = true
+		}
+	}
+	report := meetingFreeDays(analyzeTime(events, first, input.Days, hours))
+	report.weekends = input.IncludeWeekends
+	return report, nil
+}
+
+func (r freeDaysReport) toolText(*Server) string { return formatFreeDays(r, r.weekends) }
+
+func formatFreeDays(r freeDaysReport, weekends bool) string {
+	var b strings.Builder
+	scope := "working days"
+	if weekends {
+		scope = "all days"
+	}
+	fmt.Fprintf(&b, "Meeting-free days %s to %s (%s): %d of %d\n", r.StartDate, r.EndDate, scope, len(r.FreeDays), r.Considered)
+	if len(r.FreeDays) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	for _, date := range r.FreeDays {
+		day, _ := time.Parse("2006-01-02", date)
+		fmt.Fprintf(&b, "- %s %s\n", day.Format("Mon"), date)
+	}
+	if streak := r.LongestStreak; streak != nil {
+		fmt.Fprintf(&b, "\nLongest meeting-free streak: %d day(s), %s to %s\n", streak.Days, streak.Start, streak.End)
+	}
+	return b.String()
+}
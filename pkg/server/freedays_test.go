@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestMeetingFreeDays(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		// Monday 2026-03-16 and Wednesday 2026-03-18 have meetings
+		{ID: "1", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+		{ID: "2", Start: "2026-03-18T10:00:00Z", End: "2026-03-18T11:00:00Z"},
+		// An evening event is outside working hours
+		{ID: "3", Start: "2026-03-19T19:00:00Z", End: "2026-03-19T20:00:00Z"},
+		// All-day events don't count as meetings
+		{ID: "4", Start: "2026-03-20", End: "2026-03-21"},
+	}
+	first := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	report := meetingFreeDays(analyzeTime(events, first, 10, defaultWorkHours))
+
+	want := []string{"2026-03-17", "2026-03-19", "2026-03-20", "2026-03-23", "2026-03-24", "2026-03-25"}
+	if strings.Join(report.FreeDays, ",") != strings.Join(want, ",") || report.Considered != 8 {
+		t.Errorf("unexpected free days %v of %d", report.FreeDays, report.Considered)
+	}
+	// The weekend doesn't break the streak
+	if s := report.LongestStreak; s == nil || s.Start != "2026-03-19" || s.End != "2026-03-25" || s.Days != 5 {
+		t.Errorf("unexpected longest streak %+v", s)
+	}
+
+	busy := meetingFreeDays(analyzeTime(events, first, 1, defaultWorkHours))
+	if len(busy.FreeDays) != 0 || busy.LongestStreak != nil {
+		t.Errorf("expected no free days, got %+v", busy)
+	}
+}
+
+func TestCallMeetingFreeDays(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Sync", Start: "2026-03-20T09:00:00Z", End: "2026-03-20T09:30:00Z"},
+	}}
+	s := newTestServer(fake)
+
+	args := json.RawMessage(`{"start_date":"2026-03-20","days":3,"include_weekends":true}`)
+	resp := s.callTool(context.Background(), &toolCall{name: toolMeetingFree, id: float64(1), args: args})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastStart != "2026-03-20" || fake.lastEnd != "2026-03-22" {
+		t.Errorf("unexpected range %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"(all days): 2 of 3", "- Sat 2026-03-21", "Longest meeting-free streak: 2 day(s), 2026-03-21 to 2026-03-22"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if s.workHours.days[time.Saturday] {
+		t.Error("include_weekends must not change the configured working days")
+	}
+
+	resp = s.callTool(context.Background(), &toolCall{name: toolMeetingFree, id: float64(2), args: json.RawMessage(`{"days":365}`)})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for too many days, got %+v", resp.Error)
+	}
+}
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// toolHandler answers a tools/call request for one tool
+type toolHandler func(s *Server, ctx context.Context, call *toolCall) *JSONRPCResponse
+
+// toolInput is implemented by the argument structs of tools; schemagen
+// generates the method along with the schema
+type toolInput interface {
+	inputSchema() map[string]interface{}
+}
+
+// toolOutput is implemented by what tools return. The text is the content
+// the model reads; the output itself becomes structuredContent unless it is
+// textOnly.
+type toolOutput interface {
+	toolText(s *Server) string
+}
+
+// textOnly outputs have no structured form
+type textOnly interface {
+	textOnly()
+}
+
+// contentOutput outputs add content blocks, such as resource links, after
+// the text
+type contentOutput interface {
+	toolContent(s *Server) []map[string]interface{}
+}
+
+// partialOutput outputs report several operations of which all may have
+// failed without the call itself failing, e.g. creating an event on several
+// calendars. The result is flagged isError when nothing succeeded.
+type partialOutput interface {
+	allFailed() bool
+}
+
+// textOutput is the output of tools that answer with text alone
+type textOutput string
+
+func (t textOutput) toolText(*Server) string { return string(t) }
+func (textOutput) textOnly()                 {}
+
+// argumentError is an argument a handler rejects. Like malformed requests,
+// it is answered with a JSON-RPC error rather than a failed tool result.
+type argumentError struct {
+	err error
+}
+
+func (e *argumentError) Error() string { return e.err.Error() }
+func (e *argumentError) Unwrap() error { return e.err }
+
+func badArgument(err error) error {
+	return &argumentError{err: err}
+}
+
+func badArgumentf(format string, args ...interface{}) error {
+	return badArgument(fmt.Errorf(format, args...))
+}
+
+// registerTool adds a tool answered by fn to toolDefinitions. Its input
+// schema is that of I; the arguments of a call are validated against it
+// and decoded into I, and what fn returns is turned into the result:
+// argument errors into -32602 errors, other errors into failed tool results
+// with an error code, and outputs into text, at the verbosity the call asks
+// for, and structured content. Unless the tool is local, calls go through
+// the circuit breaker; all of them are bounded by the configured tool
+// timeouts. Responses of calls that create events warn when the server nears
+// Google's daily limit.
+func registerTool[I toolInput, O toolOutput](def toolDefinition, fn func(s *Server, ctx context.Context, input I) (O, error)) {
+	var zero I
+	def.inputSchema = zero.inputSchema()
+	def.handler = func(s *Server, ctx context.Context, call *toolCall) *JSONRPCResponse {
+		var input I
+		if len(call.args) > 0 {
+			if err := json.Unmarshal(call.args, &input); err != nil {
+				return s.paramError(call.id, "Invalid arguments", err.Error())
+			}
+		}
+
+		if !def.local {
+			if err := s.breaker.allow(); err != nil {
+				return s.errorResponse(call.id, err)
+			}
+		}
+		mutating := def.mutating || def.mutates != nil && def.mutates(s, call.args)
+		// created counts the events this call creates; only calls that
+		// create events, or are refused for the usage limits, warn about
+		// them
+		var created atomic.Int32
+		if s.usage != nil && mutating {
+			ctx = gcal.WithCreatedReporter(ctx, func() {
+				created.Add(1)
+				s.usage.add()
+			})
+		}
+		timeout := s.toolTimeouts.of(def.name)
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		out, err := fn(s, ctx, input)
+		if err != nil && timeout > 0 && ctx.Err() == context.DeadlineExceeded {
+			err = withErrorCode(errCodeTimeout, fmt.Errorf("%s did not finish within %s", s.exposedToolName(def.name), timeout))
+		}
+		if !def.local {
+			s.breaker.record(err)
+		}
+
+		var argErr *argumentError
+		if errors.As(err, &argErr) {
+			return s.paramError(call.id, argErr.Error(), nil)
+		}
+		if mutating {
+			s.audit.record(ctx, def.name, call.args, auditStatus(out, err), err)
+		}
+		var warning string
+		if created.Load() > 0 || mutating && err != nil && errorCode(err) == errCodeRateLimited {
+			warning = s.usage.warning()
+		}
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				if info := gcal.RequestInfoFrom(ctx).String(); info != "" {
+					log.Printf("Tool %s failed (%s): %v", def.name, info, err)
+				} else {
+					log.Printf("Tool %s failed: %v", def.name, err)
+				}
+			}
+			resp := s.errorResponse(call.id, err)
+			if warning != "" {
+				addTextContent(resp, warning)
+			}
+			return resp
+		}
+
+		var resp *JSONRPCResponse
+		text := s.outputText(out, call.verbosity)
+		if _, ok := any(out).(textOnly); ok {
+			resp = s.successResponse(call.id, text)
+		} else {
+			resp = s.structuredResponse(call.id, text, out)
+		}
+		if c, ok := any(out).(contentOutput); ok {
+			for _, block := range c.toolContent(s) {
+				// Terse answers leave out links, resource links included
+				if call.verbosity == verbosityTerse && block["type"] == "resource_link" {
+					continue
+				}
+				addContent(resp, block)
+			}
+		}
+		if p, ok := any(out).(partialOutput); ok && p.allFailed() {
+			resp.Result.(map[string]interface{})["isError"] = true
+		}
+		if warning != "" {
+			addTextContent(resp, warning)
+		}
+		return resp
+	}
+	toolDefinitions = append(toolDefinitions, def)
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type echoInput struct {
+	Text string `json:"text"`
+}
+
+func (echoInput) inputSchema() map[string]interface{} {
+	return map[string]interface{}{"type": "object"}
+}
+
+type echoOutput struct {
+	Text   string `json:"text"`
+	failed bool
+}
+
+func (o echoOutput) toolText(*Server) string { return o.Text }
+func (o echoOutput) allFailed() bool         { return o.failed }
+
+// registerTestTool registers a tool for the duration of the test
+func registerTestTool[I toolInput, O toolOutput](t *testing.T, fn func(s *Server, ctx context.Context, input I) (O, error)) toolDefinition {
+	saved := toolDefinitions
+	t.Cleanup(func() { toolDefinitions = saved })
+	toolDefinitions = nil
+	registerTool(toolDefinition{name: "echo"}, fn)
+	return toolDefinitions[0]
+}
+
+func TestRegisterTool(t *testing.T) {
+	def := registerTestTool(t, func(_ *Server, _ context.Context, input echoInput) (echoOutput, error) {
+		switch input.Text {
+		case "bad":
+			return echoOutput{}, badArgumentf("text must not be %q", input.Text)
+		case "boom":
+			return echoOutput{}, errors.New("boom")
+		}
+		return echoOutput{Text: input.Text, failed: input.Text == ""}, nil
+	})
+	if def.inputSchema["type"] != "object" {
+		t.Errorf("expected the schema of echoInput, got %v", def.inputSchema)
+	}
+
+	s := newTestServer(&fakeCalendar{})
+	s.setProtocolVersion(protocolVersion20250618)
+	call := func(args string) *JSONRPCResponse {
+		return def.handler(s, context.Background(), &toolCall{id: float64(1), name: "echo", args: json.RawMessage(args)})
+	}
+
+	result := call(`{"text":"hi"}`).Result.(map[string]interface{})
+	if text := result["content"].([]map[string]interface{})[0]["text"]; text != "hi" {
+		t.Errorf("expected the output text, got %v", text)
+	}
+	if out, ok := result["structuredContent"].(echoOutput); !ok || out.Text != "hi" {
+		t.Errorf("expected the output as structuredContent, got %v", result["structuredContent"])
+	}
+	if _, ok := result["isError"]; ok {
+		t.Error("a successful call should not be flagged isError")
+	}
+
+	if resp := call(`{"text":"bad"}`); resp.Error == nil || resp.Error.Code != -32602 || resp.Error.Message != `text must not be "bad"` {
+		t.Errorf("expected an argument error to be a -32602 error, got %+v", resp.Error)
+	}
+	if resp := call(`{"text":1}`); resp.Error == nil || resp.Error.Message != "Invalid arguments" {
+		t.Errorf("expected undecodable arguments to be rejected, got %+v", resp.Error)
+	}
+	if result := call(`{"text":"boom"}`).Result.(map[string]interface{}); result["isError"] != true {
+		t.Error("expected other errors to be failed tool results")
+	}
+	if result := call(``).Result.(map[string]interface{}); result["isError"] != true {
+		t.Error("expected a partial output where everything failed to be flagged isError")
+	}
+}
+
+func TestRegisterTool_TextOnly(t *testing.T) {
+	def := registerTestTool(t, func(_ *Server, _ context.Context, input echoInput) (textOutput, error) {
+		return textOutput(input.Text), nil
+	})
+
+	s := newTestServer(&fakeCalendar{})
+	s.setProtocolVersion(protocolVersion20250618)
+	result := def.handler(s, context.Background(), &toolCall{id: float64(1), args: json.RawMessage(`{"text":"hi"}`)}).Result.(map[string]interface{})
+	if _, ok := result["structuredContent"]; ok {
+		t.Error("text-only outputs should have no structuredContent")
+	}
+	if text := result["content"].([]map[string]interface{})[0]["text"]; text != "hi" {
+		t.Errorf("expected the text, got %v", text)
+	}
+}
+
+func TestRegisterTool_Timeout(t *testing.T) {
+	def := registerTestTool(t, func(_ *Server, ctx context.Context, _ echoInput) (textOutput, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+
+	s := newTestServer(&fakeCalendar{})
+	s.toolTimeouts = toolTimeouts{"": 10 * time.Millisecond}
+	result := def.handler(s, context.Background(), &toolCall{id: float64(1)}).Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	if result["isError"] != true || !strings.Contains(text, "TIMEOUT") || !strings.Contains(text, "echo did not finish within 10ms") {
+		t.Errorf("expected a timeout error, got %q", text)
+	}
+}
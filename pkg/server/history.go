@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// defaultHistoryDays is how far back meeting_history looks without a
+// start_date
+const defaultHistoryDays = 90
+
+type pastMeeting struct {
+	Date    string  `json:"date"`
+	Summary string  `json:"summary"`
+	Hours   float64 `json:"hours"`
+	ID      string  `json:"id"`
+}
+
+// meetingHistory summarizes past meetings with a person or organization
+type meetingHistory struct {
+	Attendee    string        `json:"attendee"`
+	StartDate   string        `json:"startDate"`
+	EndDate     string        `json:"endDate"`
+	Count       int           `json:"count"`
+	TotalHours  float64       `json:"totalHours"`
+	LastMet     string        `json:"lastMet,omitempty"`
+	FirstMet    string        `json:"firstMet,omitempty"`
+	Meetings    []pastMeeting `json:"meetings"`
+	MatchedWith []string      `json:"matchedWith"`
+}
+
+// attendeeMatcher matches an exact address (alice@acme.com) or, given a
+// domain (acme.com or @acme.com), everyone at that domain and its
+// subdomains
+func attendeeMatcher(query string) func(email string) bool {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if at := strings.Index(query, "@"); at > 0 {
+		return func(email string) bool { return strings.EqualFold(email, query) }
+	}
+	domain := strings.TrimPrefix(query, "@")
+	return func(email string) bool {
+		email = strings.ToLower(email)
+		return strings.HasSuffix(email, "@"+domain) || strings.HasSuffix(email, "."+domain)
+	}
+}
+
+// buildMeetingHistory collects the events that ended before now in which an
+// attendee matching query took part without declining
+func buildMeetingHistory(events []gcal.CalendarEvent, query string, now time.Time) meetingHistory {
+	matches := attendeeMatcher(query)
+	history := meetingHistory{Attendee: query, Meetings: []pastMeeting{}, MatchedWith: []string{}}
+	seen := make(map[string]bool)
+
+	for _, e := range events {
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil || end.After(now) {
+			continue
+		}
+
+		matched := false
+		for _, g := range e.Guests {
+			if g.Self || g.ResponseStatus == "declined" || !matches(g.Email) {
+				continue
+			}
+			matched = true
+			if email := strings.ToLower(g.Email); !seen[email] {
+				seen[email] = true
+				history.MatchedWith = append(history.MatchedWith, email)
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		hours := end.Sub(start).Hours()
+		history.Count++
+		history.TotalHours += hours
+		history.Meetings = append(history.Meetings, pastMeeting{
+			Date:    start.Format("2006-01-02"),
+			Summary: e.Summary,
+			Hours:   roundHours(hours),
+			ID:      e.ID,
+		})
+	}
+
+	history.TotalHours = roundHours(history.TotalHours)
+	if n := len(history.Meetings); n > 0 {
+		history.FirstMet = history.Meetings[0].Date
+		history.LastMet = history.Meetings[n-1].Date
+	}
+	return history
+}
+
+// meetingHistoryInput is the arguments of meeting_history
+type meetingHistoryInput struct {
+	// Email address, or a domain such as acme.com to match everyone at that
+	// organization
+	Attendee string `json:"attendee" jsonschema:"required"`
+	// Start date in YYYY-MM-DD format (default: 90 days ago)
+	StartDate string `json:"start_date"`
+	// End date in YYYY-MM-DD format (default: today)
+	EndDate string `json:"end_date"`
+}
+
+func (s *Server) callMeetingHistory(ctx context.Context, input meetingHistoryInput) (meetingHistory, error) {
+	if strings.Trim(input.Attendee, " @") == "" {
+		return meetingHistory{}, badArgumentf("attendee is required (an email address or a domain such as acme.com)")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return meetingHistory{}, badArgument(err)
+		}
+	}
+
+	now := time.Now().In(s.settings().location)
+	if input.EndDate == "" {
+		input.EndDate = now.Format("2006-01-02")
+	}
+	if input.StartDate == "" {
+		input.StartDate = now.AddDate(0, 0, -defaultHistoryDays).Format("2006-01-02")
+	}
+
+	events, err := s.settings().calendar.ListEventsRange(ctx, input.StartDate, input.EndDate)
+	if err != nil {
+		return meetingHistory{}, err
+	}
+
+	history := buildMeetingHistory(events, input.Attendee, now)
+	history.StartDate, history.EndDate = input.StartDate, input.EndDate
+	return history, nil
+}
+
+func (h meetingHistory) toolText(s *Server) string { return s.formatMeetingHistory(h) }
+
+func (s *Server) formatMeetingHistory(h meetingHistory) string {
+	if h.Count == 0 {
+		return fmt.Sprintf("No meetings with %s between %s and %s.", h.Attendee, h.StartDate, h.EndDate)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Meetings with %s between %s and %s: %d, %s hours in total\n", h.Attendee, h.StartDate, h.EndDate, h.Count, formatHours(h.TotalHours))
+	fmt.Fprintf(&b, "Last met: %s (first: %s)\n", h.LastMet, h.FirstMet)
+	fmt.Fprintf(&b, "Attendees: %s\n\n", strings.Join(h.MatchedWith, ", "))
+	for i := len(h.Meetings) - 1; i >= 0; i-- {
+		m := h.Meetings[i]
+		fmt.Fprintf(&b, "- %s %s (%sh)\n", m.Date, s.sanitize(m.Summary), formatHours(m.Hours))
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestBuildMeetingHistory(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		{ID: "1", Summary: "Kickoff", Start: "2026-02-02T10:00:00Z", End: "2026-02-02T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "me@example.com", Self: true}, {Email: "Alice@acme.com", ResponseStatus: "accepted"}}},
+		{ID: "2", Summary: "Review", Start: "2026-02-10T10:00:00Z", End: "2026-02-10T10:30:00Z",
+			Guests: []gcal.Guest{{Email: "bob@eu.acme.com", ResponseStatus: "tentative"}}},
+		// Declined invitations aren't meetings
+		{ID: "3", Summary: "Sync", Start: "2026-02-12T10:00:00Z", End: "2026-02-12T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "alice@acme.com", ResponseStatus: "declined"}}},
+		{ID: "4", Summary: "Other", Start: "2026-02-13T10:00:00Z", End: "2026-02-13T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "carol@notacme.com"}}},
+		// Meetings that haven't happened yet don't count
+		{ID: "5", Summary: "Future", Start: "2026-03-02T10:00:00Z", End: "2026-03-02T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "alice@acme.com"}}},
+	}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	h := buildMeetingHistory(events, "acme.com", now)
+
+	if h.Count != 2 || h.TotalHours != 1.5 {
+		t.Errorf("expected 2 meetings over 1.5h, got %d over %v", h.Count, h.TotalHours)
+	}
+	if h.FirstMet != "2026-02-02" || h.LastMet != "2026-02-10" {
+		t.Errorf("unexpected first/last met %s %s", h.FirstMet, h.LastMet)
+	}
+	if strings.Join(h.MatchedWith, ",") != "alice@acme.com,bob@eu.acme.com" {
+		t.Errorf("unexpected matched attendees %v", h.MatchedWith)
+	}
+
+	h = buildMeetingHistory(events, "alice@acme.com", now)
+	if h.Count != 1 || h.Meetings[0].ID != "1" {
+		t.Errorf("expected exact email match only, got %+v", h.Meetings)
+	}
+}
+
+func TestCallMeetingHistory(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", Summary: "Kickoff", Start: "2026-02-02T10:00:00Z", End: "2026-02-02T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "alice@acme.com", ResponseStatus: "accepted"}}},
+	}}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"attendee": "@acme.com", "start_date": "2026-01-01", "end_date": "2026-02-28"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolMeetingHistory, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastStart != "2026-01-01" || fake.lastEnd != "2026-02-28" {
+		t.Errorf("unexpected range %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Last met: 2026-02-02") {
+		t.Errorf("unexpected report:\n%s", text)
+	}
+
+	resp = s.callTool(context.Background(), &toolCall{name: toolMeetingHistory, id: float64(2), args: json.RawMessage(`{"attendee":"@"}`)})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params, got %+v", resp.Error)
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	// defaultHygieneDays is how many past days hygiene_report inspects
+	defaultHygieneDays = 56
+	maxHygieneDays     = 365
+
+	// A recurring series is stale when nobody has edited it for this long
+	staleSeriesAge = 90 * 24 * time.Hour
+	// and at least this many of its recent instances were all declined
+	staleSeriesMinInstances = 3
+)
+
+// staleSeries is a recurring series that looks safe to cancel
+type staleSeries struct {
+	RecurringEventID string `json:"recurringEventId"`
+	Summary          string `json:"summary"`
+	LastEdited       string `json:"lastEdited"`
+	Instances        int    `json:"instances"`
+	Declined         int    `json:"declined"`
+}
+
+// hygieneReport lists calendar clutter worth cleaning up
+type hygieneReport struct {
+	StartDate   string        `json:"startDate"`
+	EndDate     string        `json:"endDate"`
+	StaleSeries []staleSeries `json:"staleSeries"`
+}
+
+// declinedInstance reports whether an instance effectively didn't happen:
+// the calendar owner declined it, or every other guest did
+func declinedInstance(e gcal.CalendarEvent) bool {
+	others, declined := 0, 0
+	for _, g := range e.Guests {
+		if g.Self {
+			if g.ResponseStatus == "declined" {
+				return true
+			}
+			continue
+		}
+		others++
+		if g.ResponseStatus == "declined" {
+			declined++
+		}
+	}
+	return others > 0 && declined == others
+}
+
+// findStaleSeries flags recurring series that haven't been edited for
+// staleSeriesAge and whose past instances were all declined
+func findStaleSeries(events []gcal.CalendarEvent, now time.Time) []staleSeries {
+	type seriesState struct {
+		staleSeries
+		lastEdited time.Time
+	}
+	bySeries := make(map[string]*seriesState)
+
+	for _, e := range events {
+		if e.RecurringEventID == "" {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil {
+			end, err = time.Parse("2006-01-02", e.End)
+		}
+		if err != nil || end.After(now) {
+			continue
+		}
+
+		state := bySeries[e.RecurringEventID]
+		if state == nil {
+			state = &seriesState{staleSeries: staleSeries{RecurringEventID: e.RecurringEventID}}
+			bySeries[e.RecurringEventID] = state
+		}
+		state.Summary = e.Summary
+		state.Instances++
+		if declinedInstance(e) {
+			state.Declined++
+		}
+		if updated, err := time.Parse(time.RFC3339, e.Updated); err == nil && updated.After(state.lastEdited) {
+			state.lastEdited = updated
+		}
+	}
+
+	result := []staleSeries{}
+	for _, state := range bySeries {
+		if state.Instances < staleSeriesMinInstances || state.Declined < state.Instances {
+			continue
+		}
+		if state.lastEdited.IsZero() || now.Sub(state.lastEdited) < staleSeriesAge {
+			continue
+		}
+		state.LastEdited = state.lastEdited.Format("2006-01-02")
+		result = append(result, state.staleSeries)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].LastEdited < result[j].LastEdited })
+	return result
+}
+
+// hygieneReportInput is the arguments of hygiene_report
+type hygieneReportInput struct {
+	// Number of past days to inspect (default: 56, max: 365)
+	Days int `json:"days" jsonschema:"default=defaultHygieneDays,maximum=maxHygieneDays"`
+}
+
+func (s *Server) callHygieneReport(ctx context.Context, input hygieneReportInput) (hygieneReport, error) {
+	if input.Days == 0 {
+		input.Days = defaultHygieneDays
+	}
+	if input.Days < 0 || input.Days > maxHygieneDays {
+		return hygieneReport{}, badArgumentf("days must be between 1 and %d", maxHygieneDays)
+	}
+
+	now := time.Now().In(s.settings().location)
+	report := hygieneReport{
+		StartDate: now.AddDate(0, 0, -input.Days).Format("2006-01-02"),
+		EndDate:   now.Format("2006-01-02"),
+	}
+	events, err := s.settings().calendar.ListEventsRange(ctx, report.StartDate, report.EndDate)
+	if err != nil {
+		return hygieneReport{}, err
+	}
+
+	report.StaleSeries = findStaleSeries(events, now)
+	return report, nil
+}
+
+func (r hygieneReport) toolText(s *Server) string { return s.formatHygieneReport(r) }
+
+func (s *Server) formatHygieneReport(r hygieneReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Calendar hygiene %s to %s\n\n", r.StartDate, r.EndDate)
+
+	if len(r.StaleSeries) == 0 {
+		b.WriteString("No stale recurring events found.\n")
+		return b.String()
+	}
+
+	b.WriteString("Stale recurring events (not edited for months, every recent instance declined), candidates for cancellation:\n")
+	for _, series := range r.StaleSeries {
+		fmt.Fprintf(&b, "- %s: %d of %d instance(s) declined, last edited %s\n  ID: %s\n",
+			s.sanitize(series.Summary), series.Declined, series.Instances, series.LastEdited, series.RecurringEventID)
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestFindStaleSeries(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	var events []gcal.CalendarEvent
+	for week := 1; week <= 4; week++ {
+		start := now.AddDate(0, 0, -7*week)
+		// Everyone else has stopped coming to the old sync
+		events = append(events, gcal.CalendarEvent{
+			ID: fmt.Sprintf("sync_%d", week), RecurringEventID: "sync", Summary: "Old sync",
+			Start: start.Format(time.RFC3339), End: start.Add(time.Hour).Format(time.RFC3339),
+			Updated: "2025-11-01T10:00:00Z",
+			Guests:  []gcal.Guest{{Email: "me@example.com", Self: true, ResponseStatus: "accepted"}, {Email: "a@example.com", ResponseStatus: "declined"}},
+		})
+		// A series that is still attended stays
+		events = append(events, gcal.CalendarEvent{
+			ID: fmt.Sprintf("team_%d", week), RecurringEventID: "team", Summary: "Team",
+			Start: start.Format(time.RFC3339), End: start.Add(time.Hour).Format(time.RFC3339),
+			Updated: "2025-11-01T10:00:00Z",
+			Guests:  []gcal.Guest{{Email: "a@example.com", ResponseStatus: "accepted"}},
+		})
+		// A recently edited series is left alone even if declined
+		events = append(events, gcal.CalendarEvent{
+			ID: fmt.Sprintf("new_%d", week), RecurringEventID: "new", Summary: "New",
+			Start: start.Format(time.RFC3339), End: start.Add(time.Hour).Format(time.RFC3339),
+			Updated: "2026-05-01T10:00:00Z",
+			Guests:  []gcal.Guest{{Email: "me@example.com", Self: true, ResponseStatus: "declined"}},
+		})
+	}
+
+	stale := findStaleSeries(events, now)
+
+	if len(stale) != 1 {
+		t.Fatalf("expected one stale series, got %+v", stale)
+	}
+	if stale[0].RecurringEventID != "sync" || stale[0].Instances != 4 || stale[0].Declined != 4 || stale[0].LastEdited != "2025-11-01" {
+		t.Errorf("unexpected stale series %+v", stale[0])
+	}
+}
+
+func TestDeclinedInstance(t *testing.T) {
+	tests := []struct {
+		guests []gcal.Guest
+		want   bool
+	}{
+		{nil, false},
+		{[]gcal.Guest{{Email: "me", Self: true, ResponseStatus: "declined"}, {Email: "a", ResponseStatus: "accepted"}}, true},
+		{[]gcal.Guest{{Email: "me", Self: true, ResponseStatus: "accepted"}, {Email: "a", ResponseStatus: "declined"}, {Email: "b", ResponseStatus: "tentative"}}, false},
+		{[]gcal.Guest{{Email: "a", ResponseStatus: "declined"}, {Email: "b", ResponseStatus: "declined"}}, true},
+	}
+	for i, tt := range tests {
+		if got := declinedInstance(gcal.CalendarEvent{Guests: tt.guests}); got != tt.want {
+			t.Errorf("case %d: expected %v, got %v", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	icsMimeType = "text/calendar"
+	icsProdID   = "-//cherya//google-calendar-mcp//EN"
+
+	// icsLineLimit is the maximum line length in octets before folding
+	icsLineLimit = 75
+
+	// Formats get_event renders an event in
+	eventFormatText = "text"
+	eventFormatICS  = "ics"
+)
+
+// eventToICS renders an event as an iCalendar (RFC 5545) object holding a
+// single VEVENT, suitable for saving as an .ics file or forwarding as an
+// invite
+func eventToICS(e *calendar.Event, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:" + icsProdID)
+	line("METHOD:PUBLISH")
+	line("BEGIN:VEVENT")
+
+	uid := e.ICalUID
+	if uid == "" {
+		uid = e.Id
+	}
+	line("UID:" + uid)
+	line("DTSTAMP:" + now.UTC().Format("20060102T150405Z"))
+	if v := icsDateTime("DTSTART", e.Start); v != "" {
+		line(v)
+	}
+	if v := icsDateTime("DTEND", e.End); v != "" {
+		line(v)
+	}
+	for _, rule := range e.Recurrence {
+		line(rule)
+	}
+	line("SUMMARY:" + escapeICSText(e.Summary))
+	if e.Description != "" {
+		line("DESCRIPTION:" + escapeICSText(e.Description))
+	}
+	if e.Location != "" {
+		line("LOCATION:" + escapeICSText(e.Location))
+	}
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		line("ORGANIZER:mailto:" + e.Organizer.Email)
+	}
+	for _, a := range e.Attendees {
+		if a.Email != "" {
+			line("ATTENDEE:mailto:" + a.Email)
+		}
+	}
+	if e.HtmlLink != "" {
+		line("URL:" + e.HtmlLink)
+	}
+	if e.Status != "" {
+		line("STATUS:" + strings.ToUpper(e.Status))
+	}
+
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// icsDateTime formats a start or end property. Timed events are written in
+// UTC, all-day events as dates.
+func icsDateTime(name string, t *calendar.EventDateTime) string {
+	if t == nil {
+		return ""
+	}
+	if t.DateTime != "" {
+		parsed, err := time.Parse(time.RFC3339, t.DateTime)
+		if err != nil {
+			return ""
+		}
+		return name + ":" + parsed.UTC().Format("20060102T150405Z")
+	}
+	if t.Date != "" {
+		return name + ";VALUE=DATE:" + strings.ReplaceAll(t.Date, "-", "")
+	}
+	return ""
+}
+
+var icsTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escapeICSText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, continuing them
+// on lines that start with a space. Folds never split a UTF-8 sequence.
+func foldICSLine(s string) string {
+	if len(s) <= icsLineLimit {
+		return s
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for _, r := range s {
+		size := utf8.RuneLen(r)
+		if lineLen+size > icsLineLimit {
+			b.WriteString("\r\n ")
+			// The leading space counts towards the continuation line
+			lineLen = 1
+		}
+		b.WriteRune(r)
+		lineLen += size
+	}
+	return b.String()
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestEventToICS_TimedEvent(t *testing.T) {
+	event := &calendar.Event{
+		Id:          "evt-1",
+		ICalUID:     "evt-1@google.com",
+		Summary:     "Planning; Q3, draft",
+		Description: "Agenda:\nbudget",
+		Start:       &calendar.EventDateTime{DateTime: "2026-03-20T10:00:00+01:00"},
+		End:         &calendar.EventDateTime{DateTime: "2026-03-20T11:30:00+01:00"},
+		Attendees:   []*calendar.EventAttendee{{Email: "alice@example.com"}},
+		Recurrence:  []string{"RRULE:FREQ=WEEKLY;COUNT=4"},
+	}
+	now := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	ics := eventToICS(event, now)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VEVENT\r\n",
+		"UID:evt-1@google.com\r\n",
+		"DTSTAMP:20260301T080000Z\r\n",
+		"DTSTART:20260320T090000Z\r\n",
+		"DTEND:20260320T103000Z\r\n",
+		"RRULE:FREQ=WEEKLY;COUNT=4\r\n",
+		"SUMMARY:Planning\\; Q3\\, draft\r\n",
+		"DESCRIPTION:Agenda:\\nbudget\r\n",
+		"ATTENDEE:mailto:alice@example.com\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("expected %q in:\n%s", want, ics)
+		}
+	}
+}
+
+func TestEventToICS_AllDayEvent(t *testing.T) {
+	event := &calendar.Event{
+		Id:      "evt-2",
+		Summary: "Holiday",
+		Start:   &calendar.EventDateTime{Date: "2026-05-01"},
+		End:     &calendar.EventDateTime{Date: "2026-05-02"},
+	}
+
+	ics := eventToICS(event, time.Now())
+
+	if !strings.Contains(ics, "UID:evt-2\r\n") {
+		t.Errorf("expected event ID as UID fallback:\n%s", ics)
+	}
+	if !strings.Contains(ics, "DTSTART;VALUE=DATE:20260501\r\n") || !strings.Contains(ics, "DTEND;VALUE=DATE:20260502\r\n") {
+		t.Errorf("expected date values for an all-day event:\n%s", ics)
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("é", 60)
+
+	folded := foldICSLine(line)
+
+	for i, part := range strings.Split(folded, "\r\n") {
+		if len(part) > icsLineLimit {
+			t.Errorf("line %d is %d octets long", i, len(part))
+		}
+		if i > 0 && !strings.HasPrefix(part, " ") {
+			t.Errorf("continuation line %d must start with a space", i)
+		}
+	}
+	if strings.ReplaceAll(folded, "\r\n ", "") != line {
+		t.Error("unfolding should restore the original line")
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	defaultInstanceDays = 30
+	maxInstanceDays     = 366
+)
+
+// seriesOccurrence is one occurrence of a recurring series
+type seriesOccurrence struct {
+	ID    string `json:"id"`
+	Start string `json:"start,omitempty"`
+	End   string `json:"end,omitempty"`
+	// OriginalStart is when the series pattern schedules the occurrence
+	OriginalStart string `json:"originalStart"`
+	// Kind is cancelled, moved or resized for exceptions, and empty for
+	// occurrences held as scheduled
+	Kind string `json:"kind,omitempty"`
+}
+
+type instanceList struct {
+	SeriesID    string             `json:"seriesId"`
+	Summary     string             `json:"summary"`
+	StartDate   string             `json:"startDate"`
+	EndDate     string             `json:"endDate"`
+	Occurrences []seriesOccurrence `json:"occurrences"`
+}
+
+// listInstancesInput is the arguments of list_recurring_instances
+type listInstancesInput struct {
+	// ID of the recurring series or of any of its instances
+	EventID string `json:"event_id" jsonschema:"required"`
+	// Start date in YYYY-MM-DD format (default: today)
+	StartDate string `json:"start_date"`
+	// End date in YYYY-MM-DD format (default: 30 days after start_date, max
+	// range: 366 days)
+	EndDate string `json:"end_date"`
+}
+
+func (s *Server) callListInstances(ctx context.Context, input listInstancesInput) (instanceList, error) {
+	if input.EventID == "" {
+		return instanceList{}, badArgumentf("event_id is required (the ID of the series or of any of its instances)")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return instanceList{}, badArgument(err)
+		}
+	}
+	if input.StartDate == "" {
+		input.StartDate = time.Now().In(s.settings().location).Format("2006-01-02")
+	}
+	if input.EndDate == "" {
+		start, _ := time.Parse("2006-01-02", input.StartDate)
+		input.EndDate = start.AddDate(0, 0, defaultInstanceDays).Format("2006-01-02")
+	}
+	if err := checkRange(input.StartDate, input.EndDate, maxInstanceDays); err != nil {
+		return instanceList{}, err
+	}
+
+	series, err := s.seriesOf(ctx, input.EventID)
+	if err != nil {
+		return instanceList{}, err
+	}
+	instances, err := s.settings().calendar.ListInstances(ctx, series.Id, input.StartDate, input.EndDate)
+	if err != nil {
+		return instanceList{}, err
+	}
+
+	length, _ := gcal.EventDuration(series)
+	list := instanceList{
+		SeriesID:    series.Id,
+		Summary:     series.Summary,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		Occurrences: []seriesOccurrence{},
+	}
+	for _, in := range instances {
+		occurrence := seriesOccurrence{ID: in.ID, OriginalStart: in.OriginalStart, Kind: exceptionKind(in, length)}
+		if occurrence.Kind != "cancelled" {
+			occurrence.Start, occurrence.End = in.Start, in.End
+		}
+		list.Occurrences = append(list.Occurrences, occurrence)
+	}
+	return list, nil
+}
+
+func (l instanceList) toolText(s *Server) string { return s.formatInstances(l) }
+
+func (s *Server) formatInstances(l instanceList) string {
+	var b strings.Builder
+	cancelled := 0
+	for _, o := range l.Occurrences {
+		if o.Kind == "cancelled" {
+			cancelled++
+		}
+	}
+	fmt.Fprintf(&b, "%s, %s to %s: %d occurrence(s)", s.sanitize(l.Summary), l.StartDate, l.EndDate, len(l.Occurrences))
+	if cancelled > 0 {
+		fmt.Fprintf(&b, ", %d cancelled", cancelled)
+	}
+	b.WriteString("\n")
+
+	for _, o := range l.Occurrences {
+		switch o.Kind {
+		case "cancelled":
+			fmt.Fprintf(&b, "- %s cancelled", s.occurrenceTime(o.OriginalStart))
+		case "moved":
+			fmt.Fprintf(&b, "- %s-%s, moved from %s", s.occurrenceTime(o.Start), clockOf(o.End, s.settings().location), s.occurrenceTime(o.OriginalStart))
+		case "resized":
+			fmt.Fprintf(&b, "- %s-%s, length changed", s.occurrenceTime(o.Start), clockOf(o.End, s.settings().location))
+		default:
+			if _, err := time.Parse(time.RFC3339, o.Start); err != nil {
+				// All-day occurrences
+				fmt.Fprintf(&b, "- %s", o.Start)
+				break
+			}
+			fmt.Fprintf(&b, "- %s-%s", s.occurrenceTime(o.Start), clockOf(o.End, s.settings().location))
+		}
+		fmt.Fprintf(&b, " (ID %s)\n", o.ID)
+	}
+	return b.String()
+}
+
+// occurrenceTime shows an RFC 3339 timestamp with its weekday in the
+// server's timezone
+func (s *Server) occurrenceTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.In(s.settings().location).Format("Mon 2 Jan 15:04")
+}
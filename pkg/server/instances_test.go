@@ -0,0 +1,47 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCallListInstances(t *testing.T) {
+	fake := &fakeCalendar{
+		fetched: &calendar.Event{
+			Id:         "w",
+			Summary:    "Weekly sync",
+			Recurrence: []string{"RRULE:FREQ=WEEKLY"},
+			Start:      &calendar.EventDateTime{DateTime: "2026-01-05T10:00:00Z"},
+			End:        &calendar.EventDateTime{DateTime: "2026-01-05T10:30:00Z"},
+		},
+		instances: weeklyInstances(),
+	}
+	s := newTestServer(fake)
+
+	resp := callWithArgs(s, toolListInstances, `{"event_id":"w","start_date":"2026-03-01"}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if fake.lastStart != "2026-03-01" || fake.lastEnd != "2026-03-31" {
+		t.Errorf("expected 30 days from start_date, got %s to %s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{
+		"Weekly sync, 2026-03-01 to 2026-03-31: 4 occurrence(s), 1 cancelled\n",
+		"- Mon 2 Mar 10:00-10:30 (ID w_1)\n",
+		"- Mon 9 Mar 10:00 cancelled (ID w_2)\n",
+		"- Tue 17 Mar 14:00-14:30, moved from Mon 16 Mar 10:00 (ID w_3)\n",
+		"- Mon 23 Mar 10:00-11:00, length changed (ID w_4)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	fake.fetched = &calendar.Event{Id: "once", Summary: "One-off"}
+	if resp := callWithArgs(s, toolListInstances, `{"event_id":"once"}`); resp.Result.(map[string]interface{})["isError"] != true {
+		t.Errorf("expected an error for an event that doesn't repeat, got %+v", resp)
+	}
+}
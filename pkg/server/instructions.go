@@ -0,0 +1,37 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// instructions describes the server's conventions to the client's model,
+// so that it doesn't have to guess formats for tool arguments
+func (s *Server) instructions(now time.Time) string {
+	today := now.In(s.settings().location)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Google Calendar server for calendar %s.\n", s.settings().calendar.CalendarID())
+	fmt.Fprintf(&b, "Timezone: %s. Today is %s, %s.\n", s.settings().location, today.Format("Monday"), today.Format("2006-01-02"))
+
+	b.WriteString("Dates: YYYY-MM-DD is preferred. DD.MM.YYYY is also accepted")
+	switch s.dateOrder {
+	case dateOrderDMY:
+		b.WriteString(", and slash dates are read day first (DD/MM/YYYY)")
+	case dateOrderMDY:
+		b.WriteString(", and slash dates are read month first (MM/DD/YYYY)")
+	default:
+		b.WriteString("; ambiguous slash dates such as 05/03/2026 are rejected")
+	}
+	b.WriteString(".\n")
+	b.WriteString("Times: HH:MM in 24-hour format, in the calendar timezone.\n")
+	fmt.Fprintf(&b, "Event IDs come from %s or %s; pass them to %s, %s and %s.\n",
+		s.exposedToolName(toolListEvents), s.exposedToolName(toolListEventsRange),
+		s.exposedToolName(toolGetEvent), s.exposedToolName(toolUpdateEvent), s.exposedToolName(toolDeleteEvent))
+	b.WriteString("Instead of copying an ID, you can pass event_ref with the event's position in the last listing, e.g. \"#2\".\n")
+	if s.isReadOnly() {
+		b.WriteString("The server is currently in read-only mode: events cannot be created, changed or deleted.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstructions(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.dateOrder = dateOrderDMY
+	s.toolPrefix = "work"
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+
+	text := s.instructions(now)
+
+	for _, want := range []string{
+		"calendar test@example.com",
+		"Timezone: UTC. Today is Wednesday, 2026-03-18.",
+		"slash dates are read day first",
+		"HH:MM in 24-hour format",
+		"work_list_events",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in instructions:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "read-only") {
+		t.Errorf("read-only note should only appear in read-only mode:\n%s", text)
+	}
+
+	s.readOnly.Store(true)
+	if text := s.instructions(now); !strings.Contains(text, "read-only mode") {
+		t.Errorf("expected read-only note:\n%s", text)
+	}
+}
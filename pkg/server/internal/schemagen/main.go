@@ -0,0 +1,269 @@
+// Command schemagen writes the JSON Schema of every tool's arguments from
+// the Go structs the tools decode them into, so that the schema clients see
+// and the decoding can't drift apart. It is run by go generate in
+// pkg/server:
+//
+//	//go:generate go run ./internal/schemagen
+//
+// Every struct type whose name ends in "Input" gets a schema variable named
+// after it with a "Schema" suffix, e.g. listEventsInputSchema, and an
+// inputSchema method returning it, which is how registerTool finds the
+// schema of a tool from its argument type. A field's doc comment becomes
+// its description, and a jsonschema tag adds the rest as comma-separated
+// options:
+//
+//	required           the argument must be given
+//	default=EXPR       the default value
+//	enum=EXPR|EXPR     the allowed values
+//	maximum=EXPR       the largest allowed number
+//	maxItems=EXPR      the most items of an array
+//	description=EXPR   the description, instead of the doc comment
+//	type=TYPE          the JSON type, for fields such as json.RawMessage
+//
+// EXPR is a Go expression copied into the generated code, usually a
+// constant of the package, so that defaults and limits stay those the
+// handlers use. Embedded structs contribute their fields, which is how
+// tools share arguments.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	inputSuffix  = "Input"
+	schemaSuffix = "Schema"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory to read the input structs from")
+	out := flag.String("o", "schemas_gen.go", "file to write, relative to -dir")
+	flag.Parse()
+
+	code, err := generate(*dir, *out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, *out), code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// property is one argument of a tool
+type property struct {
+	name string
+	// schema holds the keywords of the property as Go expressions, in
+	// the order they are written out
+	schema [][2]string
+}
+
+// generate returns the schema file for the package in dir, leaving out the
+// previously generated file
+func generate(dir, generated string) ([]byte, error) {
+	fset := token.NewFileSet()
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	structs := make(map[string]*ast.StructType)
+	var pkg string
+	for _, path := range matches {
+		base := filepath.Base(path)
+		if base == generated || strings.HasSuffix(base, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		pkg = file.Name.Name
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					structs[spec.Name.Name] = st
+				}
+			}
+			return true
+		})
+	}
+
+	var inputs []string
+	for name := range structs {
+		if strings.HasSuffix(name, inputSuffix) {
+			inputs = append(inputs, name)
+		}
+	}
+	sort.Strings(inputs)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by schemagen; DO NOT EDIT.\n\npackage %s\n", pkg)
+	for _, name := range inputs {
+		props, required, err := properties(structs, name)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "\n// %s%s is the JSON Schema of %s\n", name, schemaSuffix, name)
+		fmt.Fprintf(&b, "var %s%s = map[string]interface{}{\n\"type\": \"object\",\n\"properties\": map[string]interface{}{\n", name, schemaSuffix)
+		for _, p := range props {
+			fmt.Fprintf(&b, "%q: map[string]interface{}{\n", p.name)
+			for _, kv := range p.schema {
+				fmt.Fprintf(&b, "%q: %s,\n", kv[0], kv[1])
+			}
+			b.WriteString("},\n")
+		}
+		b.WriteString("},\n")
+		if len(required) > 0 {
+			quoted := make([]string, len(required))
+			for i, r := range required {
+				quoted[i] = strconv.Quote(r)
+			}
+			fmt.Fprintf(&b, "\"required\": []string{%s},\n", strings.Join(quoted, ", "))
+		}
+		b.WriteString("}\n")
+		fmt.Fprintf(&b, "\nfunc (%s) inputSchema() map[string]interface{} { return %s%s }\n", name, name, schemaSuffix)
+	}
+	return format.Source(b.Bytes())
+}
+
+// properties returns the arguments of the struct called name, including
+// those of the structs it embeds, and the names of the required ones
+func properties(structs map[string]*ast.StructType, name string) ([]property, []string, error) {
+	st, ok := structs[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("struct %s not found", name)
+	}
+	var props []property
+	var required []string
+	for _, field := range st.Fields.List {
+		if len(field.Names) == 0 {
+			embedded, ok := field.Type.(*ast.Ident)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: only structs of the package can be embedded", name)
+			}
+			p, r, err := properties(structs, embedded.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			props = append(props, p...)
+			required = append(required, r...)
+			continue
+		}
+
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			unquoted, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, nil, err
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+		jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if jsonName == "" {
+			return nil, nil, fmt.Errorf("%s.%s has no json name", name, field.Names[0].Name)
+		}
+
+		p, isRequired, err := fieldProperty(jsonName, field, tag.Get("jsonschema"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s.%s: %w", name, field.Names[0].Name, err)
+		}
+		props = append(props, p)
+		if isRequired {
+			required = append(required, jsonName)
+		}
+	}
+	return props, required, nil
+}
+
+func fieldProperty(name string, field *ast.Field, options string) (property, bool, error) {
+	p := property{name: name}
+	var typ, description, items string
+	if field.Doc != nil {
+		description = strconv.Quote(strings.Join(strings.Fields(field.Doc.Text()), " "))
+	}
+	switch t := deref(field.Type).(type) {
+	case *ast.Ident:
+		typ = jsonType(t.Name)
+	case *ast.ArrayType:
+		typ = "array"
+		if elt, ok := t.Elt.(*ast.Ident); ok {
+			items = jsonType(elt.Name)
+		}
+	}
+
+	var required bool
+	var extra [][2]string
+	for _, opt := range strings.Split(options, ",") {
+		key, value, _ := strings.Cut(opt, "=")
+		switch key {
+		case "":
+		case "required":
+			required = true
+		case "type":
+			typ = value
+		case "description":
+			description = value
+		case "enum":
+			extra = append(extra, [2]string{"enum", "[]string{" + strings.ReplaceAll(value, "|", ", ") + "}"})
+		case "default", "maximum", "maxItems":
+			extra = append(extra, [2]string{key, value})
+		default:
+			return property{}, false, fmt.Errorf("unknown jsonschema option %q", key)
+		}
+	}
+	if typ == "" {
+		return property{}, false, fmt.Errorf("no JSON type for %s; set one with type=", name)
+	}
+	if typ == "array" && items == "" {
+		return property{}, false, fmt.Errorf("no JSON type for the items of %s", name)
+	}
+	if description == "" {
+		return property{}, false, fmt.Errorf("%s has no description", name)
+	}
+
+	p.schema = append(p.schema, [2]string{"type", strconv.Quote(typ)})
+	if items != "" {
+		p.schema = append(p.schema, [2]string{"items", fmt.Sprintf("map[string]interface{}{\"type\": %q}", items)})
+	}
+	p.schema = append(p.schema, extra...)
+	p.schema = append(p.schema, [2]string{"description", description})
+	return p, required, nil
+}
+
+func deref(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+	return expr
+}
+
+// jsonType maps a Go type name to its JSON Schema type, or "" when there is
+// no obvious one
+func jsonType(goType string) string {
+	switch goType {
+	case "string":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int64":
+		return "integer"
+	case "float64":
+		return "number"
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_UpToDate(t *testing.T) {
+	want, err := generate("../..", "schemas_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../schemas_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("schemas_gen.go is out of date; run go generate ./pkg/server")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := `package tools
+
+type sharedArg struct {
+	// Shared flag
+	Shared bool ` + "`json:\"shared\"`" + `
+}
+
+type pingInput struct {
+	// Who to ping
+	Target string ` + "`json:\"target\" jsonschema:\"required,enum=\\\"a\\\"|\\\"b\\\"\"`" + `
+	// How many times
+	Count *int ` + "`json:\"count\" jsonschema:\"default=1,maximum=maxCount\"`" + `
+	Tags []string ` + "`json:\"tags\" jsonschema:\"description=tagsDescription,maxItems=3\"`" + `
+	Ignored string ` + "`json:\"-\"`" + `
+	sharedArg
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "tools.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	code, err := generate(dir, "schemas_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"var pingInputSchema = map[string]interface{}{",
+		`"enum":        []string{"a", "b"},`,
+		`"default":     1,`,
+		`"maximum":     maxCount,`,
+		`"items":       map[string]interface{}{"type": "string"},`,
+		`"description": tagsDescription,`,
+		`"shared": map[string]interface{}{`,
+		`"required": []string{"target"},`,
+		"func (pingInput) inputSchema() map[string]interface{} { return pingInputSchema }",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("expected %s in\n%s", want, code)
+		}
+	}
+	if strings.Contains(string(code), "sharedArgSchema") || strings.Contains(string(code), "Ignored") {
+		t.Errorf("only Input structs and json fields should be generated:\n%s", code)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	for name, field := range map[string]string{
+		"no description": "Days int `json:\"days\"`",
+		"no json name":   "// Days\nDays int",
+		"unknown type":   "// When\nWhen struct{} `json:\"when\"`",
+		"unknown option": "// Days\nDays int `json:\"days\" jsonschema:\"minimum=1\"`",
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := "package tools\n\ntype pingInput struct {\n" + field + "\n}\n"
+			if err := os.WriteFile(filepath.Join(dir, "tools.go"), []byte(src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := generate(dir, "schemas_gen.go"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+// joinInfoDays is how far ahead join_info looks for the next meeting
+const joinInfoDays = 7
+
+// meetingURL finds video meeting links pasted into the location or the
+// description of events that have no conference attached
+var meetingURL = regexp.MustCompile(`https://[\w.-]*(zoom\.us|meet\.google\.com|teams\.microsoft\.com|teams\.live\.com|webex\.com|whereby\.com|gotomeet\.me|chime\.aws)/[^\s"'<>)\]]*`)
+
+// joinInfoInput is the arguments of join_info
+type joinInfoInput struct {
+	// Event ID or link; omit it for your next meeting
+	EventID string `json:"event_id" jsonschema:"description=eventIDDescription"`
+	eventRefArg
+}
+
+// joinInfo is how to join a meeting
+type joinInfo struct {
+	EventID  string `json:"eventId,omitempty"`
+	Summary  string `json:"summary,omitempty"`
+	Start    string `json:"start,omitempty"`
+	End      string `json:"end,omitempty"`
+	JoinURL  string `json:"joinUrl,omitempty"`
+	DialIn   string `json:"dialIn,omitempty"`
+	PIN      string `json:"pin,omitempty"`
+	Location string `json:"location,omitempty"`
+	// now is when the answer was given, for "in 5 min"
+	now time.Time
+}
+
+func (s *Server) callJoinInfo(ctx context.Context, input joinInfoInput) (joinInfo, error) {
+	now := time.Now()
+	eventID, err := s.eventIDArg(ctx, input.EventID, input.EventRef)
+	if err != nil {
+		return joinInfo{}, err
+	}
+	if eventID == "" {
+		events, err := s.settings().calendar.ListEventsForDays(ctx, joinInfoDays)
+		if err != nil {
+			return joinInfo{}, err
+		}
+		next, ok := nextMeeting(events, now)
+		if !ok {
+			return joinInfo{now: now}, nil
+		}
+		eventID = next.ID
+	}
+
+	event, err := s.settings().calendar.GetEvent(ctx, eventID)
+	if err != nil {
+		return joinInfo{}, err
+	}
+	info := joinDetails(event)
+	info.now = now
+	return info, nil
+}
+
+// nextMeeting returns the timed event in progress or starting next that
+// takes the user's time
+func nextMeeting(events []gcal.CalendarEvent, now time.Time) (gcal.CalendarEvent, bool) {
+	var next gcal.CalendarEvent
+	var nextStart time.Time
+	for _, e := range events {
+		if !blocksTime(e) {
+			continue
+		}
+		start, err1 := time.Parse(time.RFC3339, e.Start)
+		end, err2 := time.Parse(time.RFC3339, e.End)
+		if err1 != nil || err2 != nil || !end.After(now) {
+			continue
+		}
+		if nextStart.IsZero() || start.Before(nextStart) {
+			next, nextStart = e, start
+		}
+	}
+	return next, !nextStart.IsZero()
+}
+
+// joinDetails picks the ways to join an event: its conference, or else a
+// meeting link in its location or description
+func joinDetails(event *calendar.Event) joinInfo {
+	info := joinInfo{EventID: event.Id, Summary: event.Summary, Location: event.Location, JoinURL: event.HangoutLink}
+	if event.Start != nil {
+		info.Start = firstNonEmpty(event.Start.DateTime, event.Start.Date)
+	}
+	if event.End != nil {
+		info.End = firstNonEmpty(event.End.DateTime, event.End.Date)
+	}
+	if event.ConferenceData != nil {
+		for _, ep := range event.ConferenceData.EntryPoints {
+			switch ep.EntryPointType {
+			case "video":
+				if info.JoinURL == "" {
+					info.JoinURL = ep.Uri
+				}
+			case "phone":
+				if info.DialIn == "" {
+					info.DialIn = firstNonEmpty(ep.Label, strings.TrimPrefix(ep.Uri, "tel:"))
+					info.PIN = firstNonEmpty(ep.Pin, ep.AccessCode, ep.Passcode)
+				}
+			}
+		}
+	}
+	if info.JoinURL == "" {
+		info.JoinURL = meetingURL.FindString(event.Location + "\n" + event.Description)
+	}
+	// A location that is only the meeting link says nothing more
+	if strings.TrimSpace(info.Location) == info.JoinURL {
+		info.Location = ""
+	}
+	return info
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (j joinInfo) toolText(s *Server) string {
+	if j.EventID == "" {
+		return fmt.Sprintf("No meetings in the next %d days.\n", joinInfoDays)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s.", s.sanitize(j.Summary), s.joinWhen(j))
+	if j.JoinURL != "" {
+		fmt.Fprintf(&b, " Join: %s.", j.JoinURL)
+	}
+	if j.DialIn != "" {
+		fmt.Fprintf(&b, " Dial-in: %s", j.DialIn)
+		if j.PIN != "" {
+			fmt.Fprintf(&b, ", PIN %s", j.PIN)
+		}
+		b.WriteString(".")
+	}
+	if j.Location != "" {
+		fmt.Fprintf(&b, " Location: %s.", s.sanitize(j.Location))
+	}
+	if j.JoinURL == "" && j.DialIn == "" && j.Location == "" {
+		b.WriteString(" No join link or location.")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// joinWhen says when a meeting is the way someone asking how to join needs
+// it: how soon for today's meetings, the date otherwise
+func (s *Server) joinWhen(j joinInfo) string {
+	start, err := time.Parse(time.RFC3339, j.Start)
+	if err != nil {
+		return j.Start
+	}
+	end, _ := time.Parse(time.RFC3339, j.End)
+	start, now := start.In(s.settings().location), j.now.In(s.settings().location)
+	switch until := start.Sub(now); {
+	case until <= 0 && end.After(now):
+		return fmt.Sprintf("started %s ago", roundedMinutes(-until))
+	case until > 0 && until < time.Hour:
+		return fmt.Sprintf("%s (in %s)", start.Format("15:04"), roundedMinutes(until))
+	case start.YearDay() == now.YearDay() && start.Year() == now.Year():
+		return start.Format("15:04")
+	}
+	return start.Format("Mon 2 Jan 15:04")
+}
+
+func roundedMinutes(d time.Duration) string {
+	return fmt.Sprintf("%d min", int(d.Round(time.Minute).Minutes()))
+}
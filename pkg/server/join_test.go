@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestNextMeeting(t *testing.T) {
+	now := time.Date(2026, 3, 20, 9, 5, 0, 0, time.UTC)
+	events := []gcal.CalendarEvent{
+		{ID: "allday", Start: "2026-03-20", End: "2026-03-21"},
+		{ID: "over", Start: "2026-03-20T08:00:00Z", End: "2026-03-20T09:00:00Z"},
+		{ID: "declined", Start: "2026-03-20T09:00:00Z", End: "2026-03-20T09:30:00Z", Guests: []gcal.Guest{{Self: true, ResponseStatus: "declined"}}},
+		{ID: "standup", Start: "2026-03-20T09:00:00Z", End: "2026-03-20T09:15:00Z"},
+		{ID: "review", Start: "2026-03-20T10:00:00Z", End: "2026-03-20T11:00:00Z"},
+	}
+	if next, ok := nextMeeting(events, now); !ok || next.ID != "standup" {
+		t.Errorf("expected the meeting in progress, got %+v", next)
+	}
+	if _, ok := nextMeeting(events[:3], now); ok {
+		t.Error("expected no meeting")
+	}
+}
+
+func TestJoinDetails(t *testing.T) {
+	info := joinDetails(&calendar.Event{
+		Id:          "abc",
+		Summary:     "Planning",
+		HangoutLink: "https://meet.google.com/abc-defg-hij",
+		Location:    "Room 3",
+		ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+			{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+			{EntryPointType: "phone", Uri: "tel:+1-555-0100", Label: "+1 555-0100", Pin: "123456"},
+		}},
+	})
+	if info.JoinURL != "https://meet.google.com/abc-defg-hij" || info.DialIn != "+1 555-0100" || info.PIN != "123456" || info.Location != "Room 3" {
+		t.Errorf("unexpected join info %+v", info)
+	}
+
+	// Links pasted into the event are found too
+	info = joinDetails(&calendar.Event{Id: "z", Location: "https://acme.zoom.us/j/123456789?pwd=x", Description: "Agenda: https://docs.example.com/a"})
+	if info.JoinURL != "https://acme.zoom.us/j/123456789?pwd=x" || info.Location != "" {
+		t.Errorf("expected the Zoom link from the location, got %+v", info)
+	}
+}
+
+func TestCallJoinInfo(t *testing.T) {
+	now := time.Now().UTC()
+	fake := &fakeCalendar{
+		events: []gcal.CalendarEvent{{ID: "sync", Start: now.Add(10 * time.Minute).Format(time.RFC3339), End: now.Add(40 * time.Minute).Format(time.RFC3339)}},
+		fetched: &calendar.Event{
+			Id: "sync", Summary: "Team sync", HangoutLink: "https://meet.google.com/xyz",
+			Start: &calendar.EventDateTime{DateTime: now.Add(10 * time.Minute).Format(time.RFC3339)},
+			End:   &calendar.EventDateTime{DateTime: now.Add(40 * time.Minute).Format(time.RFC3339)},
+		},
+	}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolJoinInfo, id: float64(1)})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.HasPrefix(text, "Team sync, ") || !strings.Contains(text, "(in 10 min). Join: https://meet.google.com/xyz.") || strings.Count(text, "\n") != 1 {
+		t.Errorf("expected a one-line answer, got %q", text)
+	}
+	if fake.lastDays != joinInfoDays {
+		t.Errorf("expected the next %d days to be searched, got %d", joinInfoDays, fake.lastDays)
+	}
+
+	fake.events = nil
+	resp = s.callTool(context.Background(), &toolCall{name: toolJoinInfo, id: float64(2)})
+	if text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string); !strings.Contains(text, "No meetings") {
+		t.Errorf("expected no meetings, got %q", text)
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+// sessionState tracks the MCP lifecycle: initialize, then the initialized
+// notification, then normal operation
+type sessionState int
+
+const (
+	// stateNew accepts only initialize and ping
+	stateNew sessionState = iota
+	// stateInitializing has answered initialize and waits for the client's
+	// initialized notification
+	stateInitializing
+	stateReady
+)
+
+const (
+	// errCodeNotInitialized rejects requests sent before the lifecycle
+	// allows them
+	errCodeNotInitialized = -32002
+
+	// shutdownGracePeriod is how long in-flight requests may run after the
+	// input closes before they are cancelled
+	shutdownGracePeriod = 10 * time.Second
+)
+
+// checkLifecycle enforces the order of the session: initialize exactly
+// once, then the initialized notification, then anything else. It returns
+// the error to answer req with, or nil when req may be handled. Pings and
+// cancellations are always allowed.
+func (s *Server) checkLifecycle(req JSONRPCRequest) *RPCError {
+	switch req.Method {
+	case "ping", "notifications/cancelled":
+		return nil
+	}
+
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+
+	switch {
+	case req.Method == "initialize":
+		if s.state != stateNew {
+			return &RPCError{Code: -32600, Message: "initialize has already been called"}
+		}
+		s.state = stateInitializing
+	case isInitializedNotification(req.Method):
+		if s.state == stateInitializing {
+			s.state = stateReady
+		}
+	case s.state != stateReady:
+		return &RPCError{Code: errCodeNotInitialized, Message: "server not initialized: send initialize and the initialized notification first"}
+	}
+	return nil
+}
+
+// initializeFailed lets the client retry an initialize that was rejected,
+// e.g. for invalid params: checkLifecycle moved the session on before
+// handleInitialize got to validate them.
+func (s *Server) initializeFailed() {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	if s.state == stateInitializing {
+		s.state = stateNew
+	}
+}
+
+func (s *Server) currentSessionID() string {
+	s.sessionMu.RLock()
+	defer s.sessionMu.RUnlock()
+	return s.sessionID
+}
+
+func isInitializedNotification(method string) bool {
+	return method == "notifications/initialized" || method == "initialized"
+}
+
+// shutdown runs once the input is closed: requests already read are still
+// answered, and any still running after shutdownGracePeriod are cancelled
+func (s *Server) shutdown() {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(shutdownGracePeriod):
+	}
+
+	s.cancelInFlight("shutting down")
+	<-done
+}
+
+func (s *Server) cancelInFlight(reason string) {
+	s.inFlightMu.Lock()
+	defer s.inFlightMu.Unlock()
+	for key, cancel := range s.inFlight {
+		log.Printf("Cancelling request %s (session %s): %s", key, s.currentSessionID(), reason)
+		cancel()
+	}
+}
+
+// resetSession forgets everything a previous client set up, so that the
+// next one starts over with the initialize handshake. id identifies the new
+// session.
+func (s *Server) resetSession(id string) {
+	s.sessionMu.Lock()
+	s.sessionID = id
+	s.state = stateNew
+	s.negotiatedVersion = protocolVersion20241105
+	s.clientSampling = false
+	s.sessionMu.Unlock()
+
+	s.subMu.Lock()
+	s.subscriptions = make(map[string]string)
+	s.subMu.Unlock()
+
+	s.rememberListing(nil)
+	s.snapshotsMu.Lock()
+	s.snapshots = nil
+	s.snapshotsMu.Unlock()
+	s.restoreDefaults()
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestLifecycle_RejectsRequestsBeforeInitialized(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	params, _ := json.Marshal(map[string]interface{}{"name": "list_events"})
+	call := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params}
+
+	if resp := s.handleRequest(call); resp.Error == nil || resp.Error.Code != errCodeNotInitialized {
+		t.Fatalf("expected not initialized before initialize, got %+v", resp)
+	}
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "ping"}); resp.Error != nil {
+		t.Errorf("expected ping to work before initialize, got %+v", resp.Error)
+	}
+
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(3), Method: "initialize"})
+	if resp := s.handleRequest(call); resp.Error == nil || resp.Error.Code != errCodeNotInitialized {
+		t.Fatalf("expected not initialized before the initialized notification, got %+v", resp)
+	}
+
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}); resp != nil {
+		t.Errorf("expected no reply to a notification, got %+v", resp)
+	}
+	if resp := s.handleRequest(call); resp.Error != nil {
+		t.Errorf("expected tools/call to work once initialized, got %+v", resp.Error)
+	}
+}
+
+func TestLifecycle_RejectsDuplicateInitialize(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	initialize := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize"}
+
+	if resp := s.handleRequest(initialize); resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if resp := s.handleRequest(initialize); resp.Error == nil || resp.Error.Code != -32600 {
+		t.Errorf("expected invalid request for a second initialize, got %+v", resp)
+	}
+}
+
+func TestLifecycle_RetryFailedInitialize(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+
+	invalid := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: json.RawMessage(`"oops"`)}
+	if resp := s.handleRequest(invalid); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Fatalf("expected invalid params, got %+v", resp)
+	}
+	// The failed initialize doesn't count: the client may try again
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "initialize"}); resp.Error != nil {
+		t.Fatalf("expected the retried initialize to succeed, got %+v", resp.Error)
+	}
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+	if !s.ready() {
+		t.Error("expected the session to be ready after the retry")
+	}
+}
+
+func TestLifecycle_NoReplyToEarlyNotifications(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/roots/list_changed"}); resp != nil {
+		t.Errorf("expected no reply, got %+v", resp)
+	}
+}
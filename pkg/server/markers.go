@@ -0,0 +1,104 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// Status markers that can be shown in front of listed events
+const (
+	markerAccepted  = "accepted"
+	markerNeedsRSVP = "needs_rsvp"
+	markerDeclined  = "declined"
+	markerRecurring = "recurring"
+	markerLocation  = "location"
+)
+
+// markerOrder is the order markers appear in, with their default symbols
+var markerOrder = []struct{ name, symbol string }{
+	{markerAccepted, "✅"},
+	{markerNeedsRSVP, "❓"},
+	{markerDeclined, "❌"},
+	{markerRecurring, "🔁"},
+	{markerLocation, "📍"},
+}
+
+// statusMarkers maps the enabled markers to their symbols
+type statusMarkers map[string]string
+
+// parseStatusMarkers reads CALENDAR_STATUS_MARKERS: "true" enables every
+// marker with its default symbol, otherwise it is a comma-separated list of
+// marker names, each optionally with its own symbol (accepted=👍).
+func parseStatusMarkers(spec string) (statusMarkers, error) {
+	markers := make(statusMarkers)
+	if strings.TrimSpace(spec) == "true" {
+		for _, m := range markerOrder {
+			markers[m.name] = m.symbol
+		}
+		return markers, nil
+	}
+
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, symbol, custom := strings.Cut(item, "=")
+		name, symbol = strings.TrimSpace(name), strings.TrimSpace(symbol)
+		known := false
+		for _, m := range markerOrder {
+			if m.name == name {
+				known = true
+				if !custom {
+					symbol = m.symbol
+				}
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown marker %q: expected %s, %s, %s, %s or %s", name, markerAccepted, markerNeedsRSVP, markerDeclined, markerRecurring, markerLocation)
+		}
+		if symbol == "" {
+			return nil, fmt.Errorf("marker %q: empty symbol", name)
+		}
+		markers[name] = symbol
+	}
+	return markers, nil
+}
+
+// prefix returns the markers that apply to e followed by a space, or an
+// empty string when none do
+func (m statusMarkers) prefix(e gcal.CalendarEvent) string {
+	if len(m) == 0 {
+		return ""
+	}
+	applies := map[string]bool{
+		markerRecurring: e.RecurringEventID != "",
+		markerLocation:  strings.TrimSpace(e.Location) != "",
+	}
+	for _, g := range e.Guests {
+		if !g.Self {
+			continue
+		}
+		switch g.ResponseStatus {
+		case "accepted":
+			applies[markerAccepted] = true
+		case "needsAction":
+			applies[markerNeedsRSVP] = true
+		case "declined":
+			applies[markerDeclined] = true
+		}
+	}
+
+	var symbols []string
+	for _, o := range markerOrder {
+		if symbol, enabled := m[o.name]; enabled && applies[o.name] {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return ""
+	}
+	return strings.Join(symbols, "") + " "
+}
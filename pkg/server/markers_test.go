@@ -0,0 +1,65 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestParseStatusMarkers(t *testing.T) {
+	all, err := parseStatusMarkers("true")
+	if err != nil || len(all) != len(markerOrder) || all[markerDeclined] != "❌" {
+		t.Errorf("unexpected markers %v, %v", all, err)
+	}
+
+	some, err := parseStatusMarkers("needs_rsvp, recurring=(R)")
+	if err != nil || len(some) != 2 || some[markerNeedsRSVP] != "❓" || some[markerRecurring] != "(R)" {
+		t.Errorf("unexpected markers %v, %v", some, err)
+	}
+
+	for _, bad := range []string{"rsvp", "accepted="} {
+		if _, err := parseStatusMarkers(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestStatusMarkersPrefix(t *testing.T) {
+	markers, _ := parseStatusMarkers("true")
+	tests := []struct {
+		event gcal.CalendarEvent
+		want  string
+	}{
+		{gcal.CalendarEvent{Guests: []gcal.Guest{{Email: "me@example.com", ResponseStatus: "accepted", Self: true}}}, "✅ "},
+		{gcal.CalendarEvent{Guests: []gcal.Guest{{Email: "other@example.com", ResponseStatus: "accepted"}, {Email: "me@example.com", ResponseStatus: "needsAction", Self: true}}}, "❓ "},
+		{gcal.CalendarEvent{RecurringEventID: "series", Location: "Room 1", Guests: []gcal.Guest{{Email: "me@example.com", ResponseStatus: "declined", Self: true}}}, "❌🔁📍 "},
+		// Tentative answers and events without guests get no RSVP marker
+		{gcal.CalendarEvent{Guests: []gcal.Guest{{Email: "me@example.com", ResponseStatus: "tentative", Self: true}}}, ""},
+		{gcal.CalendarEvent{}, ""},
+	}
+	for _, tt := range tests {
+		if got := markers.prefix(tt.event); got != tt.want {
+			t.Errorf("prefix(%+v) = %q, want %q", tt.event, got, tt.want)
+		}
+	}
+
+	var none statusMarkers
+	if got := none.prefix(tests[0].event); got != "" {
+		t.Errorf("expected no markers when disabled, got %q", got)
+	}
+}
+
+func TestFormatEvents_StatusMarkers(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	events := []gcal.CalendarEvent{{ID: "1", Summary: "Planning", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:00:00Z", RecurringEventID: "series"}}
+
+	if text := s.formatEvents(events); !strings.Contains(text, "- Planning\n") {
+		t.Errorf("expected no markers by default:\n%s", text)
+	}
+
+	s.markers, _ = parseStatusMarkers("recurring")
+	if text := s.formatEvents(events); !strings.Contains(text, "- 🔁 Planning\n") {
+		t.Errorf("expected the recurring marker:\n%s", text)
+	}
+}
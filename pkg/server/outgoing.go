@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// clientResponse is a client's reply to a request sent by the server
+type clientResponse struct {
+	Result json.RawMessage
+	Error  *RPCError
+}
+
+// request sends a JSON-RPC request to the client and waits for its reply.
+// It must not be called from the input loop itself, which is what delivers
+// the reply.
+func (s *Server) request(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	s.pendingMu.Lock()
+	s.nextRequestID++
+	id := fmt.Sprintf("srv-%d", s.nextRequestID)
+	ch := make(chan clientResponse, 1)
+	s.pending[id] = ch
+	s.pendingMu.Unlock()
+
+	defer func() {
+		s.pendingMu.Lock()
+		delete(s.pending, id)
+		s.pendingMu.Unlock()
+	}()
+
+	data, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.writeLine(data)
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return nil, fmt.Errorf("client returned error %d: %s", resp.Error.Code, resp.Error.Message)
+		}
+		return resp.Result, nil
+	case <-ctx.Done():
+		s.sendNotification("notifications/cancelled", map[string]interface{}{
+			"requestId": id,
+			"reason":    ctx.Err().Error(),
+		})
+		return nil, ctx.Err()
+	}
+}
+
+// isClientResponse reports whether an incoming message is a reply to a
+// server-initiated request rather than a request or notification
+func isClientResponse(req JSONRPCRequest) bool {
+	return req.Method == "" && req.ID != nil
+}
+
+// handleClientResponse delivers a client reply to the waiting request.
+// Only the first reply to a request is delivered: later ones, such as a
+// duplicate in a batch, are dropped rather than blocking the input loop.
+func (s *Server) handleClientResponse(line []byte) {
+	var msg struct {
+		ID     interface{}     `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *RPCError       `json:"error"`
+	}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	s.pendingMu.Lock()
+	ch, ok := s.pending[fmt.Sprint(msg.ID)]
+	delete(s.pending, fmt.Sprint(msg.ID))
+	s.pendingMu.Unlock()
+
+	if !ok {
+		log.Printf("Ignoring response for unknown request %v", msg.ID)
+		return
+	}
+	select {
+	case ch <- clientResponse{Result: msg.Result, Error: msg.Error}:
+	default:
+	}
+}
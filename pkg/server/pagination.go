@@ -0,0 +1,54 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// listPageLimit is the number of entries returned per tools/list or
+// resources/list page. It leaves room for the whole tool list, since some
+// clients never ask for a second page.
+const listPageLimit = 50
+
+const cursorPrefix = "offset:"
+
+// cursorParam extracts the optional cursor shared by the list methods
+func cursorParam(raw json.RawMessage) (string, error) {
+	var params struct {
+		Cursor string `json:"cursor"`
+	}
+	if len(raw) == 0 {
+		return "", nil
+	}
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return "", err
+	}
+	return params.Cursor, nil
+}
+
+// paginate returns the page of items starting at cursor and the cursor for
+// the next page, which is empty on the last page. Cursors are opaque to
+// clients; internally they encode an offset.
+func paginate[T any](items []T, cursor string, limit int) ([]T, string, error) {
+	offset := 0
+	if cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+		offset, err = strconv.Atoi(strings.TrimPrefix(string(decoded), cursorPrefix))
+		if err != nil || offset < 0 || offset > len(items) {
+			return nil, "", fmt.Errorf("invalid cursor %q", cursor)
+		}
+	}
+
+	end := min(offset+limit, len(items))
+	next := ""
+	if end < len(items) {
+		next = base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(end)))
+	}
+	return items[offset:end], next, nil
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	page, next, err := paginate(items, "", 2)
+	if err != nil || len(page) != 2 || page[0] != 1 || next == "" {
+		t.Fatalf("unexpected first page: %v %q %v", page, next, err)
+	}
+
+	page, next, err = paginate(items, next, 2)
+	if err != nil || len(page) != 2 || page[0] != 3 || next == "" {
+		t.Fatalf("unexpected second page: %v %q %v", page, next, err)
+	}
+
+	page, next, err = paginate(items, next, 2)
+	if err != nil || len(page) != 1 || page[0] != 5 || next != "" {
+		t.Fatalf("unexpected last page: %v %q %v", page, next, err)
+	}
+}
+
+func TestPaginate_InvalidCursor(t *testing.T) {
+	for _, cursor := range []string{"garbage!", "b2Zmc2V0Oi0x", "b2Zmc2V0Ojk5"} {
+		if _, _, err := paginate([]int{1, 2}, cursor, 2); err == nil {
+			t.Errorf("expected error for cursor %q", cursor)
+		}
+	}
+}
+
+func TestHandleToolsList_Pagination(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/list"})
+	if _, ok := resp.Result.(map[string]interface{})["nextCursor"]; ok {
+		t.Error("expected no nextCursor when all tools fit on one page")
+	}
+
+	params, _ := json.Marshal(map[string]string{"cursor": "not-a-cursor"})
+	resp = s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "tools/list", Params: params})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for a bad cursor, got %+v", resp)
+	}
+}
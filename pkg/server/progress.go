@@ -0,0 +1,43 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// experimentalEventStreaming is the experimental capability under which
+// listings stream pages of events in progress notifications
+const experimentalEventStreaming = "eventStreaming"
+
+// progressReporter returns a gcal.ProgressFunc that sends
+// notifications/progress for the given client token
+func (s *Server) progressReporter(token interface{}) gcal.ProgressFunc {
+	return func(progress, total int, message string) {
+		params := map[string]interface{}{
+			"progressToken": token,
+			"progress":      progress,
+		}
+		if total > 0 {
+			params["total"] = total
+		}
+		if message != "" {
+			params["message"] = message
+		}
+		s.sendNotification("notifications/progress", params)
+	}
+}
+
+// pageStreamer returns a gcal.PageFunc that sends every page as a progress
+// notification carrying the page's events in _meta.events. The final tool
+// result still contains all events, so the pages are only a preview.
+func (s *Server) pageStreamer(token interface{}) gcal.PageFunc {
+	return func(fetched int, page []gcal.CalendarEvent) {
+		s.sendNotification("notifications/progress", map[string]interface{}{
+			"progressToken": token,
+			"progress":      fetched,
+			"message":       fmt.Sprintf("Fetched %d events", fetched),
+			"_meta":         map[string]interface{}{"events": page},
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	promptWeeklyReview = "weekly_review"
+
+	maxReviewDays  = 31
+	maxFocusLength = 200
+)
+
+type promptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type promptDefinition struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []promptArgument `json:"arguments"`
+	// render validates the arguments and returns the prompt text; errors
+	// with errCodeInvalidArgument are reported as invalid params
+	render func(s *Server, ctx context.Context, args map[string]string) (string, error)
+}
+
+var promptDefinitions = []promptDefinition{
+	{
+		Name:        promptWeeklyReview,
+		Description: "Review a week of meetings: where the time went, what to follow up on, and what to change next week",
+		Arguments: []promptArgument{
+			{Name: "start_date", Description: "First day to review in YYYY-MM-DD format (default: 6 days ago)"},
+			{Name: "end_date", Description: fmt.Sprintf("Last day to review in YYYY-MM-DD format (default: 6 days after start_date, max range: %d days)", maxReviewDays)},
+			{Name: "calendar", Description: calendarArgDescription},
+			{Name: "focus", Description: "Keyword or topic the review should concentrate on, e.g. \"hiring\" (optional)"},
+		},
+		render: (*Server).weeklyReview,
+	},
+}
+
+func (s *Server) handlePromptsList(req JSONRPCRequest) *JSONRPCResponse {
+	cursor, err := cursorParam(req.Params)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+	page, next, err := paginate(promptDefinitions, cursor, listPageLimit)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	result := map[string]interface{}{
+		"prompts": page,
+	}
+	if next != "" {
+		result["nextCursor"] = next
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+func (s *Server) handlePromptsGet(req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	prompt, ok := findPrompt(params.Name)
+	if !ok {
+		return s.paramError(req.ID, "Unknown prompt: "+params.Name, nil)
+	}
+	for name := range params.Arguments {
+		if !prompt.hasArgument(name) {
+			return s.paramError(req.ID, fmt.Sprintf("Unknown argument %q for prompt %s", name, prompt.Name), nil)
+		}
+	}
+
+	ctx, cancel := s.startRequest(req.ID)
+	defer cancel()
+
+	text, err := prompt.render(s, ctx, params.Arguments)
+	if err != nil {
+		if errorCode(err) == errCodeInvalidArgument {
+			return s.paramError(req.ID, err.Error(), nil)
+		}
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32603,
+				Message: "Failed to build prompt",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"description": prompt.Description,
+			"messages": []map[string]interface{}{
+				{
+					"role":    "user",
+					"content": map[string]string{"type": "text", "text": text},
+				},
+			},
+		},
+	}
+}
+
+func findPrompt(name string) (promptDefinition, bool) {
+	for _, p := range promptDefinitions {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return promptDefinition{}, false
+}
+
+func (p promptDefinition) hasArgument(name string) bool {
+	for _, a := range p.Arguments {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// weeklyReview validates the weekly_review arguments and renders the
+// prompt with the events of the reviewed range
+func (s *Server) weeklyReview(ctx context.Context, args map[string]string) (string, error) {
+	startDate, endDate := args["start_date"], args["end_date"]
+	for _, date := range []*string{&startDate, &endDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return "", withErrorCode(errCodeInvalidArgument, err)
+		}
+	}
+	if startDate == "" {
+		startDate = time.Now().In(s.settings().location).AddDate(0, 0, -6).Format("2006-01-02")
+	}
+	if endDate == "" {
+		start, _ := time.Parse("2006-01-02", startDate)
+		endDate = start.AddDate(0, 0, 6).Format("2006-01-02")
+	}
+	if err := checkRange(startDate, endDate, maxReviewDays); err != nil {
+		return "", invalidInputf("%s", err)
+	}
+
+	focus := strings.TrimSpace(args["focus"])
+	if utf8.RuneCountInString(focus) > maxFocusLength {
+		return "", invalidInputf("focus must be at most %d characters", maxFocusLength)
+	}
+
+	events, err := s.listCalendarRange(ctx, args["calendar"], startDate, endDate)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Review my calendar from %s to %s", startDate, endDate)
+	if name := strings.TrimSpace(args["calendar"]); name != "" {
+		fmt.Fprintf(&b, " (calendar: %s)", s.sanitize(name))
+	}
+	b.WriteString(". Summarize where my time went, point out meetings that need a follow-up, and suggest what to change next week.")
+	if focus != "" {
+		fmt.Fprintf(&b, " Concentrate on anything related to: %s.", s.sanitize(focus))
+	}
+	b.WriteString("\n\n")
+	b.WriteString(s.formatEvents(events))
+	return b.String(), nil
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestHandlePromptsList(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "prompts/list"})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	data, _ := json.Marshal(resp.Result)
+	var result struct {
+		Prompts []promptDefinition `json:"prompts"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Prompts) != 1 || result.Prompts[0].Name != promptWeeklyReview {
+		t.Fatalf("unexpected prompts %+v", result.Prompts)
+	}
+	var names []string
+	for _, a := range result.Prompts[0].Arguments {
+		names = append(names, a.Name)
+	}
+	if got := strings.Join(names, ","); got != "start_date,end_date,calendar,focus" {
+		t.Errorf("unexpected arguments %s", got)
+	}
+}
+
+func getPrompt(s *Server, name string, args map[string]string) *JSONRPCResponse {
+	params, _ := json.Marshal(map[string]interface{}{"name": name, "arguments": args})
+	return s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "prompts/get", Params: params})
+}
+
+func TestHandlePromptsGet_WeeklyReview(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:  []string{"maria@example.com"},
+		calendarNames: map[string]string{"maria@example.com": "Maria"},
+		extraCalendars: map[string][]gcal.CalendarEvent{
+			"maria@example.com": {{ID: "1", Summary: "Hiring sync", Start: "2026-03-17T10:00:00Z", End: "2026-03-17T11:00:00Z"}},
+		},
+	}
+	s := newTestServer(fake)
+
+	resp := getPrompt(s, promptWeeklyReview, map[string]string{"start_date": "2026-03-16", "calendar": "Maria", "focus": "hiring"})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	messages := resp.Result.(map[string]interface{})["messages"].([]map[string]interface{})
+	text := messages[0]["content"].(map[string]string)["text"]
+	for _, want := range []string{"from 2026-03-16 to 2026-03-22", "calendar: Maria", "related to: hiring", "Hiring sync"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in %s", want, text)
+		}
+	}
+}
+
+func TestHandlePromptsGet_InvalidArguments(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	tests := []struct {
+		name string
+		args map[string]string
+	}{
+		{"unknown argument", map[string]string{"topic": "hiring"}},
+		{"bad date", map[string]string{"start_date": "next week"}},
+		{"range too long", map[string]string{"start_date": "2026-01-01", "end_date": "2026-03-01"}},
+		{"reversed range", map[string]string{"start_date": "2026-03-10", "end_date": "2026-03-01"}},
+		{"unknown calendar", map[string]string{"calendar": "Nobody"}},
+		{"long focus", map[string]string{"focus": strings.Repeat("x", maxFocusLength+1)}},
+	}
+	for _, tt := range tests {
+		resp := getPrompt(s, promptWeeklyReview, tt.args)
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected invalid params, got %+v", tt.name, resp.Error)
+		}
+	}
+
+	if resp := getPrompt(s, "daily_review", nil); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for an unknown prompt, got %+v", resp.Error)
+	}
+}
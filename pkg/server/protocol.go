@@ -0,0 +1,61 @@
+package server
+
+import (
+	"log"
+	"time"
+)
+
+// MCP protocol revisions the server implements
+const (
+	protocolVersion20241105 = "2024-11-05"
+	protocolVersion20250326 = "2025-03-26"
+	protocolVersion20250618 = "2025-06-18"
+)
+
+// supportedProtocolVersions lists the implemented revisions, newest first
+var supportedProtocolVersions = []string{
+	protocolVersion20250618,
+	protocolVersion20250326,
+	protocolVersion20241105,
+}
+
+// negotiateProtocolVersion picks the revision to speak with a client. A
+// supported requested version is echoed back. For a revision the server
+// doesn't implement it offers the closest one: the newest revision older
+// than the request, since a client that knows a later revision usually
+// still speaks the earlier ones, or the oldest one for a request older than
+// all of them. Requests that aren't a revision date get the latest version.
+func negotiateProtocolVersion(requested string) string {
+	if _, err := time.Parse("2006-01-02", requested); err != nil {
+		return supportedProtocolVersions[0]
+	}
+	for _, v := range supportedProtocolVersions {
+		if v <= requested {
+			if v != requested {
+				log.Printf("Client requested unsupported protocol version %s, offering %s", requested, v)
+			}
+			return v
+		}
+	}
+	oldest := supportedProtocolVersions[len(supportedProtocolVersions)-1]
+	log.Printf("Client requested unsupported protocol version %s, offering %s", requested, oldest)
+	return oldest
+}
+
+func (s *Server) protocolVersion() string {
+	s.sessionMu.RLock()
+	defer s.sessionMu.RUnlock()
+	return s.negotiatedVersion
+}
+
+func (s *Server) setProtocolVersion(version string) {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	s.negotiatedVersion = version
+}
+
+// supportsVersion reports whether the negotiated protocol revision is at
+// least version. Revisions are dates, so they compare as strings.
+func (s *Server) supportsVersion(version string) bool {
+	return s.protocolVersion() >= version
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+// quickAddEventInput is the arguments of quick_add_event
+type quickAddEventInput struct {
+	// The event in plain words, with its time, e.g. Lunch with Sam Friday
+	// 1pm
+	Text string `json:"text" jsonschema:"required"`
+	calendarArg
+}
+
+func (s *Server) callQuickAddEvent(ctx context.Context, input quickAddEventInput) (textOutput, error) {
+	input.Text = strings.TrimSpace(input.Text)
+	if input.Text == "" {
+		return "", badArgumentf("text is required")
+	}
+	calendarID, err := s.resolveCalendar(ctx, input.Calendar)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkWritable(ctx, calendarID); err != nil {
+		return "", err
+	}
+
+	event, err := s.settings().calendar.QuickAddEvent(ctx, calendarID, input.Text)
+	if err != nil {
+		return "", err
+	}
+
+	// Google guesses rather than fails, so the event is described as it
+	// was understood
+	result := fmt.Sprintf("Event created successfully!\nID: %s\nSummary: %s", event.Id, s.sanitize(event.Summary))
+	if event.Start != nil {
+		result += "\nStart: " + s.readableTime(event.Start)
+	}
+	if event.End != nil {
+		result += "\nEnd: " + s.readableTime(event.End)
+	}
+	if d, ok := gcal.EventDuration(event); ok {
+		result += "\nDuration: " + gcal.FormatDuration(d)
+	}
+	if event.Location != "" {
+		result += "\nLocation: " + s.sanitize(event.Location)
+	}
+	result += "\nLink: " + event.HtmlLink
+	result += "\nCheck that this is what was meant; fix it with update_event or remove it with delete_event."
+	if warning := s.budgetWarning(ctx, event); warning != "" {
+		result += "\n" + warning
+	}
+	return textOutput(result), nil
+}
+
+// readableTime spells out an event time with its weekday in the server's
+// timezone, which makes a misread day stand out
+func (s *Server) readableTime(t *calendar.EventDateTime) string {
+	if t.DateTime == "" {
+		day, err := time.Parse("2006-01-02", t.Date)
+		if err != nil {
+			return t.Date
+		}
+		return day.Format("Mon 2 Jan 2006") + " (all day)"
+	}
+	at, err := time.Parse(time.RFC3339, t.DateTime)
+	if err != nil {
+		return t.DateTime
+	}
+	return at.In(s.settings().location).Format("Mon 2 Jan 2006 15:04 MST")
+}
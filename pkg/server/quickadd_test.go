@@ -0,0 +1,37 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCallQuickAddEvent(t *testing.T) {
+	fake := &fakeCalendar{created: &calendar.Event{
+		Id:       "evt-1",
+		Summary:  "Lunch with Sam",
+		Start:    &calendar.EventDateTime{DateTime: "2026-03-20T13:00:00+01:00"},
+		End:      &calendar.EventDateTime{DateTime: "2026-03-20T14:00:00+01:00"},
+		HtmlLink: "https://calendar.google.com/event?eid=1",
+	}}
+	s := newTestServer(fake)
+
+	resp := callWithArgs(s, toolQuickAddEvent, `{"text":" Lunch with Sam Friday 1pm "}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if fake.quickAdd != [2]string{"test@example.com", "Lunch with Sam Friday 1pm"} {
+		t.Errorf("unexpected call %q", fake.quickAdd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"ID: evt-1", "Summary: Lunch with Sam", "Start: Fri 20 Mar 2026 12:00 UTC", "End: Fri 20 Mar 2026 13:00 UTC", "Duration: 1h"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if resp := callWithArgs(s, toolQuickAddEvent, `{"text":"  "}`); resp.Error == nil {
+		t.Error("expected empty text to be rejected")
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// callLimiter spaces tool calls to at most rate per second, letting up to
+// burst of them through at once after a quiet period
+type callLimiter struct {
+	interval time.Duration
+	burst    int
+
+	mu sync.Mutex
+	// next is when the limiter is fully used up again if no more calls
+	// arrive
+	next time.Time
+}
+
+func newCallLimiter(rate float64, burst int) *callLimiter {
+	return &callLimiter{interval: time.Duration(float64(time.Second) / rate), burst: max(burst, 1)}
+}
+
+// wait blocks until the call may proceed, or returns the context's error
+// if it is cancelled first. A nil limiter never blocks.
+func (l *callLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(l.interval)
+	delay := l.next.Sub(now) - time.Duration(l.burst)*l.interval
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
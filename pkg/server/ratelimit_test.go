@@ -0,0 +1,38 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCallLimiter(t *testing.T) {
+	l := newCallLimiter(20, 2)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The burst passes at once, the other three are spaced 50ms apart
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > time.Second {
+		t.Errorf("expected about 150ms for 5 calls, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := newCallLimiter(1, 1).wait(ctx); err != nil {
+		t.Errorf("expected the first call to pass, got %v", err)
+	}
+	busy := newCallLimiter(0.1, 1)
+	busy.wait(context.Background())
+	if err := busy.wait(ctx); err != context.Canceled {
+		t.Errorf("expected a cancelled wait to fail, got %v", err)
+	}
+
+	var none *callLimiter
+	if err := none.wait(ctx); err != nil {
+		t.Errorf("expected a nil limiter not to block, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// rawMethodsRead is the CALENDAR_RAW_METHODS shorthand for every method of
+// gcal_raw_request that doesn't change calendar data
+const rawMethodsRead = "read"
+
+// rawPolicy is the set of Calendar API methods gcal_raw_request may call;
+// a nil policy leaves the tool disabled
+type rawPolicy map[string]gcal.RawMethod
+
+// parseRawPolicy parses a comma-separated list of method names such as
+// "events.list,freebusy.query", where "read" stands for all read-only
+// methods
+func parseRawPolicy(spec string) (rawPolicy, error) {
+	policy := make(rawPolicy)
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if name == rawMethodsRead {
+			for _, m := range gcal.RawMethods() {
+				if !m.Mutating {
+					policy[m.Name] = m
+				}
+			}
+			continue
+		}
+		m, ok := gcal.LookupRawMethod(name)
+		if !ok {
+			return nil, fmt.Errorf("unsupported method %q", name)
+		}
+		policy[name] = m
+	}
+	if len(policy) == 0 {
+		return nil, fmt.Errorf("no methods in %q", spec)
+	}
+	return policy, nil
+}
+
+// names returns the allowed methods, sorted
+func (p rawPolicy) names() []string {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mutating reports whether any allowed method changes calendar data
+func (p rawPolicy) mutating() bool {
+	for _, m := range p {
+		if m.Mutating {
+			return true
+		}
+	}
+	return false
+}
+
+// rawCallMutates reports whether a gcal_raw_request call asks for a method
+// that changes calendar data
+func (s *Server) rawCallMutates(args json.RawMessage) bool {
+	var input rawRequestInput
+	if err := json.Unmarshal(args, &input); err != nil {
+		return false
+	}
+	m, ok := s.rawPolicy[input.Method]
+	return ok && m.Mutating
+}
+
+// rawToolDefinition narrows the gcal_raw_request definition to the methods
+// the policy allows, so that its schema and hints describe what it can do
+func (s *Server) rawToolDefinition(t toolDefinition) toolDefinition {
+	// Writes through the raw API can overwrite or remove events
+	t.mutating = s.rawPolicy.mutating()
+	t.destructive = t.mutating
+
+	properties := make(map[string]interface{})
+	for name, prop := range t.inputSchema["properties"].(map[string]interface{}) {
+		properties[name] = prop
+	}
+	method := make(map[string]interface{})
+	for k, v := range properties["method"].(map[string]interface{}) {
+		method[k] = v
+	}
+	method["enum"] = s.rawPolicy.names()
+	properties["method"] = method
+
+	schema := make(map[string]interface{})
+	for k, v := range t.inputSchema {
+		schema[k] = v
+	}
+	schema["properties"] = properties
+	t.inputSchema = schema
+	return t
+}
+
+// rawRequestInput is the arguments of gcal_raw_request
+type rawRequestInput struct {
+	// API method, e.g. events.list or freebusy.query
+	Method string `json:"method" jsonschema:"required"`
+	// Method parameters named as in the Calendar API reference, e.g.
+	// {"timeMin": "2025-01-06T00:00:00Z", "q": "standup"}; calendarId
+	// defaults to the configured calendar, and body holds the request body
+	// of events.insert, events.patch and freebusy.query
+	Params json.RawMessage `json:"params" jsonschema:"type=object"`
+}
+
+func (s *Server) callRawRequest(ctx context.Context, input rawRequestInput) (rawResponse, error) {
+	m, ok := s.rawPolicy[input.Method]
+	if !ok {
+		return rawResponse{}, badArgumentf("method %q is not allowed; allowed methods: %s", input.Method, strings.Join(s.rawPolicy.names(), ", "))
+	}
+	if m.Mutating && s.isReadOnly() {
+		return rawResponse{}, badArgumentf("Method %s is disabled in read-only mode", m.Name)
+	}
+
+	var params gcal.RawParams
+	if len(input.Params) > 0 {
+		var given map[string]json.RawMessage
+		if err := json.Unmarshal(input.Params, &given); err != nil {
+			return rawResponse{}, badArgumentf("params must be an object")
+		}
+		for name := range given {
+			if !slices.Contains(m.Params(), name) {
+				if len(m.Params()) == 0 {
+					return rawResponse{}, badArgumentf("%s takes no params", m.Name)
+				}
+				return rawResponse{}, badArgumentf("%s does not take %q; it takes %s", m.Name, name, strings.Join(m.Params(), ", "))
+			}
+		}
+		if err := json.Unmarshal(input.Params, &params); err != nil {
+			return rawResponse{}, badArgumentf("Invalid params: %v", err)
+		}
+	}
+	account := gcal.AccountFrom(ctx)
+	calendarID := params.CalendarID
+	if calendarID == "" {
+		calendarID = s.accountService(account).CalendarID()
+	}
+	if m.Mutating && s.calendarReadOnly(account, calendarID) {
+		return rawResponse{}, badArgumentf("Method %s is disabled: you have read-only access to calendar %s", m.Name, calendarID)
+	}
+
+	raw, err := s.settings().calendar.RawRequest(ctx, m.Name, params)
+	if err != nil {
+		return rawResponse{}, err
+	}
+	return rawResponse{raw}, nil
+}
+
+// rawResponse is the output of gcal_raw_request: the Calendar API response
+// as structured content and, indented, as text
+type rawResponse struct {
+	json.RawMessage
+}
+
+func (r rawResponse) toolText(*Server) string {
+	var text bytes.Buffer
+	if err := json.Indent(&text, r.RawMessage, "", "  "); err != nil {
+		return string(r.RawMessage)
+	}
+	return text.String()
+}
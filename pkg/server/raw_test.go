@@ -0,0 +1,151 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRawPolicy(t *testing.T) {
+	policy, err := parseRawPolicy("read, events.patch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := policy["events.list"]; !ok {
+		t.Error("expected read to allow events.list")
+	}
+	if _, ok := policy["events.delete"]; ok {
+		t.Error("expected read not to allow events.delete")
+	}
+	if !policy.mutating() {
+		t.Error("expected events.patch to make the policy mutating")
+	}
+
+	for _, spec := range []string{"events.import", " , "} {
+		if _, err := parseRawPolicy(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestRawRequestTool_Listed(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.setProtocolVersion(protocolVersion20250618)
+	rawTool := func() map[string]interface{} {
+		tools := s.handleToolsList(JSONRPCRequest{ID: float64(1)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+		for _, tool := range tools {
+			if tool["name"] == toolRawRequest {
+				return tool
+			}
+		}
+		return nil
+	}
+
+	if rawTool() != nil {
+		t.Fatal("gcal_raw_request should be hidden without CALENDAR_RAW_METHODS")
+	}
+
+	s.rawPolicy, _ = parseRawPolicy("events.get,events.delete")
+	tool := rawTool()
+	if tool == nil {
+		t.Fatal("expected gcal_raw_request once methods are allowed")
+	}
+	method := tool["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})["method"].(map[string]interface{})
+	if !slices.Equal(method["enum"].([]string), []string{"events.delete", "events.get"}) {
+		t.Errorf("expected the allowed methods as enum, got %v", method["enum"])
+	}
+	if tool["annotations"].(map[string]interface{})["destructiveHint"] != true {
+		t.Error("expected events.delete to make the tool destructive")
+	}
+	def, _ := findTool(toolRawRequest)
+	if def.inputSchema["properties"].(map[string]interface{})["method"].(map[string]interface{})["enum"] != nil {
+		t.Error("narrowing the listed schema should leave the definition alone")
+	}
+}
+
+func TestCallRawRequest(t *testing.T) {
+	fake := &fakeCalendar{raw: json.RawMessage(`{"kind":"calendar#event","id":"abc"}`)}
+	s := newTestServer(fake)
+	s.rawPolicy, _ = parseRawPolicy("events.get,events.delete,colors.get")
+
+	args := json.RawMessage(`{"method":"events.get","params":{"eventId":"abc","calendarId":"team@example.com"}}`)
+	resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(1), args: args})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, `"id": "abc"`) {
+		t.Errorf("expected the raw response, got %s", text)
+	}
+	if fake.rawMethod != "events.get" || fake.rawParams.EventID != "abc" || fake.rawParams.CalendarID != "team@example.com" {
+		t.Errorf("unexpected call: %s %+v", fake.rawMethod, fake.rawParams)
+	}
+
+	for name, args := range map[string]string{
+		"not allowed":   `{"method":"events.list"}`,
+		"unknown param": `{"method":"events.get","params":{"eventId":"abc","fields":"id"}}`,
+		"no params":     `{"method":"colors.get","params":{"calendarId":"x"}}`,
+		"not an object": `{"method":"events.get","params":["abc"]}`,
+	} {
+		if resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(2), args: json.RawMessage(args)}); resp.Error == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	s.readOnly.Store(true)
+	args = json.RawMessage(`{"method":"events.delete","params":{"eventId":"abc"}}`)
+	if resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(3), args: args}); resp.Error == nil || !strings.Contains(resp.Error.Message, "read-only") {
+		t.Errorf("expected events.delete to be refused in read-only mode, got %+v", resp.Error)
+	}
+}
+
+func TestHandleToolsCall_RawRequestDisabled(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	params, _ := json.Marshal(map[string]interface{}{"name": toolRawRequest, "arguments": map[string]string{"method": "events.get"}})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+	if resp.Error == nil || !strings.Contains(resp.Error.Message, "CALENDAR_RAW_METHODS") {
+		t.Errorf("expected the tool to be disabled, got %+v", resp.Error)
+	}
+}
+
+func TestCallRawRequest_Writes(t *testing.T) {
+	fake := &fakeCalendar{
+		raw:           json.RawMessage(`{"id":"abc"}`),
+		calendarList:  []string{"team@example.com", "test@example.com"},
+		calendarRoles: map[string]string{"team@example.com": "reader", "test@example.com": "owner"},
+	}
+	s := newTestServer(fake)
+	s.rawPolicy, _ = parseRawPolicy("events.get,events.delete")
+	s.checkCalendars(context.Background())
+
+	// Only calls of methods that change calendar data are audited
+	for i, args := range []string{
+		`{"method":"events.get","params":{"eventId":"abc"}}`,
+		`{"method":"events.delete","params":{"eventId":"abc"}}`,
+	} {
+		if resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(i), args: json.RawMessage(args)}); resp.Error != nil {
+			t.Fatalf("unexpected error: %+v", resp.Error)
+		}
+	}
+	entries := readAuditEntries(t, s)
+	if len(entries) != 1 || entries[0].Tool != toolRawRequest || !strings.Contains(string(entries[0].Arguments), "events.delete") {
+		t.Errorf("expected the events.delete call to be audited, got %+v", entries)
+	}
+
+	// Writes to a calendar the user may only read are refused, reads aren't
+	fake.rawMethod = ""
+	args := json.RawMessage(`{"method":"events.delete","params":{"eventId":"abc","calendarId":"team@example.com"}}`)
+	resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(3), args: args})
+	if resp.Error == nil || !strings.Contains(resp.Error.Message, "read-only access to calendar team@example.com") {
+		t.Errorf("expected the write to be refused, got %+v", resp.Error)
+	}
+	if fake.rawMethod != "" {
+		t.Errorf("expected no call to the API, got %s", fake.rawMethod)
+	}
+	args = json.RawMessage(`{"method":"events.get","params":{"eventId":"abc","calendarId":"team@example.com"}}`)
+	if resp := s.callTool(context.Background(), &toolCall{name: toolRawRequest, id: float64(4), args: args}); resp.Error != nil {
+		t.Errorf("unexpected error reading a read-only calendar: %+v", resp.Error)
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// reauthenticateInput is the arguments of reauthenticate
+type reauthenticateInput struct {
+	// Authorization code, or the whole address the browser was sent to after allowing access; omit it to get the authorization URL
+	Code string `json:"code"`
+	// Use the device flow instead, for a server without a browser nearby: returns a code to enter at a URL on any device, and the new token is used once it was entered
+	Device bool `json:"device"`
+}
+
+// callReauthenticate walks the user through authorizing the server again
+// after its OAuth token was revoked: without a code it returns the URL to
+// grant access at, and with one it swaps in the new token, so that the
+// server keeps running
+func (s *Server) callReauthenticate(ctx context.Context, input reauthenticateInput) (textOutput, error) {
+	reauthorizer, ok := s.settings().calendar.(gcal.Reauthorizer)
+	if !ok {
+		return "", fmt.Errorf("this calendar backend can't be authorized again; check its credentials and restart the server")
+	}
+	if input.Device {
+		return s.authorizeDevice(ctx)
+	}
+	if input.Code == "" {
+		authURL, err := reauthorizer.AuthURL(ctx)
+		if err != nil {
+			return "", err
+		}
+		return textOutput(fmt.Sprintf("To restore access:\n"+
+			"1. Open %s\n"+
+			"2. Sign in with the calendar's Google account and allow access.\n"+
+			"3. The browser is then sent to an address that may not load. Copy that whole address, or the code in it, and call %s again with it as code.\n",
+			authURL, s.exposedToolName(toolReauthenticate))), nil
+	}
+
+	code, err := gcal.AuthCode(input.Code)
+	if err != nil {
+		return "", err
+	}
+	if err := reauthorizer.Reauthorize(ctx, code); err != nil {
+		return "", err
+	}
+	return "Access restored; the new token is saved and used from now on.", nil
+}
+
+// authorizeDevice starts the device authorization flow and returns the
+// code for the user to enter; the token is swapped in in the background
+func (s *Server) authorizeDevice(ctx context.Context) (textOutput, error) {
+	authorizer, ok := s.settings().calendar.(gcal.DeviceAuthorizer)
+	if !ok {
+		return "", fmt.Errorf("this calendar backend doesn't support the device flow; check its credentials and restart the server")
+	}
+	auth, err := authorizer.AuthorizeDevice(ctx)
+	if err != nil {
+		return "", err
+	}
+	go func() {
+		if err := <-auth.Done; err != nil {
+			log.Printf("Device authorization failed: %v", err)
+			return
+		}
+		log.Printf("Device authorization succeeded; the new token is saved")
+	}()
+	return textOutput(fmt.Sprintf("To restore access:\n"+
+		"1. Open %s on any device.\n"+
+		"2. Enter the code %s and allow access with the calendar's Google account before %s.\n"+
+		"The server picks up the new token on its own; tool calls work again once access is allowed.\n",
+		auth.VerificationURL, auth.UserCode, auth.Expiry.Format("15:04 MST"))), nil
+}
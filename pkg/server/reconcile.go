@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// defaultReconcileAt is when, in the calendar timezone, the nightly
+	// reconciliation runs
+	defaultReconcileAt = "03:00"
+	// defaultReconcileSpacing separates the Calendar API reads of a
+	// reconciliation, so that it doesn't use up the quota tool calls need
+	defaultReconcileSpacing = 2 * time.Second
+)
+
+// Kinds of discrepancy a reconciliation reports
+const (
+	driftCalendarAdded   = "calendar_added"
+	driftCalendarRemoved = "calendar_removed"
+	driftRoleChanged     = "role_changed"
+	// driftSubscriptionStale is a subscribed resource that changed without
+	// the client being told
+	driftSubscriptionStale = "subscription_stale"
+	// driftSubscriptionGone is a subscribed event or calendar that no
+	// longer exists
+	driftSubscriptionGone = "subscription_gone"
+	// driftSubscriptionUnlisted is a subscribed calendar that left the
+	// calendar list but can still be read, such as one shared by email
+	driftSubscriptionUnlisted = "subscription_unlisted"
+)
+
+// discrepancy is a difference a reconciliation found between what the
+// server keeps and what the Calendar API returns
+type discrepancy struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Detail  string `json:"detail,omitempty"`
+	// Fixed is whether the server's state was brought in line
+	Fixed bool `json:"fixed"`
+}
+
+// reconcileReport is what one reconciliation checked, found and fixed
+type reconcileReport struct {
+	// Session is set for the reconciliation of a session's subscriptions
+	Session  string `json:"session,omitempty"`
+	Started  string `json:"started"`
+	Finished string `json:"finished"`
+	// Checked counts calendar list entries and subscriptions compared;
+	// Failed those that couldn't be read
+	Checked       int           `json:"checked"`
+	Failed        int           `json:"failed"`
+	Found         int           `json:"found"`
+	Fixed         int           `json:"fixed"`
+	Discrepancies []discrepancy `json:"discrepancies"`
+}
+
+func newReconcileReport(session string) *reconcileReport {
+	return &reconcileReport{Session: session, Started: time.Now().UTC().Format(time.RFC3339), Discrepancies: []discrepancy{}}
+}
+
+func (r *reconcileReport) add(d discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, d)
+	r.Found++
+	if d.Fixed {
+		r.Fixed++
+	}
+}
+
+func (r *reconcileReport) finish() *reconcileReport {
+	r.Finished = time.Now().UTC().Format(time.RFC3339)
+	return r
+}
+
+// log writes the report to the log as a single JSON line, for scripts to
+// pick up
+func (r *reconcileReport) log() {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+	log.Printf("Reconciliation report: %s", data)
+}
+
+// nextReconcile returns when the nightly reconciliation runs next after
+// now, in loc, or the zero time when it is turned off
+func (s *Server) nextReconcile(now time.Time, loc *time.Location) time.Time {
+	clock, err := time.Parse("15:04", s.reconcileAt)
+	if err != nil {
+		return time.Time{}
+	}
+	now = now.In(loc)
+	next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	return next
+}
+
+// reconcileAfter returns a channel that fires at the next reconciliation,
+// or nil when reconciliation is turned off
+func (s *Server) reconcileAfter(loc *time.Location) <-chan time.Time {
+	next := s.nextReconcile(time.Now(), loc)
+	if next.IsZero() {
+		return nil
+	}
+	return time.After(time.Until(next))
+}
+
+// runReconciliation reconciles the calendar list every night until the
+// process exits. Subscriptions are reconciled by the sessions that hold
+// them, see pollSubscriptions.
+func (s *Server) runReconciliation() {
+	for {
+		next := s.reconcileAfter(s.configured().location)
+		if next == nil {
+			return
+		}
+		<-next
+		s.reconcileCalendarList(context.Background()).log()
+	}
+}
+
+// pace waits out the spacing between two Calendar API reads of a
+// reconciliation. It reports false, to stop, once the session has ended.
+func (s *Server) pace() bool {
+	timer := time.NewTimer(s.reconcileSpacing)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-s.ended:
+		return false
+	}
+}
+
+// reconcileCalendarList reads the calendar lists afresh and compares them
+// with the calendars and access roles the server keeps, which the regular
+// sync leaves stale for accounts whose list couldn't be read. The fresh
+// lists are then stored.
+func (s *Server) reconcileCalendarList(ctx context.Context) *reconcileReport {
+	report := newReconcileReport("")
+	cal := s.configured().calendar
+	ids, roles, err := readCalendarLists(ctx, cal, s.pace)
+	if err != nil {
+		log.Printf("Failed to reconcile calendar list: %v", err)
+		report.Failed++
+		return report.finish()
+	}
+	for _, read := range roles {
+		report.Checked += len(read)
+	}
+
+	s.calendarList.mu.Lock()
+	knownIDs, knownRoles := s.calendarList.ids, s.calendarList.roles
+	s.calendarList.mu.Unlock()
+	// Before the first sync there is nothing to compare with
+	if knownIDs != nil {
+		for _, id := range ids {
+			if !slices.Contains(knownIDs, id) {
+				report.add(discrepancy{Kind: driftCalendarAdded, Subject: id, Fixed: true})
+			}
+		}
+		for _, id := range knownIDs {
+			if !slices.Contains(ids, id) {
+				report.add(discrepancy{Kind: driftCalendarRemoved, Subject: id, Fixed: true})
+			}
+		}
+		for _, account := range slices.Sorted(maps.Keys(roles)) {
+			for _, id := range slices.Sorted(maps.Keys(roles[account])) {
+				known, ok := knownRoles[account][id]
+				if !ok || known == roles[account][id] {
+					continue
+				}
+				detail := fmt.Sprintf("was %s, now %s", known, roles[account][id])
+				if account != "" {
+					detail = fmt.Sprintf("account %s: %s", account, detail)
+				}
+				report.add(discrepancy{Kind: driftRoleChanged, Subject: id, Detail: detail, Fixed: true})
+			}
+		}
+	}
+	s.storeCalendarList(cal, ids, roles)
+	return report.finish()
+}
+
+// reconcileSubscriptions re-reads every subscribed resource of the session
+// and compares it with what the session last told the client and with the
+// calendar list. Subscriptions of events and calendars that are gone are
+// dropped, after a last notification that makes the client read the error.
+func (s *Server) reconcileSubscriptions(ctx context.Context) *reconcileReport {
+	report := newReconcileReport(s.currentSessionID())
+	s.subMu.Lock()
+	uris := slices.Sorted(maps.Keys(s.subscriptions))
+	s.subMu.Unlock()
+
+	for i, uri := range uris {
+		if i > 0 && !s.pace() {
+			break
+		}
+		text, err := s.readResource(ctx, uri)
+		if err != nil {
+			if code := errorCode(err); code != errCodeEventNotFound && code != errCodeCalendarNotFound {
+				log.Printf("Failed to reconcile resource %s: %v", uri, err)
+				report.Failed++
+				continue
+			}
+			s.subMu.Lock()
+			_, subscribed := s.subscriptions[uri]
+			delete(s.subscriptions, uri)
+			s.subMu.Unlock()
+			if subscribed {
+				report.Checked++
+				report.add(discrepancy{Kind: driftSubscriptionGone, Subject: uri, Detail: err.Error(), Fixed: true})
+				s.sendNotification("notifications/resources/updated", map[string]string{"uri": uri})
+			}
+			continue
+		}
+		report.Checked++
+
+		fingerprint := resourceFingerprint(text)
+		s.subMu.Lock()
+		previous, subscribed := s.subscriptions[uri]
+		stale := subscribed && previous != fingerprint
+		if stale {
+			s.subscriptions[uri] = fingerprint
+		}
+		s.subMu.Unlock()
+		if stale {
+			report.add(discrepancy{Kind: driftSubscriptionStale, Subject: uri, Fixed: true})
+			s.sendNotification("notifications/resources/updated", map[string]string{"uri": uri})
+		}
+
+		if calendarID, ok := parseCalendarResourceURI(uri); ok && s.unlistedCalendar(calendarID) {
+			report.add(discrepancy{
+				Kind:    driftSubscriptionUnlisted,
+				Subject: uri,
+				Detail:  fmt.Sprintf("calendar %s is no longer in the calendar list but can still be read; the subscription is kept", calendarID),
+			})
+		}
+	}
+	return report.finish()
+}
+
+// unlistedCalendar reports whether a calendar sync has happened and
+// didn't list calendarID
+func (s *Server) unlistedCalendar(calendarID string) bool {
+	s.calendarList.mu.Lock()
+	synced := s.calendarList.ids != nil
+	s.calendarList.mu.Unlock()
+	return synced && !s.knownCalendar(calendarID)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// goneCalendar is a fakeCalendar on which some events and calendars were
+// deleted, and which can read calendars shared by email
+type goneCalendar struct {
+	*fakeCalendar
+	goneEvents    []string
+	goneCalendars []string
+	shared        map[string][]gcal.CalendarEvent
+}
+
+func (g *goneCalendar) GetEvent(ctx context.Context, eventID string) (*calendar.Event, error) {
+	for _, id := range g.goneEvents {
+		if id == eventID {
+			return nil, &googleapi.Error{Code: 404, Message: "Not Found"}
+		}
+	}
+	return g.fakeCalendar.GetEvent(ctx, eventID)
+}
+
+func (g *goneCalendar) ListCalendarEvents(ctx context.Context, calendarID, start, end string) ([]gcal.CalendarEvent, error) {
+	for _, id := range g.goneCalendars {
+		if id == calendarID {
+			return nil, &googleapi.Error{Code: 404, Message: "Not Found"}
+		}
+	}
+	if events, ok := g.shared[calendarID]; ok {
+		return events, nil
+	}
+	return g.fakeCalendar.ListCalendarEvents(ctx, calendarID, start, end)
+}
+
+func TestReconcileCalendarList(t *testing.T) {
+	fake := &fakeCalendar{
+		calendarList:  []string{"team@example.com", "test@example.com"},
+		calendarRoles: map[string]string{"team@example.com": "writer", "test@example.com": "owner"},
+	}
+	out := &bytes.Buffer{}
+	s := New(fake, out)
+	s.reconcileSpacing = 0
+
+	// Before the first sync there is nothing to compare with
+	if report := s.reconcileCalendarList(context.Background()); report.Checked != 2 || report.Found != 0 {
+		t.Fatalf("expected 2 calendars checked and nothing found, got %+v", report)
+	}
+	out.Reset()
+
+	fake.calendarList = []string{"ops@example.com", "test@example.com"}
+	fake.calendarRoles = map[string]string{"ops@example.com": "reader", "test@example.com": "reader"}
+	report := s.reconcileCalendarList(context.Background())
+	want := []discrepancy{
+		{Kind: driftCalendarAdded, Subject: "ops@example.com", Fixed: true},
+		{Kind: driftCalendarRemoved, Subject: "team@example.com", Fixed: true},
+		{Kind: driftRoleChanged, Subject: "test@example.com", Detail: "was owner, now reader", Fixed: true},
+	}
+	if len(report.Discrepancies) != len(want) || report.Found != 3 || report.Fixed != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for i, d := range want {
+		if report.Discrepancies[i] != d {
+			t.Errorf("discrepancy %d: got %+v, want %+v", i, report.Discrepancies[i], d)
+		}
+	}
+	if report.Finished == "" {
+		t.Error("expected the report to be finished")
+	}
+
+	// The drift is repaired: the calendars and roles are stored and the
+	// client told
+	if !s.knownCalendar("ops@example.com") || s.knownCalendar("team@example.com") || !s.calendarReadOnly("", "test@example.com") {
+		t.Error("expected the fresh calendar list to be stored")
+	}
+	if !strings.Contains(out.String(), "notifications/resources/list_changed") || !strings.Contains(out.String(), "notifications/tools/list_changed") {
+		t.Errorf("expected list_changed notifications, got %q", out.String())
+	}
+	if report := s.reconcileCalendarList(context.Background()); report.Found != 0 {
+		t.Errorf("expected nothing left to fix, got %+v", report.Discrepancies)
+	}
+}
+
+func TestReconcileSubscriptions(t *testing.T) {
+	fake := &goneCalendar{
+		fakeCalendar:  &fakeCalendar{calendarList: []string{"test@example.com"}},
+		goneCalendars: []string{"gone@example.com"},
+		// Readable, but not in the calendar list
+		shared: map[string][]gcal.CalendarEvent{"shared@example.com": {{ID: "sync", Summary: "Sync"}}},
+	}
+	out := &bytes.Buffer{}
+	s := New(fake, out)
+	s.reconcileSpacing = 0
+	s.checkCalendars(context.Background())
+
+	event := eventResourceURI(fake.CalendarID(), "review")
+	shared := calendarResourceURI("shared@example.com")
+	gone := calendarResourceURI("gone@example.com")
+	for _, uri := range []string{event, shared, resourceUpcomingEvents} {
+		text, err := s.readResource(context.Background(), uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.subscriptions[uri] = resourceFingerprint(text)
+	}
+	s.subscriptions[gone] = ""
+	// A change the polls missed
+	s.subscriptions[resourceUpcomingEvents] = "stale"
+	fake.goneEvents = []string{"review"}
+
+	report := s.reconcileSubscriptions(context.Background())
+	kinds := make(map[string]string)
+	for _, d := range report.Discrepancies {
+		kinds[d.Subject] = d.Kind
+	}
+	want := map[string]string{
+		event:                  driftSubscriptionGone,
+		gone:                   driftSubscriptionGone,
+		shared:                 driftSubscriptionUnlisted,
+		resourceUpcomingEvents: driftSubscriptionStale,
+	}
+	if len(kinds) != len(want) || report.Checked != 4 || report.Fixed != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for uri, kind := range want {
+		if kinds[uri] != kind {
+			t.Errorf("%s: got %q, want %q", uri, kinds[uri], kind)
+		}
+	}
+
+	// Subscriptions of what is gone are dropped after telling the client
+	if _, ok := s.subscriptions[event]; ok {
+		t.Error("expected the subscription of the deleted event to be dropped")
+	}
+	if _, ok := s.subscriptions[shared]; !ok {
+		t.Error("expected the subscription of the readable calendar to be kept")
+	}
+	if n := strings.Count(out.String(), "notifications/resources/updated"); n != 3 {
+		t.Errorf("expected 3 notifications, got %d: %q", n, out.String())
+	}
+}
+
+func TestNextReconcile(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	for _, tt := range []struct{ now, want string }{
+		{"2026-03-16T01:00:00+01:00", "2026-03-16T03:00:00+01:00"},
+		{"2026-03-16T03:00:00+01:00", "2026-03-17T03:00:00+01:00"},
+		// Across the switch to summer time
+		{"2026-03-28T22:00:00+01:00", "2026-03-29T03:00:00+02:00"},
+	} {
+		now, _ := time.Parse(time.RFC3339, tt.now)
+		if got := s.nextReconcile(now, loc).Format(time.RFC3339); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.now, got, tt.want)
+		}
+	}
+
+	s.reconcileAt = ""
+	if next := s.nextReconcile(time.Now(), loc); !next.IsZero() {
+		t.Errorf("expected no reconciliation when turned off, got %s", next)
+	}
+}
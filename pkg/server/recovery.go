@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// doctorCommand checks the configuration the way the server does at
+// startup, and is what recovery steps ask the user to run
+const doctorCommand = "google-calendar-mcp --doctor"
+
+// authRecovery tells an agent how to walk the user through fixing
+// credentials that stopped working
+type authRecovery struct {
+	Problem string `json:"problem"`
+	// Settings are the environment variables to check or change
+	Settings []string `json:"settings"`
+	Steps    []string `json:"steps"`
+	// Command checks the configuration once it is fixed
+	Command string `json:"command"`
+	// AuthURL is where the user grants access again in OAuth mode
+	AuthURL string `json:"authUrl,omitempty"`
+}
+
+// recoveryFor returns the recovery steps of an UNAUTHENTICATED error
+func recoveryFor(err error) *authRecovery {
+	var authErr *gcal.AuthError
+	if errors.As(err, &authErr) {
+		r := &authRecovery{
+			Problem:  "The OAuth refresh token expired or access was revoked; the user has to authorize the server again.",
+			Settings: []string{"GOOGLE_OAUTH_TOKEN_FILE", "GOOGLE_CREDENTIALS_FILE", "GOOGLE_OAUTH_TOKEN_STORE"},
+			Command:  doctorCommand,
+			AuthURL:  authErr.AuthURL,
+		}
+		if r.AuthURL != "" {
+			r.Steps = append(r.Steps,
+				"Open the authorization URL, sign in with the calendar's Google account and allow access.",
+				"Copy the address the browser is sent to afterwards, even if the page doesn't load, and call the "+toolReauthenticate+" tool with it as code. The server saves the new token and keeps running.",
+			)
+		} else {
+			r.Steps = append(r.Steps,
+				"Authorize the OAuth client of GOOGLE_CREDENTIALS_FILE again, e.g. with Google's Go quickstart.",
+				"Save the new token to the file GOOGLE_OAUTH_TOKEN_FILE names. With GOOGLE_OAUTH_TOKEN_STORE=keychain, also delete the old item from the keychain so that the file is imported again.",
+				"Restart the server.",
+			)
+		}
+		r.Steps = append(r.Steps, "Run "+doctorCommand+" to confirm that the calendar is reachable.")
+		return r
+	}
+	return &authRecovery{
+		Problem:  "Google rejected the server's credentials, e.g. because the service account key was deleted or disabled.",
+		Settings: []string{"GOOGLE_CREDENTIALS_FILE"},
+		Steps: []string{
+			"Check that GOOGLE_CREDENTIALS_FILE points to a current key; with several accounts, the variable ends in the account name, like GOOGLE_CREDENTIALS_FILE_WORK.",
+			"If the key is gone, create a new one in the Google Cloud console under IAM & Admin → Service Accounts → Keys and point GOOGLE_CREDENTIALS_FILE to it.",
+			"Restart the server.",
+			"Run " + doctorCommand + " to confirm that the calendar is reachable.",
+		},
+		Command: doctorCommand,
+	}
+}
+
+// text renders the recovery steps for the text content of an error
+func (r *authRecovery) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\nTo fix it:\n", r.Problem)
+	for i, step := range r.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	if r.AuthURL != "" {
+		fmt.Fprintf(&b, "Authorization URL: %s\n", r.AuthURL)
+	}
+	return b.String()
+}
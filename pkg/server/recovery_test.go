@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/googleapi"
+)
+
+func TestErrorResponse_Recovery(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.setProtocolVersion(protocolVersion20250618)
+
+	authErr := &gcal.AuthError{Err: errors.New("invalid_grant"), AuthURL: "https://accounts.google.com/o/oauth2/auth?access_type=offline"}
+	result := s.errorResponse(float64(1), authErr).Result.(map[string]interface{})
+	text := result["content"].([]map[string]interface{})[0]["text"].(string)
+	for _, want := range []string{"Error [UNAUTHENTICATED]", "To fix it:\n1. Open the authorization URL", "Authorization URL: " + authErr.AuthURL, doctorCommand} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	detail := result["structuredContent"].(map[string]interface{})["error"].(errorDetail)
+	if detail.Recovery == nil || detail.Recovery.AuthURL != authErr.AuthURL {
+		t.Errorf("expected the recovery payload, got %+v", detail.Recovery)
+	}
+
+	// Credentials files get pointed at their key instead
+	recovery := describeError(&googleapi.Error{Code: 401}).Recovery
+	if recovery == nil || recovery.AuthURL != "" || recovery.Settings[0] != "GOOGLE_CREDENTIALS_FILE" {
+		t.Errorf("expected service account recovery steps, got %+v", recovery)
+	}
+
+	if describeError(context.DeadlineExceeded).Recovery != nil {
+		t.Error("expected no recovery steps for other errors")
+	}
+}
+
+func TestCallReauthenticate(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+
+	text := func(resp *JSONRPCResponse) string {
+		t.Helper()
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %+v", resp.Error)
+		}
+		return resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	}
+
+	if got := text(callWithArgs(s, toolReauthenticate, `{}`)); !strings.Contains(got, "1. Open https://accounts.google.com/o/oauth2/auth") {
+		t.Errorf("expected the authorization URL, got:\n%s", got)
+	}
+	if got := text(callWithArgs(s, toolReauthenticate, `{"code":"http://localhost/?state=google-calendar-mcp&code=4/abc&scope=calendar"}`)); !strings.Contains(got, "Access restored") || fake.reauthCode != "4/abc" {
+		t.Errorf("expected the code of the redirect address to be exchanged, got %q:\n%s", fake.reauthCode, got)
+	}
+	if got := text(callWithArgs(s, toolReauthenticate, `{"device":true}`)); !strings.Contains(got, "Open https://www.google.com/device") || !strings.Contains(got, "Enter the code ABCD-EFGH") {
+		t.Errorf("expected the device code, got:\n%s", got)
+	}
+	resp := callWithArgs(s, toolReauthenticate, `{"code":"http://localhost/?error=access_denied"}`)
+	if result, _ := resp.Result.(map[string]interface{}); result["isError"] != true {
+		t.Errorf("expected a denied authorization to fail, got %+v", resp)
+	}
+}
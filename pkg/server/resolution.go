@@ -0,0 +1,227 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	// maxMovableAttendees is the largest meeting suggested for moving;
+	// rescheduling bigger ones takes more than one person's calendar
+	maxMovableAttendees = 4
+	// maxSuggestedSlots is how many alternate slots a resolution offers
+	maxSuggestedSlots = 3
+	// slotStep aligns suggested slots to the half hour
+	slotStep = 30 * time.Minute
+)
+
+// resolution proposes moving one event of a conflict to a free slot
+type resolution struct {
+	MoveEventID string `json:"moveEventId"`
+	MoveSummary string `json:"moveSummary"`
+	Reason      string `json:"reason"`
+	Slots       []slot `json:"slots"`
+}
+
+// slot is a free time for the moved event, in the configured timezone
+type slot struct {
+	Date      string `json:"date"`
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime"`
+}
+
+// movable reports whether the user can move an event on their own: it is
+// on the primary calendar, they organize it and only a few people attend
+func (s *Server) movable(ctx context.Context, e gcal.CalendarEvent) bool {
+	if e.CalendarID != "" && e.CalendarID != s.primaryCalendar(ctx) {
+		return false
+	}
+	return e.OrganizerSelf && e.Attendees <= maxMovableAttendees
+}
+
+// suggestResolution picks the event of a conflict that is easiest to move,
+// preferring fewer attendees and then the shorter event, and finds free
+// working-hours slots for it from the day of the conflict to lastDay that
+// the schedule constraints allow. It returns nil when neither event is
+// movable.
+func (s *Server) suggestResolution(ctx context.Context, c conflict, events []gcal.CalendarEvent, now, lastDay time.Time) *resolution {
+	var candidates []gcal.CalendarEvent
+	for _, e := range []gcal.CalendarEvent{c.Second, c.First} {
+		if s.movable(ctx, e) {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	move := candidates[0]
+	if len(candidates) == 2 {
+		other := candidates[1]
+		if other.Attendees < move.Attendees ||
+			other.Attendees == move.Attendees && eventLength(other) < eventLength(move) {
+			move = other
+		}
+	}
+
+	from, err := time.Parse(time.RFC3339, c.OverlapStart)
+	if err != nil {
+		return nil
+	}
+	return &resolution{
+		MoveEventID: move.ID,
+		MoveSummary: move.Summary,
+		Reason:      fmt.Sprintf("you organize it and %d attendee(s) are invited", move.Attendees),
+		Slots: freeSlots(move, events, maxTime(from, now).In(s.settings().location), lastDay, s.workHours, func(start, end time.Time) bool {
+			return s.settings().calendar.CheckSchedule(ctx, start, end) == nil
+		}),
+	}
+}
+
+func eventLength(e gcal.CalendarEvent) time.Duration {
+	start, err := time.Parse(time.RFC3339, e.Start)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse(time.RFC3339, e.End)
+	if err != nil {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// freeSlots returns up to maxSuggestedSlots times within working hours,
+// from "from" through lastDay, where e fits without overlapping any other
+// event that blocks time and that allowed accepts
+func freeSlots(e gcal.CalendarEvent, events []gcal.CalendarEvent, from, lastDay time.Time, hours workHours, allowed func(start, end time.Time) bool) []slot {
+	length := eventLength(e)
+	result := []slot{}
+	if length <= 0 {
+		return result
+	}
+
+	var others []gcal.CalendarEvent
+	for _, other := range events {
+		if other.ID != e.ID && blocksTime(other) {
+			others = append(others, other)
+		}
+	}
+	busy := timedIntervals(others, from.Location())
+
+	loc := from.Location()
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	for ; !day.After(lastDay) && len(result) < maxSuggestedSlots; day = day.AddDate(0, 0, 1) {
+		if !hours.days[day.Weekday()] {
+			continue
+		}
+		workEnd := day.Add(hours.end)
+		start := day.Add(hours.start)
+		for start.Before(from) {
+			start = start.Add(slotStep)
+		}
+		for ; !start.Add(length).After(workEnd) && len(result) < maxSuggestedSlots; start = start.Add(slotStep) {
+			end := start.Add(length)
+			if !overlapsAny(busy, start, end) && allowed(start, end) {
+				result = append(result, slot{
+					Date:      start.Format("2006-01-02"),
+					StartTime: start.Format("15:04"),
+					EndTime:   end.Format("15:04"),
+				})
+			}
+		}
+	}
+	return result
+}
+
+func overlapsAny(busy []interval, start, end time.Time) bool {
+	for _, b := range busy {
+		if b.start.Before(end) && b.end.After(start) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) formatResolution(r *resolution) string {
+	if r == nil {
+		return fmt.Sprintf("  No suggestion: neither event is yours to move (organized by you, at most %d attendees)\n", maxMovableAttendees)
+	}
+	text := fmt.Sprintf("  Suggestion: move %q (ID %s), %s.", s.sanitize(r.MoveSummary), r.MoveEventID, r.Reason)
+	if len(r.Slots) == 0 {
+		return text + " No free slot found in the checked range.\n"
+	}
+	slots := make([]string, 0, len(r.Slots))
+	for _, sl := range r.Slots {
+		slots = append(slots, fmt.Sprintf("%s %s-%s", sl.Date, sl.StartTime, sl.EndTime))
+	}
+	return text + " Free slots: " + strings.Join(slots, ", ") + " (use apply_resolution)\n"
+}
+
+// applyResolutionInput is the arguments of apply_resolution
+type applyResolutionInput struct {
+	// ID of the event to move (moveEventId of the resolution)
+	EventID string `json:"event_id" jsonschema:"required"`
+	// Date of the chosen slot in YYYY-MM-DD format
+	Date string `json:"date" jsonschema:"required"`
+	// Start time of the chosen slot in HH:MM format; the event keeps its
+	// duration
+	StartTime string `json:"start_time" jsonschema:"required"`
+	// Move the event even if the slot is no longer free or is blocked by the
+	// configured schedule constraints (optional)
+	Force bool `json:"force"`
+}
+
+func (s *Server) callApplyResolution(ctx context.Context, input applyResolutionInput) (textOutput, error) {
+	if input.EventID == "" || input.Date == "" || input.StartTime == "" {
+		return "", badArgumentf("event_id, date and start_time are required (use find_conflicts for suggestions)")
+	}
+	if err := s.normalizeDateArg(&input.Date); err != nil {
+		return "", badArgument(err)
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", input.Date+" "+input.StartTime, s.settings().location)
+	if err != nil {
+		return "", badArgumentf("invalid date or start_time: %v", err)
+	}
+
+	existing, err := s.settings().calendar.GetEvent(ctx, input.EventID)
+	if err != nil {
+		return "", err
+	}
+	length, ok := gcal.EventDuration(existing)
+	if !ok {
+		return "", invalidInputf("only timed events can be moved")
+	}
+	end := start.Add(length)
+
+	if !input.Force {
+		events, err := s.listAllCalendars(ctx, input.Date, end.Format("2006-01-02"))
+		if err != nil {
+			return "", err
+		}
+		for _, e := range events {
+			if e.ID == input.EventID || !blocksTime(e) {
+				continue
+			}
+			if overlapsAny(timedIntervals([]gcal.CalendarEvent{e}, s.settings().location), start, end) {
+				return "", withErrorCode(errCodeConflict,
+					fmt.Errorf("the slot is no longer free: it overlaps %q (ID %s); pick another slot or set force", s.sanitize(e.Summary), e.ID))
+			}
+		}
+	}
+
+	startTime := start.Format("15:04")
+	event, err := s.settings().calendar.UpdateEvent(ctx, input.EventID, gcal.EventUpdates{
+		Date:      &input.Date,
+		StartTime: &startTime,
+		Force:     input.Force,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return textOutput(fmt.Sprintf("Conflict resolved: moved %s to %s %s-%s\nID: %s\nLink: %s",
+		s.sanitize(event.Summary), input.Date, startTime, end.Format("15:04"), event.Id, event.HtmlLink)), nil
+}
@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestSuggestResolution(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	// 2026-03-16 is a Monday
+	events := []gcal.CalendarEvent{
+		{ID: "a", Summary: "All hands", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:00:00Z", Attendees: 40},
+		{ID: "b", Summary: "1:1", Start: "2026-03-16T09:30:00Z", End: "2026-03-16T10:00:00Z", Attendees: 2, OrganizerSelf: true},
+		{ID: "c", Summary: "Review", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+	}
+	days := findConflicts(events, time.UTC)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
+
+	r := s.suggestResolution(context.Background(), days[0].Conflicts[0], events, now, lastDay)
+	if r == nil || r.MoveEventID != "b" {
+		t.Fatalf("expected to move the 1:1, got %+v", r)
+	}
+	want := []slot{
+		{Date: "2026-03-16", StartTime: "11:00", EndTime: "11:30"},
+		{Date: "2026-03-16", StartTime: "11:30", EndTime: "12:00"},
+		{Date: "2026-03-16", StartTime: "12:00", EndTime: "12:30"},
+	}
+	if len(r.Slots) != len(want) {
+		t.Fatalf("expected %d slots, got %+v", len(want), r.Slots)
+	}
+	for i := range want {
+		if r.Slots[i] != want[i] {
+			t.Errorf("slot %d: expected %+v, got %+v", i, want[i], r.Slots[i])
+		}
+	}
+
+	// Nothing is suggested when both events belong to someone else
+	c := days[0].Conflicts[0]
+	c.Second.OrganizerSelf = false
+	if r := s.suggestResolution(context.Background(), c, events, now, lastDay); r != nil {
+		t.Errorf("expected no suggestion, got %+v", r)
+	}
+}
+
+func TestFreeSlots_SkipsPastAndNonWorkingTime(t *testing.T) {
+	e := gcal.CalendarEvent{ID: "x", Start: "2026-03-20T16:00:00Z", End: "2026-03-20T17:00:00Z"}
+	// Friday 16:10 is too late for a one-hour slot, and the weekend is off
+	from := time.Date(2026, 3, 20, 16, 10, 0, 0, time.UTC)
+	lastDay := time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC)
+
+	slots := freeSlots(e, nil, from, lastDay, defaultWorkHours, func(time.Time, time.Time) bool { return true })
+	if len(slots) != maxSuggestedSlots || slots[0] != (slot{Date: "2026-03-23", StartTime: "09:00", EndTime: "10:00"}) {
+		t.Errorf("expected slots on Monday morning, got %+v", slots)
+	}
+}
+
+func TestCallApplyResolution(t *testing.T) {
+	fake := &fakeCalendar{
+		fetched: &calendar.Event{
+			Id:    "b",
+			Start: &calendar.EventDateTime{DateTime: "2026-03-16T09:30:00Z"},
+			End:   &calendar.EventDateTime{DateTime: "2026-03-16T10:00:00Z"},
+		},
+		updated: &calendar.Event{Id: "b", Summary: "1:1"},
+		events:  []gcal.CalendarEvent{{ID: "c", Summary: "Review", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"}},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"event_id": "b", "date": "2026-03-16", "start_time": "11:00"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolApplyResolution, id: float64(1), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "moved 1:1 to 2026-03-16 11:00-11:30") {
+		t.Errorf("unexpected result: %s", text)
+	}
+	if *fake.lastUpdate.Date != "2026-03-16" || *fake.lastUpdate.StartTime != "11:00" || fake.lastUpdate.EndTime != nil {
+		t.Errorf("unexpected update %+v", fake.lastUpdate)
+	}
+
+	// A slot that has been taken in the meantime is refused
+	args, _ = json.Marshal(map[string]string{"event_id": "b", "date": "2026-03-16", "start_time": "10:30"})
+	resp = s.callTool(context.Background(), &toolCall{name: toolApplyResolution, id: float64(2), args: args})
+	text = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Error [CONFLICT]") || !strings.Contains(text, "Review") {
+		t.Errorf("expected a conflict error, got %s", text)
+	}
+
+	fake.err = errors.New("boom")
+	resp = s.callTool(context.Background(), &toolCall{name: toolApplyResolution, id: float64(3), args: args})
+	if resp.Result.(map[string]interface{})["isError"] != true {
+		t.Error("expected an error result when the event can't be fetched")
+	}
+}
+
+func TestSuggestResolution_ScheduleConstraints(t *testing.T) {
+	// Lunch is blocked, so the slots skip to the afternoon
+	fake := &fakeCalendar{scheduleBlocked: [2]time.Time{
+		time.Date(2026, 3, 16, 11, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC),
+	}}
+	s := newTestServer(fake)
+	events := []gcal.CalendarEvent{
+		{ID: "a", Summary: "All hands", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:00:00Z", Attendees: 40},
+		{ID: "b", Summary: "1:1", Start: "2026-03-16T09:30:00Z", End: "2026-03-16T10:00:00Z", Attendees: 2, OrganizerSelf: true},
+		{ID: "c", Summary: "Review", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+	}
+	days := findConflicts(events, time.UTC)
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC)
+
+	r := s.suggestResolution(context.Background(), days[0].Conflicts[0], events, now, lastDay)
+	if r == nil || len(r.Slots) == 0 || r.Slots[0] != (slot{Date: "2026-03-16", StartTime: "12:00", EndTime: "12:30"}) {
+		t.Fatalf("expected the first slot after the blocked time, got %+v", r)
+	}
+}
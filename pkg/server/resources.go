@@ -0,0 +1,529 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	resourceUpcomingEvents = "calendar://events/upcoming"
+
+	upcomingResourceDays = 7
+	maxRangeResourceDays = 90
+	defaultPollInterval  = time.Minute
+)
+
+func (s *Server) handleResourcesList(req JSONRPCRequest) *JSONRPCResponse {
+	resources := []map[string]interface{}{
+		{
+			"uri":         resourceUpcomingEvents,
+			"name":        "Upcoming events",
+			"description": "Calendar events for the next 7 days",
+			"mimeType":    "text/plain",
+		},
+		{
+			"uri":         resourceAudit,
+			"name":        "Recent changes",
+			"description": fmt.Sprintf("The last %d calls of tools that change the calendar, as JSON, oldest first", maxAuditEntries),
+			"mimeType":    "application/json",
+		},
+	}
+	resources = append(resources, s.calendarResources()...)
+
+	cursor, err := cursorParam(req.Params)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+	page, next, err := paginate(resources, cursor, listPageLimit)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	result := map[string]interface{}{
+		"resources": page,
+	}
+	if next != "" {
+		result["nextCursor"] = next
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+// resourceTemplates describe the parameterized URIs readResource accepts,
+// so clients can build reads without calling a tool first
+var resourceTemplates = []map[string]interface{}{
+	{
+		"uriTemplate": "calendar://{calendarId}/events/{eventId}",
+		"name":        "Event",
+		"description": "Full details of a single event of the primary calendar",
+		"mimeType":    "text/plain",
+	},
+	{
+		"uriTemplate": "calendar://{calendarId}/range/{start}/{end}",
+		"name":        "Events in a date range",
+		"description": fmt.Sprintf("Events of a calendar between two dates in YYYY-MM-DD format, inclusive (at most %d days)", maxRangeResourceDays),
+		"mimeType":    "text/plain",
+	},
+	{
+		"uriTemplate": "calendar://{calendarId}/upcoming",
+		"name":        "Upcoming events of a calendar",
+		"description": fmt.Sprintf("Events of a calendar for the next %d days", upcomingResourceDays),
+		"mimeType":    "text/plain",
+	},
+}
+
+func (s *Server) handleResourceTemplatesList(req JSONRPCRequest) *JSONRPCResponse {
+	cursor, err := cursorParam(req.Params)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+	page, next, err := paginate(resourceTemplates, cursor, listPageLimit)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	result := map[string]interface{}{
+		"resourceTemplates": page,
+	}
+	if next != "" {
+		result["nextCursor"] = next
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+func (s *Server) handleResourcesRead(req JSONRPCRequest) *JSONRPCResponse {
+	uri, errResp := s.resourceURIParam(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	text, err := s.readResource(context.Background(), uri)
+	if err != nil {
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32603,
+				Message: "Failed to read resource",
+				Data:    err.Error(),
+			},
+		}
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"contents": []map[string]string{
+				{"uri": uri, "mimeType": resourceMimeType(uri), "text": text},
+			},
+		},
+	}
+}
+
+func (s *Server) handleResourcesSubscribe(req JSONRPCRequest) *JSONRPCResponse {
+	uri, errResp := s.resourceURIParam(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	// Record the current state so the first poll only reports real changes
+	fingerprint := ""
+	if text, err := s.readResource(context.Background(), uri); err == nil {
+		fingerprint = resourceFingerprint(text)
+	}
+
+	s.subMu.Lock()
+	s.subscriptions[uri] = fingerprint
+	s.subMu.Unlock()
+	if uri == resourceAudit {
+		s.audit.watch(s)
+	}
+
+	s.pollOnce.Do(func() {
+		go s.pollSubscriptions()
+	})
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{},
+	}
+}
+
+func (s *Server) handleResourcesUnsubscribe(req JSONRPCRequest) *JSONRPCResponse {
+	uri, errResp := s.resourceURIParam(req)
+	if errResp != nil {
+		return errResp
+	}
+
+	s.subMu.Lock()
+	delete(s.subscriptions, uri)
+	s.subMu.Unlock()
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  map[string]interface{}{},
+	}
+}
+
+// resourceURIParam extracts and validates the uri parameter shared by the
+// resources/read, resources/subscribe and resources/unsubscribe methods.
+func (s *Server) resourceURIParam(req JSONRPCRequest) (string, *JSONRPCResponse) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return "", s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	if !s.knownResource(params.URI) {
+		return "", &JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32002,
+				Message: "Resource not found",
+				Data:    map[string]string{"uri": params.URI},
+			},
+		}
+	}
+
+	return params.URI, nil
+}
+
+func (s *Server) knownResource(uri string) bool {
+	if uri == resourceUpcomingEvents || uri == resourceAudit {
+		return true
+	}
+	if calendarID, ok := parseCalendarResourceURI(uri); ok {
+		return s.knownCalendar(calendarID)
+	}
+	if calendarID, start, end, ok := parseRangeResourceURI(uri); ok {
+		return s.knownCalendar(calendarID) && validResourceRange(start, end)
+	}
+	calendarID, _, ok := parseEventResourceURI(uri)
+	return ok && calendarID == s.settings().calendar.CalendarID()
+}
+
+func (s *Server) readResource(ctx context.Context, uri string) (string, error) {
+	if uri == resourceAudit {
+		return s.readAuditResource()
+	}
+	if _, eventID, ok := parseEventResourceURI(uri); ok {
+		event, err := s.settings().calendar.GetEvent(ctx, eventID)
+		if err != nil {
+			return "", err
+		}
+		return s.formatEventDetails(event), nil
+	}
+	if calendarID, ok := parseCalendarResourceURI(uri); ok {
+		return s.readCalendarResource(ctx, calendarID)
+	}
+	if calendarID, start, end, ok := parseRangeResourceURI(uri); ok {
+		events, err := s.settings().calendar.ListCalendarEvents(ctx, calendarID, start, end)
+		if err != nil {
+			return "", err
+		}
+		return s.formatEvents(events), nil
+	}
+
+	events, err := s.settings().calendar.ListEventsForDays(ctx, upcomingResourceDays)
+	if err != nil {
+		return "", err
+	}
+	return s.formatEvents(events), nil
+}
+
+func resourceMimeType(uri string) string {
+	if uri == resourceAudit {
+		return "application/json"
+	}
+	return "text/plain"
+}
+
+// eventResourceURI identifies a single event, e.g.
+// calendar://team@example.com/events/abc123
+func eventResourceURI(calendarID, eventID string) string {
+	return "calendar://" + url.PathEscape(calendarID) + "/events/" + url.PathEscape(eventID)
+}
+
+func parseEventResourceURI(uri string) (calendarID, eventID string, ok bool) {
+	rest, found := strings.CutPrefix(uri, "calendar://")
+	if !found {
+		return "", "", false
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[1] != "events" {
+		return "", "", false
+	}
+
+	calendarID, err := url.PathUnescape(parts[0])
+	if err != nil || calendarID == "" {
+		return "", "", false
+	}
+	eventID, err = url.PathUnescape(parts[2])
+	if err != nil || eventID == "" {
+		return "", "", false
+	}
+	return calendarID, eventID, true
+}
+
+// parseRangeResourceURI splits calendar://{calendarId}/range/{start}/{end}
+func parseRangeResourceURI(uri string) (calendarID, start, end string, ok bool) {
+	rest, found := strings.CutPrefix(uri, "calendar://")
+	if !found {
+		return "", "", "", false
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 4 || parts[1] != "range" {
+		return "", "", "", false
+	}
+
+	calendarID, err := url.PathUnescape(parts[0])
+	if err != nil || calendarID == "" {
+		return "", "", "", false
+	}
+	return calendarID, parts[2], parts[3], true
+}
+
+// validResourceRange accepts an inclusive YYYY-MM-DD range of at most
+// maxRangeResourceDays
+func validResourceRange(startDate, endDate string) bool {
+	start, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return false
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil || end.Before(start) {
+		return false
+	}
+	return int(end.Sub(start).Hours()/24)+1 <= maxRangeResourceDays
+}
+
+// eventResourceLink builds a resource_link content block that clients can
+// render as an openable event and resolve with resources/read
+func (s *Server) eventResourceLink(e gcal.CalendarEvent) map[string]interface{} {
+	calendarID := e.CalendarID
+	if calendarID == "" {
+		calendarID = s.settings().calendar.CalendarID()
+	}
+
+	link := map[string]interface{}{
+		"type":     "resource_link",
+		"uri":      eventResourceURI(calendarID, e.ID),
+		"name":     s.sanitize(e.Summary),
+		"mimeType": "text/plain",
+	}
+	if e.HTMLLink != "" {
+		link["description"] = "Open in Google Calendar: " + e.HTMLLink
+	}
+	return link
+}
+
+// formatEventDetails describes everything about an event a listing leaves
+// out: who organizes and attends it, how to join, how it repeats and when
+// it was created and last changed
+func (s *Server) formatEventDetails(e *calendar.Event) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n", s.sanitize(e.Summary))
+	if e.Status == "cancelled" || e.Status == "tentative" {
+		fmt.Fprintf(&b, "Status: %s\n", e.Status)
+	}
+	if e.Start != nil {
+		fmt.Fprintf(&b, "Start: %s\n", gcal.EventTime(e.Start))
+	}
+	if e.End != nil {
+		fmt.Fprintf(&b, "End: %s\n", gcal.EventTime(e.End))
+	}
+	for _, rule := range e.Recurrence {
+		fmt.Fprintf(&b, "Repeats: %s\n", rule)
+	}
+	if e.RecurringEventId != "" {
+		fmt.Fprintf(&b, "Recurring event: %s\n", e.RecurringEventId)
+	}
+	if e.Location != "" {
+		fmt.Fprintf(&b, "Location: %s\n", s.sanitize(e.Location))
+	}
+	if join := joinDetails(e); join.JoinURL != "" || join.DialIn != "" {
+		b.WriteString("Join:")
+		if join.JoinURL != "" {
+			fmt.Fprintf(&b, " %s", join.JoinURL)
+		}
+		if join.DialIn != "" {
+			fmt.Fprintf(&b, " dial-in %s", join.DialIn)
+			if join.PIN != "" {
+				fmt.Fprintf(&b, " PIN %s", join.PIN)
+			}
+		}
+		b.WriteString("\n")
+	}
+	if e.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", s.sanitize(e.Description))
+	}
+	if e.Organizer != nil && e.Organizer.Email != "" {
+		fmt.Fprintf(&b, "Organizer: %s\n", s.formatPerson(e.Organizer.Email, e.Organizer.DisplayName))
+	}
+	if len(e.Attendees) > 0 {
+		b.WriteString("Guests:\n")
+	}
+	for _, a := range e.Attendees {
+		fmt.Fprintf(&b, "- %s: %s", s.formatPerson(a.Email, a.DisplayName), firstNonEmpty(a.ResponseStatus, "needsAction"))
+		if a.Organizer {
+			b.WriteString(", organizer")
+		}
+		if a.Optional {
+			b.WriteString(", optional")
+		}
+		if a.Resource {
+			b.WriteString(", room")
+		}
+		b.WriteString("\n")
+	}
+	if reminders := formatReminders(e.Reminders); reminders != "" {
+		fmt.Fprintf(&b, "Reminders: %s\n", reminders)
+	}
+	if e.Visibility != "" && e.Visibility != "default" {
+		fmt.Fprintf(&b, "Visibility: %s\n", e.Visibility)
+	}
+	if e.Transparency == "transparent" {
+		b.WriteString("Shows as: free\n")
+	}
+	if e.Created != "" {
+		fmt.Fprintf(&b, "Created: %s\n", e.Created)
+	}
+	if e.Updated != "" {
+		fmt.Fprintf(&b, "Updated: %s\n", e.Updated)
+	}
+	fmt.Fprintf(&b, "ID: %s\n", e.Id)
+	if e.HtmlLink != "" {
+		fmt.Fprintf(&b, "Link: %s\n", e.HtmlLink)
+	}
+	return b.String()
+}
+
+// formatPerson writes an email with the name it belongs to, when known
+func (s *Server) formatPerson(email, name string) string {
+	if name == "" {
+		return email
+	}
+	return fmt.Sprintf("%s <%s>", s.sanitize(name), email)
+}
+
+// formatReminders describes when the user is reminded of an event, e.g.
+// "popup 10 min before, email 1 day before"
+func formatReminders(r *calendar.EventReminders) string {
+	if r == nil {
+		return ""
+	}
+	if r.UseDefault {
+		return "calendar default"
+	}
+	if len(r.Overrides) == 0 {
+		return "none"
+	}
+	var parts []string
+	for _, o := range r.Overrides {
+		parts = append(parts, fmt.Sprintf("%s %s before", o.Method, gcal.FormatDuration(time.Duration(o.Minutes)*time.Minute)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pollSubscriptions periodically re-reads every subscribed resource and
+// sends notifications/resources/updated when its content changes, and
+// reconciles the subscriptions every night, until the session ends.
+func (s *Server) pollSubscriptions() {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	reconcile := s.reconcileAfter(s.settings().location)
+
+	for {
+		select {
+		case <-ticker.C:
+			s.checkSubscriptions(context.Background())
+		case <-reconcile:
+			s.reconcileSubscriptions(context.Background()).log()
+			reconcile = s.reconcileAfter(s.settings().location)
+		case <-s.ended:
+			return
+		}
+	}
+}
+
+func (s *Server) checkSubscriptions(ctx context.Context) {
+	s.subMu.Lock()
+	uris := make([]string, 0, len(s.subscriptions))
+	for uri := range s.subscriptions {
+		uris = append(uris, uri)
+	}
+	s.subMu.Unlock()
+
+	for _, uri := range uris {
+		text, err := s.readResource(ctx, uri)
+		if err != nil {
+			log.Printf("Failed to poll resource %s: %v", uri, err)
+			continue
+		}
+		fingerprint := resourceFingerprint(text)
+
+		s.subMu.Lock()
+		previous, subscribed := s.subscriptions[uri]
+		changed := subscribed && previous != fingerprint
+		if changed {
+			s.subscriptions[uri] = fingerprint
+		}
+		s.subMu.Unlock()
+
+		if changed {
+			s.sendNotification("notifications/resources/updated", map[string]string{"uri": uri})
+		}
+	}
+}
+
+// resourceUpdated tells the client about a change of uri right away, rather
+// than at the next poll, if it subscribed to it
+func (s *Server) resourceUpdated(uri string) {
+	text, err := s.readResource(context.Background(), uri)
+	if err != nil {
+		return
+	}
+
+	s.subMu.Lock()
+	_, subscribed := s.subscriptions[uri]
+	if subscribed {
+		s.subscriptions[uri] = resourceFingerprint(text)
+	}
+	s.subMu.Unlock()
+
+	if subscribed {
+		s.sendNotification("notifications/resources/updated", map[string]string{"uri": uri})
+	}
+}
+
+func resourceFingerprint(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,258 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestHandleInitialize_AdvertisesResourceSubscribe(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize"})
+
+	result := resp.Result.(map[string]interface{})
+	capabilities := result["capabilities"].(map[string]interface{})
+	resources, ok := capabilities["resources"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected resources capability")
+	}
+	if resources["subscribe"] != true {
+		t.Error("expected resources.subscribe to be true")
+	}
+}
+
+func TestHandleResourcesList(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/list"})
+
+	result := resp.Result.(map[string]interface{})
+	resources := result["resources"].([]map[string]interface{})
+	if len(resources) != 2 || resources[0]["uri"] != resourceUpcomingEvents || resources[1]["uri"] != resourceAudit {
+		t.Errorf("unexpected resources: %v", resources)
+	}
+}
+
+func TestHandleResourcesRead(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Standup"}}}
+	s := newTestServer(fake)
+
+	params, _ := json.Marshal(map[string]string{"uri": resourceUpcomingEvents})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/read", Params: params})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	contents := resp.Result.(map[string]interface{})["contents"].([]map[string]string)
+	if !strings.Contains(contents[0]["text"], "Standup") {
+		t.Errorf("expected event in resource text, got %q", contents[0]["text"])
+	}
+	if fake.lastDays != upcomingResourceDays {
+		t.Errorf("expected %d days, got %d", upcomingResourceDays, fake.lastDays)
+	}
+}
+
+func TestHandleResourcesSubscribe_UnknownURI(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	params, _ := json.Marshal(map[string]string{"uri": "calendar://nope"})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/subscribe", Params: params})
+
+	if resp.Error == nil || resp.Error.Code != -32002 {
+		t.Errorf("expected resource not found error, got %v", resp.Error)
+	}
+}
+
+func TestCheckSubscriptions_NotifiesOnChange(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Before"}}}
+	out := &bytes.Buffer{}
+	s := New(fake, out)
+	s.subscriptions[resourceUpcomingEvents] = ""
+
+	// First check records the state and reports the change from empty
+	s.checkSubscriptions(context.Background())
+	out.Reset()
+
+	s.checkSubscriptions(context.Background())
+	if out.Len() != 0 {
+		t.Fatalf("expected no notification without changes, got %q", out.String())
+	}
+
+	fake.events = []gcal.CalendarEvent{{ID: "1", Summary: "After"}}
+	s.checkSubscriptions(context.Background())
+
+	var notification JSONRPCNotification
+	if err := json.Unmarshal(out.Bytes(), &notification); err != nil {
+		t.Fatalf("expected a notification, got %q: %v", out.String(), err)
+	}
+	if notification.Method != "notifications/resources/updated" {
+		t.Errorf("unexpected method %q", notification.Method)
+	}
+}
+
+func TestCheckSubscriptions_SkipsUnsubscribed(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Event"}}}
+	out := &bytes.Buffer{}
+	s := New(fake, out)
+
+	params, _ := json.Marshal(map[string]string{"uri": resourceUpcomingEvents})
+	s.subscriptions[resourceUpcomingEvents] = "stale"
+	s.handleResourcesUnsubscribe(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Params: params})
+
+	s.checkSubscriptions(context.Background())
+	if out.Len() != 0 {
+		t.Errorf("expected no notification after unsubscribe, got %q", out.String())
+	}
+}
+
+func TestEventResourceURI_RoundTrip(t *testing.T) {
+	uri := eventResourceURI("team@example.com", "abc123")
+	if uri != "calendar://team@example.com/events/abc123" {
+		t.Errorf("unexpected URI %q", uri)
+	}
+
+	calendarID, eventID, ok := parseEventResourceURI(uri)
+	if !ok || calendarID != "team@example.com" || eventID != "abc123" {
+		t.Errorf("unexpected parse result: %q %q %v", calendarID, eventID, ok)
+	}
+
+	for _, bad := range []string{"calendar://events/upcoming", "https://example.com/events/1", "calendar:///events/1"} {
+		if _, _, ok := parseEventResourceURI(bad); ok {
+			t.Errorf("expected %q not to parse as an event URI", bad)
+		}
+	}
+}
+
+func TestHandleResourcesRead_Event(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	params, _ := json.Marshal(map[string]string{"uri": "calendar://test@example.com/events/evt-9"})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/read", Params: params})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	contents := resp.Result.(map[string]interface{})["contents"].([]map[string]string)
+	if !strings.Contains(contents[0]["text"], "Event evt-9") {
+		t.Errorf("expected event details, got %q", contents[0]["text"])
+	}
+
+	params, _ = json.Marshal(map[string]string{"uri": "calendar://other@example.com/events/evt-9"})
+	resp = s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "resources/read", Params: params})
+	if resp.Error == nil || resp.Error.Code != -32002 {
+		t.Errorf("expected resource not found for another calendar, got %+v", resp.Error)
+	}
+}
+
+func TestListEvents_ResourceLinks(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "1", CalendarID: "test@example.com", Summary: "Standup", HTMLLink: "https://calendar.google.com/event?eid=1"},
+	}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	if content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{}); len(content) != 1 {
+		t.Errorf("resource links should not be sent on 2024-11-05, got %d blocks", len(content))
+	}
+
+	s.setProtocolVersion(protocolVersion20250618)
+	resp = s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+	if len(content) != 2 {
+		t.Fatalf("expected text plus one resource link, got %d blocks", len(content))
+	}
+	link := content[1]
+	if link["type"] != "resource_link" || link["uri"] != "calendar://test@example.com/events/1" {
+		t.Errorf("unexpected resource link %v", link)
+	}
+	if !strings.Contains(link["description"].(string), "https://calendar.google.com/event?eid=1") {
+		t.Errorf("expected HtmlLink in description, got %q", link["description"])
+	}
+}
+
+func TestHandleResourceTemplatesList(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/templates/list"})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	templates := resp.Result.(map[string]interface{})["resourceTemplates"].([]map[string]interface{})
+	var uris []string
+	for _, tmpl := range templates {
+		uris = append(uris, tmpl["uriTemplate"].(string))
+	}
+	if !strings.Contains(strings.Join(uris, " "), "calendar://{calendarId}/range/{start}/{end}") {
+		t.Errorf("expected a range template, got %v", uris)
+	}
+}
+
+func TestHandleResourcesRead_Range(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Standup"}}}
+	s := newTestServer(fake)
+
+	params, _ := json.Marshal(map[string]string{"uri": "calendar://test@example.com/range/2026-03-01/2026-03-31"})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "resources/read", Params: params})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastStart != "2026-03-01" || fake.lastEnd != "2026-03-31" {
+		t.Errorf("unexpected range %s to %s", fake.lastStart, fake.lastEnd)
+	}
+
+	for _, uri := range []string{
+		"calendar://test@example.com/range/2026-03-31/2026-03-01",
+		"calendar://test@example.com/range/2026-01-01/2026-12-31",
+		"calendar://test@example.com/range/march/april",
+		"calendar://unknown@example.com/range/2026-03-01/2026-03-31",
+	} {
+		params, _ := json.Marshal(map[string]string{"uri": uri})
+		resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "resources/read", Params: params})
+		if resp.Error == nil {
+			t.Errorf("%s: expected an error", uri)
+		}
+	}
+}
+
+func TestFormatEventDetails(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	text := s.formatEventDetails(&calendar.Event{
+		Id:         "evt-1",
+		Summary:    "Planning",
+		Start:      &calendar.EventDateTime{DateTime: "2026-03-16T10:00:00+01:00"},
+		End:        &calendar.EventDateTime{DateTime: "2026-03-16T11:00:00+01:00"},
+		Recurrence: []string{"RRULE:FREQ=WEEKLY;BYDAY=MO"},
+		Organizer:  &calendar.EventOrganizer{Email: "ana@example.com", DisplayName: "Ana"},
+		Attendees: []*calendar.EventAttendee{
+			{Email: "ana@example.com", DisplayName: "Ana", ResponseStatus: "accepted", Organizer: true},
+			{Email: "ben@example.com", Optional: true},
+		},
+		HangoutLink: "https://meet.google.com/abc-defg-hij",
+		Reminders:   &calendar.EventReminders{Overrides: []*calendar.EventReminder{{Method: "popup", Minutes: 10}, {Method: "email", Minutes: 90}}},
+		Visibility:  "private",
+		Created:     "2026-03-01T09:00:00Z",
+		Updated:     "2026-03-02T09:00:00Z",
+	})
+	for _, want := range []string{
+		"Repeats: RRULE:FREQ=WEEKLY;BYDAY=MO\n",
+		"Join: https://meet.google.com/abc-defg-hij\n",
+		"Organizer: Ana <ana@example.com>\n",
+		"- Ana <ana@example.com>: accepted, organizer\n",
+		"- ben@example.com: needsAction, optional\n",
+		"Reminders: popup 10m before, email 1h30m before\n",
+		"Visibility: private\n",
+		"Created: 2026-03-01T09:00:00Z\nUpdated: 2026-03-02T09:00:00Z\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+
+	if text := s.formatEventDetails(&calendar.Event{Id: "evt-2", Summary: "Focus", Reminders: &calendar.EventReminders{UseDefault: true}}); !strings.Contains(text, "Reminders: calendar default\n") || strings.Contains(text, "Guests") {
+		t.Errorf("unexpected details:\n%s", text)
+	}
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	samplingTimeout   = 2 * time.Minute
+	samplingMaxTokens = 800
+)
+
+const summarizeSystemPrompt = "You summarize calendar schedules. Be concise: group events by day, point out busy stretches, conflicts and free time. Do not invent events."
+
+func (s *Server) clientSupportsSampling() bool {
+	s.sessionMu.RLock()
+	defer s.sessionMu.RUnlock()
+	return s.clientSampling
+}
+
+// summarizeScheduleInput is the arguments of summarize_schedule
+type summarizeScheduleInput struct {
+	// Number of days to summarize (default: 7)
+	Days int `json:"days" jsonschema:"default=7"`
+	// What the summary should focus on, e.g. "meetings with customers"
+	// (optional)
+	Focus string `json:"focus"`
+}
+
+// callSummarizeSchedule asks the client's LLM, via sampling/createMessage,
+// to summarize the upcoming events, keeping text generation client-side
+func (s *Server) callSummarizeSchedule(ctx context.Context, input summarizeScheduleInput) (textOutput, error) {
+	if input.Days <= 0 {
+		input.Days = 7
+	}
+
+	events, err := s.settings().calendar.ListEventsForDays(ctx, input.Days)
+	if err != nil {
+		return "", err
+	}
+	if len(events) == 0 {
+		return textOutput(fmt.Sprintf("No events in the next %d days.", input.Days)), nil
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Summarize my schedule for the next %d days.", input.Days)
+	if input.Focus != "" {
+		fmt.Fprintf(&prompt, " Focus on: %s.", s.sanitize(input.Focus))
+	}
+	prompt.WriteString("\n\n")
+	prompt.WriteString(s.formatEvents(events))
+
+	ctx, cancel := context.WithTimeout(ctx, samplingTimeout)
+	defer cancel()
+
+	raw, err := s.request(ctx, "sampling/createMessage", map[string]interface{}{
+		"messages": []map[string]interface{}{
+			{
+				"role":    "user",
+				"content": map[string]string{"type": "text", "text": prompt.String()},
+			},
+		},
+		"systemPrompt": summarizeSystemPrompt,
+		"maxTokens":    samplingMaxTokens,
+	})
+	if err != nil {
+		return "", withErrorCode(errCodeSamplingFailed, fmt.Errorf("sampling failed: %w", err))
+	}
+
+	var result struct {
+		Content struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return "", withErrorCode(errCodeSamplingFailed, fmt.Errorf("invalid sampling result: %w", err))
+	}
+	if result.Content.Type != "text" || result.Content.Text == "" {
+		return "", withErrorCode(errCodeSamplingFailed, errors.New("sampling returned no text"))
+	}
+
+	return textOutput(result.Content.Text), nil
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestSummarizeSchedule_HiddenWithoutSampling(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize"})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"})
+
+	tools := s.handleToolsList(JSONRPCRequest{ID: float64(2)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	for _, tool := range tools {
+		if tool["name"] == toolSummarize {
+			t.Error("summarize_schedule should not be listed without sampling support")
+		}
+	}
+
+	params, _ := json.Marshal(map[string]interface{}{"name": toolSummarize})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(3), Method: "tools/call", Params: params})
+	if resp.Error == nil {
+		t.Error("expected error calling summarize_schedule without sampling support")
+	}
+}
+
+func TestSummarizeSchedule_UsesSampling(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Design review"}}}
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	s := New(fake, outW)
+
+	go s.Run(inR)
+	out := bufio.NewReader(outR)
+	readLine := func() map[string]interface{} {
+		line, err := out.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		var msg map[string]interface{}
+		json.Unmarshal(line, &msg)
+		return msg
+	}
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`+"\n")
+	readLine()
+	io.WriteString(inW, `{"jsonrpc":"2.0","method":"notifications/initialized"}`+"\n")
+
+	io.WriteString(inW, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"summarize_schedule"}}`+"\n")
+
+	samplingReq := readLine()
+	if samplingReq["method"] != "sampling/createMessage" {
+		t.Fatalf("expected sampling request, got %v", samplingReq)
+	}
+	params, _ := json.Marshal(samplingReq["params"])
+	if !strings.Contains(string(params), "Design review") {
+		t.Errorf("expected events in sampling prompt, got %s", params)
+	}
+
+	reply, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      samplingReq["id"],
+		"result": map[string]interface{}{
+			"role":    "assistant",
+			"content": map[string]string{"type": "text", "text": "A quiet week with one design review."},
+			"model":   "test-model",
+		},
+	})
+	inW.Write(append(reply, '\n'))
+
+	toolResp := readLine()
+	if toolResp["id"] != float64(2) {
+		t.Fatalf("expected tool response, got %v", toolResp)
+	}
+	data, _ := json.Marshal(toolResp["result"])
+	if !strings.Contains(string(data), "A quiet week") {
+		t.Errorf("expected sampled summary in result, got %s", data)
+	}
+
+	inW.Close()
+}
@@ -0,0 +1,760 @@
+// Code generated by schemagen; DO NOT EDIT.
+
+package server
+
+// analyzeTimeInputSchema is the JSON Schema of analyzeTimeInput
+var analyzeTimeInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day in YYYY-MM-DD format (default: today)",
+		},
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     defaultAnalysisDays,
+			"maximum":     maxAnalysisDays,
+			"description": "Number of days to analyze (default: 7, max: 90)",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+}
+
+func (analyzeTimeInput) inputSchema() map[string]interface{} { return analyzeTimeInputSchema }
+
+// applyResolutionInputSchema is the JSON Schema of applyResolutionInput
+var applyResolutionInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": "ID of the event to move (moveEventId of the resolution)",
+		},
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "Date of the chosen slot in YYYY-MM-DD format",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "Start time of the chosen slot in HH:MM format; the event keeps its duration",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Move the event even if the slot is no longer free or is blocked by the configured schedule constraints (optional)",
+		},
+	},
+	"required": []string{"event_id", "date", "start_time"},
+}
+
+func (applyResolutionInput) inputSchema() map[string]interface{} { return applyResolutionInputSchema }
+
+// authStatusInputSchema is the JSON Schema of authStatusInput
+var authStatusInputSchema = map[string]interface{}{
+	"type":       "object",
+	"properties": map[string]interface{}{},
+}
+
+func (authStatusInput) inputSchema() map[string]interface{} { return authStatusInputSchema }
+
+// broadcastEventInputSchema is the JSON Schema of broadcastEventInput
+var broadcastEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"calendars": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"maxItems":    maxBroadcastCalendars,
+			"description": "Calendars to create the event on: emails, calendar IDs or names from your calendar list",
+		},
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "Event title",
+		},
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "Event date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "Start time in HH:MM format (24-hour)",
+		},
+		"end_time": map[string]interface{}{
+			"type":        "string",
+			"description": "End time in HH:MM format (24-hour)",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "Event description (optional)",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow durations over 12 hours or under 1 minute, and times blocked by the configured schedule constraints (optional)",
+		},
+	},
+	"required": []string{"calendars", "summary", "date", "start_time", "end_time"},
+}
+
+func (broadcastEventInput) inputSchema() map[string]interface{} { return broadcastEventInputSchema }
+
+// comparePeriodsInputSchema is the JSON Schema of comparePeriodsInput
+var comparePeriodsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day of the period in YYYY-MM-DD format (default: Monday of this week)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Last day of the period in YYYY-MM-DD format (default: 6 days after start_date, max range: 90 days)",
+		},
+		"compare_start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day of the range to compare with (default: the same number of days right before the period)",
+		},
+		"compare_end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Last day of the range to compare with; required with compare_start_date",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+}
+
+func (comparePeriodsInput) inputSchema() map[string]interface{} { return comparePeriodsInputSchema }
+
+// createEventInputSchema is the JSON Schema of createEventInput
+var createEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "Event title",
+		},
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "Event date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "Start time in HH:MM format (24-hour)",
+		},
+		"end_time": map[string]interface{}{
+			"type":        "string",
+			"description": "End time in HH:MM format (24-hour)",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "Event description (optional)",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow durations over 12 hours or under 1 minute, and times blocked by the configured schedule constraints (optional)",
+		},
+	},
+	"required": []string{"summary", "date", "start_time", "end_time"},
+}
+
+func (createEventInput) inputSchema() map[string]interface{} { return createEventInputSchema }
+
+// delegatedActionsInputSchema is the JSON Schema of delegatedActionsInput
+var delegatedActionsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     defaultDelegatedDays,
+			"maximum":     maxDelegatedDays,
+			"description": "Number of past days to report (default: 7, max: 28)",
+		},
+	},
+}
+
+func (delegatedActionsInput) inputSchema() map[string]interface{} { return delegatedActionsInputSchema }
+
+// deleteEventInputSchema is the JSON Schema of deleteEventInput
+var deleteEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": eventIDDescription,
+		},
+		"event_ref": map[string]interface{}{
+			"type":        "string",
+			"description": eventRefDescription,
+		},
+		"apply_to": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{applyToThisEvent, applyToThisAndFollowing, applyToAll},
+			"description": "For recurring events: this_event for one occurrence, this_and_following for it and the later ones, all for the whole series (optional; by default an occurrence's ID acts on the occurrence and a series ID on the whole series)",
+		},
+		"occurrence_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Date of the occurrence meant, in YYYY-MM-DD format, when event_id is that of a whole series and apply_to is this_event or this_and_following",
+		},
+	},
+}
+
+func (deleteEventInput) inputSchema() map[string]interface{} { return deleteEventInputSchema }
+
+// diffRangeInputSchema is the JSON Schema of diffRangeInput
+var diffRangeInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted); defaults to the range of the snapshot",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted); defaults to the range of the snapshot",
+		},
+		"snapshot": map[string]interface{}{
+			"type":        "string",
+			"description": "Token returned by an earlier diff_range call to compare against; omit it to take the first snapshot",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+}
+
+func (diffRangeInput) inputSchema() map[string]interface{} { return diffRangeInputSchema }
+
+// editLinkedInputSchema is the JSON Schema of editLinkedInput
+var editLinkedInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": "ID of the copy on your calendar",
+		},
+		"event_ref": map[string]interface{}{
+			"type":        "string",
+			"description": eventRefDescription,
+		},
+		"broadcast_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Broadcast ID reported by create_event_on_calendars, when your calendar has no copy; requires calendars",
+		},
+		"calendars": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "With broadcast_id, the calendars holding the copies",
+		},
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "New event title (optional)",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "New event description (optional)",
+		},
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "New date in YYYY-MM-DD format, DD.MM.YYYY or DD/MM/YYYY (optional)",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "New start time in HH:MM format (optional)",
+		},
+		"end_time": map[string]interface{}{
+			"type":        "string",
+			"description": "New end time in HH:MM format (optional)",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow durations over 12 hours or under 1 minute, and times blocked by the configured schedule constraints (optional)",
+		},
+	},
+}
+
+func (editLinkedInput) inputSchema() map[string]interface{} { return editLinkedInputSchema }
+
+// findConflictsInputSchema is the JSON Schema of findConflictsInput
+var findConflictsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (default: today)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (default: 6 days after start_date, max range: 90 days)",
+		},
+	},
+}
+
+func (findConflictsInput) inputSchema() map[string]interface{} { return findConflictsInputSchema }
+
+// freeBusyInputSchema is the JSON Schema of freeBusyInput
+var freeBusyInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"calendars": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"maxItems":    maxFreeBusyCalendars,
+			"description": "Calendars to check: email addresses of people or rooms, calendar IDs or names from your calendar list",
+		},
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day of the window in YYYY-MM-DD format (default: today)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Last day of the window in YYYY-MM-DD format (default: start_date, max range: 60 days)",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "Start of the window on start_date in HH:MM format (default: 00:00)",
+		},
+		"end_time": map[string]interface{}{
+			"type":        "string",
+			"description": "End of the window on end_date in HH:MM format (default: the end of the day)",
+		},
+	},
+	"required": []string{"calendars"},
+}
+
+func (freeBusyInput) inputSchema() map[string]interface{} { return freeBusyInputSchema }
+
+// getEventInputSchema is the JSON Schema of getEventInput
+var getEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": eventIDDescription,
+		},
+		"event_ref": map[string]interface{}{
+			"type":        "string",
+			"description": eventRefDescription,
+		},
+		"format": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{eventFormatText, eventFormatICS},
+			"description": "text (default) or ics to also embed the event as a text/calendar resource",
+		},
+	},
+}
+
+func (getEventInput) inputSchema() map[string]interface{} { return getEventInputSchema }
+
+// hygieneReportInputSchema is the JSON Schema of hygieneReportInput
+var hygieneReportInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     defaultHygieneDays,
+			"maximum":     maxHygieneDays,
+			"description": "Number of past days to inspect (default: 56, max: 365)",
+		},
+	},
+}
+
+func (hygieneReportInput) inputSchema() map[string]interface{} { return hygieneReportInputSchema }
+
+// joinInfoInputSchema is the JSON Schema of joinInfoInput
+var joinInfoInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": eventIDDescription,
+		},
+		"event_ref": map[string]interface{}{
+			"type":        "string",
+			"description": eventRefDescription,
+		},
+	},
+}
+
+func (joinInfoInput) inputSchema() map[string]interface{} { return joinInfoInputSchema }
+
+// listAccountsInputSchema is the JSON Schema of listAccountsInput
+var listAccountsInputSchema = map[string]interface{}{
+	"type":       "object",
+	"properties": map[string]interface{}{},
+}
+
+func (listAccountsInput) inputSchema() map[string]interface{} { return listAccountsInputSchema }
+
+// listEventsInputSchema is the JSON Schema of listEventsInput
+var listEventsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     7,
+			"description": "Number of days to look ahead (default: 7)",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+}
+
+func (listEventsInput) inputSchema() map[string]interface{} { return listEventsInputSchema }
+
+// listEventsRangeInputSchema is the JSON Schema of listEventsRangeInput
+var listEventsRangeInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+	"required": []string{"start_date", "end_date"},
+}
+
+func (listEventsRangeInput) inputSchema() map[string]interface{} { return listEventsRangeInputSchema }
+
+// listInstancesInputSchema is the JSON Schema of listInstancesInput
+var listInstancesInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": "ID of the recurring series or of any of its instances",
+		},
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (default: today)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (default: 30 days after start_date, max range: 366 days)",
+		},
+	},
+	"required": []string{"event_id"},
+}
+
+func (listInstancesInput) inputSchema() map[string]interface{} { return listInstancesInputSchema }
+
+// meetingFreeDaysInputSchema is the JSON Schema of meetingFreeDaysInput
+var meetingFreeDaysInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day in YYYY-MM-DD format (default: today)",
+		},
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     defaultFreeDaysRange,
+			"maximum":     maxAnalysisDays,
+			"description": "Number of days to check (default: 14, max: 90)",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+		"include_weekends": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Also check days outside the working week; by default they are skipped and don't break a streak",
+		},
+	},
+}
+
+func (meetingFreeDaysInput) inputSchema() map[string]interface{} { return meetingFreeDaysInputSchema }
+
+// meetingHistoryInputSchema is the JSON Schema of meetingHistoryInput
+var meetingHistoryInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"attendee": map[string]interface{}{
+			"type":        "string",
+			"description": "Email address, or a domain such as acme.com to match everyone at that organization",
+		},
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (default: 90 days ago)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (default: today)",
+		},
+	},
+	"required": []string{"attendee"},
+}
+
+func (meetingHistoryInput) inputSchema() map[string]interface{} { return meetingHistoryInputSchema }
+
+// planVacationInputSchema is the JSON Schema of planVacationInput
+var planVacationInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "First day of the vacation in YYYY-MM-DD format",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Last day of the vacation in YYYY-MM-DD format (max range: 60 days)",
+		},
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "Title of the out-of-office event (default: Out of office)",
+		},
+		"conflicts": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{vacationKeep, vacationFlag, vacationDecline},
+			"default":     vacationFlag,
+			"description": "What to do with overlapping meetings: keep them, flag them in the summary (default), or decline them. Declining also auto-declines new invitations; meetings you organize are only flagged",
+		},
+		"decline_message": map[string]interface{}{
+			"type":        "string",
+			"description": "Message sent with declined invitations (optional)",
+		},
+	},
+	"required": []string{"start_date", "end_date"},
+}
+
+func (planVacationInput) inputSchema() map[string]interface{} { return planVacationInputSchema }
+
+// quickAddEventInputSchema is the JSON Schema of quickAddEventInput
+var quickAddEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"text": map[string]interface{}{
+			"type":        "string",
+			"description": "The event in plain words, with its time, e.g. Lunch with Sam Friday 1pm",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+	"required": []string{"text"},
+}
+
+func (quickAddEventInput) inputSchema() map[string]interface{} { return quickAddEventInputSchema }
+
+// rawRequestInputSchema is the JSON Schema of rawRequestInput
+var rawRequestInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"method": map[string]interface{}{
+			"type":        "string",
+			"description": "API method, e.g. events.list or freebusy.query",
+		},
+		"params": map[string]interface{}{
+			"type":        "object",
+			"description": "Method parameters named as in the Calendar API reference, e.g. {\"timeMin\": \"2025-01-06T00:00:00Z\", \"q\": \"standup\"}; calendarId defaults to the configured calendar, and body holds the request body of events.insert, events.patch and freebusy.query",
+		},
+	},
+	"required": []string{"method"},
+}
+
+func (rawRequestInput) inputSchema() map[string]interface{} { return rawRequestInputSchema }
+
+// reauthenticateInputSchema is the JSON Schema of reauthenticateInput
+var reauthenticateInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"code": map[string]interface{}{
+			"type":        "string",
+			"description": "Authorization code, or the whole address the browser was sent to after allowing access; omit it to get the authorization URL",
+		},
+		"device": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Use the device flow instead, for a server without a browser nearby: returns a code to enter at a URL on any device, and the new token is used once it was entered",
+		},
+	},
+}
+
+func (reauthenticateInput) inputSchema() map[string]interface{} { return reauthenticateInputSchema }
+
+// recurringExceptionsInputSchema is the JSON Schema of recurringExceptionsInput
+var recurringExceptionsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": "ID of the recurring series or of any of its instances",
+		},
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Start date in YYYY-MM-DD format (default: 90 days ago)",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "End date in YYYY-MM-DD format (default: today)",
+		},
+	},
+	"required": []string{"event_id"},
+}
+
+func (recurringExceptionsInput) inputSchema() map[string]interface{} {
+	return recurringExceptionsInputSchema
+}
+
+// searchEventsInputSchema is the JSON Schema of searchEventsInput
+var searchEventsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"query": map[string]interface{}{
+			"type":        "string",
+			"description": "Words to look for in the summary, description, location and guests of events, e.g. dentist",
+		},
+		"start_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Only events on or after this date, YYYY-MM-DD; omit it to search the past too",
+		},
+		"end_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Only events on or before this date, YYYY-MM-DD; omit it to search the whole future",
+		},
+		"calendar": map[string]interface{}{
+			"type":        "string",
+			"description": calendarArgDescription,
+		},
+	},
+	"required": []string{"query"},
+}
+
+func (searchEventsInput) inputSchema() map[string]interface{} { return searchEventsInputSchema }
+
+// serverVersionInputSchema is the JSON Schema of serverVersionInput
+var serverVersionInputSchema = map[string]interface{}{
+	"type":       "object",
+	"properties": map[string]interface{}{},
+}
+
+func (serverVersionInput) inputSchema() map[string]interface{} { return serverVersionInputSchema }
+
+// summarizeScheduleInputSchema is the JSON Schema of summarizeScheduleInput
+var summarizeScheduleInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     7,
+			"description": "Number of days to summarize (default: 7)",
+		},
+		"focus": map[string]interface{}{
+			"type":        "string",
+			"description": "What the summary should focus on, e.g. \"meetings with customers\" (optional)",
+		},
+	},
+}
+
+func (summarizeScheduleInput) inputSchema() map[string]interface{} {
+	return summarizeScheduleInputSchema
+}
+
+// timezoneMigrationInputSchema is the JSON Schema of timezoneMigrationInput
+var timezoneMigrationInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"from_timezone": map[string]interface{}{
+			"type":        "string",
+			"description": "IANA timezone you moved from, e.g. Europe/Berlin",
+		},
+		"days": map[string]interface{}{
+			"type":        "integer",
+			"default":     defaultMigrationDays,
+			"maximum":     maxMigrationDays,
+			"description": "Number of upcoming days to check (default: 30, max: 90)",
+		},
+		"apply": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Move the events you organize to their suggested times (default: only report)",
+		},
+		"event_ids": map[string]interface{}{
+			"type":        "array",
+			"items":       map[string]interface{}{"type": "string"},
+			"description": "With apply, adjust only these events (default: all movable ones)",
+		},
+	},
+	"required": []string{"from_timezone"},
+}
+
+func (timezoneMigrationInput) inputSchema() map[string]interface{} {
+	return timezoneMigrationInputSchema
+}
+
+// updateEventInputSchema is the JSON Schema of updateEventInput
+var updateEventInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"event_id": map[string]interface{}{
+			"type":        "string",
+			"description": eventIDDescription,
+		},
+		"event_ref": map[string]interface{}{
+			"type":        "string",
+			"description": eventRefDescription,
+		},
+		"summary": map[string]interface{}{
+			"type":        "string",
+			"description": "New event title (optional)",
+		},
+		"description": map[string]interface{}{
+			"type":        "string",
+			"description": "New event description (optional)",
+		},
+		"date": map[string]interface{}{
+			"type":        "string",
+			"description": "New date in YYYY-MM-DD format, DD.MM.YYYY or DD/MM/YYYY (optional)",
+		},
+		"start_time": map[string]interface{}{
+			"type":        "string",
+			"description": "New start time in HH:MM format (optional)",
+		},
+		"end_time": map[string]interface{}{
+			"type":        "string",
+			"description": "New end time in HH:MM format (optional)",
+		},
+		"force": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Allow durations over 12 hours or under 1 minute, and times blocked by the configured schedule constraints (optional)",
+		},
+		"apply_to": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{applyToThisEvent, applyToThisAndFollowing, applyToAll},
+			"description": "For recurring events: this_event for one occurrence, this_and_following for it and the later ones, all for the whole series (optional; by default an occurrence's ID acts on the occurrence and a series ID on the whole series)",
+		},
+		"occurrence_date": map[string]interface{}{
+			"type":        "string",
+			"description": "Date of the occurrence meant, in YYYY-MM-DD format, when event_id is that of a whole series and apply_to is this_event or this_and_following",
+		},
+	},
+}
+
+func (updateEventInput) inputSchema() map[string]interface{} { return updateEventInputSchema }
+
+// weekStatsInputSchema is the JSON Schema of weekStatsInput
+var weekStatsInputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"format": map[string]interface{}{
+			"type":        "string",
+			"enum":        []string{statsFormatJSON, statsFormatPrometheus},
+			"default":     statsFormatJSON,
+			"description": "Output format of the text content (default: json)",
+		},
+	},
+}
+
+func (weekStatsInput) inputSchema() map[string]interface{} { return weekStatsInputSchema }
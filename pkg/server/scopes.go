@@ -0,0 +1,70 @@
+package server
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const (
+	scopeCalendar               = "https://www.googleapis.com/auth/calendar"
+	scopeCalendarReadonly       = "https://www.googleapis.com/auth/calendar.readonly"
+	scopeCalendarEvents         = "https://www.googleapis.com/auth/calendar.events"
+	scopeCalendarEventsReadonly = "https://www.googleapis.com/auth/calendar.events.readonly"
+)
+
+var (
+	// readScopes are the OAuth scopes that let the server read events
+	readScopes = []string{scopeCalendar, scopeCalendarReadonly, scopeCalendarEvents, scopeCalendarEventsReadonly}
+	// writeScopes are the OAuth scopes that let the server change events
+	writeScopes = []string{scopeCalendar, scopeCalendarEvents}
+	// identityScopes describe who signed in, which OAuth tokens commonly
+	// carry along with the ones requested
+	identityScopes = []string{"openid", "email", "profile", "https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"}
+)
+
+// auditScopes compares the scopes a token grants with what the tools the
+// server offers need, and describes each mismatch: too few scopes make
+// tools fail with 403, too many give a leaked token more reach than the
+// server ever uses. Nothing is reported when the scopes are unknown.
+func (s *Server) auditScopes(granted []string) []string {
+	if len(granted) == 0 {
+		return nil
+	}
+	grants := func(scopes []string) bool {
+		return slices.ContainsFunc(granted, func(scope string) bool { return slices.Contains(scopes, scope) })
+	}
+
+	var reads, writes []string
+	for _, t := range toolDefinitions {
+		if t.local || !s.toolAvailable(t) {
+			continue
+		}
+		if t.mutating {
+			writes = append(writes, s.exposedToolName(t.name))
+		} else {
+			reads = append(reads, s.exposedToolName(t.name))
+		}
+	}
+
+	var warnings []string
+	switch {
+	case !grants(readScopes) && len(reads)+len(writes) > 0:
+		warnings = append(warnings, fmt.Sprintf("under-privileged: the token grants no Calendar scope, so every calendar tool fails with 403; grant %s", scopeCalendarReadonly))
+	case len(writes) > 0 && !grants(writeScopes):
+		warnings = append(warnings, fmt.Sprintf("under-privileged: %s are enabled but the token can't change events; grant %s, or set CALENDAR_READ_ONLY=true", strings.Join(writes, ", "), scopeCalendarEvents))
+	case len(writes) == 0 && grants(writeScopes):
+		warnings = append(warnings, fmt.Sprintf("over-privileged: the token can change events while the server only reads them; %s would do", scopeCalendarReadonly))
+	}
+
+	var unused []string
+	for _, scope := range granted {
+		if !strings.HasPrefix(scope, scopeCalendar) && !slices.Contains(identityScopes, scope) {
+			unused = append(unused, scope)
+		}
+	}
+	if len(unused) > 0 {
+		warnings = append(warnings, fmt.Sprintf("over-privileged: the token also grants scopes the server doesn't use: %s", strings.Join(unused, ", ")))
+	}
+	return warnings
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestAuditScopes(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	for _, tc := range []struct {
+		readOnly bool
+		granted  []string
+		want     []string
+	}{
+		{false, []string{scopeCalendar, "openid", "email"}, nil},
+		{false, []string{scopeCalendarReadonly}, []string{"under-privileged: " + toolCreateEvent}},
+		{false, []string{"https://www.googleapis.com/auth/drive"}, []string{"grants no Calendar scope", "scopes the server doesn't use: https://www.googleapis.com/auth/drive"}},
+		{true, []string{scopeCalendar}, []string{"over-privileged: the token can change events"}},
+		{true, []string{scopeCalendarEventsReadonly}, nil},
+		{false, nil, nil},
+	} {
+		s.readOnly.Store(tc.readOnly)
+		got := s.auditScopes(tc.granted)
+		if len(got) != len(tc.want) {
+			t.Errorf("%v (read-only %t): expected %d warnings, got %q", tc.granted, tc.readOnly, len(tc.want), got)
+			continue
+		}
+		for i, want := range tc.want {
+			if !strings.Contains(got[i], want) {
+				t.Errorf("%v: expected %q in %q", tc.granted, want, got[i])
+			}
+		}
+	}
+}
+
+// scopedChecker is a configuration checker whose token grants scopes
+type scopedChecker struct {
+	fakeChecker
+	scopes []string
+}
+
+func (c scopedChecker) AuthStatus(context.Context) (*gcal.AuthStatus, error) {
+	return &gcal.AuthStatus{Mode: "oauth", Scopes: c.scopes}, nil
+}
+
+func TestStartupDiagnostics_ScopeWarnings(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	d := s.startupDiagnostics(context.Background(), scopedChecker{scopes: []string{scopeCalendarReadonly}})
+	if d.Status != "ok" || len(d.Warnings) != 1 || !strings.Contains(d.Warnings[0], "can't change events") {
+		t.Errorf("expected an ok status with a scope warning, got %+v", d)
+	}
+}
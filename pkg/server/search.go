@@ -0,0 +1,45 @@
+package server
+
+import (
+	"context"
+	"strings"
+)
+
+// searchEventsInput is the arguments of search_events
+type searchEventsInput struct {
+	// Words to look for in the summary, description, location and guests of events, e.g. dentist
+	Query string `json:"query" jsonschema:"required"`
+	// Only events on or after this date, YYYY-MM-DD; omit it to search the past too
+	StartDate string `json:"start_date"`
+	// Only events on or before this date, YYYY-MM-DD; omit it to search the whole future
+	EndDate string `json:"end_date"`
+	calendarArg
+}
+
+func (s *Server) callSearchEvents(ctx context.Context, input searchEventsInput) (eventList, error) {
+	input.Query = strings.TrimSpace(input.Query)
+	if input.Query == "" {
+		return eventList{}, badArgumentf("query is required")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if *date == "" {
+			continue
+		}
+		if err := s.normalizeDateArg(date); err != nil {
+			return eventList{}, badArgument(err)
+		}
+	}
+	if input.StartDate != "" && input.EndDate != "" && input.EndDate < input.StartDate {
+		return eventList{}, badArgumentf("end_date is before start_date")
+	}
+
+	calendarID, err := s.resolveCalendar(ctx, input.Calendar)
+	if err != nil {
+		return eventList{}, err
+	}
+	events, err := s.settings().calendar.SearchEvents(ctx, calendarID, input.Query, input.StartDate, input.EndDate)
+	if err != nil {
+		return eventList{}, err
+	}
+	return s.eventList(events), nil
+}
@@ -0,0 +1,1136 @@
+// Package server implements a Model Context Protocol server that exposes a
+// Google Calendar, or any other gcal.Service, as tools, resources and
+// prompts. A Server holds one MCP session; Run serves it over stdio-style
+// streams, while ServeTCP and SSEHandler serve remote clients.
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+const (
+	serverName = "google-calendar"
+
+	toolListEvents      = "list_events"
+	toolListEventsRange = "list_events_range"
+	toolDiffRange       = "diff_range"
+	toolGetEvent        = "get_event"
+	toolSearchEvents    = "search_events"
+	toolJoinInfo        = "join_info"
+	toolCreateEvent     = "create_event"
+	toolQuickAddEvent   = "quick_add_event"
+	toolBroadcastEvent  = "create_event_on_calendars"
+	toolEditLinked      = "edit_linked_events"
+	toolDeleteEvent     = "delete_event"
+	toolUpdateEvent     = "update_event"
+	toolServerVersion   = "get_server_version"
+	toolListAccounts    = "list_accounts"
+	toolAuthStatus      = "auth_status"
+	toolReauthenticate  = "reauthenticate"
+	toolAnalyzeTime     = "analyze_time"
+	toolMeetingFree     = "meeting_free_days"
+	toolComparePeriods  = "compare_periods"
+	toolMeetingHistory  = "meeting_history"
+	toolHygieneReport   = "hygiene_report"
+	toolFindConflicts   = "find_conflicts"
+	toolFreeBusy        = "freebusy_query"
+	toolApplyResolution = "apply_resolution"
+	toolPlanVacation    = "plan_vacation"
+	toolExceptions      = "recurring_exceptions"
+	toolListInstances   = "list_recurring_instances"
+	toolMigrateTimezone = "timezone_migration"
+	toolDelegated       = "delegated_actions"
+	toolWeekStats       = "week_stats"
+	toolRawRequest      = "gcal_raw_request"
+	toolSummarize       = "summarize_schedule"
+)
+
+type JSONRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type JSONRPCResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *RPCError   `json:"error,omitempty"`
+}
+
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Server is an MCP server for one calendar. Configure it with LoadEnv or
+// SetFraming before serving it.
+type Server struct {
+	// current is the calendar, timezone and verbosity of the session. A
+	// session config or reset replaces it as a whole, see settings.
+	current atomic.Pointer[sessionSettings]
+	// name identifies this instance in serverInfo
+	name string
+	// toolPrefix namespaces tool names when several calendar servers are
+	// attached to the same client
+	toolPrefix string
+
+	outMu sync.Mutex
+	out   io.Writer
+
+	sessionMu sync.RWMutex
+	// sessionID identifies the session in logs and in the request info
+	// passed on to the calendar
+	sessionID         string
+	negotiatedVersion string
+	state             sessionState
+	clientSampling    bool
+
+	pendingMu     sync.Mutex
+	pending       map[string]chan clientResponse
+	nextRequestID int
+
+	// readOnly is shared by every session of the TCP transport, so that
+	// toggling it applies to all of them
+	readOnly *atomic.Bool
+	// sessionReadOnly is read-only mode asked for by the client of this
+	// session only
+	sessionReadOnly atomic.Bool
+	// defaults is the server's own configuration, which session configs
+	// of clients start from and background work uses. It is taken once,
+	// by configured, and never changes afterwards.
+	defaults     *sessionSettings
+	defaultsOnce sync.Once
+	// dateOrder resolves DD/MM/YYYY vs MM/DD/YYYY input; empty accepts
+	// only unambiguous slash dates
+	dateOrder string
+	// altCalendars add dates in other calendar systems to event output
+	altCalendars []alternateCalendar
+	// maxFieldLength limits event text fields in tool output; 0 means no limit
+	maxFieldLength int
+	workHours      workHours
+	// hourlyRate, when set, turns meeting person-hours into cost estimates
+	hourlyRate *hourlyRate
+	// limiter paces tool calls; nil means no limit. Sessions of the TCP
+	// transport share it.
+	limiter *callLimiter
+	// breaker fails tool calls at once while the calendar backend keeps
+	// failing; nil means it is turned off. Sessions of the TCP transport
+	// share it.
+	breaker *circuitBreaker
+	// usage counts created events to warn near Google's usage limits; nil
+	// turns the warnings off. Sessions of the TCP transport share it.
+	usage *creationUsage
+	// audit records the calls of mutating tools for audit://recent.
+	// Sessions of the TCP transport share it.
+	audit *auditLog
+	// toolTimeouts, when set, bound how long tool calls may take
+	toolTimeouts toolTimeouts
+	// categories, when set, sort events into the configured categories in
+	// listings and reports
+	categories taxonomy
+	// budgets are the weekly hours allowed per category; analyze_time and
+	// create_event warn about categories going over them
+	budgets categoryBudgets
+	// rawPolicy is what gcal_raw_request may call; nil hides the tool
+	rawPolicy rawPolicy
+	// framing is how messages are delimited on stdio: FramingNewline, or
+	// FramingContentLength for hosts that use LSP-style headers
+	framing string
+	// markers prefix listed events with their RSVP and other status; nil
+	// shows none
+	markers statusMarkers
+
+	inFlightMu sync.Mutex
+	inFlight   map[string]context.CancelFunc
+	wg         sync.WaitGroup
+
+	subMu         sync.Mutex
+	subscriptions map[string]string
+	pollInterval  time.Duration
+	pollOnce      sync.Once
+	// reconcileAt is when, HH:MM in the calendar timezone, the calendar
+	// list and subscriptions are reconciled every night; "" turns it off.
+	// reconcileSpacing separates the API reads of a reconciliation.
+	reconcileAt      string
+	reconcileSpacing time.Duration
+	// ended is closed when a session of the TCP transport ends, to stop
+	// its background work
+	ended chan struct{}
+
+	refsMu sync.Mutex
+	// lastListed is the latest list_events or list_events_range result,
+	// which event_ref indexes into
+	lastListed []gcal.CalendarEvent
+
+	snapshotsMu sync.Mutex
+	// snapshots are the states diff_range handed out tokens for, oldest
+	// first
+	snapshots []rangeSnapshot
+
+	calendarList *calendarSync
+
+	// webhooks are the triggers that call out to smart-home automations
+	// before matching events
+	webhooks      []webhookTrigger
+	webhookClient *http.Client
+	firedMu       sync.Mutex
+	// fired holds the start time of each event a trigger fired for, keyed
+	// by trigger and event
+	fired map[string]time.Time
+
+	// auth guards the HTTP transport; nil leaves it open
+	auth *httpAuth
+}
+
+// New returns a server acting on cal that writes replies and notifications
+// to out
+func New(cal gcal.Service, out io.Writer) *Server {
+	s := &Server{
+		name:              serverName,
+		out:               out,
+		sessionID:         rand.Text(),
+		negotiatedVersion: protocolVersion20241105,
+		inFlight:          make(map[string]context.CancelFunc),
+		pending:           make(map[string]chan clientResponse),
+		subscriptions:     make(map[string]string),
+		pollInterval:      defaultPollInterval,
+		reconcileAt:       defaultReconcileAt,
+		reconcileSpacing:  defaultReconcileSpacing,
+		ended:             make(chan struct{}),
+		workHours:         defaultWorkHours,
+		readOnly:          new(atomic.Bool),
+		calendarList:      &calendarSync{},
+		webhookClient:     http.DefaultClient,
+		fired:             make(map[string]time.Time),
+		breaker:           newCircuitBreaker(defaultBreakerFailures, defaultBreakerCooldown),
+		audit:             newAuditLog(),
+		usage:             newCreationUsage(defaultDailyEventLimit),
+	}
+	s.current.Store(&sessionSettings{calendar: cal, location: time.UTC, verbosity: verbosityNormal})
+	return s
+}
+
+// SetFraming sets how messages are delimited on the streams served by Run:
+// FramingNewline, the default, or FramingContentLength
+func (s *Server) SetFraming(framing string) error {
+	switch framing {
+	case FramingNewline, FramingContentLength:
+		s.framing = framing
+		return nil
+	}
+	return fmt.Errorf("invalid framing %q: expected %s or %s", framing, FramingNewline, FramingContentLength)
+}
+
+// Start runs the background work of the server until the process exits:
+// watching the calendar list for changes, reconciling it every night and,
+// when configured, firing webhooks
+func (s *Server) Start() {
+	// Sessions may replace the calendar and timezone from now on
+	s.configured()
+	go s.syncCalendars()
+	go s.runReconciliation()
+	if len(s.webhooks) > 0 {
+		go s.runWebhooks()
+	}
+}
+
+// Run serves requests until the input is closed. It returns an error only
+// when reading the input fails.
+func (s *Server) Run(in io.Reader) error {
+	defer s.shutdown()
+
+	reader := newMessageReader(in)
+	reader.framing = s.framing
+	for {
+		line, err := reader.readMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if errors.Is(err, errMessageTooLarge) {
+			log.Printf("Dropped message larger than %d bytes", maxMessageSize)
+			s.sendError(nil, -32600, "Invalid Request", fmt.Sprintf("message exceeds %d bytes", maxMessageSize))
+			continue
+		}
+		if err != nil {
+			log.Printf("Failed to read input: %v", err)
+			return err
+		}
+		if len(line) == 0 {
+			continue
+		}
+		s.handleMessage(line)
+	}
+}
+
+// handleMessage handles one message of any transport; replies are written
+// to the server output
+func (s *Server) handleMessage(line []byte) {
+	if isBatch(line) {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handleBatch(line)
+		}()
+		return
+	}
+
+	var req JSONRPCRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		s.sendError(nil, -32700, "Parse error", err.Error())
+		return
+	}
+
+	if isClientResponse(req) {
+		s.handleClientResponse(line)
+		return
+	}
+
+	// Tool calls can take a while, so they run concurrently to keep the
+	// loop free for pings and cancellation notifications
+	if req.Method == "tools/call" {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.dispatch(req)
+		}()
+		return
+	}
+
+	s.dispatch(req)
+}
+
+func (s *Server) dispatch(req JSONRPCRequest) {
+	response := s.handleRequest(req)
+	if response != nil {
+		s.sendResponse(response)
+	}
+}
+
+// startRequest registers a cancellable context for an in-flight request
+func (s *Server) startRequest(id interface{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if id == nil {
+		return ctx, cancel
+	}
+
+	key := fmt.Sprint(id)
+	ctx = gcal.WithRequestInfo(ctx, gcal.RequestInfo{SessionID: s.currentSessionID(), RequestID: key})
+	s.inFlightMu.Lock()
+	s.inFlight[key] = cancel
+	s.inFlightMu.Unlock()
+
+	return ctx, func() {
+		s.inFlightMu.Lock()
+		delete(s.inFlight, key)
+		s.inFlightMu.Unlock()
+		cancel()
+	}
+}
+
+func (s *Server) handleCancelled(req JSONRPCRequest) {
+	var params struct {
+		RequestID interface{} `json:"requestId"`
+		Reason    string      `json:"reason"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.RequestID == nil {
+		return
+	}
+
+	s.inFlightMu.Lock()
+	cancel, ok := s.inFlight[fmt.Sprint(params.RequestID)]
+	s.inFlightMu.Unlock()
+
+	if ok {
+		log.Printf("Cancelling request %v (session %s): %s", params.RequestID, s.currentSessionID(), params.Reason)
+		cancel()
+	}
+}
+
+func (s *Server) isReadOnly() bool {
+	return s.readOnly.Load() || s.sessionReadOnly.Load()
+}
+
+// setReadOnly switches read-only mode at runtime and tells the client to
+// refresh its tool list, since mutating tools appear or disappear.
+func (s *Server) setReadOnly(readOnly bool) {
+	if s.readOnly.Swap(readOnly) == readOnly {
+		return
+	}
+	log.Printf("Read-only mode set to %t", readOnly)
+	s.sendNotification("notifications/tools/list_changed", nil)
+}
+
+func (s *Server) sendResponse(resp *JSONRPCResponse) {
+	if data := encodeResponse(resp); data != nil {
+		s.writeLine(data)
+	}
+}
+
+// encodeResponse marshals a response. When that fails it reports the
+// failure instead of emitting a broken line, so the client is not left
+// waiting for the request forever.
+func encodeResponse(resp *JSONRPCResponse) []byte {
+	data, err := json.Marshal(resp)
+	if err == nil {
+		return data
+	}
+
+	log.Printf("Failed to marshal response for request %v: %v", resp.ID, err)
+	data, err = json.Marshal(&JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      resp.ID,
+		Error: &RPCError{
+			Code:    -32603,
+			Message: "Internal error",
+			Data:    "failed to encode response",
+		},
+	})
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func (s *Server) sendNotification(method string, params interface{}) {
+	data, err := json.Marshal(&JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		log.Printf("Failed to marshal %s notification: %v", method, err)
+		return
+	}
+	s.writeLine(data)
+}
+
+// writeLine writes a single message to the output stream. Responses and
+// notifications are sent from several goroutines, so writes are serialized
+// to keep messages from interleaving.
+func (s *Server) writeLine(data []byte) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+
+	msg := append(data, '\n')
+	if s.framing == FramingContentLength {
+		msg = append([]byte(fmt.Sprintf("Content-Length: %d\r\n\r\n", len(data))), data...)
+	}
+	if _, err := s.out.Write(msg); err != nil {
+		log.Printf("Failed to write message: %v", err)
+	}
+}
+
+func (s *Server) sendError(id interface{}, code int, message string, data interface{}) {
+	resp := &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &RPCError{
+			Code:    code,
+			Message: message,
+			Data:    data,
+		},
+	}
+	s.sendResponse(resp)
+}
+
+func (s *Server) handleRequest(req JSONRPCRequest) *JSONRPCResponse {
+	if rpcErr := s.checkLifecycle(req); rpcErr != nil {
+		log.Printf("Rejected %s: %s", req.Method, rpcErr.Message)
+		if req.ID == nil {
+			// Notifications never get a reply
+			return nil
+		}
+		return &JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Error: rpcErr}
+	}
+
+	switch req.Method {
+	case "initialize":
+		resp := s.handleInitialize(req)
+		if resp.Error != nil {
+			s.initializeFailed()
+		}
+		return resp
+	case "initialized", "notifications/initialized":
+		return nil
+	case "ping":
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Result:  map[string]interface{}{},
+		}
+	case "notifications/cancelled":
+		s.handleCancelled(req)
+		return nil
+	case "tools/list":
+		return s.handleToolsList(req)
+	case "tools/call":
+		return s.handleToolsCall(req)
+	case "resources/list":
+		return s.handleResourcesList(req)
+	case "resources/read":
+		return s.handleResourcesRead(req)
+	case "resources/templates/list":
+		return s.handleResourceTemplatesList(req)
+	case "resources/subscribe":
+		return s.handleResourcesSubscribe(req)
+	case "resources/unsubscribe":
+		return s.handleResourcesUnsubscribe(req)
+	case "prompts/list":
+		return s.handlePromptsList(req)
+	case "prompts/get":
+		return s.handlePromptsGet(req)
+	default:
+		return &JSONRPCResponse{
+			JSONRPC: "2.0",
+			ID:      req.ID,
+			Error: &RPCError{
+				Code:    -32601,
+				Message: "Method not found",
+			},
+		}
+	}
+}
+
+func (s *Server) handleInitialize(req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		Capabilities    struct {
+			Sampling json.RawMessage `json:"sampling"`
+		} `json:"capabilities"`
+		Meta struct {
+			SessionConfig *sessionConfig `json:"sessionConfig"`
+		} `json:"_meta"`
+	}
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.paramError(req.ID, "Invalid params", err.Error())
+		}
+	}
+	if cfg := params.Meta.SessionConfig; cfg != nil {
+		if err := s.applySessionConfig(*cfg); err != nil {
+			return s.paramError(req.ID, "Invalid session config", err.Error())
+		}
+	}
+
+	negotiated := negotiateProtocolVersion(params.ProtocolVersion)
+	s.setProtocolVersion(negotiated)
+
+	s.sessionMu.Lock()
+	s.clientSampling = len(params.Capabilities.Sampling) > 0 && string(params.Capabilities.Sampling) != "null"
+	s.sessionMu.Unlock()
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result: map[string]interface{}{
+			"protocolVersion": negotiated,
+			"serverInfo": map[string]string{
+				"name":    s.name,
+				"version": version,
+			},
+			"instructions": s.instructions(time.Now()),
+			"capabilities": map[string]interface{}{
+				"tools": map[string]interface{}{
+					"listChanged": true,
+				},
+				"resources": map[string]interface{}{
+					"subscribe":   true,
+					"listChanged": true,
+				},
+				"prompts": map[string]interface{}{},
+				"experimental": map[string]interface{}{
+					experimentalEventStreaming: map[string]interface{}{},
+					experimentalSessionConfig:  map[string]interface{}{},
+				},
+			},
+		},
+	}
+}
+
+// toolAvailable reports whether a tool can be listed with the current
+// configuration and client capabilities. Mutating tools are listed while
+// any account may write to its primary calendar.
+func (s *Server) toolAvailable(t toolDefinition) bool {
+	if t.mutating && (s.isReadOnly() || s.primariesReadOnly(s.settings().calendar)) {
+		return false
+	}
+	return s.toolSupported(t)
+}
+
+// toolAvailableFor reports whether a tool can be called for account, ""
+// for the default one; mutating tools need write access to its primary
+// calendar
+func (s *Server) toolAvailableFor(t toolDefinition, account string) bool {
+	if t.mutating && (s.isReadOnly() || s.calendarReadOnly(account, s.accountService(account).CalendarID())) {
+		return false
+	}
+	return s.toolSupported(t)
+}
+
+// toolSupported reports whether the configuration and the client
+// capabilities allow a tool
+func (s *Server) toolSupported(t toolDefinition) bool {
+	if t.requiresSampling && !s.clientSupportsSampling() {
+		return false
+	}
+	if t.requiresRawPolicy && s.rawPolicy == nil {
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleToolsList(req JSONRPCRequest) *JSONRPCResponse {
+	tools := make([]map[string]interface{}, 0, len(toolDefinitions))
+	for _, t := range toolDefinitions {
+		if !s.toolAvailable(t) {
+			continue
+		}
+		if t.requiresRawPolicy {
+			t = s.rawToolDefinition(t)
+		}
+		if accounts := s.accounts(); accounts != nil && !t.local {
+			t = accountToolDefinition(t, accounts.Names())
+		}
+		if s.settings().verbosity != verbosityNormal {
+			t = verbosityToolDefinition(t)
+		}
+		tool := map[string]interface{}{
+			"name":        s.exposedToolName(t.name),
+			"description": t.description,
+			"inputSchema": t.inputSchema,
+		}
+		if s.supportsVersion(protocolVersion20250326) {
+			tool["annotations"] = t.annotations()
+		}
+		if s.supportsVersion(protocolVersion20250618) {
+			tool["title"] = t.title
+			if t.outputSchema != nil {
+				tool["outputSchema"] = t.outputSchema
+			}
+		}
+		tools = append(tools, tool)
+	}
+
+	cursor, err := cursorParam(req.Params)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+	page, next, err := paginate(tools, cursor, listPageLimit)
+	if err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	result := map[string]interface{}{
+		"tools": page,
+	}
+	if next != "" {
+		result["nextCursor"] = next
+	}
+
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      req.ID,
+		Result:  result,
+	}
+}
+
+func (s *Server) handleToolsCall(req JSONRPCRequest) *JSONRPCResponse {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+		Meta      requestMeta     `json:"_meta"`
+	}
+
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return s.paramError(req.ID, "Invalid params", err.Error())
+	}
+
+	name, ok := s.internalToolName(params.Name)
+	if !ok {
+		return s.paramError(req.ID, "Unknown tool: "+params.Name, nil)
+	}
+	name, deprecation := resolveToolAlias(name)
+
+	account, err := s.accountArg(params.Arguments)
+	if err != nil {
+		return s.paramError(req.ID, err.Error(), nil)
+	}
+	if t, ok := findTool(name); ok && !s.toolAvailableFor(t, account) {
+		if t.requiresRawPolicy && s.rawPolicy == nil {
+			return s.paramError(req.ID, "Tool "+params.Name+" is disabled; set CALENDAR_RAW_METHODS to enable it", nil)
+		}
+		if t.requiresSampling && !s.clientSupportsSampling() {
+			return s.paramError(req.ID, "Tool "+params.Name+" requires a client with sampling support", nil)
+		}
+		if calendarID := s.accountService(account).CalendarID(); t.mutating && !s.isReadOnly() && s.calendarReadOnly(account, calendarID) {
+			return s.paramError(req.ID, "Tool "+params.Name+" is disabled: you have read-only access to calendar "+calendarID, nil)
+		}
+		return s.paramError(req.ID, "Tool "+params.Name+" is disabled in read-only mode", nil)
+	}
+	if t, ok := findTool(name); ok {
+		if err := validateArguments(t.inputSchema, params.Arguments); err != nil {
+			return s.paramError(req.ID, err.Error(), nil)
+		}
+	}
+
+	ctx, cancel := s.startRequest(req.ID)
+	defer cancel()
+	if traceID := params.Meta.traceID(); traceID != "" {
+		info := gcal.RequestInfoFrom(ctx)
+		info.TraceID = traceID
+		ctx = gcal.WithRequestInfo(ctx, info)
+	}
+	if account != "" {
+		ctx = gcal.WithAccount(ctx, account)
+	}
+	verbosity, err := s.verbosityArg(params.Arguments)
+	if err != nil {
+		return s.paramError(req.ID, err.Error(), nil)
+	}
+	// A request cancelled while waiting for its turn gets no response
+	if err := s.limiter.wait(ctx); err != nil {
+		return nil
+	}
+	if params.Meta.ProgressToken != nil {
+		ctx = gcal.WithProgress(ctx, s.progressReporter(params.Meta.ProgressToken))
+		if params.Meta.EventStreaming {
+			ctx = gcal.WithPageReporter(ctx, s.pageStreamer(params.Meta.ProgressToken))
+		}
+	}
+
+	call := &toolCall{id: req.ID, name: name, args: params.Arguments, meta: params.Meta, verbosity: verbosity}
+	resp := s.callTool(ctx, call)
+
+	// A cancelled request gets no response, per the MCP cancellation spec
+	if ctx.Err() == context.Canceled {
+		return nil
+	}
+	if deprecation != "" {
+		addTextContent(resp, deprecation)
+	}
+	return resp
+}
+
+func (s *Server) callTool(ctx context.Context, call *toolCall) *JSONRPCResponse {
+	if t, ok := findTool(call.name); ok {
+		return t.handler(s, ctx, call)
+	}
+	return s.paramError(call.id, "Unknown tool: "+call.name, nil)
+}
+
+// listEventsInput is the arguments of list_events
+type listEventsInput struct {
+	// Number of days to look ahead (default: 7)
+	Days int `json:"days" jsonschema:"default=7"`
+	calendarArg
+}
+
+func (s *Server) callListEvents(ctx context.Context, input listEventsInput) (eventList, error) {
+	if input.Days <= 0 {
+		input.Days = 7
+	}
+
+	var events []gcal.CalendarEvent
+	var err error
+	if input.Calendar == "" {
+		events, err = s.settings().calendar.ListEventsForDays(ctx, input.Days)
+	} else {
+		today := time.Now().In(s.settings().location)
+		events, err = s.listCalendarRange(ctx, input.Calendar, today.Format("2006-01-02"), today.AddDate(0, 0, input.Days).Format("2006-01-02"))
+	}
+	if err != nil {
+		return eventList{}, err
+	}
+
+	return s.eventList(events), nil
+}
+
+// listEventsRangeInput is the arguments of list_events_range
+type listEventsRangeInput struct {
+	// Start date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)
+	StartDate string `json:"start_date" jsonschema:"required"`
+	// End date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also accepted)
+	EndDate string `json:"end_date" jsonschema:"required"`
+	calendarArg
+}
+
+func (s *Server) callListEventsRange(ctx context.Context, input listEventsRangeInput) (eventList, error) {
+	if input.StartDate == "" || input.EndDate == "" {
+		return eventList{}, badArgumentf("start_date and end_date are required")
+	}
+
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return eventList{}, badArgument(err)
+		}
+	}
+
+	events, err := s.listCalendarRange(ctx, input.Calendar, input.StartDate, input.EndDate)
+	if err != nil {
+		return eventList{}, err
+	}
+
+	return s.eventList(events), nil
+}
+
+// getEventInput is the arguments of get_event
+type getEventInput struct {
+	EventID string `json:"event_id" jsonschema:"description=eventIDDescription"`
+	eventRefArg
+	// text (default) or ics to also embed the event as a text/calendar resource
+	Format string `json:"format" jsonschema:"enum=eventFormatText|eventFormatICS"`
+}
+
+func (s *Server) callGetEvent(ctx context.Context, input getEventInput) (eventDetails, error) {
+	eventID, calendarID, err := s.eventArg(ctx, input.EventID, input.EventRef)
+	if err != nil {
+		return eventDetails{}, err
+	}
+	if eventID == "" {
+		return eventDetails{}, badArgumentf("event_id or event_ref is required (use list_events to find events)")
+	}
+	switch input.Format {
+	case "", eventFormatText, eventFormatICS:
+	default:
+		return eventDetails{}, badArgumentf("format must be text or ics")
+	}
+
+	cal := s.onCalendar(calendarID)
+	event, err := cal.GetEvent(ctx, eventID)
+	if err != nil {
+		return eventDetails{}, err
+	}
+	return eventDetails{event: event, calendarID: cal.CalendarID(), ics: input.Format == eventFormatICS}, nil
+}
+
+// eventDetails is the output of get_event: the event as text and, with ics,
+// as an embedded text/calendar resource
+type eventDetails struct {
+	event      *calendar.Event
+	calendarID string
+	ics        bool
+}
+
+func (d eventDetails) toolText(s *Server) string { return s.formatEventDetails(d.event) }
+func (eventDetails) textOnly()                   {}
+
+func (d eventDetails) toolContent(s *Server) []map[string]interface{} {
+	if !d.ics {
+		return nil
+	}
+	return []map[string]interface{}{{
+		"type": "resource",
+		"resource": map[string]string{
+			"uri":      eventResourceURI(d.calendarID, d.event.Id),
+			"mimeType": icsMimeType,
+			"text":     eventToICS(d.event, time.Now()),
+		},
+	}}
+}
+
+// createEventInput is the arguments of create_event
+type createEventInput struct {
+	newEventArgs
+}
+
+func (s *Server) callCreateEvent(ctx context.Context, input createEventInput) (textOutput, error) {
+	if input.Summary == "" || input.Date == "" || input.StartTime == "" || input.EndTime == "" {
+		return "", badArgumentf("summary, date, start_time, and end_time are required")
+	}
+
+	if err := s.normalizeDateArg(&input.Date); err != nil {
+		return "", badArgument(err)
+	}
+
+	event, err := s.settings().calendar.CreateEvent(ctx, input.Summary, input.Description, input.Date, input.StartTime, input.EndTime, input.Force)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Event created successfully!\nID: %s\nLink: %s", event.Id, event.HtmlLink)
+	if d, ok := gcal.EventDuration(event); ok {
+		result += "\nDuration: " + gcal.FormatDuration(d)
+	}
+	if warning := s.budgetWarning(ctx, event); warning != "" {
+		result += "\n" + warning
+	}
+	return textOutput(result), nil
+}
+
+// deleteEventInput is the arguments of delete_event
+type deleteEventInput struct {
+	EventID string `json:"event_id" jsonschema:"description=eventIDDescription"`
+	eventRefArg
+	seriesScopeArgs
+}
+
+func (s *Server) callDeleteEvent(ctx context.Context, input deleteEventInput) (textOutput, error) {
+	eventID, calendarID, err := s.eventArg(ctx, input.EventID, input.EventRef)
+	if err != nil {
+		return "", err
+	}
+	if eventID == "" {
+		return "", badArgumentf("event_id or event_ref is required (use list_events to find events)")
+	}
+	// Write access to the primary calendar is checked before the call
+	if calendarID != "" {
+		if err := s.checkWritable(ctx, calendarID); err != nil {
+			return "", err
+		}
+	}
+	cal := s.onCalendar(calendarID)
+	scope, err := input.scope(s)
+	if err != nil {
+		return "", err
+	}
+
+	if scope.ApplyTo == "" {
+		err = cal.DeleteEvent(ctx, eventID)
+	} else {
+		err = cal.DeleteOccurrences(ctx, cal.CalendarID(), eventID, scope)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "Event deleted successfully!", nil
+}
+
+// updateEventInput is the arguments of update_event
+type updateEventInput struct {
+	EventID string `json:"event_id" jsonschema:"description=eventIDDescription"`
+	eventRefArg
+	eventChangeArgs
+	seriesScopeArgs
+}
+
+func (s *Server) callUpdateEvent(ctx context.Context, input updateEventInput) (textOutput, error) {
+	eventID, calendarID, err := s.eventArg(ctx, input.EventID, input.EventRef)
+	if err != nil {
+		return "", err
+	}
+	if eventID == "" {
+		return "", badArgumentf("event_id or event_ref is required (use list_events to find events)")
+	}
+	// Write access to the primary calendar is checked before the call
+	if calendarID != "" {
+		if err := s.checkWritable(ctx, calendarID); err != nil {
+			return "", err
+		}
+	}
+	cal := s.onCalendar(calendarID)
+
+	if err := s.normalizeDateArg(input.Date); err != nil {
+		return "", badArgument(err)
+	}
+	scope, err := input.scope(s)
+	if err != nil {
+		return "", err
+	}
+
+	updates := gcal.EventUpdates{
+		Summary:     input.Summary,
+		Description: input.Description,
+		Date:        input.Date,
+		StartTime:   input.StartTime,
+		EndTime:     input.EndTime,
+		Force:       input.Force,
+		Scope:       scope,
+	}
+
+	event, err := cal.UpdateEvent(ctx, eventID, updates)
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Event updated successfully!\nID: %s\nSummary: %s\nLink: %s", event.Id, s.sanitize(event.Summary), event.HtmlLink)
+	if d, ok := gcal.EventDuration(event); ok {
+		result += "\nDuration: " + gcal.FormatDuration(d)
+	}
+	return textOutput(result), nil
+}
+
+// eventList is the output of the listing tools: the events as text and
+// structured content, with a resource link per event on protocol revisions
+// that support them
+type eventList struct {
+	Events []gcal.CalendarEvent `json:"events"`
+}
+
+// eventList remembers the events of a listing for event_ref
+func (s *Server) eventList(events []gcal.CalendarEvent) eventList {
+	s.rememberListing(events)
+	return eventList{Events: events}
+}
+
+func (l eventList) toolText(s *Server) string    { return s.formatEventList(l.Events, true, false) }
+func (l eventList) verboseText(s *Server) string { return s.formatEventList(l.Events, true, true) }
+
+func (l eventList) toolContent(s *Server) []map[string]interface{} {
+	if !s.supportsVersion(protocolVersion20250618) {
+		return nil
+	}
+	var links []map[string]interface{}
+	for _, e := range l.Events {
+		// Event resources can only be read from the primary calendar
+		if e.CalendarID == "" || e.CalendarID == s.settings().calendar.CalendarID() {
+			links = append(links, s.eventResourceLink(e))
+		}
+	}
+	return links
+}
+
+// serverVersionInput is the arguments of get_server_version, which takes
+// none
+type serverVersionInput struct{}
+
+func (s *Server) callServerVersion(context.Context, serverVersionInput) (buildInfo, error) {
+	return currentBuildInfo(), nil
+}
+
+func (s *Server) formatEvents(events []gcal.CalendarEvent) string {
+	return s.formatEventList(events, false, false)
+}
+
+// formatEventList renders events as text; with refs, each one is numbered
+// for use as event_ref, and verbose adds their location, guests and link
+func (s *Server) formatEventList(events []gcal.CalendarEvent, refs, verbose bool) string {
+	if len(events) == 0 {
+		return "No events found."
+	}
+
+	result := fmt.Sprintf("Found %d event(s):\n\n", len(events))
+	for i, e := range events {
+		result += fmt.Sprintf("- %s%s\n  Start: %s\n  End: %s\n", s.markers.prefix(e), s.sanitize(e.Summary), e.Start, e.End)
+		result += s.alternateDates(e.Start)
+		result += s.eventCost(e)
+		if s.categories != nil {
+			if c := s.categories.categorize(e); c != "" {
+				result += fmt.Sprintf("  Category: %s\n", c)
+			}
+		}
+		if verbose {
+			if e.Location != "" {
+				result += fmt.Sprintf("  Location: %s\n", s.sanitize(e.Location))
+			}
+			if e.Attendees > 0 {
+				result += fmt.Sprintf("  Guests: %d\n", e.Attendees)
+			}
+			if e.HTMLLink != "" {
+				result += fmt.Sprintf("  Link: %s\n", e.HTMLLink)
+			}
+		}
+		if refs {
+			result += fmt.Sprintf("  Ref: #%d\n", i+1)
+		}
+		result += fmt.Sprintf("  ID: %s\n\n", e.ID)
+	}
+
+	return result
+}
+
+func (s *Server) sanitize(text string) string {
+	return sanitizeText(text, s.maxFieldLength)
+}
+
+func (s *Server) successResponse(id interface{}, text string) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{"type": "text", "text": text},
+			},
+		},
+	}
+}
+
+// structuredResponse is a successResponse that also carries machine-readable
+// structuredContent for clients on protocol revisions that support it
+func (s *Server) structuredResponse(id interface{}, text string, structured interface{}) *JSONRPCResponse {
+	resp := s.successResponse(id, text)
+	if s.supportsVersion(protocolVersion20250618) {
+		resp.Result.(map[string]interface{})["structuredContent"] = structured
+	}
+	return resp
+}
+
+// addTextContent appends a text block to a tool result
+func addTextContent(resp *JSONRPCResponse, text string) {
+	addContent(resp, map[string]interface{}{"type": "text", "text": text})
+}
+
+// addContent appends a content block to a tool result
+func addContent(resp *JSONRPCResponse, block map[string]interface{}) {
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		return
+	}
+	content, _ := result["content"].([]map[string]interface{})
+	result["content"] = append(content, block)
+}
+
+// errorResponse reports a failed tool call as an isError result, so the
+// model sees what went wrong. The text carries the error code; on protocol
+// revisions that support it, structuredContent adds the HTTP status, the
+// Google error reason and whether a retry may help. Malformed requests are
+// JSON-RPC errors instead, see paramError. Credentials that stopped working
+// come with recovery steps.
+func (s *Server) errorResponse(id interface{}, err error) *JSONRPCResponse {
+	detail := describeError(err)
+	text := fmt.Sprintf("Error [%s]: %s", detail.Code, detail.Message)
+	if detail.Retryable {
+		text += " (temporary, retry later)"
+	}
+	if detail.Recovery != nil {
+		text += "\n\n" + detail.Recovery.text()
+	}
+	result := map[string]interface{}{
+		"content": []map[string]interface{}{
+			{"type": "text", "text": text},
+		},
+		"isError": true,
+	}
+	if s.supportsVersion(protocolVersion20250618) {
+		result["structuredContent"] = map[string]interface{}{"error": detail}
+	}
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Result:  result,
+	}
+}
+
+func (s *Server) paramError(id interface{}, message string, data interface{}) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: &RPCError{
+			Code:    -32602,
+			Message: message,
+			Data:    data,
+		},
+	}
+}
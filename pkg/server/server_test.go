@@ -0,0 +1,1212 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+// fakeCalendar implements gcal.Service for testing
+type fakeCalendar struct {
+	events  []gcal.CalendarEvent
+	err     error
+	created *calendar.Event
+	// createInfo is the request info CreateEvent was called with
+	createInfo gcal.RequestInfo
+	updated    *calendar.Event
+	// fetched, when set, is what GetEvent returns
+	fetched    *calendar.Event
+	lastUpdate gcal.EventUpdates
+	lastDays   int
+	lastStart  string
+	lastEnd    string
+	deletedID  string
+	deleteErr  error
+	// deleteScope records the scope DeleteOccurrences was called with
+	deleteScope gcal.SeriesScope
+	// started, when set, is closed once a listing begins; the call then
+	// blocks until its context is cancelled
+	started chan struct{}
+	// extraCalendars holds the events of calendars other than the primary
+	extraCalendars map[string][]gcal.CalendarEvent
+	// calendarList is what ListCalendars returns, with names from
+	// calendarNames
+	calendarList  []string
+	calendarNames map[string]string
+	calendarRoles map[string]string
+	outOfOffice   *outOfOfficeCall
+	// responses records RespondToEvent calls by event ID
+	responses  map[string]string
+	respondErr error
+	// instances is what ListInstances returns
+	instances []gcal.SeriesInstance
+	// delegated is what ListDelegatedActions returns
+	delegated []gcal.DelegatedAction
+	// drafts records CreateCalendarEvent calls by calendar ID; createErrs
+	// makes creating or updating events on some calendars fail
+	drafts     map[string]gcal.EventDraft
+	createErrs map[string]error
+	// linked holds the copies ListLinkedEvents finds per calendar;
+	// linkedUpdates records UpdateCalendarEvent calls by calendar ID
+	linked        map[string][]gcal.CalendarEvent
+	linkedUpdates map[string]gcal.EventUpdates
+	// raw is what RawRequest returns; rawMethod and rawParams record the
+	// last call
+	raw       json.RawMessage
+	rawMethod string
+	rawParams gcal.RawParams
+	// authStatus is what AuthStatus returns
+	authStatus gcal.AuthStatus
+	// reauthCode records the code Reauthorize was called with
+	reauthCode string
+	// lastQuery records the query SearchEvents was called with
+	lastQuery string
+	// quickAdd records the calendar and text QuickAddEvent was called with
+	quickAdd [2]string
+	// busy is what QueryFreeBusy returns per calendar, those missing are
+	// notFound; freeBusyMin and freeBusyMax record the last window
+	busy                     map[string][]gcal.BusyBlock
+	freeBusyMin, freeBusyMax time.Time
+	// scheduleBlocked, when set, is the range CheckSchedule refuses
+	scheduleBlocked [2]time.Time
+
+	// viewCalendar is the calendar of the last event read, updated or
+	// deleted through a view of another calendar, see WithDefaults, or the
+	// calendar DeleteOccurrences was given
+	viewCalendar string
+}
+
+type outOfOfficeCall struct {
+	summary     string
+	start, end  time.Time
+	autoDecline bool
+}
+
+func (f *fakeCalendar) ListEventsForDays(ctx context.Context, days int) ([]gcal.CalendarEvent, error) {
+	f.lastDays = days
+	gcal.ReportPage(ctx, len(f.events), f.events)
+	if f.started != nil {
+		close(f.started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return f.events, f.err
+}
+
+func (f *fakeCalendar) AuthStatus(context.Context) (*gcal.AuthStatus, error) {
+	return &f.authStatus, f.err
+}
+
+func (f *fakeCalendar) AuthURL(context.Context) (string, error) {
+	return "https://accounts.google.com/o/oauth2/auth?access_type=offline", f.err
+}
+
+func (f *fakeCalendar) Reauthorize(_ context.Context, code string) error {
+	f.reauthCode = code
+	return f.err
+}
+
+func (f *fakeCalendar) AuthorizeDevice(context.Context) (*gcal.DeviceAuth, error) {
+	done := make(chan error, 1)
+	done <- f.err
+	return &gcal.DeviceAuth{VerificationURL: "https://www.google.com/device", UserCode: "ABCD-EFGH", Expiry: time.Now().Add(30 * time.Minute), Done: done}, nil
+}
+
+func (f *fakeCalendar) CalendarID() string {
+	return "test@example.com"
+}
+
+func (f *fakeCalendar) Calendars() []string {
+	ids := []string{f.CalendarID()}
+	for id := range f.extraCalendars {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids[1:])
+	return ids
+}
+
+func (f *fakeCalendar) ListCalendarEvents(ctx context.Context, calendarID, start, end string) ([]gcal.CalendarEvent, error) {
+	if calendarID == f.CalendarID() {
+		return f.ListEventsRange(ctx, start, end)
+	}
+	return f.extraCalendars[calendarID], f.err
+}
+
+func (f *fakeCalendar) CheckSchedule(_ context.Context, start, end time.Time) error {
+	if start.Before(f.scheduleBlocked[1]) && end.After(f.scheduleBlocked[0]) {
+		return &gcal.InvalidInputError{Err: errors.New("the time is blocked")}
+	}
+	return nil
+}
+
+func (f *fakeCalendar) QueryFreeBusy(_ context.Context, calendarIDs []string, timeMin, timeMax time.Time) ([]gcal.BusyCalendar, error) {
+	f.freeBusyMin, f.freeBusyMax = timeMin, timeMax
+	var result []gcal.BusyCalendar
+	for _, id := range calendarIDs {
+		busy, ok := f.busy[id]
+		if !ok {
+			result = append(result, gcal.BusyCalendar{ID: id, Busy: []gcal.BusyBlock{}, Error: "notFound"})
+			continue
+		}
+		result = append(result, gcal.BusyCalendar{ID: id, Busy: busy})
+	}
+	return result, f.err
+}
+
+func (f *fakeCalendar) SearchEvents(_ context.Context, calendarID, query, start, end string) ([]gcal.CalendarEvent, error) {
+	f.lastQuery, f.lastStart, f.lastEnd = query, start, end
+	var result []gcal.CalendarEvent
+	for _, e := range f.events {
+		if strings.Contains(strings.ToLower(e.Summary), strings.ToLower(query)) {
+			result = append(result, e)
+		}
+	}
+	return result, f.err
+}
+
+func (f *fakeCalendar) ListCalendars(context.Context) ([]gcal.CalendarInfo, error) {
+	var result []gcal.CalendarInfo
+	for _, id := range f.calendarList {
+		result = append(result, gcal.CalendarInfo{ID: id, Summary: f.calendarNames[id], AccessRole: f.calendarRoles[id]})
+	}
+	return result, f.err
+}
+
+func (f *fakeCalendar) GetEvent(_ context.Context, eventID string) (*calendar.Event, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.fetched != nil {
+		return f.fetched, nil
+	}
+	return &calendar.Event{Id: eventID, Summary: "Event " + eventID}, nil
+}
+
+func (f *fakeCalendar) ListEventsRange(_ context.Context, start, end string) ([]gcal.CalendarEvent, error) {
+	f.lastStart = start
+	f.lastEnd = end
+	return f.events, f.err
+}
+
+func (f *fakeCalendar) CreateEvent(ctx context.Context, summary, description, date, startTime, endTime string, force bool) (*calendar.Event, error) {
+	f.createInfo = gcal.RequestInfoFrom(ctx)
+	if f.err == nil {
+		gcal.ReportCreated(ctx)
+	}
+	return f.created, f.err
+}
+
+func (f *fakeCalendar) CreateCalendarEvent(_ context.Context, calendarID string, draft gcal.EventDraft) (*calendar.Event, error) {
+	if err := f.createErrs[calendarID]; err != nil {
+		return nil, err
+	}
+	if f.drafts == nil {
+		f.drafts = make(map[string]gcal.EventDraft)
+	}
+	f.drafts[calendarID] = draft
+	return &calendar.Event{Id: "evt-" + calendarID, Summary: draft.Summary}, nil
+}
+
+func (f *fakeCalendar) QuickAddEvent(_ context.Context, calendarID, text string) (*calendar.Event, error) {
+	f.quickAdd = [2]string{calendarID, text}
+	return f.created, f.err
+}
+
+func (f *fakeCalendar) UpdateEvent(_ context.Context, eventID string, updates gcal.EventUpdates) (*calendar.Event, error) {
+	f.lastUpdate = updates
+	return f.updated, f.err
+}
+
+func (f *fakeCalendar) UpdateCalendarEvent(_ context.Context, calendarID, eventID string, updates gcal.EventUpdates) (*calendar.Event, error) {
+	if err := f.createErrs[calendarID]; err != nil {
+		return nil, err
+	}
+	if f.linkedUpdates == nil {
+		f.linkedUpdates = make(map[string]gcal.EventUpdates)
+	}
+	f.linkedUpdates[calendarID] = updates
+	return &calendar.Event{Id: eventID}, nil
+}
+
+func (f *fakeCalendar) ListLinkedEvents(_ context.Context, calendarID, broadcastID string) ([]gcal.CalendarEvent, error) {
+	return f.linked[calendarID], f.err
+}
+
+func (f *fakeCalendar) CreateOutOfOffice(_ context.Context, summary string, start, end time.Time, autoDecline bool, message string) (*calendar.Event, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.outOfOffice = &outOfOfficeCall{summary: summary, start: start, end: end, autoDecline: autoDecline}
+	return &calendar.Event{Id: "ooo-1", Summary: summary}, nil
+}
+
+func (f *fakeCalendar) RespondToEvent(_ context.Context, eventID, status, comment string) error {
+	if f.responses == nil {
+		f.responses = make(map[string]string)
+	}
+	f.responses[eventID] = status
+	return f.respondErr
+}
+
+func (f *fakeCalendar) ListInstances(_ context.Context, seriesID, startDate, endDate string) ([]gcal.SeriesInstance, error) {
+	f.lastStart, f.lastEnd = startDate, endDate
+	return f.instances, f.err
+}
+
+func (f *fakeCalendar) ListDelegatedActions(context.Context, time.Time) ([]gcal.DelegatedAction, error) {
+	return f.delegated, f.err
+}
+
+func (f *fakeCalendar) RawRequest(_ context.Context, method string, params gcal.RawParams) (json.RawMessage, error) {
+	f.rawMethod, f.rawParams = method, params
+	return f.raw, f.err
+}
+
+// sessionCalendar is the fake seen by a session with its own default
+// calendar; everything else goes to the shared fake
+type sessionCalendar struct {
+	*fakeCalendar
+	calendarID, timezone string
+}
+
+func (f *fakeCalendar) WithDefaults(calendarID, timezone string) gcal.Service {
+	if calendarID == "" {
+		calendarID = f.CalendarID()
+	}
+	return &sessionCalendar{fakeCalendar: f, calendarID: calendarID, timezone: timezone}
+}
+
+func (c *sessionCalendar) CalendarID() string {
+	return c.calendarID
+}
+
+func (c *sessionCalendar) GetEvent(ctx context.Context, eventID string) (*calendar.Event, error) {
+	c.viewCalendar = c.calendarID
+	return c.fakeCalendar.GetEvent(ctx, eventID)
+}
+
+func (c *sessionCalendar) UpdateEvent(ctx context.Context, eventID string, updates gcal.EventUpdates) (*calendar.Event, error) {
+	c.viewCalendar = c.calendarID
+	return c.fakeCalendar.UpdateEvent(ctx, eventID, updates)
+}
+
+func (c *sessionCalendar) DeleteEvent(ctx context.Context, eventID string) error {
+	c.viewCalendar = c.calendarID
+	return c.fakeCalendar.DeleteEvent(ctx, eventID)
+}
+
+func (f *fakeCalendar) DeleteEvent(_ context.Context, eventID string) error {
+	f.deletedID = eventID
+	return f.deleteErr
+}
+
+func (f *fakeCalendar) DeleteOccurrences(_ context.Context, calendarID, eventID string, scope gcal.SeriesScope) error {
+	f.viewCalendar, f.deletedID, f.deleteScope = calendarID, eventID, scope
+	return f.deleteErr
+}
+
+// newTestServer returns a server that has completed the initialize
+// handshake
+func newTestServer(fake *fakeCalendar) *Server {
+	return newReadyServer(fake, &bytes.Buffer{})
+}
+
+func newReadyServer(fake *fakeCalendar, out io.Writer) *Server {
+	s := New(fake, out)
+	s.state = stateReady
+	return s
+}
+
+func TestHandleInitialize(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	params, _ := json.Marshal(map[string]string{"protocolVersion": "2024-11-05"})
+	req := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: params}
+
+	resp := s.handleRequest(req)
+	if resp == nil {
+		t.Fatal("expected response, got nil")
+	}
+
+	result, ok := resp.Result.(map[string]interface{})
+	if !ok {
+		t.Fatal("result is not a map")
+	}
+	if result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("expected protocol version 2024-11-05, got %v", result["protocolVersion"])
+	}
+
+	serverInfo := result["serverInfo"].(map[string]string)
+	if serverInfo["name"] != "google-calendar" {
+		t.Errorf("expected server name google-calendar, got %s", serverInfo["name"])
+	}
+	if serverInfo["version"] != version {
+		t.Errorf("expected server version %s, got %s", version, serverInfo["version"])
+	}
+	if _, ok := result["instructions"].(string); !ok {
+		t.Error("expected instructions in initialize result")
+	}
+}
+
+func TestHandleInitialize_ServerNameSuffix(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	s.name = serverName + "-work"
+
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize"})
+	serverInfo := resp.Result.(map[string]interface{})["serverInfo"].(map[string]string)
+	if serverInfo["name"] != "google-calendar-work" {
+		t.Errorf("expected server name google-calendar-work, got %s", serverInfo["name"])
+	}
+}
+
+func TestHandleInitialize_NegotiatesVersion(t *testing.T) {
+	tests := []struct {
+		requested string
+		want      string
+	}{
+		{"2025-06-18", "2025-06-18"},
+		{"2025-03-26", "2025-03-26"},
+		{"2024-11-05", "2024-11-05"},
+		{"2099-01-01", "2025-06-18"},
+		{"", "2025-06-18"},
+		{"latest", "2025-06-18"},
+		// Unknown revisions get the closest older one
+		{"2025-05-01", "2025-03-26"},
+		{"2025-01-15", "2024-11-05"},
+		{"2024-01-01", "2024-11-05"},
+	}
+
+	for _, tt := range tests {
+		s := New(&fakeCalendar{}, &bytes.Buffer{})
+		params, _ := json.Marshal(map[string]string{"protocolVersion": tt.requested})
+		resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: params})
+
+		result := resp.Result.(map[string]interface{})
+		if result["protocolVersion"] != tt.want {
+			t.Errorf("requested %q: expected %q, got %v", tt.requested, tt.want, result["protocolVersion"])
+		}
+		if s.protocolVersion() != tt.want {
+			t.Errorf("requested %q: server kept %q", tt.requested, s.protocolVersion())
+		}
+	}
+}
+
+func TestHandleInitialize_DowngradeAdjustsResults(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	params, _ := json.Marshal(map[string]string{"protocolVersion": "2025-05-01"})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: params})
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolServerVersion, id: float64(2)})
+	if _, ok := resp.Result.(map[string]interface{})["structuredContent"]; ok {
+		t.Error("expected no structuredContent after downgrading to 2025-03-26")
+	}
+}
+
+func TestHandleToolsList_VersionSpecificFields(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	tools := s.handleToolsList(JSONRPCRequest{ID: float64(1)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	if _, ok := tools[0]["annotations"]; ok {
+		t.Error("annotations should not be sent on 2024-11-05")
+	}
+
+	s.setProtocolVersion(protocolVersion20250618)
+	tools = s.handleToolsList(JSONRPCRequest{ID: float64(1)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	annotations, ok := tools[0]["annotations"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected annotations on 2025-06-18")
+	}
+	if annotations["readOnlyHint"] != true {
+		t.Error("expected list_events to be read-only")
+	}
+	if _, ok := tools[0]["outputSchema"]; !ok {
+		t.Error("expected outputSchema for list_events on 2025-06-18")
+	}
+}
+
+func TestCallListEvents_StructuredContent(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Standup"}}}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	if _, ok := resp.Result.(map[string]interface{})["structuredContent"]; ok {
+		t.Error("structuredContent should not be sent on 2024-11-05")
+	}
+
+	s.setProtocolVersion(protocolVersion20250618)
+	resp = s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	structured, ok := resp.Result.(map[string]interface{})["structuredContent"].(eventList)
+	if !ok {
+		t.Fatal("expected structuredContent on 2025-06-18")
+	}
+	if len(structured.Events) != 1 {
+		t.Errorf("expected 1 structured event, got %d", len(structured.Events))
+	}
+}
+
+func TestHandleInitialized(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	req := JSONRPCRequest{JSONRPC: "2.0", Method: "initialized"}
+
+	resp := s.handleRequest(req)
+	if resp != nil {
+		t.Error("expected nil response for initialized notification")
+	}
+}
+
+func TestHandlePing(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "ping"})
+
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected successful ping response, got %+v", resp)
+	}
+	data, _ := json.Marshal(resp)
+	if string(data) != `{"jsonrpc":"2.0","id":1,"result":{}}` {
+		t.Errorf("expected empty result, got %s", data)
+	}
+}
+
+func TestHandleUnknownMethod(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	req := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "unknown/method"}
+
+	resp := s.handleRequest(req)
+	if resp == nil {
+		t.Fatal("expected error response")
+	}
+	if resp.Error == nil {
+		t.Fatal("expected RPC error")
+	}
+	if resp.Error.Code != -32601 {
+		t.Errorf("expected error code -32601, got %d", resp.Error.Code)
+	}
+}
+
+func TestHandleToolsList(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	req := JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/list"}
+
+	resp := s.handleRequest(req)
+	if resp == nil {
+		t.Fatal("expected response")
+	}
+
+	result := resp.Result.(map[string]interface{})
+	tools := result["tools"].([]map[string]interface{})
+
+	expectedTools := []string{"list_events", "list_events_range", "diff_range", "get_event", "search_events", "join_info", "create_event", "quick_add_event", "create_event_on_calendars", "edit_linked_events", "delete_event", "update_event", "analyze_time", "meeting_free_days", "compare_periods", "meeting_history", "hygiene_report", "find_conflicts", "freebusy_query", "recurring_exceptions", "list_recurring_instances", "apply_resolution", "plan_vacation", "timezone_migration", "delegated_actions", "week_stats", "get_server_version", "list_accounts", "auth_status", "reauthenticate"}
+	if len(tools) != len(expectedTools) {
+		t.Fatalf("expected %d tools, got %d", len(expectedTools), len(tools))
+	}
+
+	for i, name := range expectedTools {
+		if tools[i]["name"] != name {
+			t.Errorf("tool %d: expected name %q, got %q", i, name, tools[i]["name"])
+		}
+	}
+}
+
+func TestHandleToolsList_ReadOnlyHidesMutatingTools(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	s.readOnly.Store(true)
+
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/list"})
+	tools := resp.Result.(map[string]interface{})["tools"].([]map[string]interface{})
+
+	expectedTools := []string{"list_events", "list_events_range", "diff_range", "get_event", "search_events", "join_info", "analyze_time", "meeting_free_days", "compare_periods", "meeting_history", "hygiene_report", "find_conflicts", "freebusy_query", "recurring_exceptions", "list_recurring_instances", "delegated_actions", "week_stats", "get_server_version", "list_accounts", "auth_status", "reauthenticate"}
+	if len(tools) != len(expectedTools) {
+		t.Fatalf("expected %d tools, got %d", len(expectedTools), len(tools))
+	}
+	for i, name := range expectedTools {
+		if tools[i]["name"] != name {
+			t.Errorf("tool %d: expected name %q, got %q", i, name, tools[i]["name"])
+		}
+	}
+}
+
+func TestCallMutatingTool_ReadOnly(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	s.readOnly.Store(true)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":      "delete_event",
+		"arguments": map[string]string{"event_id": "evt-1"},
+	})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	if resp.Error == nil {
+		t.Fatal("expected error for mutating tool in read-only mode")
+	}
+	if fake.deletedID != "" {
+		t.Error("event should not be deleted in read-only mode")
+	}
+}
+
+func TestCallTool_RequestInfo(t *testing.T) {
+	fake := &fakeCalendar{created: &calendar.Event{Id: "evt-1", Summary: "Sync"}}
+	s := newTestServer(fake)
+	s.resetSession("abc")
+	s.state = stateReady
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":      toolCreateEvent,
+		"arguments": map[string]string{"summary": "Sync", "date": "2026-03-20", "start_time": "10:00", "end_time": "11:00"},
+		"_meta":     map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(7), Method: "tools/call", Params: params})
+
+	want := gcal.RequestInfo{SessionID: "abc", RequestID: "7", TraceID: "4bf92f3577b34da6a3ce929d0e0e4736"}
+	if fake.createInfo != want {
+		t.Errorf("expected the calendar call to carry %+v, got %+v", want, fake.createInfo)
+	}
+}
+
+func TestSetReadOnly_NotifiesListChanged(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	s.setReadOnly(false)
+	if out.Len() != 0 {
+		t.Fatalf("expected no notification when mode is unchanged, got %q", out.String())
+	}
+
+	s.setReadOnly(true)
+	var notification JSONRPCNotification
+	if err := json.Unmarshal(out.Bytes(), &notification); err != nil {
+		t.Fatalf("expected notification, got %q: %v", out.String(), err)
+	}
+	if notification.Method != "notifications/tools/list_changed" {
+		t.Errorf("unexpected method %q", notification.Method)
+	}
+}
+
+func TestCancelledToolCall_NoResponse(t *testing.T) {
+	fake := &fakeCalendar{started: make(chan struct{})}
+	s := newTestServer(fake)
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "list_events"})
+	done := make(chan *JSONRPCResponse)
+	go func() {
+		done <- s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(7), Method: "tools/call", Params: params})
+	}()
+
+	<-fake.started
+	cancelParams, _ := json.Marshal(map[string]interface{}{"requestId": 7, "reason": "user aborted"})
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/cancelled", Params: cancelParams}); resp != nil {
+		t.Error("expected no response to a notification")
+	}
+
+	if resp := <-done; resp != nil {
+		t.Errorf("expected no response for a cancelled request, got %+v", resp)
+	}
+	if len(s.inFlight) != 0 {
+		t.Errorf("expected in-flight registry to be empty, got %d entries", len(s.inFlight))
+	}
+}
+
+func TestCancelled_UnknownRequestIgnored(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	params, _ := json.Marshal(map[string]interface{}{"requestId": 99})
+	if resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/cancelled", Params: params}); resp != nil {
+		t.Error("expected no response for cancellation of an unknown request")
+	}
+}
+
+func TestToolPrefix(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	s.toolPrefix = "gcal_work"
+
+	tools := s.handleToolsList(JSONRPCRequest{ID: float64(1)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+	if tools[0]["name"] != "gcal_work_list_events" {
+		t.Errorf("expected prefixed tool name, got %v", tools[0]["name"])
+	}
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "gcal_work_list_events", "arguments": map[string]int{"days": 3}})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(2), Method: "tools/call", Params: params})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.lastDays != 3 {
+		t.Errorf("expected prefixed call to reach list_events, got days=%d", fake.lastDays)
+	}
+
+	params, _ = json.Marshal(map[string]interface{}{"name": "list_events"})
+	resp = s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(3), Method: "tools/call", Params: params})
+	if resp.Error == nil {
+		t.Error("expected unprefixed name to be rejected when a prefix is configured")
+	}
+}
+
+func TestCallUnknownTool(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	params, _ := json.Marshal(map[string]interface{}{"name": "nonexistent"})
+	req := JSONRPCRequest{
+		JSONRPC: "2.0",
+		ID:      float64(1),
+		Method:  "tools/call",
+		Params:  params,
+	}
+
+	resp := s.handleRequest(req)
+	if resp.Error == nil {
+		t.Fatal("expected error for unknown tool")
+	}
+	if resp.Error.Code != -32602 {
+		t.Errorf("expected error code -32602, got %d", resp.Error.Code)
+	}
+}
+
+func TestCallListEvents_Default7Days(t *testing.T) {
+	fake := &fakeCalendar{
+		events: []gcal.CalendarEvent{
+			{ID: "1", Summary: "Test Event", Start: "2026-02-20T10:00:00+04:00", End: "2026-02-20T11:00:00+04:00"},
+		},
+	}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1)})
+	if fake.lastDays != 7 {
+		t.Errorf("expected default 7 days, got %d", fake.lastDays)
+	}
+
+	result := resp.Result.(map[string]interface{})
+	content := result["content"].([]map[string]interface{})
+	if len(content) == 0 {
+		t.Fatal("expected content in response")
+	}
+	text := content[0]["text"].(string)
+	if text == "" {
+		t.Error("expected non-empty text")
+	}
+}
+
+func TestCallListEvents_ReportsProgress(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1"}, {ID: "2"}}}
+	out := &bytes.Buffer{}
+	s := newReadyServer(fake, out)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":  "list_events",
+		"_meta": map[string]interface{}{"progressToken": "tok-1"},
+	})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			ProgressToken string `json:"progressToken"`
+			Progress      int    `json:"progress"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &notification); err != nil {
+		t.Fatalf("expected progress notification, got %q: %v", out.String(), err)
+	}
+	if notification.Method != "notifications/progress" {
+		t.Errorf("unexpected method %q", notification.Method)
+	}
+	if notification.Params.ProgressToken != "tok-1" || notification.Params.Progress != 2 {
+		t.Errorf("unexpected progress params: %+v", notification.Params)
+	}
+}
+
+func TestCallListEvents_StreamsEventPages(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1", Summary: "Standup"}, {ID: "2", Summary: "Review"}}}
+	out := &bytes.Buffer{}
+	s := newReadyServer(fake, out)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":  "list_events",
+		"_meta": map[string]interface{}{"progressToken": "tok-1", "eventStreaming": true},
+	})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	var notification struct {
+		Method string `json:"method"`
+		Params struct {
+			Progress int `json:"progress"`
+			Meta     struct {
+				Events []gcal.CalendarEvent `json:"events"`
+			} `json:"_meta"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &notification); err != nil {
+		t.Fatalf("expected progress notification, got %q: %v", out.String(), err)
+	}
+	if notification.Params.Progress != 2 || len(notification.Params.Meta.Events) != 2 || notification.Params.Meta.Events[1].Summary != "Review" {
+		t.Errorf("unexpected page: %+v", notification.Params)
+	}
+	if resp == nil || resp.Error != nil {
+		t.Fatalf("expected the full result after streaming, got %+v", resp)
+	}
+}
+
+func TestCallListEvents_NoProgressWithoutToken(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "1"}}}
+	out := &bytes.Buffer{}
+	s := newReadyServer(fake, out)
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "list_events"})
+	s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	if out.Len() != 0 {
+		t.Errorf("expected no notifications without a progress token, got %q", out.String())
+	}
+}
+
+func TestCallListEvents_CustomDays(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{}}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]int{"days": 14})
+	s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1), args: args})
+
+	if fake.lastDays != 14 {
+		t.Errorf("expected 14 days, got %d", fake.lastDays)
+	}
+}
+
+func TestCallListEvents_InvalidDaysUsesDefault(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{}}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]int{"days": -1})
+	s.callTool(context.Background(), &toolCall{name: toolListEvents, id: float64(1), args: args})
+
+	if fake.lastDays != 7 {
+		t.Errorf("expected default 7 days for negative input, got %d", fake.lastDays)
+	}
+}
+
+func TestCallListEventsRange(t *testing.T) {
+	fake := &fakeCalendar{
+		events: []gcal.CalendarEvent{
+			{ID: "1", Summary: "Range Event", Start: "2026-03-01", End: "2026-03-02"},
+		},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-03-01", "end_date": "2026-03-31"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEventsRange, id: float64(1), args: args})
+
+	if fake.lastStart != "2026-03-01" {
+		t.Errorf("expected start 2026-03-01, got %s", fake.lastStart)
+	}
+	if fake.lastEnd != "2026-03-31" {
+		t.Errorf("expected end 2026-03-31, got %s", fake.lastEnd)
+	}
+	if resp.Error != nil {
+		t.Errorf("unexpected error: %v", resp.Error)
+	}
+}
+
+func TestCallListEventsRange_MissingParams(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-03-01"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolListEventsRange, id: float64(1), args: args})
+
+	if resp.Error == nil {
+		t.Error("expected error for missing end_date")
+	}
+}
+
+func TestCallCreateEvent(t *testing.T) {
+	fake := &fakeCalendar{
+		created: &calendar.Event{Id: "new-id", HtmlLink: "https://calendar.google.com/event/new-id"},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{
+		"summary":    "New Event",
+		"date":       "2026-03-15",
+		"start_time": "10:00",
+		"end_time":   "11:00",
+	})
+	resp := s.callTool(context.Background(), &toolCall{name: toolCreateEvent, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	result := resp.Result.(map[string]interface{})
+	content := result["content"].([]map[string]interface{})
+	text := content[0]["text"].(string)
+	if text == "" {
+		t.Error("expected non-empty response text")
+	}
+}
+
+func TestCallCreateEvent_IncludesDuration(t *testing.T) {
+	fake := &fakeCalendar{
+		created: &calendar.Event{
+			Id:    "new-id",
+			Start: &calendar.EventDateTime{DateTime: "2026-03-15T10:00:00Z"},
+			End:   &calendar.EventDateTime{DateTime: "2026-03-15T11:30:00Z"},
+		},
+	}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{
+		"summary":    "New Event",
+		"date":       "2026-03-15",
+		"start_time": "10:00",
+		"end_time":   "11:30",
+	})
+	resp := s.callTool(context.Background(), &toolCall{name: toolCreateEvent, id: float64(1), args: args})
+
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !contains(text, "Duration: 1h30m") {
+		t.Errorf("expected duration in response, got %q", text)
+	}
+}
+
+func TestCallCreateEvent_MissingRequired(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{"summary": "No times"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolCreateEvent, id: float64(1), args: args})
+
+	if resp.Error == nil {
+		t.Error("expected error for missing required fields")
+	}
+}
+
+func TestCallDeleteEvent(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"event_id": "evt-del"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.deletedID != "evt-del" {
+		t.Errorf("expected deleted ID evt-del, got %s", fake.deletedID)
+	}
+}
+
+func TestCallDeleteEvent_MissingEventID(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{})
+	resp := s.callTool(context.Background(), &toolCall{name: toolDeleteEvent, id: float64(1), args: args})
+
+	if resp.Error == nil {
+		t.Error("expected error for missing event_id")
+	}
+}
+
+func TestCallUpdateEvent(t *testing.T) {
+	fake := &fakeCalendar{
+		updated: &calendar.Event{Id: "evt-1", Summary: "Updated", HtmlLink: "https://calendar.google.com/event/evt-1"},
+	}
+	s := newTestServer(fake)
+
+	summary := "Updated"
+	args, _ := json.Marshal(map[string]interface{}{"event_id": "evt-1", "summary": summary})
+	resp := s.callTool(context.Background(), &toolCall{name: toolUpdateEvent, id: float64(1), args: args})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+}
+
+func TestCallDeprecatedAlias(t *testing.T) {
+	fake := &fakeCalendar{
+		updated: &calendar.Event{Id: "evt-1", Summary: "Updated"},
+	}
+	s := newTestServer(fake)
+
+	params, _ := json.Marshal(map[string]interface{}{
+		"name":      "edit_event",
+		"arguments": map[string]string{"event_id": "evt-1", "summary": "Updated"},
+	})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+	if len(content) != 2 {
+		t.Fatalf("expected result plus deprecation note, got %v", content)
+	}
+	if !contains(content[1]["text"].(string), "deprecated") || !contains(content[1]["text"].(string), "update_event") {
+		t.Errorf("unexpected deprecation note %q", content[1]["text"])
+	}
+}
+
+func TestToolAliasesNotListed(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	tools := s.handleToolsList(JSONRPCRequest{ID: float64(1)}).Result.(map[string]interface{})["tools"].([]map[string]interface{})
+
+	for _, tool := range tools {
+		if _, ok := toolAliases[tool["name"].(string)]; ok {
+			t.Errorf("alias %v should not be listed", tool["name"])
+		}
+	}
+}
+
+func TestCallUpdateEvent_MissingEventID(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{"summary": "No ID"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolUpdateEvent, id: float64(1), args: args})
+
+	if resp.Error == nil {
+		t.Error("expected error for missing event_id")
+	}
+}
+
+func TestCallUpdateEvent_ApplyTo(t *testing.T) {
+	fake := &fakeCalendar{updated: &calendar.Event{Id: "standup_20260323T090000Z", Summary: "Standup"}}
+	s := newTestServer(fake)
+
+	resp := callWithArgs(s, toolUpdateEvent, `{"event_id":"standup","start_time":"09:30","apply_to":"this_and_following","occurrence_date":"23.03.2026"}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if want := (gcal.SeriesScope{ApplyTo: gcal.ApplyToThisAndFollowing, Occurrence: "2026-03-23"}); fake.lastUpdate.Scope != want {
+		t.Errorf("expected scope %+v, got %+v", want, fake.lastUpdate.Scope)
+	}
+
+	for _, args := range []string{
+		`{"event_id":"standup","summary":"x","apply_to":"future"}`,
+		`{"event_id":"standup","summary":"x","occurrence_date":"2026-03-23"}`,
+	} {
+		if resp := callWithArgs(s, toolUpdateEvent, args); resp.Error == nil {
+			t.Errorf("%s: expected an invalid argument error", args)
+		}
+	}
+}
+
+func TestCallDeleteEvent_ApplyTo(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+
+	if resp := callWithArgs(s, toolDeleteEvent, `{"event_id":"standup_20260323T090000Z","apply_to":"all"}`); resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	if fake.deletedID != "standup_20260323T090000Z" || fake.deleteScope.ApplyTo != gcal.ApplyToAll {
+		t.Errorf("unexpected deletion of %s with %+v", fake.deletedID, fake.deleteScope)
+	}
+
+	// Without apply_to the event is deleted as before
+	fake.deleteScope = gcal.SeriesScope{}
+	callWithArgs(s, toolDeleteEvent, `{"event_id":"evt-1"}`)
+	if fake.deletedID != "evt-1" || fake.deleteScope != (gcal.SeriesScope{}) {
+		t.Errorf("unexpected deletion of %s with %+v", fake.deletedID, fake.deleteScope)
+	}
+}
+
+func TestCallServerVersion(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	params, _ := json.Marshal(map[string]interface{}{"name": "get_server_version"})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !contains(text, version) || !contains(text, "commit") {
+		t.Errorf("expected version and commit in %q", text)
+	}
+}
+
+func TestSuccessResponse(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.successResponse(float64(1), "hello")
+
+	result := resp.Result.(map[string]interface{})
+	content := result["content"].([]map[string]interface{})
+	if content[0]["type"] != "text" {
+		t.Errorf("expected type text, got %s", content[0]["type"])
+	}
+	if content[0]["text"] != "hello" {
+		t.Errorf("expected text hello, got %s", content[0]["text"])
+	}
+}
+
+func TestSendResponse_MarshalFailure(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	s.sendResponse(&JSONRPCResponse{JSONRPC: "2.0", ID: float64(3), Result: math.Inf(1)})
+
+	var resp JSONRPCResponse
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", out.String(), err)
+	}
+	if resp.Error == nil || resp.Error.Code != -32603 {
+		t.Errorf("expected internal error, got %+v", resp)
+	}
+	if resp.ID != float64(3) {
+		t.Errorf("expected ID 3, got %v", resp.ID)
+	}
+}
+
+func TestSendResponse_ConcurrentWritesDoNotInterleave(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := New(&fakeCalendar{}, out)
+
+	const writers = 50
+	payload := strings.Repeat("x", 64*1024)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				s.sendResponse(s.successResponse(float64(i), payload))
+			} else {
+				s.sendNotification("notifications/message", map[string]string{"data": payload})
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != writers {
+		t.Fatalf("expected %d lines, got %d", writers, len(lines))
+	}
+	for i, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("line %d is not valid JSON (interleaved write?)", i)
+		}
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	resp := s.errorResponse(float64(1), context.DeadlineExceeded)
+
+	result := resp.Result.(map[string]interface{})
+	isError := result["isError"].(bool)
+	if !isError {
+		t.Error("expected isError to be true")
+	}
+}
+
+func TestFormatEvents_Empty(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	text := s.formatEvents(nil)
+	if text != "No events found." {
+		t.Errorf("expected 'No events found.', got %q", text)
+	}
+}
+
+func TestFormatEvents_Multiple(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	events := []gcal.CalendarEvent{
+		{ID: "1", Summary: "First", Start: "2026-02-20T10:00:00+04:00", End: "2026-02-20T11:00:00+04:00"},
+		{ID: "2", Summary: "Second", Start: "2026-02-21T14:00:00+04:00", End: "2026-02-21T15:00:00+04:00"},
+	}
+	text := s.formatEvents(events)
+
+	if text == "No events found." {
+		t.Error("should not return empty message for non-empty list")
+	}
+	// Check that both events are present
+	for _, e := range events {
+		if !contains(text, e.Summary) {
+			t.Errorf("expected text to contain %q", e.Summary)
+		}
+		if !contains(text, e.ID) {
+			t.Errorf("expected text to contain ID %q", e.ID)
+		}
+	}
+}
+
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && searchString(s, substr)
+}
+
+func searchString(s, substr string) bool {
+	for i := 0; i <= len(s)-len(substr); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCallGetEvent_ICS(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	args, _ := json.Marshal(map[string]string{"event_id": "evt-1", "format": "ics"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolGetEvent, id: float64(1), args: args})
+
+	content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})
+	if len(content) != 2 {
+		t.Fatalf("expected text and embedded resource, got %d blocks", len(content))
+	}
+	if !contains(content[0]["text"].(string), "Event evt-1") {
+		t.Errorf("expected event details, got %q", content[0]["text"])
+	}
+	if content[1]["type"] != "resource" {
+		t.Fatalf("expected embedded resource, got %v", content[1]["type"])
+	}
+	resource := content[1]["resource"].(map[string]string)
+	if resource["mimeType"] != "text/calendar" || resource["uri"] != "calendar://test@example.com/events/evt-1" {
+		t.Errorf("unexpected resource %v", resource)
+	}
+	if !contains(resource["text"], "BEGIN:VEVENT") {
+		t.Errorf("expected a VEVENT, got %q", resource["text"])
+	}
+}
+
+func TestCallGetEvent_Validation(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+
+	for _, args := range []string{`{}`, `{"event_id":"evt-1","format":"pdf"}`} {
+		resp := s.callTool(context.Background(), &toolCall{name: toolGetEvent, id: float64(1), args: json.RawMessage(args)})
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected invalid params error, got %+v", args, resp.Error)
+		}
+	}
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolGetEvent, id: float64(1), args: json.RawMessage(`{"event_id":"evt-1"}`)})
+	if content := resp.Result.(map[string]interface{})["content"].([]map[string]interface{}); len(content) != 1 {
+		t.Errorf("text format should not embed a resource, got %d blocks", len(content))
+	}
+}
+
+func TestCallSearchEvents(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "evt-1", Summary: "Dentist", Start: "2026-03-16T10:00:00Z", End: "2026-03-16T11:00:00Z"},
+		{ID: "evt-2", Summary: "Planning", Start: "2026-03-17T10:00:00Z", End: "2026-03-17T11:00:00Z"},
+	}}
+	s := newTestServer(fake)
+
+	resp := callWithArgs(s, toolSearchEvents, `{"query":" dentist ","start_date":"16.03.2026"}`)
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Dentist") || strings.Contains(text, "Planning") {
+		t.Errorf("expected only the matching event:\n%s", text)
+	}
+	if fake.lastQuery != "dentist" || fake.lastStart != "2026-03-16" || fake.lastEnd != "" {
+		t.Errorf("unexpected search %q from %q to %q", fake.lastQuery, fake.lastStart, fake.lastEnd)
+	}
+	// Results can be referred to like a listing
+	if id, err := s.eventIDArg(context.Background(), "", "#1"); err != nil || id != "evt-1" {
+		t.Errorf("expected #1 to be the found event, got %q, %v", id, err)
+	}
+
+	for _, args := range []string{`{}`, `{"query":"x","start_date":"2026-03-20","end_date":"2026-03-16"}`, `{"query":"x","end_date":"soon"}`} {
+		if resp := callWithArgs(s, toolSearchEvents, args); resp.Error == nil {
+			t.Errorf("%s: expected invalid arguments to be rejected", args)
+		}
+	}
+}
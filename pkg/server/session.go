@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// experimentalSessionConfig is the capability announcing that clients can
+// configure their own session in the _meta of initialize
+const experimentalSessionConfig = "sessionConfig"
+
+// sessionConfig is what a client may choose for its own session: the
+// calendar events are created on by default, the timezone, read-only mode
+// and the verbosity of tool output. Empty fields keep the server's configuration.
+type sessionConfig struct {
+	CalendarID string `json:"calendarId"`
+	Timezone   string `json:"timezone"`
+	// ReadOnly can only make a session stricter: it has no effect when the
+	// server itself is read-only
+	ReadOnly bool `json:"readOnly"`
+	// Verbosity is terse, normal or verbose
+	Verbosity string `json:"verbosity"`
+}
+
+// sessionSettings is what a session config may replace. It is never
+// changed once stored: a new session config stores new settings.
+type sessionSettings struct {
+	calendar gcal.Service
+	// location is the calendar timezone, used to split analytics into days
+	location *time.Location
+	// verbosity is how much tool output says by default: verbosityTerse,
+	// verbosityNormal or verbosityVerbose
+	verbosity string
+}
+
+// settings returns the calendar, timezone and verbosity of the current
+// session. Under the SSE transport a new connection resets the session
+// while polls and requests of the previous one may still read them.
+func (s *Server) settings() *sessionSettings {
+	return s.current.Load()
+}
+
+// configure changes the server's own settings. It is only called while
+// configuring the server, before configured takes them.
+func (s *Server) configure(change func(*sessionSettings)) {
+	settings := *s.settings()
+	change(&settings)
+	s.current.Store(&settings)
+}
+
+// configured returns the server's own configuration. Background work uses
+// it rather than the settings a session config replaces.
+func (s *Server) configured() *sessionSettings {
+	s.defaultsOnce.Do(func() {
+		s.defaults = s.settings()
+	})
+	return s.defaults
+}
+
+// applySessionConfig switches the session to cfg. It is called during
+// initialize, before any request that could use the calendar.
+func (s *Server) applySessionConfig(cfg sessionConfig) error {
+	base := s.configured()
+
+	location := base.location
+	if cfg.Timezone != "" {
+		loc, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return fmt.Errorf("unknown timezone %q", cfg.Timezone)
+		}
+		location = loc
+	}
+	verbosity := base.verbosity
+	if cfg.Verbosity != "" {
+		v, err := parseVerbosity(cfg.Verbosity)
+		if err != nil {
+			return err
+		}
+		verbosity = v
+	}
+	if cfg.CalendarID != "" && cfg.CalendarID != base.calendar.CalendarID() && !s.knownCalendar(cfg.CalendarID) {
+		return fmt.Errorf("calendar %s is not in the calendar list", cfg.CalendarID)
+	}
+
+	s.current.Store(&sessionSettings{
+		calendar:  base.calendar.WithDefaults(cfg.CalendarID, cfg.Timezone),
+		location:  location,
+		verbosity: verbosity,
+	})
+	s.sessionReadOnly.Store(cfg.ReadOnly)
+	return nil
+}
+
+// restoreDefaults undoes applySessionConfig
+func (s *Server) restoreDefaults() {
+	s.current.Store(s.configured())
+	s.sessionReadOnly.Store(false)
+}
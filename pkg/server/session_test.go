@@ -0,0 +1,100 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func initializeWith(s *Server, config string) *JSONRPCResponse {
+	params := json.RawMessage(`{"protocolVersion":"2025-06-18","_meta":{"sessionConfig":` + config + `}}`)
+	return s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "initialize", Params: params})
+}
+
+func TestSessionConfig(t *testing.T) {
+	fake := &fakeCalendar{extraCalendars: map[string][]gcal.CalendarEvent{"team@example.com": nil}}
+	s := New(fake, &bytes.Buffer{})
+
+	resp := initializeWith(s, `{"calendarId":"team@example.com","timezone":"Asia/Tokyo","readOnly":true}`)
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	if s.settings().calendar.CalendarID() != "team@example.com" || s.settings().location.String() != "Asia/Tokyo" || !s.isReadOnly() {
+		t.Errorf("session config not applied: %s %s %t", s.settings().calendar.CalendarID(), s.settings().location, s.isReadOnly())
+	}
+	result := resp.Result.(map[string]interface{})
+	if !strings.Contains(result["instructions"].(string), "calendar team@example.com") {
+		t.Errorf("expected the instructions to name the session calendar:\n%s", result["instructions"])
+	}
+	experimental := result["capabilities"].(map[string]interface{})["experimental"].(map[string]interface{})
+	if _, ok := experimental[experimentalSessionConfig]; !ok {
+		t.Errorf("expected the %s capability, got %v", experimentalSessionConfig, experimental)
+	}
+
+	// A new session on the same server starts from the server's configuration
+	s.resetSession("second")
+	if s.settings().calendar.CalendarID() != "test@example.com" || s.settings().location.String() != "UTC" || s.isReadOnly() {
+		t.Errorf("defaults not restored: %s %s %t", s.settings().calendar.CalendarID(), s.settings().location, s.isReadOnly())
+	}
+}
+
+func TestSessionConfig_Invalid(t *testing.T) {
+	for _, config := range []string{`{"calendarId":"stranger@example.com"}`, `{"timezone":"Mars/Olympus"}`, `"yes"`} {
+		s := New(&fakeCalendar{}, &bytes.Buffer{})
+		resp := initializeWith(s, config)
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%s: expected invalid params, got %+v", config, resp)
+		}
+		if s.settings().calendar.CalendarID() != "test@example.com" {
+			t.Errorf("%s: the calendar must not change, got %s", config, s.settings().calendar.CalendarID())
+		}
+	}
+}
+
+func TestSessionConfig_ReadOnlyIsPerSession(t *testing.T) {
+	base := New(&fakeCalendar{}, &bytes.Buffer{})
+	strict, open := base.newSession(&bytes.Buffer{}), base.newSession(&bytes.Buffer{})
+
+	initializeWith(strict, `{"readOnly":true}`)
+	initializeWith(open, `{}`)
+	if !strict.isReadOnly() || open.isReadOnly() {
+		t.Errorf("expected only the strict session to be read-only: %t %t", strict.isReadOnly(), open.isReadOnly())
+	}
+
+	// The server's own read-only mode still applies to every session, and a
+	// session can't opt out of it
+	base.setReadOnly(true)
+	if !open.isReadOnly() {
+		t.Error("expected server read-only mode to apply to every session")
+	}
+	base.setReadOnly(false)
+	if !strict.isReadOnly() {
+		t.Error("expected the strict session to stay read-only")
+	}
+}
+
+func TestSessionConfig_BackgroundWorkUsesServerConfig(t *testing.T) {
+	fake := &fakeCalendar{extraCalendars: map[string][]gcal.CalendarEvent{"team@example.com": nil}}
+	s := New(fake, &bytes.Buffer{})
+	s.configured()
+
+	// Background checks run alongside initialize; go test -race catches
+	// them reading what the session config writes
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.checkCalendars(context.Background())
+	}()
+	if resp := initializeWith(s, `{"calendarId":"team@example.com","timezone":"Asia/Tokyo"}`); resp.Error != nil {
+		t.Fatalf("unexpected error: %+v", resp.Error)
+	}
+	<-done
+
+	if base := s.configured(); base.calendar.CalendarID() != "test@example.com" || base.location.String() != "UTC" {
+		t.Errorf("expected background work to keep the server's calendar and timezone, got %s %s", base.calendar.CalendarID(), base.location)
+	}
+}
@@ -0,0 +1,47 @@
+//go:build !windows
+
+package server
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// WatchSignals toggles read-only mode on SIGUSR1 and reloads the
+// credentials file on SIGHUP
+func (s *Server) WatchSignals() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1, syscall.SIGHUP)
+
+	go func() {
+		for sig := range ch {
+			if sig == syscall.SIGHUP {
+				s.reloadCredentials()
+				continue
+			}
+			s.setReadOnly(!s.isReadOnly())
+		}
+	}()
+}
+
+// reloadCredentials has the calendar read its credentials file again, e.g.
+// right after a key was rotated rather than when the change is noticed
+func (s *Server) reloadCredentials() error {
+	reloader, ok := s.settings().calendar.(gcal.CredentialsReloader)
+	if !ok {
+		err := errors.New("this calendar backend can't reload its credentials")
+		log.Printf("Ignoring SIGHUP: %v", err)
+		return err
+	}
+	if err := reloader.ReloadCredentials(); err != nil {
+		log.Printf("Failed to reload the credentials, keeping the previous ones: %v", err)
+		return err
+	}
+	log.Printf("Reloaded the credentials")
+	return nil
+}
@@ -0,0 +1,6 @@
+//go:build windows
+
+package server
+
+// WatchSignals is a no-op on Windows, which has no SIGUSR1 or SIGHUP
+func (s *Server) WatchSignals() {}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// sseTransport serves the HTTP+SSE transport of protocol revision
+// 2024-11-05: the client opens an event stream with GET /sse, which
+// announces the endpoint it then POSTs messages to. Replies and
+// notifications travel on the stream. The server holds the state of a
+// single session, so one client is served at a time.
+type sseTransport struct {
+	server *Server
+
+	mu sync.Mutex
+	// sessionID identifies the open stream; empty when no client is
+	// connected
+	sessionID string
+}
+
+// SSEHandler returns the HTTP handler of the HTTP+SSE transport, along with
+// the stats endpoints
+func (s *Server) SSEHandler() http.Handler {
+	if s.auth == nil {
+		log.Printf("Warning: the HTTP transport accepts requests without authentication; set CALENDAR_AUTH_TOKEN or CALENDAR_OIDC_ISSUER when it is reachable by others")
+	}
+	t := &sseTransport{server: s}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", t.handleStream)
+	mux.HandleFunc("POST /messages", t.handlePost)
+	mux.HandleFunc("GET /stats", s.handleStats)
+	mux.HandleFunc("GET /metrics", s.handleStats)
+	return s.auth.wrap(mux)
+}
+
+func (t *sseTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	t.mu.Lock()
+	if t.sessionID != "" {
+		t.mu.Unlock()
+		http.Error(w, "another client is connected", http.StatusConflict)
+		return
+	}
+	id := rand.Text()
+	t.sessionID = id
+	t.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: endpoint\ndata: /messages?sessionId=%s\n\n", id)
+	flusher.Flush()
+
+	s := t.server
+	s.resetSession(id)
+	s.setOutput(&sseWriter{w: w, flusher: flusher})
+	log.Printf("SSE client connected (session %s)", id)
+
+	<-r.Context().Done()
+
+	// Nobody is left to read the replies of requests still running
+	s.setOutput(io.Discard)
+	s.cancelInFlight("client disconnected")
+	s.wg.Wait()
+	log.Printf("SSE client disconnected (session %s)", id)
+
+	t.mu.Lock()
+	t.sessionID = ""
+	t.mu.Unlock()
+}
+
+func (t *sseTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	active := t.sessionID != "" && r.URL.Query().Get("sessionId") == t.sessionID
+	t.mu.Unlock()
+	if !active {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, fmt.Sprintf("message exceeds %d bytes", maxMessageSize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		http.Error(w, "empty message", http.StatusBadRequest)
+		return
+	}
+
+	// The reply goes out on the event stream
+	w.WriteHeader(http.StatusAccepted)
+	t.server.handleMessage(body)
+}
+
+// sseWriter sends each message written by the server as an SSE message
+// event
+type sseWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (sw *sseWriter) Write(p []byte) (int, error) {
+	if _, err := fmt.Fprintf(sw.w, "event: message\ndata: %s\n\n", bytes.TrimRight(p, "\n")); err != nil {
+		return 0, err
+	}
+	sw.flusher.Flush()
+	return len(p), nil
+}
+
+// setOutput switches where replies and notifications are written
+func (s *Server) setOutput(w io.Writer) {
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	s.out = w
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// readEvent reads the next server-sent event from a stream
+func readEvent(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event != "":
+			return event, data
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestSSETransport(t *testing.T) {
+	s := New(&fakeCalendar{}, &strings.Builder{})
+	srv := httptest.NewServer(s.SSEHandler())
+	defer srv.Close()
+
+	stream, err := http.Get(srv.URL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	if ct := stream.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	events := bufio.NewReader(stream.Body)
+
+	event, endpoint := readEvent(t, events)
+	if event != "endpoint" || !strings.HasPrefix(endpoint, "/messages?sessionId=") {
+		t.Fatalf("expected the endpoint event, got %s: %s", event, endpoint)
+	}
+
+	// Only one client at a time
+	second, err := http.Get(srv.URL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.Body.Close()
+	if second.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 for a second stream, got %d", second.StatusCode)
+	}
+
+	post := func(path, body string) int {
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post("/messages?sessionId=wrong", `{"jsonrpc":"2.0","id":1,"method":"ping"}`); code != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown session, got %d", code)
+	}
+
+	if code := post(endpoint, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`); code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", code)
+	}
+	event, data := readEvent(t, events)
+	if event != "message" || !strings.Contains(data, `"protocolVersion":"2024-11-05"`) {
+		t.Errorf("expected the initialize result on the stream, got %s: %s", event, data)
+	}
+
+	post(endpoint, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	post(endpoint, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	if _, data := readEvent(t, events); !strings.Contains(data, `"id":2`) || !strings.Contains(data, toolListEvents) {
+		t.Errorf("expected the tool list on the stream, got %s", data)
+	}
+}
+
+func TestSSETransport_Reconnect(t *testing.T) {
+	s := New(&fakeCalendar{}, &strings.Builder{})
+	srv := httptest.NewServer(s.SSEHandler())
+	defer srv.Close()
+
+	first, err := http.Get(srv.URL + "/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	readEvent(t, bufio.NewReader(first.Body))
+	first.Body.Close()
+
+	// The old stream is released once the server notices the disconnect
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := http.Get(srv.URL + "/sse")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode == http.StatusOK {
+			resp.Body.Close()
+			break
+		}
+		resp.Body.Close()
+		if time.Now().After(deadline) {
+			t.Fatalf("expected a new client to connect, got %d", resp.StatusCode)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSSETransport_ReconnectWhilePolling(t *testing.T) {
+	s := New(slowCalendar{&fakeCalendar{}}, &strings.Builder{})
+	s.pollInterval = time.Millisecond
+	srv := httptest.NewServer(s.SSEHandler())
+	defer srv.Close()
+
+	// connect opens a stream once the previous one is released, starts a
+	// session with a session config of its own and subscribes to the
+	// calendar, whose slow read keeps a poll going across the reconnect
+	subscribe := `{"jsonrpc":"2.0","id":2,"method":"resources/subscribe","params":{"uri":"` + calendarResourceURI("test@example.com") + `"}}`
+	connect := func(timezone string) *http.Response {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			stream, err := http.Get(srv.URL + "/sse")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stream.StatusCode == http.StatusOK {
+				_, endpoint := readEvent(t, bufio.NewReader(stream.Body))
+				initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","_meta":{"sessionConfig":{"timezone":"` + timezone + `","verbosity":"terse"}}}}`
+				for _, body := range []string{initialize, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, subscribe} {
+					resp, err := http.Post(srv.URL+endpoint, "application/json", strings.NewReader(body))
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp.Body.Close()
+				}
+				return stream
+			}
+			stream.Body.Close()
+			if time.Now().After(deadline) {
+				t.Fatalf("expected a new client to connect, got %d", stream.StatusCode)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	stream := connect("Asia/Tokyo")
+	// The subscription poll keeps reading the session's calendar and
+	// timezone while every new connection replaces them; go test -race
+	// catches unguarded access
+	for _, timezone := range []string{"Europe/Berlin", "America/New_York", "Asia/Tokyo", "UTC"} {
+		stream.Body.Close()
+		stream = connect(timezone)
+	}
+	stream.Body.Close()
+
+	if loc := s.settings().location.String(); loc != "UTC" {
+		t.Errorf("expected the last session's timezone, got %s", loc)
+	}
+}
+
+// slowCalendar takes a while to list a calendar, which it finds empty, in
+// session views too
+type slowCalendar struct {
+	gcal.Service
+}
+
+func (c slowCalendar) ListCalendarEvents(context.Context, string, string, string) ([]gcal.CalendarEvent, error) {
+	time.Sleep(5 * time.Millisecond)
+	return nil, nil
+}
+
+func (c slowCalendar) WithDefaults(calendarID, timezone string) gcal.Service {
+	return slowCalendar{c.Service.WithDefaults(calendarID, timezone)}
+}
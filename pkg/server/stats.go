@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	statsFormatJSON       = "json"
+	statsFormatPrometheus = "prometheus"
+
+	// metricPrefix namespaces the exported Prometheus metrics
+	metricPrefix = "gcal_"
+)
+
+// dayLoad is the meeting load of one working day
+type dayLoad struct {
+	Date         string  `json:"date"`
+	Weekday      string  `json:"weekday"`
+	Meetings     int     `json:"meetings"`
+	MeetingHours float64 `json:"meetingHours"`
+	FreeHours    float64 `json:"freeHours"`
+}
+
+// weekStats summarizes the coming week for dashboards. Hours count working
+// hours only; BusiestDay is empty when the week has no meetings.
+type weekStats struct {
+	StartDate       string    `json:"startDate"`
+	EndDate         string    `json:"endDate"`
+	Days            []dayLoad `json:"days"`
+	Meetings        int       `json:"meetings"`
+	MeetingHours    float64   `json:"meetingHours"`
+	FreeHours       float64   `json:"freeHours"`
+	BusiestDay      string    `json:"busiestDay,omitempty"`
+	BusiestDayHours float64   `json:"busiestDayHours"`
+	GeneratedAt     string    `json:"generatedAt"`
+}
+
+func buildWeekStats(a timeAnalysis, now time.Time) weekStats {
+	stats := weekStats{
+		StartDate:    a.StartDate,
+		EndDate:      a.EndDate,
+		Days:         []dayLoad{},
+		Meetings:     a.Meetings,
+		MeetingHours: roundHours(float64(a.BusyMinutes) / 60),
+		FreeHours:    roundHours(float64(a.FreeMinutes) / 60),
+		GeneratedAt:  now.Format(time.RFC3339),
+	}
+	busiest := 0
+	for _, d := range a.Days {
+		day, _ := time.Parse("2006-01-02", d.Date)
+		free := 0
+		for _, f := range d.FreeBlocks {
+			free += f.Minutes
+		}
+		stats.Days = append(stats.Days, dayLoad{
+			Date:         d.Date,
+			Weekday:      day.Format("Monday"),
+			Meetings:     d.Meetings,
+			MeetingHours: roundHours(float64(d.BusyMinutes) / 60),
+			FreeHours:    roundHours(float64(free) / 60),
+		})
+		if d.BusyMinutes > busiest {
+			busiest = d.BusyMinutes
+			stats.BusiestDay = d.Date
+			stats.BusiestDayHours = roundHours(float64(d.BusyMinutes) / 60)
+		}
+	}
+	return stats
+}
+
+// weekStats analyzes the seven days starting today
+func (s *Server) weekStats(ctx context.Context) (weekStats, error) {
+	now := time.Now().In(s.settings().location)
+	first := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, s.settings().location)
+	end := first.AddDate(0, 0, defaultAnalysisDays-1)
+	events, err := s.settings().calendar.ListEventsRange(ctx, first.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return weekStats{}, err
+	}
+	return buildWeekStats(analyzeTime(events, first, defaultAnalysisDays, s.workHours), now), nil
+}
+
+// formatPrometheus renders the stats in the Prometheus text exposition
+// format
+func formatPrometheus(w weekStats) string {
+	var b strings.Builder
+	gauge := func(name, help string) {
+		fmt.Fprintf(&b, "# HELP %s%s %s\n# TYPE %s%s gauge\n", metricPrefix, name, help, metricPrefix, name)
+	}
+
+	gauge("day_meeting_hours", "Meeting hours within working hours, per day of the coming week")
+	for _, d := range w.Days {
+		fmt.Fprintf(&b, "%sday_meeting_hours{date=%q,weekday=%q} %s\n", metricPrefix, d.Date, d.Weekday, formatHours(d.MeetingHours))
+	}
+	gauge("day_free_hours", "Free working hours, per day of the coming week")
+	for _, d := range w.Days {
+		fmt.Fprintf(&b, "%sday_free_hours{date=%q,weekday=%q} %s\n", metricPrefix, d.Date, d.Weekday, formatHours(d.FreeHours))
+	}
+	gauge("day_meetings", "Meetings per day of the coming week")
+	for _, d := range w.Days {
+		fmt.Fprintf(&b, "%sday_meetings{date=%q,weekday=%q} %d\n", metricPrefix, d.Date, d.Weekday, d.Meetings)
+	}
+
+	gauge("week_meeting_hours", "Meeting hours within working hours in the coming week")
+	fmt.Fprintf(&b, "%sweek_meeting_hours %s\n", metricPrefix, formatHours(w.MeetingHours))
+	gauge("week_free_hours", "Free working hours in the coming week")
+	fmt.Fprintf(&b, "%sweek_free_hours %s\n", metricPrefix, formatHours(w.FreeHours))
+	gauge("week_meetings", "Meetings in the coming week")
+	fmt.Fprintf(&b, "%sweek_meetings %d\n", metricPrefix, w.Meetings)
+	if w.BusiestDay != "" {
+		gauge("busiest_day_hours", "Meeting hours of the busiest day of the coming week")
+		fmt.Fprintf(&b, "%sbusiest_day_hours{date=%q} %s\n", metricPrefix, w.BusiestDay, formatHours(w.BusiestDayHours))
+	}
+	return b.String()
+}
+
+// weekStatsInput is the arguments of week_stats
+type weekStatsInput struct {
+	// Output format of the text content (default: json)
+	Format string `json:"format" jsonschema:"enum=statsFormatJSON|statsFormatPrometheus,default=statsFormatJSON"`
+}
+
+func (s *Server) callWeekStats(ctx context.Context, input weekStatsInput) (weekStatsOutput, error) {
+	switch input.Format {
+	case "", statsFormatJSON, statsFormatPrometheus:
+	default:
+		return weekStatsOutput{}, badArgumentf("format must be json or prometheus")
+	}
+
+	stats, err := s.weekStats(ctx)
+	if err != nil {
+		return weekStatsOutput{}, err
+	}
+	return weekStatsOutput{weekStats: stats, format: input.Format}, nil
+}
+
+// weekStatsOutput is the output of week_stats: the stats as structured
+// content and, in the requested format, as text
+type weekStatsOutput struct {
+	weekStats
+	format string
+}
+
+func (w weekStatsOutput) toolText(*Server) string {
+	if w.format == statsFormatPrometheus {
+		return formatPrometheus(w.weekStats)
+	}
+	data, _ := json.MarshalIndent(w.weekStats, "", "  ")
+	return string(data)
+}
+
+// handleStats serves the stats over HTTP for dashboards that poll them:
+// JSON on /stats, the Prometheus text format on /metrics
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.weekStats(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	if r.URL.Path == "/metrics" {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, formatPrometheus(stats))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestBuildWeekStats(t *testing.T) {
+	events := []gcal.CalendarEvent{
+		{ID: "1", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T10:30:00Z"},
+		{ID: "2", Start: "2026-03-17T09:00:00Z", End: "2026-03-17T12:00:00Z"},
+		{ID: "3", Start: "2026-03-17T14:00:00Z", End: "2026-03-17T15:00:00Z"},
+	}
+	first := time.Date(2026, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	stats := buildWeekStats(analyzeTime(events, first, 7, defaultWorkHours), first)
+
+	// The weekend is outside working hours
+	if len(stats.Days) != 5 || stats.StartDate != "2026-03-16" || stats.EndDate != "2026-03-22" {
+		t.Fatalf("unexpected range: %+v", stats)
+	}
+	if d := stats.Days[1]; d.Weekday != "Tuesday" || d.Meetings != 2 || d.MeetingHours != 4 || d.FreeHours != 4 {
+		t.Errorf("unexpected Tuesday: %+v", d)
+	}
+	if stats.Meetings != 3 || stats.MeetingHours != 5.5 || stats.FreeHours != 34.5 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.BusiestDay != "2026-03-17" || stats.BusiestDayHours != 4 {
+		t.Errorf("expected Tuesday to be the busiest day, got %s (%v)", stats.BusiestDay, stats.BusiestDayHours)
+	}
+
+	empty := buildWeekStats(analyzeTime(nil, first, 7, defaultWorkHours), first)
+	if empty.BusiestDay != "" {
+		t.Errorf("expected no busiest day without meetings, got %s", empty.BusiestDay)
+	}
+}
+
+func TestFormatPrometheus(t *testing.T) {
+	stats := weekStats{
+		Days:            []dayLoad{{Date: "2026-03-16", Weekday: "Monday", Meetings: 1, MeetingHours: 1.5, FreeHours: 6.5}},
+		Meetings:        1,
+		MeetingHours:    1.5,
+		FreeHours:       6.5,
+		BusiestDay:      "2026-03-16",
+		BusiestDayHours: 1.5,
+	}
+
+	text := formatPrometheus(stats)
+
+	for _, want := range []string{
+		"# TYPE gcal_day_meeting_hours gauge\n",
+		`gcal_day_meeting_hours{date="2026-03-16",weekday="Monday"} 1.5` + "\n",
+		`gcal_day_free_hours{date="2026-03-16",weekday="Monday"} 6.5` + "\n",
+		"gcal_week_meetings 1\n",
+		`gcal_busiest_day_hours{date="2026-03-16"} 1.5` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestCallWeekStats(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+
+	resp := s.callTool(context.Background(), &toolCall{name: toolWeekStats, id: float64(1)})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+	today := time.Now().In(s.settings().location)
+	if fake.lastStart != today.Format("2006-01-02") || fake.lastEnd != today.AddDate(0, 0, 6).Format("2006-01-02") {
+		t.Errorf("expected the 7 days from today, got %s..%s", fake.lastStart, fake.lastEnd)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	var decoded weekStats
+	if err := json.Unmarshal([]byte(text), &decoded); err != nil || decoded.StartDate != fake.lastStart {
+		t.Errorf("expected the stats as JSON, got %v:\n%s", err, text)
+	}
+
+	resp = s.callTool(context.Background(), &toolCall{name: toolWeekStats, id: float64(2), args: json.RawMessage(`{"format":"prometheus"}`)})
+	text = resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "gcal_week_free_hours ") {
+		t.Errorf("expected Prometheus metrics, got:\n%s", text)
+	}
+
+	resp = s.callTool(context.Background(), &toolCall{name: toolWeekStats, id: float64(3), args: json.RawMessage(`{"format":"csv"}`)})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for an unknown format, got %+v", resp.Error)
+	}
+}
+
+func TestStatsEndpoints(t *testing.T) {
+	s := New(&fakeCalendar{}, io.Discard)
+	srv := httptest.NewServer(s.SSEHandler())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats weekStats
+	err = json.NewDecoder(resp.Body).Decode(&stats)
+	resp.Body.Close()
+	if err != nil || resp.Header.Get("Content-Type") != "application/json" || stats.StartDate == "" {
+		t.Errorf("unexpected /stats reply: %v %q %+v", err, resp.Header.Get("Content-Type"), stats)
+	}
+
+	resp, err = http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") || !strings.Contains(string(body), "gcal_week_meetings 0\n") {
+		t.Errorf("unexpected /metrics reply %q:\n%s", resp.Header.Get("Content-Type"), body)
+	}
+
+	fail := New(&fakeCalendar{err: errors.New("backend unavailable")}, io.Discard)
+	failing := httptest.NewServer(fail.SSEHandler())
+	defer failing.Close()
+	resp, err = http.Get(failing.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected 502 when the calendar fails, got %d", resp.StatusCode)
+	}
+}
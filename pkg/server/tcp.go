@@ -0,0 +1,140 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net"
+	"sync"
+)
+
+const (
+	maxTCPSessions = 16
+
+	// Tool calls of all TCP sessions share one limit, so that several
+	// clients can't exhaust the Calendar API quota between them
+	defaultTCPCallRate  = 5
+	defaultTCPCallBurst = 10
+)
+
+// tcpTransport serves MCP over plain TCP, one session per connection, with
+// messages framed as on stdio. Every session has its own lifecycle,
+// subscriptions and in-flight requests, and all of them share the calendar
+// client, the configuration and the rate limit.
+type tcpTransport struct {
+	server *Server
+
+	mu       sync.Mutex
+	sessions map[*Server]bool
+}
+
+// ServeTCP accepts clients until the listener fails. Unless a rate limit is
+// configured, tool calls of all clients are limited to
+// defaultTCPCallRate per second.
+func (s *Server) ServeTCP(l net.Listener) error {
+	if s.limiter == nil {
+		s.limiter = newCallLimiter(defaultTCPCallRate, defaultTCPCallBurst)
+	}
+	t := &tcpTransport{server: s, sessions: make(map[*Server]bool)}
+	// Notifications of the shared server, such as calendar list and
+	// read-only changes, go to every session
+	s.setOutput(t)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go t.serve(conn)
+	}
+}
+
+func (t *tcpTransport) serve(conn net.Conn) {
+	defer conn.Close()
+
+	session := t.server.newSession(conn)
+	if !t.add(session) {
+		log.Printf("Rejected TCP client %s: %d sessions already open", conn.RemoteAddr(), maxTCPSessions)
+		session.sendError(nil, -32000, "Too many sessions", nil)
+		return
+	}
+	defer t.remove(session)
+
+	log.Printf("TCP client %s connected (session %s)", conn.RemoteAddr(), session.currentSessionID())
+	if err := session.Run(conn); err != nil {
+		log.Printf("TCP client %s: %v", conn.RemoteAddr(), err)
+	}
+	close(session.ended)
+	session.audit.unwatch(session)
+	log.Printf("TCP client %s disconnected (session %s)", conn.RemoteAddr(), session.currentSessionID())
+}
+
+func (t *tcpTransport) add(session *Server) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.sessions) >= maxTCPSessions {
+		return false
+	}
+	t.sessions[session] = true
+	return true
+}
+
+func (t *tcpTransport) remove(session *Server) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.sessions, session)
+}
+
+// Write sends a message of the shared server to every initialized session
+func (t *tcpTransport) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	sessions := make([]*Server, 0, len(t.sessions))
+	for session := range t.sessions {
+		sessions = append(sessions, session)
+	}
+	t.mu.Unlock()
+
+	for _, session := range sessions {
+		if session.ready() {
+			session.writeLine(bytes.TrimRight(p, "\n"))
+		}
+	}
+	return len(p), nil
+}
+
+// newSession returns a server for one more client, writing to out. It
+// shares the calendar client and the configuration of s but starts with a
+// session of its own.
+func (s *Server) newSession(out io.Writer) *Server {
+	base := s.configured()
+	session := New(base.calendar, out)
+	session.name = s.name
+	session.toolPrefix = s.toolPrefix
+	session.readOnly = s.readOnly
+	session.dateOrder = s.dateOrder
+	session.altCalendars = s.altCalendars
+	session.maxFieldLength = s.maxFieldLength
+	session.current.Store(base)
+	session.workHours = s.workHours
+	session.hourlyRate = s.hourlyRate
+	session.markers = s.markers
+	session.categories = s.categories
+	session.budgets = s.budgets
+	session.rawPolicy = s.rawPolicy
+	session.limiter = s.limiter
+	session.breaker = s.breaker
+	session.audit = s.audit
+	session.usage = s.usage
+	session.toolTimeouts = s.toolTimeouts
+	session.pollInterval = s.pollInterval
+	session.reconcileAt = s.reconcileAt
+	session.reconcileSpacing = s.reconcileSpacing
+	session.calendarList = s.calendarList
+	return session
+}
+
+func (s *Server) ready() bool {
+	s.sessionMu.RLock()
+	defer s.sessionMu.RUnlock()
+	return s.state == stateReady
+}
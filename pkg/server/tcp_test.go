@@ -0,0 +1,117 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type tcpClient struct {
+	conn    net.Conn
+	replies *bufio.Reader
+}
+
+func dialTCP(t *testing.T, addr string) *tcpClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &tcpClient{conn: conn, replies: bufio.NewReader(conn)}
+}
+
+func (c *tcpClient) send(t *testing.T, msg string) {
+	t.Helper()
+	if _, err := fmt.Fprintln(c.conn, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *tcpClient) read(t *testing.T) string {
+	t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	line, err := c.replies.ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read a reply: %v", err)
+	}
+	return line
+}
+
+func (c *tcpClient) initialize(t *testing.T) {
+	t.Helper()
+	c.send(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`)
+	if reply := c.read(t); !strings.Contains(reply, `"protocolVersion":"2024-11-05"`) {
+		t.Fatalf("unexpected initialize result: %s", reply)
+	}
+	c.send(t, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+}
+
+func TestTCPTransport_ConcurrentSessions(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	s := New(&fakeCalendar{}, io.Discard)
+	s.limiter = newCallLimiter(defaultTCPCallRate, defaultTCPCallBurst)
+	go s.ServeTCP(l)
+
+	first := dialTCP(t, l.Addr().String())
+	second := dialTCP(t, l.Addr().String())
+
+	first.initialize(t)
+
+	// Each session has its own handshake
+	second.send(t, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)
+	if reply := second.read(t); !strings.Contains(reply, fmt.Sprint(errCodeNotInitialized)) {
+		t.Errorf("expected the second session to be uninitialized, got %s", reply)
+	}
+	second.initialize(t)
+
+	first.send(t, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_events","arguments":{}}}`)
+	second.send(t, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"list_events","arguments":{}}}`)
+	if reply := first.read(t); !strings.Contains(reply, `"id":2`) {
+		t.Errorf("expected the first session's reply, got %s", reply)
+	}
+	if reply := second.read(t); !strings.Contains(reply, `"id":7`) {
+		t.Errorf("expected the second session's reply, got %s", reply)
+	}
+
+	// Shared settings apply to every session, and so do their notifications
+	s.setReadOnly(true)
+	for _, c := range []*tcpClient{first, second} {
+		if reply := c.read(t); !strings.Contains(reply, "notifications/tools/list_changed") {
+			t.Errorf("expected a tool list change, got %s", reply)
+		}
+	}
+	second.send(t, `{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"delete_event","arguments":{"event_id":"x"}}}`)
+	if reply := second.read(t); !strings.Contains(reply, "read-only mode") {
+		t.Errorf("expected read-only mode in the second session, got %s", reply)
+	}
+}
+
+func TestTCPTransport_SessionLimit(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	s := New(&fakeCalendar{}, io.Discard)
+	go s.ServeTCP(l)
+
+	for i := 0; i < maxTCPSessions; i++ {
+		c := dialTCP(t, l.Addr().String())
+		c.send(t, `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+		c.read(t)
+	}
+
+	extra := dialTCP(t, l.Addr().String())
+	if reply := extra.read(t); !strings.Contains(reply, "Too many sessions") {
+		t.Errorf("expected the extra client to be rejected, got %s", reply)
+	}
+}
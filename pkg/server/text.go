@@ -0,0 +1,129 @@
+package server
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	zeroWidthJoiner       = '\u200d'
+	firstStrongIsolate    = '\u2068'
+	popDirectionalIsolate = '\u2069'
+)
+
+// rtlScripts are the scripts written right-to-left that event titles
+// commonly use
+var rtlScripts = []*unicode.RangeTable{
+	unicode.Hebrew,
+	unicode.Arabic,
+	unicode.Syriac,
+	unicode.Thaana,
+	unicode.Nko,
+}
+
+// sanitizeText prepares calendar-provided text for embedding in a tool
+// result line: newlines and tabs become spaces, other control characters
+// and explicit bidi formatting are dropped, and the result is truncated to
+// maxLen characters when maxLen > 0. Right-to-left text is wrapped in a
+// directional isolate so it cannot reorder the surrounding output.
+func sanitizeText(s string, maxLen int) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	lastSpace := false
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			if !lastSpace {
+				b.WriteRune(' ')
+			}
+			lastSpace = true
+			continue
+		case unicode.IsControl(r), isBidiControl(r):
+			continue
+		}
+		b.WriteRune(r)
+		lastSpace = r == ' '
+	}
+
+	text := truncateText(strings.TrimSpace(b.String()), maxLen)
+	if containsRTL(text) {
+		text = string(firstStrongIsolate) + text + string(popDirectionalIsolate)
+	}
+	return text
+}
+
+// truncateText cuts s to at most maxLen user-perceived characters, marking
+// the cut with an ellipsis. Cuts never split a base character from its
+// combining marks, emoji modifiers or joined emoji sequences.
+func truncateText(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return s
+	}
+
+	boundaries := clusterBoundaries(s)
+	if len(boundaries) <= maxLen {
+		return s
+	}
+	if maxLen == 1 {
+		return "…"
+	}
+	return s[:boundaries[maxLen-1]] + "…"
+}
+
+// clusterBoundaries returns the byte offset at which each character cluster
+// of s starts. It approximates extended grapheme clusters closely enough
+// for truncating display text.
+func clusterBoundaries(s string) []int {
+	var boundaries []int
+	var prev rune
+	regionalRun := 0
+
+	for i, r := range s {
+		extends := i > 0 && (isClusterExtender(r) || prev == zeroWidthJoiner)
+		if isRegionalIndicator(r) {
+			// Flags are pairs of regional indicators
+			if i > 0 && isRegionalIndicator(prev) && regionalRun%2 == 1 {
+				extends = true
+			}
+			regionalRun++
+		} else {
+			regionalRun = 0
+		}
+
+		if !extends {
+			boundaries = append(boundaries, i)
+		}
+		prev = r
+	}
+
+	return boundaries
+}
+
+func isClusterExtender(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == zeroWidthJoiner ||
+		(r >= '\ufe00' && r <= '\ufe0f') || // variation selectors
+		(r >= 0x1f3fb && r <= 0x1f3ff) || // emoji skin tone modifiers
+		(r >= 0xe0020 && r <= 0xe007f) // emoji tag sequences
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isBidiControl reports explicit directional formatting characters, which
+// can leak out of a field and reorder the rest of the line
+func isBidiControl(r rune) bool {
+	return (r >= '\u202a' && r <= '\u202e') || (r >= '\u2066' && r <= '\u2069') ||
+		r == '\u200e' || r == '\u200f' || r == '\u061c'
+}
+
+func containsRTL(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, rtlScripts...) && unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
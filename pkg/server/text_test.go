@@ -0,0 +1,77 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"plain", "Team sync", 0, "Team sync"},
+		{"newlines", "Line one\nLine two\r\nLine three", 0, "Line one Line two Line three"},
+		{"tabs and trailing newline", "\tIndented\n", 0, "Indented"},
+		{"control chars", "Bell\x07 and\x00 null\x1b[31m", 0, "Bell and null[31m"},
+		{"truncated", "Quarterly planning", 10, "Quarterly…"},
+		{"no truncation needed", "Short", 10, "Short"},
+		{"multibyte truncation", "Встреча с командой", 8, "Встреча…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeText(tt.in, tt.maxLen); got != tt.want {
+				t.Errorf("sanitizeText(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatEvents_SanitizesSummary(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	text := s.formatEvents([]gcal.CalendarEvent{{ID: "1", Summary: "Injected\n  ID: fake"}})
+
+	if contains(text, "Injected\n") {
+		t.Errorf("expected newline in summary to be removed, got %q", text)
+	}
+}
+
+func TestTruncateText_GraphemeBoundaries(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"combining accent kept", "Cafe\u0301 meeting", 5, "Cafe\u0301…"},
+		{"skin tone kept", "👍🏽👍🏽👍🏽", 3, "👍🏽👍🏽👍🏽"},
+		{"skin tone not split", "👍🏽👍🏽👍🏽", 2, "👍🏽…"},
+		{"zwj family not split", "👨\u200d👩\u200d👧 party", 2, "👨\u200d👩\u200d👧…"},
+		{"flags paired", "🇩🇪🇫🇷🇮🇹", 2, "🇩🇪…"},
+		{"hebrew", "פגישת צוות שבועית", 6, "פגישת…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateText(tt.in, tt.maxLen); got != tt.want {
+				t.Errorf("truncateText(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeText_RTL(t *testing.T) {
+	got := sanitizeText("اجتماع الفريق", 0)
+	if got != "\u2068اجتماع الفريق\u2069" {
+		t.Errorf("expected RTL text to be isolated, got %q", got)
+	}
+
+	got = sanitizeText("Meeting \u202eevil\u202c", 0)
+	if got != "Meeting evil" {
+		t.Errorf("expected bidi overrides to be stripped, got %q", got)
+	}
+}
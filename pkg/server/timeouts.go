@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// toolTimeouts bounds how long tool calls may take, per tool name; the
+// entry for "" applies to the tools without one of their own
+type toolTimeouts map[string]time.Duration
+
+// parseToolTimeouts reads CALENDAR_TOOL_TIMEOUTS: comma-separated
+// durations, each either for one tool (find_conflicts=1m) or, without a
+// name, for all the others (30s)
+func parseToolTimeouts(spec string) (toolTimeouts, error) {
+	timeouts := make(toolTimeouts)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			name, value = "", entry
+		}
+		name = strings.TrimSpace(name)
+		if _, known := findTool(name); name != "" && !known {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+		if _, dup := timeouts[name]; dup {
+			if name == "" {
+				return nil, fmt.Errorf("more than one default timeout")
+			}
+			return nil, fmt.Errorf("more than one timeout for %s", name)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", value)
+		}
+		timeouts[name] = d
+	}
+	if len(timeouts) == 0 {
+		return nil, fmt.Errorf("no timeouts given")
+	}
+	return timeouts, nil
+}
+
+// of returns the timeout of a tool, zero when it has none
+func (t toolTimeouts) of(name string) time.Duration {
+	if d, ok := t[name]; ok {
+		return d
+	}
+	return t[""]
+}
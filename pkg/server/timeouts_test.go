@@ -0,0 +1,25 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseToolTimeouts(t *testing.T) {
+	timeouts, err := parseToolTimeouts("20s, find_conflicts=2m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timeouts.of(toolFindConflicts) != 2*time.Minute || timeouts.of(toolListEvents) != 20*time.Second {
+		t.Errorf("unexpected timeouts %v", timeouts)
+	}
+	if d := (toolTimeouts{toolListEvents: time.Second}).of(toolGetEvent); d != 0 {
+		t.Errorf("expected no timeout without a default, got %v", d)
+	}
+
+	for _, spec := range []string{"soon", "0s", "list_event=1s", "1s,2s", "get_event=1s,get_event=2s", " , "} {
+		if _, err := parseToolTimeouts(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
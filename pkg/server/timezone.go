@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	defaultMigrationDays = 30
+	maxMigrationDays     = 90
+)
+
+// relocatedEvent is an upcoming event that sat within working hours in the
+// old timezone but falls outside them in the configured one
+type relocatedEvent struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	// OldLocal and NewLocal are the event's start in each timezone
+	OldLocal string `json:"oldLocal"`
+	NewLocal string `json:"newLocal"`
+	// SuggestedDate and SuggestedStart keep the old local time of day in the
+	// new timezone
+	SuggestedDate  string `json:"suggestedDate"`
+	SuggestedStart string `json:"suggestedStart"`
+	// Movable is set for events you organize, which the tool can adjust
+	Movable bool   `json:"movable"`
+	Status  string `json:"status,omitempty"`
+}
+
+type migrationReport struct {
+	FromTimezone string           `json:"fromTimezone"`
+	ToTimezone   string           `json:"toTimezone"`
+	Events       []relocatedEvent `json:"events"`
+	Adjusted     int              `json:"adjusted"`
+	// applied is whether the events were adjusted rather than only listed,
+	// for the text
+	applied bool
+}
+
+// withinWorkHours reports whether an interval lies inside working hours of
+// a working day, in the location of start
+func withinWorkHours(start, end time.Time, hours workHours) bool {
+	if !hours.days[start.Weekday()] {
+		return false
+	}
+	midnight := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	return !start.Before(midnight.Add(hours.start)) && !end.After(midnight.Add(hours.end))
+}
+
+// findRelocatedEvents returns the timed events whose fixed times were
+// comfortable in from but are awkward in to
+func findRelocatedEvents(events []gcal.CalendarEvent, from, to *time.Location, hours workHours) []relocatedEvent {
+	result := []relocatedEvent{}
+	for _, e := range events {
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(time.RFC3339, e.End)
+		if err != nil {
+			continue
+		}
+		if !withinWorkHours(start.In(from), end.In(from), hours) || withinWorkHours(start.In(to), end.In(to), hours) {
+			continue
+		}
+		old := start.In(from)
+		result = append(result, relocatedEvent{
+			ID:             e.ID,
+			Summary:        e.Summary,
+			OldLocal:       old.Format("2006-01-02 15:04"),
+			NewLocal:       start.In(to).Format("2006-01-02 15:04"),
+			SuggestedDate:  old.Format("2006-01-02"),
+			SuggestedStart: old.Format("15:04"),
+			Movable:        e.OrganizerSelf,
+		})
+	}
+	return result
+}
+
+// timezoneMigrationInput is the arguments of timezone_migration
+type timezoneMigrationInput struct {
+	// IANA timezone you moved from, e.g. Europe/Berlin
+	FromTimezone string `json:"from_timezone" jsonschema:"required"`
+	// Number of upcoming days to check (default: 30, max: 90)
+	Days int `json:"days" jsonschema:"default=defaultMigrationDays,maximum=maxMigrationDays"`
+	// Move the events you organize to their suggested times (default: only
+	// report)
+	Apply bool `json:"apply"`
+	// With apply, adjust only these events (default: all movable ones)
+	EventIDs []string `json:"event_ids"`
+}
+
+func (s *Server) callTimezoneMigration(ctx context.Context, input timezoneMigrationInput) (migrationReport, error) {
+	if input.FromTimezone == "" {
+		return migrationReport{}, badArgumentf("from_timezone is required (the IANA timezone you moved from, e.g. Europe/Berlin)")
+	}
+	from, err := time.LoadLocation(input.FromTimezone)
+	if err != nil {
+		return migrationReport{}, badArgumentf("unknown timezone %q", input.FromTimezone)
+	}
+	if input.Days == 0 {
+		input.Days = defaultMigrationDays
+	}
+	if input.Days < 0 || input.Days > maxMigrationDays {
+		return migrationReport{}, badArgumentf("days must be between 1 and %d", maxMigrationDays)
+	}
+
+	now := time.Now().In(s.settings().location)
+	events, err := s.settings().calendar.ListEventsRange(ctx, now.Format("2006-01-02"), now.AddDate(0, 0, input.Days-1).Format("2006-01-02"))
+	if err != nil {
+		return migrationReport{}, err
+	}
+
+	report := migrationReport{
+		FromTimezone: from.String(),
+		ToTimezone:   s.settings().location.String(),
+		Events:       findRelocatedEvents(events, from, s.settings().location, s.workHours),
+		applied:      input.Apply,
+	}
+
+	if input.Apply {
+		selected := make(map[string]bool, len(input.EventIDs))
+		for _, id := range input.EventIDs {
+			selected[id] = true
+		}
+		for i := range report.Events {
+			e := &report.Events[i]
+			if !e.Movable || len(selected) > 0 && !selected[e.ID] {
+				continue
+			}
+			_, err := s.settings().calendar.UpdateEvent(ctx, e.ID, gcal.EventUpdates{Date: &e.SuggestedDate, StartTime: &e.SuggestedStart})
+			if err != nil {
+				e.Status = fmt.Sprintf("not adjusted: %v", err)
+				continue
+			}
+			e.Status = "adjusted"
+			report.Adjusted++
+		}
+	}
+	return report, nil
+}
+
+func (r migrationReport) toolText(s *Server) string { return s.formatMigration(r, r.applied) }
+
+func (s *Server) formatMigration(r migrationReport, applied bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Moving from %s to %s: ", r.FromTimezone, r.ToTimezone)
+	if len(r.Events) == 0 {
+		b.WriteString("no upcoming events move out of working hours.\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "%d upcoming event(s) now fall outside working hours\n\n", len(r.Events))
+
+	for _, e := range r.Events {
+		fmt.Fprintf(&b, "- %s (ID %s): was %s, now %s", s.sanitize(e.Summary), e.ID, e.OldLocal, e.NewLocal)
+		switch {
+		case e.Status != "":
+			fmt.Fprintf(&b, ", %s", e.Status)
+		case e.Movable:
+			fmt.Fprintf(&b, ", can move to %s %s", e.SuggestedDate, e.SuggestedStart)
+		default:
+			b.WriteString(", organized by someone else")
+		}
+		b.WriteString("\n")
+	}
+
+	if applied {
+		fmt.Fprintf(&b, "\nAdjusted %d event(s).\n", r.Adjusted)
+	} else {
+		b.WriteString("\nCall again with apply: true (and optionally event_ids) to move the events you organize to their old local times.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func migrationEvents() []gcal.CalendarEvent {
+	// 2026-03-16 is a Monday: Berlin is UTC+1, New York UTC-4
+	return []gcal.CalendarEvent{
+		{ID: "standup", Summary: "Standup", Start: "2026-03-16T09:00:00Z", End: "2026-03-16T09:30:00Z", OrganizerSelf: true},
+		{ID: "review", Summary: "Review", Start: "2026-03-16T08:00:00Z", End: "2026-03-16T09:00:00Z"},
+		{ID: "fine", Summary: "Still fine", Start: "2026-03-16T15:00:00Z", End: "2026-03-16T16:00:00Z"},
+		{ID: "late", Summary: "Was already late", Start: "2026-03-16T19:00:00Z", End: "2026-03-16T20:00:00Z"},
+	}
+}
+
+func TestFindRelocatedEvents(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	newYork, _ := time.LoadLocation("America/New_York")
+
+	moved := findRelocatedEvents(migrationEvents(), berlin, newYork, defaultWorkHours)
+	if len(moved) != 2 {
+		t.Fatalf("expected 2 relocated events, got %+v", moved)
+	}
+	want := relocatedEvent{
+		ID: "standup", Summary: "Standup", OldLocal: "2026-03-16 10:00", NewLocal: "2026-03-16 05:00",
+		SuggestedDate: "2026-03-16", SuggestedStart: "10:00", Movable: true,
+	}
+	if moved[0] != want {
+		t.Errorf("expected %+v, got %+v", want, moved[0])
+	}
+	if moved[1].ID != "review" || moved[1].Movable {
+		t.Errorf("expected the review to be reported as someone else's, got %+v", moved[1])
+	}
+}
+
+func TestCallTimezoneMigration_Apply(t *testing.T) {
+	fake := &fakeCalendar{events: migrationEvents(), updated: &calendar.Event{Id: "standup"}}
+	s := newTestServer(fake)
+	s.settings().location, _ = time.LoadLocation("America/New_York")
+
+	args, _ := json.Marshal(map[string]interface{}{"from_timezone": "Europe/Berlin", "apply": true})
+	resp := s.callTool(context.Background(), &toolCall{name: toolMigrateTimezone, id: float64(1), args: args})
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+
+	if fake.lastUpdate.StartTime == nil || *fake.lastUpdate.StartTime != "10:00" || *fake.lastUpdate.Date != "2026-03-16" {
+		t.Errorf("unexpected update %+v", fake.lastUpdate)
+	}
+	if !strings.Contains(text, "2 upcoming event(s)") || !strings.Contains(text, "Adjusted 1 event(s)") {
+		t.Errorf("unexpected report:\n%s", text)
+	}
+
+	args, _ = json.Marshal(map[string]string{"from_timezone": "Mars/Olympus"})
+	resp = s.callTool(context.Background(), &toolCall{name: toolMigrateTimezone, id: float64(2), args: args})
+	if resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("expected invalid params for an unknown timezone, got %+v", resp.Error)
+	}
+}
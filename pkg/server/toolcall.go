@@ -0,0 +1,77 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// toolCall carries a single tools/call request through dispatch to its
+// handler
+type toolCall struct {
+	id   interface{}
+	name string
+	args json.RawMessage
+	meta requestMeta
+	// verbosity is how much the result says; empty means normal
+	verbosity string
+}
+
+// requestMeta is the _meta object of a request. Fields the server
+// understands are decoded; everything the client sent is kept in raw.
+type requestMeta struct {
+	ProgressToken interface{} `json:"progressToken"`
+	// Traceparent and Tracestate carry W3C trace context from the client
+	Traceparent string `json:"traceparent"`
+	Tracestate  string `json:"tracestate"`
+	// EventStreaming asks for pages of events in progress notifications,
+	// see experimentalEventStreaming
+	EventStreaming bool `json:"eventStreaming"`
+
+	raw map[string]json.RawMessage
+}
+
+func (m *requestMeta) UnmarshalJSON(data []byte) error {
+	type known requestMeta
+	var decoded known
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = requestMeta(decoded)
+	m.raw = raw
+	return nil
+}
+
+// field returns a _meta value by key, including keys the server does not
+// interpret itself
+func (m requestMeta) field(key string) (json.RawMessage, bool) {
+	v, ok := m.raw[key]
+	return v, ok
+}
+
+// traceID returns the trace ID of the W3C traceparent the client sent, or
+// "" when there is none or it is malformed
+func (m requestMeta) traceID() string {
+	// Versions after 00 may append fields, but 00 has exactly four
+	parts := strings.Split(m.Traceparent, "-")
+	if len(parts) < 4 || parts[0] == "00" && len(parts) != 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return ""
+	}
+	id := parts[1]
+	if len(id) != 32 || !isLowerHex(id) || strings.Trim(id, "0") == "" {
+		return ""
+	}
+	return id
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
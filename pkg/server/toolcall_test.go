@@ -0,0 +1,60 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRequestMeta_Unmarshal(t *testing.T) {
+	var params struct {
+		Meta requestMeta `json:"_meta"`
+	}
+	data := `{"_meta":{"progressToken":"tok-1","traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01","vendor.example/session":"abc"}}`
+
+	if err := json.Unmarshal([]byte(data), &params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	meta := params.Meta
+	if meta.ProgressToken != "tok-1" {
+		t.Errorf("expected progress token tok-1, got %v", meta.ProgressToken)
+	}
+	if meta.Traceparent != "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01" {
+		t.Errorf("unexpected traceparent %q", meta.Traceparent)
+	}
+	if v, ok := meta.field("vendor.example/session"); !ok || string(v) != `"abc"` {
+		t.Errorf("expected unknown _meta fields to be kept, got %s %v", v, ok)
+	}
+}
+
+func TestRequestMeta_Missing(t *testing.T) {
+	var params struct {
+		Meta requestMeta `json:"_meta"`
+	}
+	if err := json.Unmarshal([]byte(`{"name":"list_events"}`), &params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params.Meta.ProgressToken != nil {
+		t.Errorf("expected no progress token, got %v", params.Meta.ProgressToken)
+	}
+	if _, ok := params.Meta.field("progressToken"); ok {
+		t.Error("expected no raw fields")
+	}
+}
+
+func TestRequestMeta_TraceID(t *testing.T) {
+	for traceparent, want := range map[string]string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":       "4bf92f3577b34da6a3ce929d0e0e4736",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"": "",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra": "",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01":       "",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01":       "",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01":       "",
+		"00-4bf92f35-00f067aa0ba902b7-01":                               "",
+	} {
+		if got := (requestMeta{Traceparent: traceparent}).traceID(); got != want {
+			t.Errorf("%q: expected %q, got %q", traceparent, want, got)
+		}
+	}
+}
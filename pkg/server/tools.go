@@ -0,0 +1,429 @@
+package server
+
+//go:generate go run ./internal/schemagen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+// toolDefinition describes a tool exposed through tools/list
+type toolDefinition struct {
+	name        string
+	title       string
+	description string
+	// inputSchema is generated from the struct the tool decodes its
+	// arguments into, set by registerTool and checked by
+	// validateArguments before the call
+	inputSchema  map[string]interface{}
+	outputSchema map[string]interface{}
+	// mutating tools change calendar data and are hidden in read-only mode
+	mutating bool
+	// mutates decides per call whether a tool that isn't always mutating
+	// changes calendar data, so that such calls are audited and counted
+	// like those of mutating tools
+	mutates func(s *Server, args json.RawMessage) bool
+	// destructive tools may overwrite or remove existing data
+	destructive bool
+	// requiresSampling tools are only offered to clients that support
+	// sampling/createMessage
+	requiresSampling bool
+	// requiresRawPolicy tools are only offered when CALENDAR_RAW_METHODS
+	// allows some raw API methods
+	requiresRawPolicy bool
+	// local tools don't call the calendar backend, so they work while the
+	// circuit breaker is open and don't close it
+	local bool
+	// handler answers calls of the tool; see registerTool
+	handler toolHandler
+}
+
+// annotations returns the tool hints defined by the 2025-03-26 revision
+func (t toolDefinition) annotations() map[string]interface{} {
+	return map[string]interface{}{
+		"title":           t.title,
+		"readOnlyHint":    !t.mutating,
+		"destructiveHint": t.destructive,
+		"idempotentHint":  !t.mutating || t.destructive,
+		"openWorldHint":   false,
+	}
+}
+
+// eventsOutputSchema describes the structuredContent of listing tools
+var eventsOutputSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"events": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"id":         map[string]interface{}{"type": "string"},
+					"calendarId": map[string]interface{}{"type": "string"},
+					"summary":    map[string]interface{}{"type": "string"},
+					"start":      map[string]interface{}{"type": "string"},
+					"end":        map[string]interface{}{"type": "string"},
+					"htmlLink":   map[string]interface{}{"type": "string"},
+					"location":   map[string]interface{}{"type": "string"},
+					"attendees":  map[string]interface{}{"type": "integer"},
+					"guests": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"email":          map[string]interface{}{"type": "string"},
+								"responseStatus": map[string]interface{}{"type": "string"},
+								"self":           map[string]interface{}{"type": "boolean"},
+							},
+							"required": []string{"email"},
+						},
+					},
+					"recurringEventId": map[string]interface{}{"type": "string"},
+					"updated":          map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"id", "summary", "start", "end"},
+			},
+		},
+	},
+	"required": []string{"events"},
+}
+
+// calendarArgDescription documents the calendar argument of tools that can
+// read other people's calendars
+const calendarArgDescription = "Whose calendar to read: a teammate's email address (if they share their calendar with you), a calendar ID, or the name of a calendar in your calendar list (default: your calendar)"
+
+// The argument structs below are embedded in the *Input structs of the
+// tools that share them; schemagen turns those into the input schemas of
+// schemas_gen.go.
+
+// calendarArg is the calendar argument of tools that can read other
+// people's calendars
+type calendarArg struct {
+	Calendar string `json:"calendar" jsonschema:"description=calendarArgDescription"`
+}
+
+// eventRefArg lets tools that act on a single event take it by position
+type eventRefArg struct {
+	EventRef string `json:"event_ref" jsonschema:"description=eventRefDescription"`
+}
+
+// forceArg overrides the checks on the times of an event
+type forceArg struct {
+	// Allow durations over 12 hours or under 1 minute, and times blocked by
+	// the configured schedule constraints (optional)
+	Force bool `json:"force"`
+}
+
+// newEventArgs describe an event to create
+type newEventArgs struct {
+	// Event title
+	Summary string `json:"summary" jsonschema:"required"`
+	// Event date in YYYY-MM-DD format (DD.MM.YYYY and DD/MM/YYYY are also
+	// accepted)
+	Date string `json:"date" jsonschema:"required"`
+	// Start time in HH:MM format (24-hour)
+	StartTime string `json:"start_time" jsonschema:"required"`
+	// End time in HH:MM format (24-hour)
+	EndTime string `json:"end_time" jsonschema:"required"`
+	// Event description (optional)
+	Description string `json:"description"`
+	forceArg
+}
+
+// Occurrences of a recurring event update_event and delete_event act on
+const (
+	applyToThisEvent        = gcal.ApplyToThisEvent
+	applyToThisAndFollowing = gcal.ApplyToThisAndFollowing
+	applyToAll              = gcal.ApplyToAll
+)
+
+// seriesScopeArgs pick the occurrences of a recurring event a change or a
+// deletion applies to
+type seriesScopeArgs struct {
+	// For recurring events: this_event for one occurrence, this_and_following
+	// for it and the later ones, all for the whole series (optional; by
+	// default an occurrence's ID acts on the occurrence and a series ID on
+	// the whole series)
+	ApplyTo string `json:"apply_to" jsonschema:"enum=applyToThisEvent|applyToThisAndFollowing|applyToAll"`
+	// Date of the occurrence meant, in YYYY-MM-DD format, when event_id is
+	// that of a whole series and apply_to is this_event or
+	// this_and_following
+	OccurrenceDate string `json:"occurrence_date"`
+}
+
+// scope checks the arguments and converts them for gcal
+func (a seriesScopeArgs) scope(s *Server) (gcal.SeriesScope, error) {
+	switch a.ApplyTo {
+	case "", applyToThisEvent, applyToThisAndFollowing, applyToAll:
+	default:
+		return gcal.SeriesScope{}, badArgumentf("apply_to must be %s, %s or %s", applyToThisEvent, applyToThisAndFollowing, applyToAll)
+	}
+	if a.OccurrenceDate != "" && a.ApplyTo == "" {
+		return gcal.SeriesScope{}, badArgumentf("occurrence_date needs apply_to")
+	}
+	if err := s.normalizeDateArg(&a.OccurrenceDate); err != nil {
+		return gcal.SeriesScope{}, badArgument(err)
+	}
+	return gcal.SeriesScope{ApplyTo: a.ApplyTo, Occurrence: a.OccurrenceDate}, nil
+}
+
+// eventChangeArgs are the changes to an existing event; nil fields are left
+// as they are
+type eventChangeArgs struct {
+	// New event title (optional)
+	Summary *string `json:"summary"`
+	// New event description (optional)
+	Description *string `json:"description"`
+	// New date in YYYY-MM-DD format, DD.MM.YYYY or DD/MM/YYYY (optional)
+	Date *string `json:"date"`
+	// New start time in HH:MM format (optional)
+	StartTime *string `json:"start_time"`
+	// New end time in HH:MM format (optional)
+	EndTime *string `json:"end_time"`
+	forceArg
+}
+
+// toolDefinitions lists the tools in the order of tools/list; registerTool
+// fills it in below
+var toolDefinitions []toolDefinition
+
+func init() {
+	registerTool(toolDefinition{
+		name:         toolListEvents,
+		title:        "List events",
+		description:  "List calendar events for the next N days",
+		outputSchema: eventsOutputSchema,
+	}, (*Server).callListEvents)
+	registerTool(toolDefinition{
+		name:         toolListEventsRange,
+		title:        "List events in range",
+		description:  "List calendar events between two dates",
+		outputSchema: eventsOutputSchema,
+	}, (*Server).callListEventsRange)
+	registerTool(toolDefinition{
+		name:        toolDiffRange,
+		title:       "Diff date range",
+		description: "Report what changed in a date range since an earlier look: events added, removed or moved. The first call returns a snapshot token; pass it back later to compare against that state. Every call returns a new token",
+	}, (*Server).callDiffRange)
+	registerTool(toolDefinition{
+		name:        toolGetEvent,
+		title:       "Get event",
+		description: "Get everything about a single event that listings leave out: description, location, join link, organizer, guests with their RSVP status, recurrence, reminders, visibility and created/updated times; optionally as an iCalendar (.ics) attachment",
+	}, (*Server).callGetEvent)
+	registerTool(toolDefinition{
+		name:         toolSearchEvents,
+		title:        "Search events",
+		description:  "Find events by words in their summary, description, location or guests, e.g. \"dentist\", optionally between two dates, instead of scanning long listings. Results are numbered like list_events, so get_event, update_event and delete_event accept their event_ref",
+		outputSchema: eventsOutputSchema,
+	}, (*Server).callSearchEvents)
+	registerTool(toolDefinition{
+		name:        toolJoinInfo,
+		title:       "Join info",
+		description: "How to join a meeting, in one line: the video link, dial-in and location of your next meeting (the one in progress or starting next), or of a given event. Meant for voice assistants and other clients that need a terse answer",
+	}, (*Server).callJoinInfo)
+	registerTool(toolDefinition{
+		name:        toolCreateEvent,
+		title:       "Create event",
+		description: "Create a new calendar event. Warns when the event takes its category over its weekly budget",
+		mutating:    true,
+	}, (*Server).callCreateEvent)
+	registerTool(toolDefinition{
+		name:        toolQuickAddEvent,
+		title:       "Quick add event",
+		description: "Create an event from a sentence such as \"Lunch with Sam Friday 1pm\", letting Google work out the title and time. Returns the event as Google understood it, with its resolved time, for confirmation",
+		mutating:    true,
+	}, (*Server).callQuickAddEvent)
+	registerTool(toolDefinition{
+		name:        toolBroadcastEvent,
+		title:       "Create event on several calendars",
+		description: "Create the same event on several calendars at once (e.g. a team, a room and a project calendar). Reports the result for each calendar; the copies share a broadcast ID stored in their private properties",
+		mutating:    true,
+	}, (*Server).callBroadcastEvent)
+	registerTool(toolDefinition{
+		name:        toolEditLinked,
+		title:       "Edit linked events",
+		description: "Apply the same change to every copy of an event created with create_event_on_calendars, found through the broadcast ID they share, so postings on several calendars stay consistent. Reports the result for each copy",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callEditLinkedEvents)
+	registerTool(toolDefinition{
+		name:        toolDeleteEvent,
+		title:       "Delete event",
+		description: "Delete a calendar event. For recurring events, apply_to deletes one occurrence, it and the following ones, or the whole series",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callDeleteEvent)
+	registerTool(toolDefinition{
+		name:        toolUpdateEvent,
+		title:       "Update event",
+		description: "Update an existing calendar event. For recurring events, apply_to changes one occurrence, it and the following ones, or the whole series",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callUpdateEvent)
+	registerTool(toolDefinition{
+		name:        toolAnalyzeTime,
+		title:       "Analyze time",
+		description: "Analyze how working hours are used: meetings, busy time, free blocks, the longest focus window per day, a fragmentation score, and hours per category when categories are configured, flagging categories over their budgets",
+	}, (*Server).callAnalyzeTime)
+	registerTool(toolDefinition{
+		name:        toolMeetingFree,
+		title:       "Meeting-free days",
+		description: "List the days without meetings during working hours and the longest meeting-free streak in a date range, e.g. to plan travel or a focus week",
+	}, (*Server).callMeetingFreeDays)
+	registerTool(toolDefinition{
+		name:        toolComparePeriods,
+		title:       "Compare periods",
+		description: "Compare meeting load between two date ranges, e.g. this week vs last week: meeting count, hours in meetings and the top categories (those of CALENDAR_CATEGORIES, or else meeting titles) with their changes",
+	}, (*Server).callComparePeriods)
+	registerTool(toolDefinition{
+		name:        toolMeetingHistory,
+		title:       "Meeting history",
+		description: "Report past meetings with a person or organization: how many, total hours, and when you last met",
+	}, (*Server).callMeetingHistory)
+	registerTool(toolDefinition{
+		name:        toolHygieneReport,
+		title:       "Calendar hygiene report",
+		description: "Find calendar clutter worth cleaning up, such as recurring events nobody has edited for months whose recent instances were all declined",
+	}, (*Server).callHygieneReport)
+	registerTool(toolDefinition{
+		name:        toolFindConflicts,
+		title:       "Find conflicts",
+		description: "Find double-bookings: overlapping events across all configured calendars, grouped by day. Events marked as free or declined are ignored",
+	}, (*Server).callFindConflicts)
+	registerTool(toolDefinition{
+		name:        toolFreeBusy,
+		title:       "Free/busy query",
+		description: "Check when people, rooms or calendars are busy in a time window, e.g. to find a time that suits everyone: returns the busy blocks of each, without event details. Works for anyone who shares their free/busy information with you, including colleagues whose calendars aren't in your list",
+	}, (*Server).callFreeBusyQuery)
+	registerTool(toolDefinition{
+		name:        toolExceptions,
+		title:       "Recurring exceptions",
+		description: "List the instances of a recurring series that deviate from its pattern (cancelled, moved or with a different length) to see how often a regular meeting actually happens",
+	}, (*Server).callRecurringExceptions)
+	registerTool(toolDefinition{
+		name:        toolListInstances,
+		title:       "List recurring instances",
+		description: "List the occurrences of a recurring event in a date range (default: the next 30 days), each with its actual time and ID, marking the cancelled ones and those moved or with a different length. The IDs work with update_event and delete_event",
+	}, (*Server).callListInstances)
+	registerTool(toolDefinition{
+		name:        toolApplyResolution,
+		title:       "Apply conflict resolution",
+		description: "Resolve a conflict reported by find_conflicts by moving the suggested event to one of its suggested slots. The slot is checked again across all calendars before the event is moved",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callApplyResolution)
+	registerTool(toolDefinition{
+		name:        toolPlanVacation,
+		title:       "Plan vacation",
+		description: "Create an out-of-office event for a vacation and deal with the meetings it overlaps: list them, or decline the ones you are invited to. Reports what was declined and what still needs attention",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callPlanVacation)
+	registerTool(toolDefinition{
+		name:        toolMigrateTimezone,
+		title:       "Timezone migration",
+		description: "After moving to another timezone (CALENDAR_TIMEZONE changed), list upcoming events that used to be within working hours but now fall outside them, and optionally move the ones you organize back to their old local time of day",
+		mutating:    true,
+		destructive: true,
+	}, (*Server).callTimezoneMigration)
+	registerTool(toolDefinition{
+		name:        toolDelegated,
+		title:       "Delegated actions",
+		description: "In delegated mode (CALENDAR_DELEGATE_LABEL set), list the events the assistant created, changed, deleted or responded to on behalf of the calendar owner, most recent first",
+	}, (*Server).callDelegatedActions)
+	registerTool(toolDefinition{
+		name:        toolWeekStats,
+		title:       "Week stats",
+		description: "Aggregate stats for the next 7 days starting today (meetings and meeting hours per working day, free hours, busiest day) as JSON or Prometheus metrics, for personal dashboards",
+	}, (*Server).callWeekStats)
+	registerTool(toolDefinition{
+		name:        toolServerVersion,
+		title:       "Server version",
+		description: "Report the server version, commit and build date (useful when reporting bugs)",
+		local:       true,
+	}, (*Server).callServerVersion)
+	registerTool(toolDefinition{
+		name:        toolListAccounts,
+		title:       "List accounts",
+		description: "List the Google accounts the server can act as (e.g. work and personal) and the calendar of each. With several accounts every other tool takes an optional account argument",
+		local:       true,
+	}, (*Server).callListAccounts)
+	registerTool(toolDefinition{
+		name:        toolAuthStatus,
+		title:       "Authentication status",
+		description: "Report who the server is authenticated as, the OAuth scopes granted, when the access token expires, and the calendars it can reach with their access roles; useful when calls start failing with permission errors",
+	}, (*Server).callAuthStatus)
+	registerTool(toolDefinition{
+		name:        toolReauthenticate,
+		title:       "Authorize again",
+		description: "Restore access after the OAuth token was revoked or expired (UNAUTHENTICATED errors), without restarting the server: call it without code to get the URL where the user allows access, then with the code or address the browser ends up at. On a machine without a browser, device returns a code to enter at a URL on any device instead",
+	}, (*Server).callReauthenticate)
+	registerTool(toolDefinition{
+		name:             toolSummarize,
+		title:            "Summarize schedule",
+		description:      "Summarize upcoming events in a few sentences, written by the client's model",
+		requiresSampling: true,
+	}, (*Server).callSummarizeSchedule)
+	registerTool(toolDefinition{
+		name:              toolRawRequest,
+		title:             "Raw Calendar API request",
+		description:       "Call a Google Calendar API method directly and get its JSON response, for what no other tool covers. Only the methods allowed by CALENDAR_RAW_METHODS can be called; list methods return one page, pass nextPageToken back as pageToken for the next",
+		requiresRawPolicy: true,
+		mutates:           (*Server).rawCallMutates,
+	}, (*Server).callRawRequest)
+}
+
+// exposedToolName returns the name a tool is advertised under
+func (s *Server) exposedToolName(name string) string {
+	if s.toolPrefix == "" {
+		return name
+	}
+	return s.toolPrefix + "_" + name
+}
+
+// internalToolName maps an advertised tool name back to the built-in name.
+// With a prefix configured, unprefixed names are rejected so that calls
+// meant for another server are never executed here.
+func (s *Server) internalToolName(exposed string) (string, bool) {
+	if s.toolPrefix == "" {
+		return exposed, true
+	}
+	return strings.CutPrefix(exposed, s.toolPrefix+"_")
+}
+
+// toolAlias keeps a renamed tool callable under its old name
+type toolAlias struct {
+	target string
+	// removal says when the old name stops working
+	removal string
+}
+
+// toolAliases maps deprecated tool names to their replacements. Aliases are
+// not listed in tools/list, but calls through them keep working with a
+// deprecation note so saved prompts don't break.
+var toolAliases = map[string]toolAlias{
+	"edit_event": {target: toolUpdateEvent, removal: "2.0.0"},
+}
+
+// resolveToolAlias returns the current name for a tool and, when name is a
+// deprecated alias, the note to attach to the response
+func resolveToolAlias(name string) (string, string) {
+	alias, ok := toolAliases[name]
+	if !ok {
+		return name, ""
+	}
+	note := fmt.Sprintf("Note: %s is deprecated and will be removed in %s; use %s instead.", name, alias.removal, alias.target)
+	return alias.target, note
+}
+
+func findTool(name string) (toolDefinition, bool) {
+	for _, t := range toolDefinitions {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return toolDefinition{}, false
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	latestReleaseURL   = "https://api.github.com/repos/cherya/google-calendar-mcp/releases/latest"
+	updateCheckTimeout = 10 * time.Second
+)
+
+type updateStatus struct {
+	Current string
+	Latest  string
+	URL     string
+}
+
+func (u updateStatus) available() bool {
+	return compareVersions(u.Latest, u.Current) > 0
+}
+
+func (u updateStatus) String() string {
+	if !u.available() {
+		return fmt.Sprintf("%s %s is up to date", serverName, u.Current)
+	}
+	return fmt.Sprintf("A newer version of %s is available: %s (running %s)\n%s", serverName, u.Latest, u.Current, u.URL)
+}
+
+// checkForUpdate asks GitHub for the latest published release
+func checkForUpdate(ctx context.Context, client *http.Client, url, current string) (updateStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, updateCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return updateStatus{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return updateStatus{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return updateStatus{}, fmt.Errorf("release check failed: %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return updateStatus{}, fmt.Errorf("invalid release response: %w", err)
+	}
+
+	return updateStatus{
+		Current: current,
+		Latest:  strings.TrimPrefix(release.TagName, "v"),
+		URL:     release.HTMLURL,
+	}, nil
+}
+
+// CheckForUpdate tells whether a newer release than this build is
+// available on GitHub
+func CheckForUpdate(ctx context.Context) (string, error) {
+	status, err := checkForUpdate(ctx, http.DefaultClient, latestReleaseURL, version)
+	if err != nil {
+		return "", err
+	}
+	return status.String(), nil
+}
+
+// NotifyUpdate logs a notice to stderr when a newer release exists. Errors
+// are ignored, since the check is advisory.
+func NotifyUpdate(ctx context.Context) {
+	status, err := checkForUpdate(ctx, http.DefaultClient, latestReleaseURL, version)
+	if err != nil || !status.available() {
+		return
+	}
+	log.Print(status)
+}
+
+// compareVersions compares dotted numeric versions such as 1.2.10,
+// ignoring a leading "v" and any pre-release suffix
+func compareVersions(a, b string) int {
+	pa := versionParts(a)
+	pb := versionParts(b)
+
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.1", "1.0.0", 1},
+		{"1.2.0", "1.10.0", -1},
+		{"v2.0", "1.9.9", 1},
+		{"1.0", "1.0.0", 0},
+		{"1.1.0-rc1", "1.1.0", 0},
+	}
+
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCheckForUpdate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v1.3.0","html_url":"https://github.com/cherya/google-calendar-mcp/releases/tag/v1.3.0"}`))
+	}))
+	defer srv.Close()
+
+	status, err := checkForUpdate(context.Background(), srv.Client(), srv.URL, "1.2.0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !status.available() {
+		t.Error("expected update to be available")
+	}
+	if status.Latest != "1.3.0" {
+		t.Errorf("expected latest 1.3.0, got %s", status.Latest)
+	}
+
+	status, _ = checkForUpdate(context.Background(), srv.Client(), srv.URL, "1.3.0")
+	if status.available() {
+		t.Error("expected no update when running the latest release")
+	}
+}
+
+func TestCheckForUpdate_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	if _, err := checkForUpdate(context.Background(), srv.Client(), srv.URL, "1.0.0"); err == nil {
+		t.Error("expected error for non-200 response")
+	}
+}
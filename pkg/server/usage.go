@@ -0,0 +1,82 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultDailyEventLimit approximates how many events Google lets an
+	// account create in a day. Google doesn't publish the number, and
+	// going over it fails further inserts with usageLimits errors for a
+	// while.
+	defaultDailyEventLimit = 10000
+	// usageWarnFraction is the share of the limit from which tool responses
+	// warn about it
+	usageWarnFraction = 0.8
+
+	usageWindow = 24 * time.Hour
+)
+
+// creationUsage counts the events the server created over the last day, to
+// warn while an import or automation approaches Google's calendar usage
+// limits instead of failing mid-batch. Sessions of the TCP transport share
+// it, as they share the account.
+type creationUsage struct {
+	limit int
+	now   func() time.Time
+
+	mu sync.Mutex
+	// created holds when each event of the window was created, oldest
+	// first
+	created []time.Time
+}
+
+func newCreationUsage(limit int) *creationUsage {
+	return &creationUsage{limit: limit, now: time.Now}
+}
+
+// add counts an event created now
+func (u *creationUsage) add() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := u.now()
+	u.expire(now)
+	u.created = append(u.created, now)
+}
+
+// count returns how many events were created over the last day
+func (u *creationUsage) count() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.expire(u.now())
+	return len(u.created)
+}
+
+func (u *creationUsage) expire(now time.Time) {
+	cutoff := now.Add(-usageWindow)
+	i := 0
+	for i < len(u.created) && !u.created[i].After(cutoff) {
+		i++
+	}
+	u.created = u.created[i:]
+}
+
+// warning returns the text tool responses carry once the count reaches
+// usageWarnFraction of the limit, or "" below it. A nil usage never warns.
+func (u *creationUsage) warning() string {
+	if u == nil {
+		return ""
+	}
+	n := u.count()
+	if float64(n) < usageWarnFraction*float64(u.limit) {
+		return ""
+	}
+	if n >= u.limit {
+		return fmt.Sprintf("Warning: %d events were created in the last 24 hours, at or above Google's daily limit of about %d. "+
+			"Google may refuse to create more events for a while; stop creating events and continue tomorrow.", n, u.limit)
+	}
+	return fmt.Sprintf("Warning: %d events were created in the last 24 hours, close to Google's daily limit of about %d. "+
+		"Past it Google refuses to create more events for a while; spread large imports over several days.", n, u.limit)
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCreationUsage_Window(t *testing.T) {
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC)
+	u := newCreationUsage(10)
+	u.now = func() time.Time { return now }
+
+	for range 7 {
+		u.add()
+	}
+	if w := u.warning(); w != "" {
+		t.Errorf("expected no warning below the threshold, got %q", w)
+	}
+	now = now.Add(time.Hour)
+	u.add()
+	if w := u.warning(); !strings.Contains(w, "8 events") || !strings.Contains(w, "close to") {
+		t.Errorf("expected a warning near the limit, got %q", w)
+	}
+	u.add()
+	u.add()
+	if w := u.warning(); !strings.Contains(w, "at or above") {
+		t.Errorf("expected a warning at the limit, got %q", w)
+	}
+
+	// The first seven fall out of the window a day later
+	now = now.Add(usageWindow - time.Hour)
+	if n := u.count(); n != 3 {
+		t.Errorf("expected 3 events in the window, got %d", n)
+	}
+	if w := (*creationUsage)(nil).warning(); w != "" {
+		t.Errorf("a nil usage should not warn, got %q", w)
+	}
+}
+
+func TestCallCreateEvent_UsageWarning(t *testing.T) {
+	s := newTestServer(&fakeCalendar{created: &calendar.Event{Id: "evt-1"}})
+	s.usage = newCreationUsage(2)
+	args, _ := json.Marshal(map[string]string{"summary": "Sync", "date": "2026-03-20", "start_time": "10:00", "end_time": "11:00"})
+
+	texts := func(name string) string {
+		resp := s.callTool(context.Background(), &toolCall{name: name, id: float64(1), args: args})
+		var all []string
+		for _, block := range resp.Result.(map[string]interface{})["content"].([]map[string]interface{}) {
+			all = append(all, block["text"].(string))
+		}
+		return strings.Join(all, "\n")
+	}
+	if text := texts(toolCreateEvent); strings.Contains(text, "Warning") {
+		t.Errorf("expected no warning for the first event, got %s", text)
+	}
+	if text := texts(toolCreateEvent); !strings.Contains(text, "2 events were created in the last 24 hours") {
+		t.Errorf("expected a usage warning, got %s", text)
+	}
+	if text := texts(toolListEvents); strings.Contains(text, "Warning") {
+		t.Errorf("calls that create nothing should not warn, got %s", text)
+	}
+}
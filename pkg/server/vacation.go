@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	maxVacationDays        = 60
+	defaultVacationSummary = "Out of office"
+
+	// Ways plan_vacation handles meetings during the vacation
+	vacationKeep    = "keep"
+	vacationFlag    = "flag"
+	vacationDecline = "decline"
+)
+
+// vacationMeeting is a meeting that falls into a planned vacation
+type vacationMeeting struct {
+	ID      string `json:"id"`
+	Summary string `json:"summary"`
+	Start   string `json:"start"`
+	// Note says what was done, or why the meeting needs attention
+	Note string `json:"note"`
+}
+
+type vacationPlan struct {
+	StartDate     string            `json:"startDate"`
+	EndDate       string            `json:"endDate"`
+	OutOfOfficeID string            `json:"outOfOfficeId"`
+	Link          string            `json:"link,omitempty"`
+	Declined      []vacationMeeting `json:"declined"`
+	Flagged       []vacationMeeting `json:"flagged"`
+}
+
+// vacationConflicts returns the meetings with other people that still
+// claim the user's time
+func vacationConflicts(events []gcal.CalendarEvent) []gcal.CalendarEvent {
+	var result []gcal.CalendarEvent
+	for _, e := range events {
+		if !blocksTime(e) {
+			continue
+		}
+		for _, g := range e.Guests {
+			if !g.Self {
+				result = append(result, e)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// planVacationInput is the arguments of plan_vacation
+type planVacationInput struct {
+	// First day of the vacation in YYYY-MM-DD format
+	StartDate string `json:"start_date" jsonschema:"required"`
+	// Last day of the vacation in YYYY-MM-DD format (max range: 60 days)
+	EndDate string `json:"end_date" jsonschema:"required"`
+	// Title of the out-of-office event (default: Out of office)
+	Summary string `json:"summary"`
+	// What to do with overlapping meetings: keep them, flag them in the
+	// summary (default), or decline them. Declining also auto-declines new
+	// invitations; meetings you organize are only flagged
+	Conflicts string `json:"conflicts" jsonschema:"enum=vacationKeep|vacationFlag|vacationDecline,default=vacationFlag"`
+	// Message sent with declined invitations (optional)
+	DeclineMessage string `json:"decline_message"`
+}
+
+func (s *Server) callPlanVacation(ctx context.Context, input planVacationInput) (vacationPlan, error) {
+	if input.StartDate == "" || input.EndDate == "" {
+		return vacationPlan{}, badArgumentf("start_date and end_date are required")
+	}
+	for _, date := range []*string{&input.StartDate, &input.EndDate} {
+		if err := s.normalizeDateArg(date); err != nil {
+			return vacationPlan{}, badArgument(err)
+		}
+	}
+	if err := checkRange(input.StartDate, input.EndDate, maxVacationDays); err != nil {
+		return vacationPlan{}, err
+	}
+	switch input.Conflicts {
+	case "":
+		input.Conflicts = vacationFlag
+	case vacationKeep, vacationFlag, vacationDecline:
+	default:
+		return vacationPlan{}, badArgumentf("conflicts must be keep, flag or decline")
+	}
+	if input.Summary == "" {
+		input.Summary = defaultVacationSummary
+	}
+
+	events, err := s.settings().calendar.ListEventsRange(ctx, input.StartDate, input.EndDate)
+	if err != nil {
+		return vacationPlan{}, err
+	}
+
+	start, _ := time.ParseInLocation("2006-01-02", input.StartDate, s.settings().location)
+	end, _ := time.ParseInLocation("2006-01-02", input.EndDate, s.settings().location)
+	decline := input.Conflicts == vacationDecline
+	ooo, err := s.settings().calendar.CreateOutOfOffice(ctx, input.Summary, start, end.AddDate(0, 0, 1), decline, input.DeclineMessage)
+	if err != nil {
+		return vacationPlan{}, err
+	}
+
+	plan := vacationPlan{
+		StartDate:     input.StartDate,
+		EndDate:       input.EndDate,
+		OutOfOfficeID: ooo.Id,
+		Link:          ooo.HtmlLink,
+		Declined:      []vacationMeeting{},
+		Flagged:       []vacationMeeting{},
+	}
+	if input.Conflicts == vacationKeep {
+		return plan, nil
+	}
+
+	for _, e := range vacationConflicts(events) {
+		meeting := vacationMeeting{ID: e.ID, Summary: e.Summary, Start: e.Start}
+		switch {
+		case !decline:
+			meeting.Note = "overlaps the vacation"
+		case e.OrganizerSelf:
+			// Declining your own meeting doesn't cancel it for the others
+			meeting.Note = "you organize it; reschedule or cancel it"
+		default:
+			if err := s.settings().calendar.RespondToEvent(ctx, e.ID, "declined", input.DeclineMessage); err != nil {
+				meeting.Note = fmt.Sprintf("could not decline: %v", err)
+				plan.Flagged = append(plan.Flagged, meeting)
+				continue
+			}
+			meeting.Note = "declined"
+			plan.Declined = append(plan.Declined, meeting)
+			continue
+		}
+		plan.Flagged = append(plan.Flagged, meeting)
+	}
+	return plan, nil
+}
+
+func (p vacationPlan) toolText(s *Server) string { return s.formatVacationPlan(p) }
+
+func (s *Server) formatVacationPlan(p vacationPlan) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Out-of-office event created for %s to %s\nID: %s\n", p.StartDate, p.EndDate, p.OutOfOfficeID)
+	if p.Link != "" {
+		fmt.Fprintf(&b, "Link: %s\n", p.Link)
+	}
+
+	if len(p.Declined) > 0 {
+		fmt.Fprintf(&b, "\nDeclined %d meeting(s):\n", len(p.Declined))
+		for _, m := range p.Declined {
+			fmt.Fprintf(&b, "- %s %s (ID %s)\n", m.Start, s.sanitize(m.Summary), m.ID)
+		}
+	}
+	if len(p.Flagged) > 0 {
+		fmt.Fprintf(&b, "\nNeeds attention, %d meeting(s):\n", len(p.Flagged))
+		for _, m := range p.Flagged {
+			fmt.Fprintf(&b, "- %s %s (ID %s): %s\n", m.Start, s.sanitize(m.Summary), m.ID, m.Note)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func vacationEvents() []gcal.CalendarEvent {
+	others := []gcal.Guest{{Email: "me@example.com", Self: true}, {Email: "bob@example.com"}}
+	return []gcal.CalendarEvent{
+		{ID: "sync", Summary: "Weekly sync", Start: "2026-08-03T10:00:00Z", End: "2026-08-03T11:00:00Z", Guests: others},
+		{ID: "mine", Summary: "Planning", Start: "2026-08-04T10:00:00Z", End: "2026-08-04T11:00:00Z", Guests: others, OrganizerSelf: true},
+		// Not meetings with others, or not blocking time
+		{ID: "gym", Summary: "Gym", Start: "2026-08-04T18:00:00Z", End: "2026-08-04T19:00:00Z"},
+		{ID: "skipped", Summary: "Already declined", Start: "2026-08-05T10:00:00Z", End: "2026-08-05T11:00:00Z",
+			Guests: []gcal.Guest{{Email: "me@example.com", Self: true, ResponseStatus: "declined"}, {Email: "bob@example.com"}}},
+	}
+}
+
+func TestCallPlanVacation_Decline(t *testing.T) {
+	fake := &fakeCalendar{events: vacationEvents()}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-08-03", "end_date": "2026-08-07", "conflicts": "decline"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolPlanVacation, id: float64(1), args: args})
+	if resp.Error != nil {
+		t.Fatalf("unexpected error: %v", resp.Error)
+	}
+
+	ooo := fake.outOfOffice
+	if ooo == nil || ooo.summary != defaultVacationSummary || !ooo.autoDecline {
+		t.Fatalf("unexpected out-of-office event %+v", ooo)
+	}
+	if !ooo.start.Equal(time.Date(2026, 8, 3, 0, 0, 0, 0, time.UTC)) || !ooo.end.Equal(time.Date(2026, 8, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected the event to cover whole days, got %s to %s", ooo.start, ooo.end)
+	}
+	if len(fake.responses) != 1 || fake.responses["sync"] != "declined" {
+		t.Errorf("expected only the weekly sync to be declined, got %v", fake.responses)
+	}
+
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Declined 1 meeting(s)") || !strings.Contains(text, "Planning (ID mine): you organize it") {
+		t.Errorf("unexpected summary:\n%s", text)
+	}
+}
+
+func TestCallPlanVacation_FlagByDefault(t *testing.T) {
+	fake := &fakeCalendar{events: vacationEvents(), respondErr: errors.New("should not be called")}
+	s := newTestServer(fake)
+
+	args, _ := json.Marshal(map[string]string{"start_date": "2026-08-03", "end_date": "2026-08-07"})
+	resp := s.callTool(context.Background(), &toolCall{name: toolPlanVacation, id: float64(1), args: args})
+
+	if len(fake.responses) != 0 || fake.outOfOffice.autoDecline {
+		t.Errorf("expected nothing to be declined, got %v", fake.responses)
+	}
+	text := resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	if !strings.Contains(text, "Needs attention, 2 meeting(s)") {
+		t.Errorf("unexpected summary:\n%s", text)
+	}
+}
+
+func TestCallPlanVacation_InvalidInput(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	for _, args := range []map[string]string{
+		{"start_date": "2026-08-03"},
+		{"start_date": "2026-08-03", "end_date": "2026-08-07", "conflicts": "ignore"},
+		{"start_date": "2026-08-03", "end_date": "2026-12-31"},
+	} {
+		raw, _ := json.Marshal(args)
+		resp := s.callTool(context.Background(), &toolCall{name: toolPlanVacation, id: float64(1), args: raw})
+		if resp.Error == nil || resp.Error.Code != -32602 {
+			t.Errorf("%v: expected invalid params, got %+v", args, resp.Error)
+		}
+	}
+}
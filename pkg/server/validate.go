@@ -0,0 +1,111 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// validateArguments checks tool arguments against the input schema of the
+// tool before they are decoded: the required arguments must be given, and
+// those given must have the declared type and stay within its enum,
+// maximum and maxItems. Arguments the schema doesn't know are left to the
+// handler, which ignores them.
+func validateArguments(schema map[string]interface{}, args json.RawMessage) error {
+	var given map[string]json.RawMessage
+	if len(args) > 0 && !bytes.Equal(bytes.TrimSpace(args), []byte("null")) {
+		if err := json.Unmarshal(args, &given); err != nil {
+			return fmt.Errorf("arguments must be an object")
+		}
+	}
+
+	required, _ := schema["required"].([]string)
+	var missing []string
+	for _, name := range required {
+		if value, ok := given[name]; !ok || isNull(value) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required arguments: %s", strings.Join(missing, ", "))
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, value := range given {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok || isNull(value) {
+			continue
+		}
+		if err := validateValue(name, prop, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateValue(name string, prop map[string]interface{}, value json.RawMessage) error {
+	typ, _ := prop["type"].(string)
+	switch typ {
+	case "string":
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			return fmt.Errorf("%s must be a string", name)
+		}
+		if enum, ok := prop["enum"].([]string); ok && !slices.Contains(enum, s) {
+			return fmt.Errorf("%s must be one of %s", name, strings.Join(enum, ", "))
+		}
+	case "integer", "number":
+		var n float64
+		if json.Unmarshal(value, &n) != nil || (typ == "integer" && n != math.Trunc(n)) {
+			return fmt.Errorf("%s must be an %s", name, typ)
+		}
+		if max, ok := schemaNumber(prop["maximum"]); ok && n > max {
+			return fmt.Errorf("%s must be at most %v", name, max)
+		}
+	case "boolean":
+		var b bool
+		if json.Unmarshal(value, &b) != nil {
+			return fmt.Errorf("%s must be true or false", name)
+		}
+	case "array":
+		var items []json.RawMessage
+		if json.Unmarshal(value, &items) != nil {
+			return fmt.Errorf("%s must be an array", name)
+		}
+		if max, ok := schemaNumber(prop["maxItems"]); ok && float64(len(items)) > max {
+			return fmt.Errorf("%s can have at most %v items", name, max)
+		}
+		if itemSchema, ok := prop["items"].(map[string]interface{}); ok {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", name, i), itemSchema, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		var m map[string]json.RawMessage
+		if json.Unmarshal(value, &m) != nil {
+			return fmt.Errorf("%s must be an object", name)
+		}
+	}
+	return nil
+}
+
+func isNull(value json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(value), []byte("null"))
+}
+
+// schemaNumber reads a numeric schema keyword, which the generated schemas
+// hold as untyped constants
+func schemaNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
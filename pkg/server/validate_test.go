@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateArguments(t *testing.T) {
+	for name, tc := range map[string]struct {
+		schema map[string]interface{}
+		args   string
+		err    string
+	}{
+		"valid":             {listEventsRangeInputSchema, `{"start_date":"2025-01-06","end_date":"2025-01-10","unknown":1}`, ""},
+		"no arguments":      {listEventsInputSchema, ``, ""},
+		"null optional":     {updateEventInputSchema, `{"event_id":"1","summary":null}`, ""},
+		"missing required":  {listEventsRangeInputSchema, `{"start_date":"2025-01-06"}`, "missing required arguments: end_date"},
+		"not an object":     {listEventsInputSchema, `[7]`, "arguments must be an object"},
+		"wrong type":        {listEventsInputSchema, `{"days":"7"}`, "days must be an integer"},
+		"fraction":          {listEventsInputSchema, `{"days":1.5}`, "days must be an integer"},
+		"over maximum":      {analyzeTimeInputSchema, `{"days":91}`, "days must be at most 90"},
+		"not in enum":       {weekStatsInputSchema, `{"format":"csv"}`, "format must be one of json, prometheus"},
+		"boolean":           {createEventInputSchema, `{"summary":"a","date":"b","start_time":"c","end_time":"d","force":"yes"}`, "force must be true or false"},
+		"too many items":    {broadcastEventInputSchema, `{"calendars":["1","2","3","4","5","6","7","8","9","10","11"],"summary":"a","date":"b","start_time":"c","end_time":"d"}`, "calendars can have at most 10 items"},
+		"wrong item type":   {timezoneMigrationInputSchema, `{"from_timezone":"UTC","event_ids":[1]}`, "event_ids[0] must be a string"},
+		"not an object arg": {rawRequestInputSchema, `{"method":"events.get","params":"abc"}`, "params must be an object"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := validateArguments(tc.schema, json.RawMessage(tc.args))
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.err {
+				t.Errorf("expected %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestHandleToolsCall_ValidatesArguments(t *testing.T) {
+	fake := &fakeCalendar{}
+	s := newTestServer(fake)
+	params, _ := json.Marshal(map[string]interface{}{"name": toolListEvents, "arguments": map[string]string{"days": "seven"}})
+	resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+	if resp.Error == nil || !strings.Contains(resp.Error.Message, "days must be an integer") {
+		t.Errorf("expected the arguments to be rejected, got %+v", resp.Error)
+	}
+	if fake.lastDays != 0 {
+		t.Error("the tool should not have been called")
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Verbosity levels of textual tool output
+const (
+	// verbosityTerse leaves out event IDs and links, for voice and mobile
+	// clients
+	verbosityTerse = "terse"
+	// verbosityNormal is the default
+	verbosityNormal = "normal"
+	// verbosityVerbose adds locations, guests and links to event listings
+	verbosityVerbose = "verbose"
+)
+
+// verbosityArgDescription describes the verbosity argument tools take when
+// the server isn't at normal verbosity
+const verbosityArgDescription = "Detail of the answer for this call: terse leaves out event IDs and links, verbose adds locations, guests and links; defaults to the server's setting"
+
+// terseDropped are the prefixes of the lines terse output leaves out
+var terseDropped = []string{"ID: ", "Link: ", "Broadcast ID: "}
+
+func parseVerbosity(v string) (string, error) {
+	switch v {
+	case verbosityTerse, verbosityNormal, verbosityVerbose:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown verbosity %q: expected %s, %s or %s", v, verbosityTerse, verbosityNormal, verbosityVerbose)
+}
+
+// verboseOutput outputs have more to say at verbose verbosity than their
+// toolText
+type verboseOutput interface {
+	verboseText(s *Server) string
+}
+
+// verbosityToolDefinition adds the verbosity argument to the schema of t
+func verbosityToolDefinition(t toolDefinition) toolDefinition {
+	return withArgument(t, "verbosity", map[string]interface{}{
+		"type":        "string",
+		"description": verbosityArgDescription,
+		"enum":        []string{verbosityTerse, verbosityNormal, verbosityVerbose},
+	})
+}
+
+// verbosityArg returns the verbosity a tool call asks for, or the server's
+// when it asks for none
+func (s *Server) verbosityArg(args json.RawMessage) (string, error) {
+	var given struct {
+		Verbosity string `json:"verbosity"`
+	}
+	if len(args) > 0 {
+		// Malformed arguments are reported by the tool itself
+		json.Unmarshal(args, &given)
+	}
+	if given.Verbosity == "" {
+		return s.settings().verbosity, nil
+	}
+	return parseVerbosity(given.Verbosity)
+}
+
+// outputText renders out at the given verbosity
+func (s *Server) outputText(out toolOutput, verbosity string) string {
+	if v, ok := out.(verboseOutput); ok && verbosity == verbosityVerbose {
+		return v.verboseText(s)
+	}
+	text := out.toolText(s)
+	if verbosity == verbosityTerse {
+		text = terseText(text)
+	}
+	return text
+}
+
+// terseText drops the ID and link lines from tool output
+func terseText(text string) string {
+	lines := strings.SplitAfter(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		dropped := false
+		for _, prefix := range terseDropped {
+			if strings.HasPrefix(trimmed, prefix) {
+				dropped = true
+				break
+			}
+		}
+		if !dropped {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
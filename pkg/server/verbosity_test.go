@@ -0,0 +1,92 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func callWithArgs(s *Server, name, args string) *JSONRPCResponse {
+	params, _ := json.Marshal(map[string]interface{}{"name": name, "arguments": json.RawMessage(args)})
+	return s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/call", Params: params})
+}
+
+func TestVerbosity_EventList(t *testing.T) {
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{{ID: "evt-1", Summary: "Planning", Start: "2026-10-15T10:00:00Z", End: "2026-10-15T11:00:00Z", Location: "Room 4", Attendees: 3, HTMLLink: "https://calendar.google.com/event?eid=1"}}}
+	s := newTestServer(fake)
+
+	text := func(resp *JSONRPCResponse) string {
+		t.Helper()
+		if resp.Error != nil {
+			t.Fatalf("unexpected error: %+v", resp.Error)
+		}
+		return resp.Result.(map[string]interface{})["content"].([]map[string]interface{})[0]["text"].(string)
+	}
+
+	normal := text(callWithArgs(s, toolListEvents, `{}`))
+	if !strings.Contains(normal, "ID: evt-1") || strings.Contains(normal, "Room 4") {
+		t.Errorf("unexpected normal listing:\n%s", normal)
+	}
+
+	s.configure(func(settings *sessionSettings) { settings.verbosity = verbosityTerse })
+	terse := text(callWithArgs(s, toolListEvents, `{}`))
+	if strings.Contains(terse, "evt-1") || !strings.Contains(terse, "- Planning\n") || !strings.Contains(terse, "Ref: #1") {
+		t.Errorf("expected the terse listing without IDs:\n%s", terse)
+	}
+	if asked := text(callWithArgs(s, toolListEvents, `{"verbosity":"normal"}`)); !strings.Contains(asked, "ID: evt-1") {
+		t.Errorf("expected IDs when the call asks for normal verbosity:\n%s", asked)
+	}
+
+	s.configure(func(settings *sessionSettings) { settings.verbosity = verbosityVerbose })
+	verbose := text(callWithArgs(s, toolListEvents, `{}`))
+	for _, want := range []string{"Location: Room 4", "Guests: 3", "Link: https://calendar.google.com/event?eid=1", "ID: evt-1"} {
+		if !strings.Contains(verbose, want) {
+			t.Errorf("expected %q in the verbose listing:\n%s", want, verbose)
+		}
+	}
+
+	if resp := callWithArgs(s, toolListEvents, `{"verbosity":"chatty"}`); resp.Error == nil {
+		t.Error("expected an unknown verbosity to be rejected")
+	}
+}
+
+func TestTerseText(t *testing.T) {
+	got := terseText("Event created successfully!\nID: evt-1\nLink: https://example.com\nDuration: 1h")
+	if want := "Event created successfully!\nDuration: 1h"; got != want {
+		t.Errorf("terseText() = %q, want %q", got, want)
+	}
+}
+
+func TestVerbosity_ToolsList(t *testing.T) {
+	s := newTestServer(&fakeCalendar{})
+	hasArg := func() bool {
+		resp := s.handleRequest(JSONRPCRequest{JSONRPC: "2.0", ID: float64(1), Method: "tools/list"})
+		tool := resp.Result.(map[string]interface{})["tools"].([]map[string]interface{})[0]
+		_, ok := tool["inputSchema"].(map[string]interface{})["properties"].(map[string]interface{})["verbosity"]
+		return ok
+	}
+	if hasArg() {
+		t.Error("expected no verbosity argument at normal verbosity")
+	}
+	s.configure(func(settings *sessionSettings) { settings.verbosity = verbosityTerse })
+	if !hasArg() {
+		t.Error("expected the verbosity argument at terse verbosity")
+	}
+}
+
+func TestSessionConfig_Verbosity(t *testing.T) {
+	s := New(&fakeCalendar{}, &bytes.Buffer{})
+	if resp := initializeWith(s, `{"verbosity":"terse"}`); resp.Error != nil || s.settings().verbosity != verbosityTerse {
+		t.Fatalf("expected terse verbosity, got %q (%+v)", s.settings().verbosity, resp.Error)
+	}
+	s.restoreDefaults()
+	if s.settings().verbosity != verbosityNormal {
+		t.Errorf("expected the server's verbosity back, got %q", s.settings().verbosity)
+	}
+	if resp := initializeWith(New(&fakeCalendar{}, &bytes.Buffer{}), `{"verbosity":"loud"}`); resp.Error == nil {
+		t.Error("expected an unknown verbosity to be rejected")
+	}
+}
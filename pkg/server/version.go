@@ -0,0 +1,68 @@
+package server
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information, set at build time with
+//
+//	go build -ldflags "-X $pkg.version=1.2.0 -X $pkg.commit=$(git rev-parse --short HEAD) -X $pkg.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// where $pkg is github.com/cherya/google-calendar-mcp/pkg/server
+var (
+	version   = "1.0.0"
+	commit    = ""
+	buildDate = ""
+)
+
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// currentBuildInfo returns the embedded build information, falling back to
+// the VCS stamp Go records for `go install` builds
+func currentBuildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// VersionInfo describes this build, e.g. for a -version flag
+func VersionInfo() string {
+	return currentBuildInfo().String()
+}
+
+func (b buildInfo) String() string {
+	return fmt.Sprintf("%s %s (commit %s, built %s, %s %s)", serverName, b.Version, b.Commit, b.BuildDate, b.GoVersion, b.Platform)
+}
+
+func (b buildInfo) toolText(*Server) string { return b.String() }
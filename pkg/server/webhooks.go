@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+const (
+	// webhookCheckInterval is how often upcoming events are checked against
+	// the triggers; it bounds how late a webhook can fire
+	webhookCheckInterval = time.Minute
+	webhookTimeout       = 10 * time.Second
+
+	// matchAllEvents as the filter of a trigger matches every event
+	matchAllEvents = "*"
+)
+
+// webhookTrigger posts to url lead before the start of every timed event
+// whose title contains match, case-insensitively
+type webhookTrigger struct {
+	spec  string
+	match string
+	lead  time.Duration
+	url   string
+}
+
+func (t webhookTrigger) matches(e gcal.CalendarEvent) bool {
+	return t.match == matchAllEvents || strings.Contains(strings.ToLower(e.Summary), t.match)
+}
+
+// webhookPayload is the JSON body posted when a trigger fires
+type webhookPayload struct {
+	Trigger       string             `json:"trigger"`
+	MinutesBefore int                `json:"minutesBefore"`
+	Event         gcal.CalendarEvent `json:"event"`
+	FiredAt       string             `json:"firedAt"`
+}
+
+// parseWebhookTriggers parses a comma-separated list of triggers, each
+// written as match/lead=url, e.g. gym/15m=https://ha.local/api/webhook/gym
+func parseWebhookTriggers(spec string) ([]webhookTrigger, error) {
+	var triggers []webhookTrigger
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		filter, target, found := strings.Cut(item, "=")
+		match, leadSpec, hasLead := strings.Cut(filter, "/")
+		if !found || !hasLead {
+			return nil, fmt.Errorf("trigger %q: expected match/lead=url, e.g. gym/15m=https://example.com/hook", item)
+		}
+		match = strings.ToLower(strings.TrimSpace(match))
+		if match == "" {
+			return nil, fmt.Errorf("trigger %q: empty match; use %s to match every event", item, matchAllEvents)
+		}
+		lead, err := time.ParseDuration(strings.TrimSpace(leadSpec))
+		if err != nil || lead < 0 {
+			return nil, fmt.Errorf("trigger %q: invalid lead time %q", item, leadSpec)
+		}
+		target = strings.TrimSpace(target)
+		if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("trigger %q: %q is not an http or https URL", item, target)
+		}
+		triggers = append(triggers, webhookTrigger{spec: item, match: match, lead: lead, url: target})
+	}
+	return triggers, nil
+}
+
+// runWebhooks fires the configured webhook triggers until the process
+// exits
+func (s *Server) runWebhooks() {
+	ticker := time.NewTicker(webhookCheckInterval)
+	defer ticker.Stop()
+
+	s.checkWebhooks(context.Background(), time.Now())
+	for now := range ticker.C {
+		s.checkWebhooks(context.Background(), now)
+	}
+}
+
+// checkWebhooks posts every trigger whose time has come for an event that
+// hasn't started yet. A trigger fires once per event and start time, so a
+// rescheduled event fires again; failed deliveries are retried on the next
+// check until the event starts.
+func (s *Server) checkWebhooks(ctx context.Context, now time.Time) {
+	var maxLead time.Duration
+	for _, t := range s.webhooks {
+		maxLead = max(maxLead, t.lead)
+	}
+	base := s.configured()
+	now = now.In(base.location)
+	events, err := base.calendar.ListEventsRange(ctx, now.Format("2006-01-02"), now.Add(maxLead).Format("2006-01-02"))
+	if err != nil {
+		log.Printf("Failed to check webhook triggers: %v", err)
+		return
+	}
+
+	s.firedMu.Lock()
+	for key, start := range s.fired {
+		if !start.After(now) {
+			delete(s.fired, key)
+		}
+	}
+	s.firedMu.Unlock()
+
+	for _, e := range events {
+		start, err := time.Parse(time.RFC3339, e.Start)
+		// All-day events have no start time to count down to
+		if err != nil || !start.After(now) {
+			continue
+		}
+		for _, t := range s.webhooks {
+			if !t.matches(e) || now.Before(start.Add(-t.lead)) {
+				continue
+			}
+			key := t.spec + "\x00" + e.CalendarID + "\x00" + e.ID + "\x00" + e.Start
+			s.firedMu.Lock()
+			_, done := s.fired[key]
+			s.firedMu.Unlock()
+			if done {
+				continue
+			}
+
+			payload := webhookPayload{
+				Trigger:       t.spec,
+				MinutesBefore: int(start.Sub(now).Round(time.Minute).Minutes()),
+				Event:         e,
+				FiredAt:       now.Format(time.RFC3339),
+			}
+			if err := s.postWebhook(ctx, t.url, payload); err != nil {
+				log.Printf("Webhook %s for event %s failed: %v", t.spec, e.ID, err)
+				continue
+			}
+			s.firedMu.Lock()
+			s.fired[key] = start
+			s.firedMu.Unlock()
+		}
+	}
+}
+
+func (s *Server) postWebhook(ctx context.Context, target string, payload webhookPayload) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cherya/google-calendar-mcp/pkg/gcal"
+)
+
+func TestParseWebhookTriggers(t *testing.T) {
+	triggers, err := parseWebhookTriggers("Gym/15m=https://ha.local/api/webhook/gym, */0s=http://localhost:8123/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(triggers) != 2 || triggers[0].match != "gym" || triggers[0].lead != 15*time.Minute || triggers[0].url != "https://ha.local/api/webhook/gym" {
+		t.Errorf("unexpected triggers %+v", triggers)
+	}
+	if triggers[1].match != matchAllEvents || triggers[1].lead != 0 {
+		t.Errorf("unexpected catch-all trigger %+v", triggers[1])
+	}
+
+	for _, bad := range []string{"gym=https://x.example", "gym/15m", "/15m=https://x.example", "gym/soon=https://x.example", "gym/-5m=https://x.example", "gym/15m=ftp://x.example", "gym/15m=not a url"} {
+		if _, err := parseWebhookTriggers(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestCheckWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var received []webhookPayload
+	status := http.StatusOK
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhookPayload
+		json.NewDecoder(r.Body).Decode(&p)
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			received = append(received, p)
+		}
+	}))
+	defer hook.Close()
+
+	fake := &fakeCalendar{events: []gcal.CalendarEvent{
+		{ID: "gym", Summary: "Evening GYM session", Start: "2026-03-16T18:00:00Z", End: "2026-03-16T19:00:00Z"},
+		{ID: "standup", Summary: "Standup", Start: "2026-03-16T18:05:00Z", End: "2026-03-16T18:20:00Z"},
+		{ID: "holiday", Summary: "Gym closed", Start: "2026-03-16", End: "2026-03-17"},
+	}}
+	s := newTestServer(fake)
+	triggers, err := parseWebhookTriggers("gym/15m=" + hook.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.webhooks = triggers
+
+	// Too early
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 17, 30, 0, 0, time.UTC))
+	if len(received) != 0 {
+		t.Fatalf("expected no webhook 30 minutes before, got %+v", received)
+	}
+
+	// A failed delivery is retried on the next check
+	setStatus := func(code int) {
+		mu.Lock()
+		defer mu.Unlock()
+		status = code
+	}
+	setStatus(http.StatusInternalServerError)
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 17, 45, 0, 0, time.UTC))
+	setStatus(http.StatusOK)
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 17, 46, 0, 0, time.UTC))
+	if len(received) != 1 || received[0].Event.ID != "gym" || received[0].MinutesBefore != 14 {
+		t.Fatalf("expected one webhook for the gym session, got %+v", received)
+	}
+	if fake.lastStart != "2026-03-16" || fake.lastEnd != "2026-03-16" {
+		t.Errorf("unexpected range %s..%s", fake.lastStart, fake.lastEnd)
+	}
+
+	// Fires once per event
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 17, 50, 0, 0, time.UTC))
+	if len(received) != 1 {
+		t.Errorf("expected the trigger to fire once, got %d webhooks", len(received))
+	}
+
+	// A rescheduled event fires again
+	fake.events[0].Start = "2026-03-16T18:10:00Z"
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 17, 56, 0, 0, time.UTC))
+	if len(received) != 2 || received[1].Event.Start != "2026-03-16T18:10:00Z" {
+		t.Errorf("expected a webhook for the new start time, got %+v", received)
+	}
+
+	// Started events are forgotten
+	s.checkWebhooks(context.Background(), time.Date(2026, 3, 16, 18, 30, 0, 0, time.UTC))
+	if len(s.fired) != 0 || len(received) != 2 {
+		t.Errorf("expected no pending triggers after the events started, got %v", s.fired)
+	}
+}